@@ -0,0 +1,100 @@
+package auth
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"os"
+	"time"
+
+	"github.com/dedegunawan/backend-ujian-telp-v5/utils"
+	"golang.org/x/oauth2"
+)
+
+const defaultLoginStateTTL = 10 * time.Minute
+
+var (
+	ErrLoginStateInvalid = errors.New("state login tidak valid")
+	ErrLoginStateExpired = errors.New("state login sudah kedaluwarsa, silakan login ulang")
+)
+
+// LoginState data yang diikat ke browser selama alur authorization code:
+// state dan nonce dicocokkan saat callback, verifier untuk PKCE.
+// Disimpan terenkripsi (AES-GCM, sekaligus menjamin integritas) di cookie HttpOnly.
+type LoginState struct {
+	State     string    `json:"s"`
+	Nonce     string    `json:"n"`
+	Verifier  string    `json:"v"`
+	BackTo    string    `json:"b"`
+	ExpiresAt time.Time `json:"e"`
+}
+
+func LoginStateTTL() time.Duration {
+	if raw := os.Getenv("OIDC_STATE_TTL"); raw != "" {
+		if parsed, err := time.ParseDuration(raw); err == nil && parsed > 0 {
+			return parsed
+		}
+	}
+	return defaultLoginStateTTL
+}
+
+// NewLoginState membuat state, nonce dan PKCE verifier baru untuk satu percobaan login
+func NewLoginState(backTo string) (*LoginState, error) {
+	state, err := utils.RandomToken(16)
+	if err != nil {
+		return nil, err
+	}
+	nonce, err := utils.RandomToken(16)
+	if err != nil {
+		return nil, err
+	}
+	return &LoginState{
+		State:     state,
+		Nonce:     nonce,
+		Verifier:  oauth2.GenerateVerifier(),
+		BackTo:    backTo,
+		ExpiresAt: time.Now().Add(LoginStateTTL()),
+	}, nil
+}
+
+// CookieName nama cookie per percobaan login, supaya login di beberapa tab tidak saling menimpa
+func (s *LoginState) CookieName() string {
+	return LoginStateCookieName(s.State)
+}
+
+func LoginStateCookieName(state string) string {
+	if len(state) > 12 {
+		state = state[:12]
+	}
+	return "oidc_state_" + state
+}
+
+func (s *LoginState) Encode() (string, error) {
+	payload, err := json.Marshal(s)
+	if err != nil {
+		return "", err
+	}
+	return utils.EncryptString(string(payload))
+}
+
+// DecodeLoginState membuka cookie dan mencocokkan state dari query callback
+func DecodeLoginState(encoded, state string) (*LoginState, error) {
+	if encoded == "" || state == "" {
+		return nil, ErrLoginStateInvalid
+	}
+	payload, err := utils.DecryptString(encoded)
+	if err != nil {
+		return nil, ErrLoginStateInvalid
+	}
+	var loginState LoginState
+	if err := json.Unmarshal([]byte(payload), &loginState); err != nil {
+		return nil, ErrLoginStateInvalid
+	}
+	if subtle.ConstantTimeCompare([]byte(loginState.State), []byte(state)) != 1 {
+		return nil, ErrLoginStateInvalid
+	}
+	if time.Now().After(loginState.ExpiresAt) {
+		return nil, ErrLoginStateExpired
+	}
+	return &loginState, nil
+}
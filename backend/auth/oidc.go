@@ -18,9 +18,14 @@ func InitOIDC() {
 	ctx := context.Background()
 	issuer := os.Getenv("OIDC_ISSUER") // e.g. http://localhost:8080/realms/myrealm
 
-	provider, err := oidc.NewProvider(ctx, issuer)
+	var provider *oidc.Provider
+	err := utils.RetryStartup("Discovery OIDC", utils.StartupTimeout(), func() error {
+		var err error
+		provider, err = oidc.NewProvider(ctx, issuer)
+		return err
+	})
 	if err != nil {
-		utils.Log.Fatal("❌ Failed to init Keycloak OIDC provider:", err)
+		utils.Log.Fatalw("❌ Failed to init Keycloak OIDC provider", "error", err)
 	}
 	Provider = provider
 
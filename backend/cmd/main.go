@@ -1,9 +1,22 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"net/http"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
 	"github.com/dedegunawan/backend-ujian-telp-v5/config"
 	"github.com/dedegunawan/backend-ujian-telp-v5/database"
+	"github.com/dedegunawan/backend-ujian-telp-v5/database/migrations"
+	"github.com/dedegunawan/backend-ujian-telp-v5/metrics"
+	"github.com/dedegunawan/backend-ujian-telp-v5/realtime"
 	"github.com/dedegunawan/backend-ujian-telp-v5/routes"
+	"github.com/dedegunawan/backend-ujian-telp-v5/services"
+	"github.com/dedegunawan/backend-ujian-telp-v5/tracing"
 	"github.com/dedegunawan/backend-ujian-telp-v5/utils"
 )
 
@@ -13,12 +26,126 @@ func main() {
 
 	config.LoadEnv()
 
+	// Kunci untuk cookie state login, secret TOTP dan token reset password
+	if err := utils.CheckEncryptionKey(); err != nil {
+		utils.Log.Fatalw("❌ Kunci enkripsi belum diatur", "error", err)
+	}
+
+	if err := services.LoadEligibilityRules(); err != nil {
+		utils.Log.Fatalw("❌ Gagal memuat aturan kelayakan", "error", err)
+	}
+
+	// Tracing dipasang sebelum koneksi lain supaya client MinIO/DB/HTTP ikut ter-trace
+	shutdownTracing, err := tracing.Init(context.Background())
+	if err != nil {
+		utils.Log.Fatalw("❌ Failed to init tracing", "error", err)
+	}
+
 	utils.InitStorage()
 
 	database.ConnectDatabasePnbp()
 
-	// Semua data hanya read-only dari DBPNBP (MySQL)
-	// Tidak ada worker, tidak ada operasi write ke database
+	// Tabel milik aplikasi ini dikelola database/migrations (CLI: go run ./cmd/migrate); set
+	// MIGRATE_ON_START=false jika migrasi dijalankan sebagai langkah deploy terpisah
+	migrateOnStart(config.GetEnv("MIGRATE_ON_START") != "false")
+
+	// SIGTERM/SIGINT membatalkan ctx: HTTP berhenti menerima request baru dan worker berhenti
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	var workers sync.WaitGroup
+	startWorker := func(run func(ctx context.Context)) {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			run(ctx)
+		}()
+	}
+
+	notificationEnabled := config.GetEnv("NOTIFICATION_ENABLED") == "true"
+	webhookEnabled := config.GetEnv("WEBHOOK_ENABLED") == "true"
+
+	// JobQueue dipakai bersama oleh export, notifikasi dan webhook. Export yang terputus oleh
+	// restart sebelumnya ditandai gagal supaya tidak tampil queued/processing selamanya.
+	if failed, err := services.NewExportService(database.DBPNBP).FailStale(envDuration("EXPORT_STALE_AFTER", time.Hour)); err != nil {
+		utils.Log.Warnw("Gagal menandai export yang terputus", "error", err)
+	} else if failed > 0 {
+		utils.Log.Warnf("⚠️ %d export terputus ditandai gagal", failed)
+	}
+	if err := metrics.RegisterJobQueue(database.DBPNBP); err != nil {
+		utils.Log.Warnw("Gagal mendaftarkan metrik job queue", "error", err)
+	}
+	startWorker(func(ctx context.Context) { services.NewWorkerService(database.DBPNBP).StartWorker(ctx, "job-worker") })
+
+	// Notifikasi mahasiswa (email/WhatsApp), aktif jika NOTIFICATION_ENABLED=true
+	if notificationEnabled {
+		pollInterval := time.Minute
+		if raw := config.GetEnv("NOTIFICATION_POLL_INTERVAL"); raw != "" {
+			if parsed, err := time.ParseDuration(raw); err == nil && parsed > 0 {
+				pollInterval = parsed
+			}
+		}
+		startWorker(func(ctx context.Context) {
+			services.NewNotificationWatcher(database.DBPNBP).StartWorker(ctx, "notification-watcher", pollInterval)
+		})
+
+		reminderInterval := time.Hour
+		if raw := config.GetEnv("REMINDER_INTERVAL"); raw != "" {
+			if parsed, err := time.ParseDuration(raw); err == nil && parsed > 0 {
+				reminderInterval = parsed
+			}
+		}
+		startWorker(func(ctx context.Context) {
+			services.NewReminderService(database.DBPNBP).StartScheduler(ctx, "payment-reminder", reminderInterval)
+		})
+	}
+
+	// Webhook keluar untuk sistem kampus lain (perpustakaan, wisuda, asrama), aktif jika WEBHOOK_ENABLED=true
+	if webhookEnabled {
+		webhookInterval := 30 * time.Second
+		if raw := config.GetEnv("WEBHOOK_POLL_INTERVAL"); raw != "" {
+			if parsed, err := time.ParseDuration(raw); err == nil && parsed > 0 {
+				webhookInterval = parsed
+			}
+		}
+		startWorker(func(ctx context.Context) {
+			services.NewWebhookWatcher(database.DBPNBP).StartWorker(ctx, "webhook-watcher", webhookInterval)
+		})
+	}
+
+	// Sesi login (user_tokens) yang access & refresh token-nya sudah kedaluwarsa
+	sessionCleanupInterval := time.Hour
+	if raw := config.GetEnv("SESSION_CLEANUP_INTERVAL"); raw != "" {
+		if parsed, err := time.ParseDuration(raw); err == nil && parsed > 0 {
+			sessionCleanupInterval = parsed
+		}
+	}
+	startWorker(func(ctx context.Context) {
+		services.NewUserTokenService(database.DBPNBP, nil).StartCleanup(ctx, "session-cleanup", sessionCleanupInterval)
+	})
+
+	// Push status pembayaran ke halaman mahasiswa (SSE), poller payments baru
+	paymentEventInterval := 10 * time.Second
+	if raw := config.GetEnv("PAYMENT_EVENT_POLL_INTERVAL"); raw != "" {
+		if parsed, err := time.ParseDuration(raw); err == nil && parsed > 0 {
+			paymentEventInterval = parsed
+		}
+	}
+	startWorker(func(ctx context.Context) {
+		services.NewPaymentEventService(database.DBPNBP).StartWorker(ctx, "payment-event-poller", paymentEventInterval)
+	})
+
+	// Tabel EPNBP tetap read-only; laporan tunggakan berkala hanya upload ke MinIO (opsional)
+	if raw := config.GetEnv("ARREARS_REPORT_INTERVAL"); raw != "" {
+		interval, err := time.ParseDuration(raw)
+		if err != nil || interval <= 0 {
+			utils.Log.Warnf("⚠️ ARREARS_REPORT_INTERVAL tidak valid: %s", raw)
+		} else {
+			startWorker(func(ctx context.Context) {
+				services.NewArrearsService(database.DBPNBP).StartScheduler(ctx, "arrears-report", interval)
+			})
+		}
+	}
 
 	r := routes.SetupRouter()
 
@@ -28,9 +155,116 @@ func main() {
 		utils.Log.Warn("⚠️ APP_PORT not set, using default port 8080")
 	}
 
-	utils.Log.Infof("✅ Server running at :%s", appPort)
-	err := r.Run(":" + appPort)
+	srv := &http.Server{Addr: ":" + appPort, Handler: r}
+	// Koneksi SSE tidak selesai sendiri; tutup hub supaya Shutdown tidak menunggu sampai timeout
+	srv.RegisterOnShutdown(realtime.Default().Close)
+
+	serverErr := make(chan error, 1)
+	go func() {
+		utils.Log.Infof("✅ Server running at :%s", appPort)
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			serverErr <- err
+		}
+		close(serverErr)
+	}()
+
+	select {
+	case err := <-serverErr:
+		if err != nil {
+			utils.Log.Fatalw("❌ Failed to start server", "error", err)
+		}
+	case <-ctx.Done():
+	}
+	stop()
+	shutdown(srv, &workers, shutdownTracing)
+}
+
+// migrateOnStart menerapkan migrasi pending (apply=true) atau, jika migrasi dijalankan sebagai
+// langkah deploy terpisah, menolak start selama masih ada yang pending: fitur yang tabelnya belum
+// dibuat baru gagal saat endpoint / worker-nya berjalan. Drift (file migrasi yang sudah
+// diterapkan berubah/hilang) selalu menghentikan start.
+func migrateOnStart(apply bool) {
+	migrator, err := migrations.New(database.DBPNBP)
+	if err != nil {
+		utils.Log.Fatalw("❌ File migrasi tidak valid", "error", err)
+	}
+
+	if apply {
+		applied, err := migrator.Up(0)
+		for _, migration := range applied {
+			utils.Log.Infow("Migrasi diterapkan", "migration", migration.ID())
+		}
+		if err != nil {
+			utils.Log.Fatalw("❌ Migrasi database gagal", "error", err)
+		}
+		return
+	}
+
+	statuses, err := migrator.Status()
 	if err != nil {
-		utils.Log.Fatal("❌ Failed to start server:", err)
+		utils.Log.Fatalw("❌ Gagal membaca status migrasi", "error", err)
+	}
+	pending := 0
+	for _, status := range statuses {
+		switch status.State {
+		case migrations.StateModified, migrations.StateMissing:
+			utils.Log.Fatalf("❌ %v: %06d_%s (%s)", migrations.ErrDrift, status.Version, status.Name, status.State)
+		case migrations.StatePending:
+			pending++
+			utils.Log.Errorw("❌ Migrasi belum diterapkan", "version", status.Version, "name", status.Name)
+		}
+	}
+	if pending > 0 {
+		utils.Log.Fatalf("❌ %d migrasi belum diterapkan, jalankan: migrate up (atau MIGRATE_ON_START=true)", pending)
+	}
+}
+
+// shutdown: /readyz 503 dulu (SHUTDOWN_DRAIN_DELAY) agar load balancer berhenti mengirim traffic,
+// lalu tunggu request yang sedang berjalan dan worker selesai maksimal SHUTDOWN_TIMEOUT
+func shutdown(srv *http.Server, workers *sync.WaitGroup, shutdownTracing func(context.Context) error) {
+	utils.Log.Info("🛑 Shutdown signal received")
+	services.MarkShuttingDown()
+
+	if delay := envDuration("SHUTDOWN_DRAIN_DELAY", 0); delay > 0 {
+		utils.Log.Infof("Menunggu %s sebelum berhenti menerima request", delay)
+		time.Sleep(delay)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), envDuration("SHUTDOWN_TIMEOUT", 30*time.Second))
+	defer cancel()
+
+	if err := srv.Shutdown(ctx); err != nil {
+		utils.Log.Errorf("❌ HTTP shutdown tidak selesai: %v", err)
+	}
+
+	workersDone := make(chan struct{})
+	go func() {
+		workers.Wait()
+		close(workersDone)
+	}()
+	select {
+	case <-workersDone:
+	case <-ctx.Done():
+		utils.Log.Error("❌ Worker belum berhenti saat SHUTDOWN_TIMEOUT habis")
+	}
+
+	// Kirim span yang masih di buffer
+	if err := shutdownTracing(ctx); err != nil {
+		utils.Log.Errorf("❌ Gagal flush trace: %v", err)
+	}
+
+	if sqlDB, err := database.DBPNBP.DB(); err == nil {
+		sqlDB.Close()
+	}
+	utils.Log.Info("✅ Server stopped")
+}
+
+func envDuration(key string, def time.Duration) time.Duration {
+	if raw := config.GetEnv(key); raw != "" {
+		if parsed, err := time.ParseDuration(raw); err == nil && parsed >= 0 {
+			return parsed
+		}
+		utils.Log.Warnf("⚠️ %s tidak valid: %s", key, raw)
 	}
+	return def
 }
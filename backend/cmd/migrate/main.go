@@ -0,0 +1,113 @@
+// Command migrate mengelola skema tabel milik backend ini (database/migrations).
+//
+//	go run ./cmd/migrate status
+//	go run ./cmd/migrate up [-steps N]      # default: semua yang pending
+//	go run ./cmd/migrate down [-steps N]    # default: 1 migrasi terakhir
+//	go run ./cmd/migrate create <nama>      # mis. add_index_export_jobs_created_at
+//
+// Koneksi memakai variabel EPNBP_DB_* yang sama dengan aplikasi.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/dedegunawan/backend-ujian-telp-v5/config"
+	"github.com/dedegunawan/backend-ujian-telp-v5/database"
+	"github.com/dedegunawan/backend-ujian-telp-v5/database/migrations"
+	"github.com/dedegunawan/backend-ujian-telp-v5/utils"
+)
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+
+	command := os.Args[1]
+	flags := flag.NewFlagSet(command, flag.ExitOnError)
+	steps := flags.Int("steps", 0, "jumlah migrasi (up: 0 = semua, down: 0 = 1)")
+	dir := flags.String("dir", "database/migrations", "folder file migrasi (untuk create)")
+	flags.Parse(os.Args[2:])
+
+	if command == "create" {
+		if flags.NArg() != 1 {
+			fail(fmt.Errorf("create membutuhkan nama migrasi, mis. migrate create add_index_export_jobs_created_at"))
+		}
+		upPath, downPath, err := migrations.Create(*dir, flags.Arg(0))
+		if err != nil {
+			fail(err)
+		}
+		fmt.Println("✅ Dibuat:", upPath)
+		fmt.Println("✅ Dibuat:", downPath)
+		fmt.Println("Build ulang binary supaya file baru ikut ter-embed.")
+		return
+	}
+
+	utils.InitLogger()
+	config.LoadEnv()
+	database.ConnectDatabasePnbp()
+
+	migrator, err := migrations.New(database.DBPNBP)
+	if err != nil {
+		fail(err)
+	}
+
+	switch command {
+	case "status":
+		statuses, err := migrator.Status()
+		if err != nil {
+			fail(err)
+		}
+		printStatus(statuses)
+	case "up":
+		applied, err := migrator.Up(*steps)
+		for _, migration := range applied {
+			fmt.Println("⬆️  Diterapkan:", migration.ID())
+		}
+		if err != nil {
+			fail(err)
+		}
+		if len(applied) == 0 {
+			fmt.Println("Tidak ada migrasi pending.")
+		}
+	case "down":
+		reverted, err := migrator.Down(*steps)
+		for _, migration := range reverted {
+			fmt.Println("⬇️  Dibatalkan:", migration.ID())
+		}
+		if err != nil {
+			fail(err)
+		}
+		if len(reverted) == 0 {
+			fmt.Println("Tidak ada migrasi yang bisa dibatalkan.")
+		}
+	default:
+		usage()
+		os.Exit(2)
+	}
+}
+
+func printStatus(statuses []migrations.MigrationStatus) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "VERSION\tNAME\tSTATE\tAPPLIED AT")
+	for _, status := range statuses {
+		appliedAt := "-"
+		if status.AppliedAt != nil {
+			appliedAt = status.AppliedAt.Format("2006-01-02 15:04:05")
+		}
+		fmt.Fprintf(w, "%06d\t%s\t%s\t%s\n", status.Version, status.Name, status.State, appliedAt)
+	}
+	w.Flush()
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "Penggunaan: migrate <status|up|down|create> [-steps N] [-dir folder] [nama]")
+}
+
+func fail(err error) {
+	fmt.Fprintln(os.Stderr, "❌", err)
+	os.Exit(1)
+}
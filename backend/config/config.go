@@ -9,7 +9,7 @@ import (
 )
 
 func LoadEnv() {
-	utils.Log.Info("Loading environment variables from env, ", os.Getenv("APP_ENV"))
+	utils.Log.Infow("Loading environment variables from env", "env", os.Getenv("APP_ENV"))
 	if os.Getenv("APP_ENV") != "production" {
 		err := godotenv.Load(".env")
 		if err != nil {
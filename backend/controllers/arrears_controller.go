@@ -0,0 +1,94 @@
+package controllers
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/dedegunawan/backend-ujian-telp-v5/database"
+	"github.com/dedegunawan/backend-ujian-telp-v5/models"
+	"github.com/dedegunawan/backend-ujian-telp-v5/services"
+	"github.com/dedegunawan/backend-ujian-telp-v5/utils"
+	"github.com/gin-gonic/gin"
+)
+
+func arrearsFilterFromQuery(c *gin.Context) models.ArrearsFilter {
+	return models.ArrearsFilter{
+		FakultasID:   c.Query("fakultas_id"),
+		ProdiID:      c.Query("prodi_id"),
+		StatusKode:   c.Query("status"),
+		IncludeItems: c.Query("include_items") == "true",
+	}
+}
+
+// GetArrearsReport GET /api/v1/finance/arrears
+// Query params:
+//   - fakultas_id, prodi_id, status (kode status akademik): filter
+//   - include_items=true: sertakan rincian item per mahasiswa
+func GetArrearsReport(c *gin.Context) {
+	report, err := services.NewArrearsService(database.DBPNBP).GenerateReport(arrearsFilterFromQuery(c))
+	if err != nil {
+		utils.LogCtx(c.Request.Context()).Errorw("GetArrearsReport: gagal mengambil data", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengambil laporan tunggakan"})
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// GetArrearsStudent GET /api/v1/finance/arrears/:npm
+// Drill-down tunggakan satu mahasiswa beserta rincian itemnya
+func GetArrearsStudent(c *gin.Context) {
+	filter := models.ArrearsFilter{NPM: c.Param("npm"), IncludeItems: true}
+	report, err := services.NewArrearsService(database.DBPNBP).GenerateReport(filter)
+	if err != nil {
+		utils.LogCtx(c.Request.Context()).Errorw("GetArrearsStudent: gagal mengambil data",
+			"npm", filter.NPM,
+			"error", err,
+		)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengambil tunggakan mahasiswa"})
+		return
+	}
+
+	if len(report.Students) == 0 {
+		c.JSON(http.StatusOK, gin.H{
+			"tahun_aktif": report.TahunAktif,
+			"student":     models.ArrearsStudent{NPM: filter.NPM, Buckets: map[string]int64{}},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"tahun_aktif": report.TahunAktif,
+		"student":     report.Students[0],
+	})
+}
+
+// ExportArrears GET /api/v1/finance/arrears/export
+// Filter sama dengan GetArrearsReport, hasil XLSX (ringkasan + rincian) di-upload ke MinIO
+func ExportArrears(c *gin.Context) {
+	service := services.NewArrearsService(database.DBPNBP)
+
+	filter := arrearsFilterFromQuery(c)
+	filter.IncludeItems = true
+	report, err := service.GenerateReport(filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	buffer, err := service.BuildWorkbook(report)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	filename := fmt.Sprintf("exports/tunggakan_%d.xlsx", time.Now().Unix())
+	url, err := utils.UploadObjectToMinio(filename, buffer.Bytes(), "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"url": url})
+}
@@ -2,24 +2,48 @@ package controllers
 
 import (
 	"context"
+	"crypto/subtle"
+	"errors"
 	"fmt"
-	"log"
 	"net/http"
+	"net/url"
 	"os"
 
-	_ "github.com/coreos/go-oidc/v3/oidc"
+	"github.com/coreos/go-oidc/v3/oidc"
 	"github.com/dedegunawan/backend-ujian-telp-v5/auth"
 	"github.com/dedegunawan/backend-ujian-telp-v5/config"
+	"github.com/dedegunawan/backend-ujian-telp-v5/database"
+	"github.com/dedegunawan/backend-ujian-telp-v5/middleware"
+	"github.com/dedegunawan/backend-ujian-telp-v5/models"
+	"github.com/dedegunawan/backend-ujian-telp-v5/repositories"
+	"github.com/dedegunawan/backend-ujian-telp-v5/services"
 	"github.com/dedegunawan/backend-ujian-telp-v5/utils"
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 	"golang.org/x/oauth2"
 )
 
 func SsoLoginHandler(c *gin.Context) {
-	backTo := c.Query("backTo") // contoh: "/dashboard"
-	state := utils.EncodeBackState(backTo)
+	backTo := utils.SanitizeBackTo(c.Query("backTo")) // contoh: "/dashboard"
 
-	authCodeURL := auth.OAuth2Config.AuthCodeURL(state, oauth2.AccessTypeOffline)
+	// state + nonce + PKCE verifier diikat ke browser lewat cookie terenkripsi
+	loginState, err := auth.NewLoginState(backTo)
+	if err != nil {
+		utils.ErrorHandler(c, http.StatusInternalServerError, "Gagal memulai login")
+		return
+	}
+	encoded, err := loginState.Encode()
+	if err != nil {
+		utils.ErrorHandler(c, http.StatusInternalServerError, "Gagal memulai login")
+		return
+	}
+	setLoginStateCookie(c, loginState.CookieName(), encoded, int(auth.LoginStateTTL().Seconds()))
+
+	authCodeURL := auth.OAuth2Config.AuthCodeURL(loginState.State,
+		oauth2.AccessTypeOffline,
+		oidc.Nonce(loginState.Nonce),
+		oauth2.S256ChallengeOption(loginState.Verifier),
+	)
 	c.Redirect(http.StatusTemporaryRedirect, authCodeURL)
 }
 
@@ -28,36 +52,127 @@ func SsoLogoutHandler(c *gin.Context) {
 	clientID := os.Getenv("OIDC_CLIENT_ID")
 
 	logoutURL := auth.GetLogoutURL(redirectURI, clientID)
+	if logoutURL == "" {
+		logoutURL = redirectURI
+	}
 
 	// Hapus session lokal (jika pakai cookie/token)
+	revokeCurrentSession(c)
+	clearAuthCookies(c)
 
 	// Redirect ke SSO logout endpoint
 	c.Redirect(http.StatusTemporaryRedirect, logoutURL)
 }
 
-func LoginHandler(c *gin.Context) {
-	// Login dengan email/password tidak didukung - gunakan SSO login
-	c.JSON(http.StatusNotFound, gin.H{"error": "Login dengan email/password tidak didukung, gunakan SSO login"})
+// LogoutHandler POST /logout
+// Untuk SPA yang menyimpan token sendiri: cabut sesi dari header Authorization lalu lanjut ke /sso-logout
+func LogoutHandler(c *gin.Context) {
+	if middleware.ExtractAccessToken(c) == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Missing or invalid Authorization header"})
+		return
+	}
+	revokeCurrentSession(c)
+	clearAuthCookies(c)
+	c.JSON(http.StatusOK, gin.H{"message": "Logout berhasil"})
 }
 
+// RefreshHandler POST /refresh
+// Refresh token dari body {"refresh_token": "..."} atau cookie HttpOnly refresh_token.
+// Refresh token dirotasi setiap kali dipakai; token lama yang dipakai lagi mencabut seluruh sesi.
 func RefreshHandler(c *gin.Context) {
+	var req struct {
+		RefreshToken string `json:"refresh_token"`
+	}
+	_ = c.ShouldBindJSON(&req)
+
+	fromCookie := false
+	if req.RefreshToken == "" {
+		if cookie, err := c.Cookie(refreshTokenCookie); err == nil && cookie != "" {
+			req.RefreshToken = cookie
+			fromCookie = true
+		}
+	}
+	if req.RefreshToken == "" {
+		utils.ErrorHandler(c, http.StatusBadRequest, "refresh_token wajib diisi")
+		return
+	}
 
+	token, session, err := sessionService(c).Refresh(req.RefreshToken)
+	if err != nil {
+		if !errors.Is(err, services.ErrSessionNotFound) && !errors.Is(err, services.ErrSessionRevoked) &&
+			!errors.Is(err, services.ErrRefreshReuse) && !errors.Is(err, services.ErrRefreshExpired) {
+			utils.LogCtx(c.Request.Context()).Errorw("Refresh token gagal", "error", err)
+		}
+		if fromCookie {
+			clearAuthCookies(c)
+		}
+		utils.ErrorHandler(c, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	// Mode cookie: token baru hanya lewat cookie HttpOnly, CSRF token ikut dirotasi
+	mode := "token"
+	if fromCookie {
+		mode = "cookie"
+	}
+	respondWithSession(c, mode, token.AccessToken, token.RefreshToken, session.ExpiresAt, session.RefreshExpiresAt)
+}
+
+// sessionService UserTokenService dengan info klien (UA, IP, fingerprint) dari request
+func sessionService(c *gin.Context) *services.UserTokenService {
+	userAgent := c.Request.UserAgent()
+	c.Set("user_agent", userAgent)
+	c.Set("ip_address", c.ClientIP())
+	c.Set("fingerprint", utils.HashToken(userAgent+"|"+c.GetHeader("Accept-Language")))
+	return services.NewUserTokenService(database.DBPNBP, c)
+}
+
+func revokeCurrentSession(c *gin.Context) {
+	accessToken := middleware.ExtractAccessToken(c)
+	if accessToken == "" {
+		return
+	}
+	if err := sessionService(c).RevokeAccessToken(accessToken, models.RevokeReasonLogout); err != nil {
+		utils.LogCtx(c.Request.Context()).Errorw("Gagal mencabut sesi saat logout", "error", err)
+	}
 }
 
 func CallbackHandler(c *gin.Context) {
 	ctx := context.Background()
 	code := c.Query("code")
+	state := c.Query("state")
 
-	_, err := utils.DecodeBackState(c.Query("state"))
+	if providerErr := c.Query("error"); providerErr != "" {
+		utils.LogCtx(c.Request.Context()).Warnw("Login SSO ditolak provider",
+			"error", providerErr,
+			"description", c.Query("error_description"),
+		)
+		utils.ErrorHandler(c, http.StatusUnauthorized, "Login SSO dibatalkan atau ditolak")
+		return
+	}
 
 	if code == "" {
 		utils.ErrorHandler(c, http.StatusBadRequest, "No code provided")
 		return
 	}
 
-	token, err := auth.OAuth2Config.Exchange(ctx, code)
+	// State wajib cocok dengan cookie dari browser yang memulai login (cegah login CSRF)
+	cookieName := auth.LoginStateCookieName(state)
+	encodedState, _ := c.Cookie(cookieName)
+	loginState, err := auth.DecodeLoginState(encodedState, state)
+	setLoginStateCookie(c, cookieName, "", -1)
 	if err != nil {
-		log.Println("❌ Token exchange failed:", err)
+		utils.LogCtx(c.Request.Context()).Warnw("State login tidak valid",
+			"error", err,
+			"ip", c.ClientIP(),
+		)
+		utils.ErrorHandler(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	token, err := auth.OAuth2Config.Exchange(ctx, code, oauth2.VerifierOption(loginState.Verifier))
+	if err != nil {
+		utils.LogCtx(c.Request.Context()).Warnw("❌ Token exchange failed", "error", err)
 		utils.ErrorHandler(c, http.StatusUnauthorized, "Token exchange failed")
 		return
 	}
@@ -69,11 +184,16 @@ func CallbackHandler(c *gin.Context) {
 	}
 
 	idToken, err := auth.Verifier.Verify(ctx, rawIDToken)
-	//utils.Log.Info("Verify token email : ", idToken)
+	//utils.LogCtx(c.Request.Context()).Info("Verify token email : ", idToken)
 	if err != nil {
 		utils.ErrorHandler(c, http.StatusUnauthorized, "Invalid ID Token")
 		return
 	}
+	if subtle.ConstantTimeCompare([]byte(idToken.Nonce), []byte(loginState.Nonce)) != 1 {
+		utils.LogCtx(c.Request.Context()).Warnw("Nonce ID token tidak cocok", "ip", c.ClientIP())
+		utils.ErrorHandler(c, http.StatusUnauthorized, "Invalid ID Token nonce")
+		return
+	}
 
 	// Ambil klaim dari token
 	var claims struct {
@@ -95,13 +215,57 @@ func CallbackHandler(c *gin.Context) {
 	// Tidak perlu create/update user - hanya cek email suffix
 	// Data mahasiswa langsung dari mahasiswa_masters (read-only)
 	studentID := utils.GetEmailPrefix(claims.Email)
-	utils.Log.Info(fmt.Sprintf("Login berhasil untuk studentID: %s, email: %s", studentID, claims.Email))
+	utils.LogCtx(c.Request.Context()).Infow("Login SSO berhasil", "npm", studentID)
+
+	// User lokal (staf) yang dinonaktifkan tidak boleh login; mahasiswa tidak ada di tabel users
+	userID := uuid.Nil
+	userRepo := repositories.UserRepository{DB: database.DBPNBP}
+	if user, err := userRepo.FindByEmail(claims.Email); err == nil && user.ID != uuid.Nil {
+		if !user.IsActive {
+			utils.ErrorHandler(c, http.StatusForbidden, "Akun tidak aktif")
+			return
+		}
+		userID = user.ID
+	}
+
+	// Simpan sesi agar bisa di-refresh dan dicabut dari server
+	session, err := sessionService(c).CreateSession(claims.Sub, claims.Email, userID, token)
+	if err != nil {
+		utils.LogCtx(c.Request.Context()).Errorw("Gagal menyimpan sesi login",
+			"email", claims.Email,
+			"error", err,
+		)
+		utils.ErrorHandler(c, http.StatusInternalServerError, "Gagal menyimpan sesi login")
+		return
+	}
+
+	// Token tidak pernah masuk URL: frontend menerima kode sekali pakai dan menukarnya via POST /auth/exchange
+	loginCode, err := services.NewAuthCodeService(database.DBPNBP).Issue(session, token)
+	if err != nil {
+		utils.LogCtx(c.Request.Context()).Errorw("Gagal membuat kode login",
+			"email", claims.Email,
+			"error", err,
+		)
+		utils.ErrorHandler(c, http.StatusInternalServerError, "Gagal membuat kode login")
+		return
+	}
 
 	frontendUrl := os.Getenv("FRONTEND_URL")
-	accessToken := token.AccessToken
+	redirectURL, err := url.Parse(frontendUrl)
+	if err != nil {
+		utils.ErrorHandler(c, http.StatusInternalServerError, "FRONTEND_URL tidak valid")
+		return
+	}
+	query := redirectURL.Query()
+	query.Set("code", loginCode)
+	if loginState.BackTo != "" {
+		// Sudah divalidasi allow-list saat /sso-login; frontend melanjutkan ke sini setelah /auth/exchange
+		query.Set("backTo", loginState.BackTo)
+	}
+	redirectURL.RawQuery = query.Encode()
 
-	utils.Log.Info("Redirect URL:", frontendUrl+"?token="+accessToken)
-	c.Redirect(http.StatusFound, frontendUrl+"?token="+accessToken)
+	c.Header("Referrer-Policy", "no-referrer")
+	c.Redirect(http.StatusFound, redirectURL.String())
 
 	// Respon sukses
 	//utils.ErrorHandler(c, http.StatusOK, gin.H{
@@ -112,3 +276,29 @@ func CallbackHandler(c *gin.Context) {
 	//	"expires_at":    token.Expiry,
 	//})
 }
+
+// ExchangeAuthCodeHandler POST /auth/exchange
+// Body: {"code": "...", "mode": "cookie" | "token"}. Mode cookie (default) memasang access/refresh token
+// sebagai cookie HttpOnly dan mengembalikan csrf_token yang wajib dikirim di header X-CSRF-Token
+// untuk request POST/PUT/DELETE. Mode token mengembalikan token di body (untuk klien non-browser).
+func ExchangeAuthCodeHandler(c *gin.Context) {
+	var req struct {
+		Code string `json:"code" binding:"required"`
+		Mode string `json:"mode"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorHandler(c, http.StatusBadRequest, "code wajib diisi")
+		return
+	}
+
+	grant, err := services.NewAuthCodeService(database.DBPNBP).Exchange(req.Code)
+	if err != nil {
+		if !errors.Is(err, services.ErrAuthCodeInvalid) && !errors.Is(err, services.ErrAuthCodeReused) {
+			utils.LogCtx(c.Request.Context()).Errorw("Penukaran kode login gagal", "error", err)
+		}
+		utils.ErrorHandler(c, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	respondWithSession(c, req.Mode, grant.AccessToken, grant.RefreshToken, grant.ExpiresAt, grant.RefreshExpiresAt)
+}
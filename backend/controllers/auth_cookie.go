@@ -0,0 +1,119 @@
+package controllers
+
+import (
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/dedegunawan/backend-ujian-telp-v5/config"
+	"github.com/dedegunawan/backend-ujian-telp-v5/middleware"
+	"github.com/dedegunawan/backend-ujian-telp-v5/utils"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	accessTokenCookie  = "access_token"
+	refreshTokenCookie = "refresh_token"
+)
+
+// cookieSecure default Secure; COOKIE_SECURE=false hanya untuk development lewat http
+func cookieSecure() bool {
+	return os.Getenv("COOKIE_SECURE") != "false"
+}
+
+// cookieSameSite dari COOKIE_SAMESITE (lax/strict/none), default lax.
+// Gunakan none jika frontend dan API berbeda site (wajib Secure).
+func cookieSameSite() http.SameSite {
+	switch strings.ToLower(os.Getenv("COOKIE_SAMESITE")) {
+	case "strict":
+		return http.SameSiteStrictMode
+	case "none":
+		return http.SameSiteNoneMode
+	default:
+		return http.SameSiteLaxMode
+	}
+}
+
+func setCookie(c *gin.Context, name, value string, maxAge int, httpOnly bool) {
+	c.SetSameSite(cookieSameSite())
+	c.SetCookie(name, value, maxAge, "/", os.Getenv("COOKIE_DOMAIN"), cookieSecure(), httpOnly)
+}
+
+func secondsUntil(t time.Time) int {
+	seconds := int(time.Until(t).Seconds())
+	if seconds < 1 {
+		return 1
+	}
+	return seconds
+}
+
+func setAccessCookie(c *gin.Context, accessToken string, expiresAt time.Time) {
+	setCookie(c, accessTokenCookie, accessToken, secondsUntil(expiresAt), true)
+}
+
+func setRefreshCookie(c *gin.Context, refreshToken string, expiresAt *time.Time) {
+	if refreshToken == "" {
+		return
+	}
+	refreshExpiresAt := config.GetDefaultRefreshTokenExpired()
+	if expiresAt != nil {
+		refreshExpiresAt = *expiresAt
+	}
+	setCookie(c, refreshTokenCookie, refreshToken, secondsUntil(refreshExpiresAt), true)
+}
+
+// setCSRFCookie membuat token CSRF baru; sengaja tidak HttpOnly agar frontend bisa mengirimnya di header
+func setCSRFCookie(c *gin.Context, expiresAt *time.Time) (string, error) {
+	csrfToken, err := utils.RandomToken(32)
+	if err != nil {
+		return "", err
+	}
+	refreshExpiresAt := config.GetDefaultRefreshTokenExpired()
+	if expiresAt != nil {
+		refreshExpiresAt = *expiresAt
+	}
+	setCookie(c, middleware.CSRFCookieName, csrfToken, secondsUntil(refreshExpiresAt), false)
+	return csrfToken, nil
+}
+
+// setLoginStateCookie cookie state OIDC; minimal Lax karena callback datang dari redirect lintas site (IdP)
+func setLoginStateCookie(c *gin.Context, name, value string, maxAge int) {
+	sameSite := http.SameSiteLaxMode
+	if cookieSameSite() == http.SameSiteNoneMode {
+		sameSite = http.SameSiteNoneMode
+	}
+	c.SetSameSite(sameSite)
+	c.SetCookie(name, value, maxAge, "/", os.Getenv("COOKIE_DOMAIN"), cookieSecure(), true)
+}
+
+func clearAuthCookies(c *gin.Context) {
+	setCookie(c, refreshTokenCookie, "", -1, true)
+	setCookie(c, accessTokenCookie, "", -1, true)
+	setCookie(c, middleware.CSRFCookieName, "", -1, false)
+}
+
+// respondWithSession mode "token" mengembalikan token di body (klien non-browser); default memasang
+// cookie HttpOnly dan mengembalikan csrf_token untuk header X-CSRF-Token
+func respondWithSession(c *gin.Context, mode, accessToken, refreshToken string, expiresAt time.Time, refreshExpiresAt *time.Time) {
+	c.Header("Cache-Control", "no-store")
+	if mode == "token" {
+		c.JSON(http.StatusOK, gin.H{
+			"access_token":       accessToken,
+			"token_type":         "Bearer",
+			"expires_at":         expiresAt,
+			"refresh_token":      refreshToken,
+			"refresh_expires_at": refreshExpiresAt,
+		})
+		return
+	}
+
+	setAccessCookie(c, accessToken, expiresAt)
+	setRefreshCookie(c, refreshToken, refreshExpiresAt)
+	csrfToken, err := setCSRFCookie(c, refreshExpiresAt)
+	if err != nil {
+		utils.ErrorHandler(c, http.StatusInternalServerError, "Gagal membuat CSRF token")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"expires_at": expiresAt, "csrf_token": csrfToken})
+}
@@ -0,0 +1,130 @@
+package controllers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/dedegunawan/backend-ujian-telp-v5/database"
+	"github.com/dedegunawan/backend-ujian-telp-v5/services"
+	"github.com/dedegunawan/backend-ujian-telp-v5/utils"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+const maxBankStatementSize = 20 << 20 // 20 MB
+
+// ImportBankStatement POST /api/v1/finance/bank-statements
+// Form: file (MT940 atau CSV), format (opsional: mt940|csv, default deteksi otomatis)
+func ImportBankStatement(c *gin.Context) {
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "File mutasi wajib diunggah"})
+		return
+	}
+	if fileHeader.Size > maxBankStatementSize {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Ukuran file maksimal 20 MB"})
+		return
+	}
+
+	format := c.PostForm("format")
+	if format != "" && format != "mt940" && format != "csv" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "format harus mt940 atau csv"})
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Gagal membaca file"})
+		return
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Gagal membaca file"})
+		return
+	}
+
+	report, err := services.NewBankStatementService(database.DBPNBP).Import(fileHeader.Filename, format, data, c.GetString("email"))
+	if err != nil {
+		utils.LogCtx(c.Request.Context()).Errorw("ImportBankStatement: import gagal",
+			"filename", fileHeader.Filename,
+			"error", err,
+		)
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// GetBankStatementImports GET /api/v1/finance/bank-statements
+func GetBankStatementImports(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if limit < 1 || limit > 200 {
+		limit = 50
+	}
+
+	imports, err := services.NewBankStatementService(database.DBPNBP).List(limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengambil daftar import"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"imports": imports})
+}
+
+// GetBankStatementReport GET /api/v1/finance/bank-statements/:id
+// Query params: status (matched|proposed|amount_mismatch|unmatched|duplicate)
+func GetBankStatementReport(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID import tidak valid"})
+		return
+	}
+
+	report, err := services.NewBankStatementService(database.DBPNBP).Report(uint(id), c.Query("status"))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Import tidak ditemukan"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengambil laporan pencocokan"})
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// DecideBankStatementProposal POST /api/v1/finance/bank-statements/lines/:id/decision
+// Body: {"confirm": true|false, "note": "..."}
+func DecideBankStatementProposal(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID baris tidak valid"})
+		return
+	}
+
+	var req struct {
+		Confirm *bool  `json:"confirm" binding:"required"`
+		Note    string `json:"note"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "confirm wajib diisi"})
+		return
+	}
+
+	line, err := services.NewBankStatementService(database.DBPNBP).DecideProposal(uint(id), *req.Confirm, c.GetString("email"), req.Note)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Baris mutasi tidak ditemukan"})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, line)
+}
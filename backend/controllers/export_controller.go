@@ -0,0 +1,95 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/dedegunawan/backend-ujian-telp-v5/database"
+	"github.com/dedegunawan/backend-ujian-telp-v5/models"
+	"github.com/dedegunawan/backend-ujian-telp-v5/services"
+	"github.com/dedegunawan/backend-ujian-telp-v5/utils"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+func enqueueExport(c *gin.Context, exportType string, filters interface{}) {
+	userID := c.GetString("sso_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User tidak dikenali"})
+		return
+	}
+
+	format := c.DefaultQuery("format", "xlsx")
+	job, err := services.NewExportService(database.DBPNBP).Enqueue(userID, c.GetString("email"), exportType, format, filters)
+	if err != nil {
+		utils.LogCtx(c.Request.Context()).Errorw("enqueueExport: gagal membuat export",
+			"type", exportType,
+			"error", err,
+		)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, job)
+}
+
+// ExportStudentBills POST /api/v1/exports/student-bills
+// Filter sama dengan GET /student-bills (student_id, academic_year, status, search), format=xlsx|csv
+func ExportStudentBills(c *gin.Context) {
+	enqueueExport(c, models.ExportTypeStudentBills, models.StudentBillFilter{
+		StudentID:    c.Query("student_id"),
+		AcademicYear: c.Query("academic_year"),
+		Status:       c.Query("status"),
+		Search:       c.Query("search"),
+	})
+}
+
+// ExportPaymentStatus POST /api/v1/exports/payment-status
+// Filter sama dengan GET /payment-status (student_id, academic_year, status), format=xlsx|csv
+func ExportPaymentStatus(c *gin.Context) {
+	enqueueExport(c, models.ExportTypePaymentStatus, models.PaymentStatusFilter{
+		StudentID:    c.Query("student_id"),
+		AcademicYear: c.Query("academic_year"),
+		Status:       c.Query("status"),
+	})
+}
+
+// GetExportHistory GET /api/v1/exports
+// Riwayat export milik user yang login
+func GetExportHistory(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	jobs, err := services.NewExportService(database.DBPNBP).History(c.GetString("sso_id"), limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengambil riwayat export"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"exports": jobs})
+}
+
+// GetExport GET /api/v1/exports/:id
+// Progress export; jika sudah selesai berisi signed URL yang berlaku sementara
+func GetExport(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID export tidak valid"})
+		return
+	}
+
+	job, err := services.NewExportService(database.DBPNBP).Get(c.GetString("sso_id"), uint(id))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Export tidak ditemukan"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengambil export"})
+		return
+	}
+
+	c.JSON(http.StatusOK, job)
+}
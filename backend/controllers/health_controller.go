@@ -0,0 +1,23 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/dedegunawan/backend-ujian-telp-v5/services"
+	"github.com/gin-gonic/gin"
+)
+
+// Healthz liveness: proses hidup dan bisa melayani HTTP, tanpa cek dependency
+func Healthz(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"status": services.HealthStatusOK})
+}
+
+// Readyz readiness: 503 jika dependency wajib (PNBP MySQL, OIDC) gagal atau sedang shutdown
+func Readyz(c *gin.Context) {
+	report := services.DefaultHealthService().Readiness(c.Request.Context())
+	status := http.StatusOK
+	if !report.Ready() {
+		status = http.StatusServiceUnavailable
+	}
+	c.JSON(status, report)
+}
@@ -0,0 +1,43 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/dedegunawan/backend-ujian-telp-v5/database"
+	"github.com/dedegunawan/backend-ujian-telp-v5/middleware"
+	"github.com/dedegunawan/backend-ujian-telp-v5/models"
+	"github.com/dedegunawan/backend-ujian-telp-v5/services"
+	"github.com/gin-gonic/gin"
+)
+
+// impersonationBanner nil jika request bukan impersonasi
+func impersonationBanner(c *gin.Context) *models.ImpersonationBanner {
+	npm := c.GetString(middleware.ImpersonatedNPMKey)
+	if npm == "" {
+		return nil
+	}
+	return &models.ImpersonationBanner{
+		Active:     true,
+		ReadOnly:   true,
+		StudentID:  npm,
+		StaffEmail: c.GetString(middleware.ImpersonatorEmailKey),
+	}
+}
+
+// GetImpersonationLogs GET /api/v1/impersonation-logs
+// Query params: staff_email, npm, limit
+func GetImpersonationLogs(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "100"))
+	if limit < 1 || limit > 500 {
+		limit = 100
+	}
+
+	rows, err := services.NewImpersonationService(database.DBPNBP).List(c.Query("staff_email"), c.Query("npm"), limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengambil log impersonasi"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"logs": rows})
+}
@@ -101,7 +101,7 @@ func GenerateInvoicePDF(c *gin.Context) {
 	tagihanRepo := repositories.NewTagihanRepository(database.DBPNBP, database.DBPNBP)
 	activeYear, err := tagihanRepo.GetActiveFinanceYear()
 	if err != nil {
-		utils.Log.Error("Gagal mengambil finance year aktif", "error", err.Error())
+		utils.LogCtx(c.Request.Context()).Errorw("Gagal mengambil finance year aktif", "error", err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Tahun aktif tidak ditemukan"})
 		return
 	}
@@ -114,7 +114,7 @@ func GenerateInvoicePDF(c *gin.Context) {
 	tagihanNewService := services.NewTagihanNewService(*tagihanRepo)
 	historyList, err := tagihanNewService.GetHistoryTagihanMahasiswa(dummyMahasiswa, activeYear)
 	if err != nil {
-		utils.Log.Error("Gagal mengambil riwayat pembayaran", "error", err.Error())
+		utils.LogCtx(c.Request.Context()).Errorw("Gagal mengambil riwayat pembayaran", "error", err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengambil data pembayaran"})
 		return
 	}
@@ -411,7 +411,7 @@ func GenerateInvoicePDF(c *gin.Context) {
 	// Output PDF
 	err = pdf.Output(c.Writer)
 	if err != nil {
-		utils.Log.Error("Gagal generate PDF", "error", err.Error())
+		utils.LogCtx(c.Request.Context()).Errorw("Gagal generate PDF", "error", err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal generate PDF"})
 		return
 	}
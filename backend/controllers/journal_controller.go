@@ -0,0 +1,122 @@
+package controllers
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/dedegunawan/backend-ujian-telp-v5/database"
+	"github.com/dedegunawan/backend-ujian-telp-v5/models"
+	"github.com/dedegunawan/backend-ujian-telp-v5/services"
+	"github.com/dedegunawan/backend-ujian-telp-v5/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// journalRangeFromQuery from & to wajib (YYYY-MM-DD), to inklusif
+func journalRangeFromQuery(c *gin.Context) (time.Time, time.Time, bool) {
+	from, err := time.ParseInLocation("2006-01-02", c.Query("from"), time.Local)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Format from harus YYYY-MM-DD"})
+		return time.Time{}, time.Time{}, false
+	}
+	to, err := time.ParseInLocation("2006-01-02", c.Query("to"), time.Local)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Format to harus YYYY-MM-DD"})
+		return time.Time{}, time.Time{}, false
+	}
+	if to.Before(from) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "to tidak boleh sebelum from"})
+		return time.Time{}, time.Time{}, false
+	}
+	return from, to.AddDate(0, 0, 1), true
+}
+
+func buildJournal(c *gin.Context) (services.JournalService, *models.JournalBatch, bool) {
+	from, to, ok := journalRangeFromQuery(c)
+	if !ok {
+		return nil, nil, false
+	}
+
+	service := services.NewJournalService(database.DBPNBP)
+	batch, err := service.Build(from, to)
+	if err != nil {
+		utils.LogCtx(c.Request.Context()).Errorw("buildJournal: gagal menyusun jurnal",
+			"from", from.Format("2006-01-02"),
+			"to", to.Format("2006-01-02"),
+			"error", err,
+		)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal menyusun jurnal"})
+		return nil, nil, false
+	}
+	return service, batch, true
+}
+
+// GetJournal GET /api/v1/finance/journal?from=YYYY-MM-DD&to=YYYY-MM-DD
+// Preview jurnal beserta hasil validasi keseimbangan
+func GetJournal(c *gin.Context) {
+	_, batch, ok := buildJournal(c)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, batch)
+}
+
+// GetJournalAccounts GET /api/v1/finance/journal/accounts
+// Pemetaan akun yang sedang dipakai
+func GetJournalAccounts(c *gin.Context) {
+	c.JSON(http.StatusOK, services.LoadJournalAccountMap())
+}
+
+// ExportJournal GET /api/v1/finance/journal/export?from=&to=&format=csv|xlsx
+// Hanya batch yang seimbang yang boleh diekspor
+func ExportJournal(c *gin.Context) {
+	format := c.DefaultQuery("format", "xlsx")
+	if format != "xlsx" && format != "csv" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "format harus xlsx atau csv"})
+		return
+	}
+
+	service, batch, ok := buildJournal(c)
+	if !ok {
+		return
+	}
+
+	if !batch.Balanced {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{
+			"error":  "Jurnal tidak seimbang, export dibatalkan",
+			"errors": batch.Errors,
+		})
+		return
+	}
+
+	var buffer *bytes.Buffer
+	var err error
+	contentType := "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	if format == "csv" {
+		buffer, err = service.WriteCSV(batch)
+		contentType = "text/csv"
+	} else {
+		buffer, err = service.WriteXLSX(batch)
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	filename := fmt.Sprintf("exports/jurnal_%s_%d.%s", batch.BatchNo, time.Now().Unix(), format)
+	url, err := utils.UploadObjectToMinio(filename, buffer.Bytes(), contentType)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"url":          url,
+		"batch_no":     batch.BatchNo,
+		"entries":      len(batch.Entries),
+		"total_debit":  batch.TotalDebit,
+		"total_credit": batch.TotalCredit,
+	})
+}
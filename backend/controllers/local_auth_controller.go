@@ -0,0 +1,260 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/dedegunawan/backend-ujian-telp-v5/database"
+	"github.com/dedegunawan/backend-ujian-telp-v5/models"
+	"github.com/dedegunawan/backend-ujian-telp-v5/repositories"
+	"github.com/dedegunawan/backend-ujian-telp-v5/services"
+	"github.com/dedegunawan/backend-ujian-telp-v5/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// LoginHandler POST /login
+// Login email/password untuk akun staf tanpa SSO. Body: {"email", "password", "mode"}.
+// Jika 2FA aktif, respon {"mfa_required": true, "mfa_token"} dilanjutkan ke POST /login/2fa.
+func LoginHandler(c *gin.Context) {
+	var req struct {
+		Email    string `json:"email" binding:"required"`
+		Password string `json:"password" binding:"required"`
+		Mode     string `json:"mode"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorHandler(c, http.StatusBadRequest, "email dan password wajib diisi")
+		return
+	}
+
+	result, err := services.NewLocalAuthService(database.DBPNBP).Login(req.Email, req.Password)
+	if err != nil {
+		respondLocalAuthError(c, err)
+		return
+	}
+	if result.MFARequired {
+		c.JSON(http.StatusOK, gin.H{"mfa_required": true, "mfa_token": result.MFAToken})
+		return
+	}
+
+	issueLocalSession(c, result.User, req.Mode)
+}
+
+// LoginSecondFactorHandler POST /login/2fa
+// Body: {"mfa_token", "code"} atau {"mfa_token", "recovery_code"}, opsional "mode"
+func LoginSecondFactorHandler(c *gin.Context) {
+	var req struct {
+		MFAToken     string `json:"mfa_token" binding:"required"`
+		Code         string `json:"code"`
+		RecoveryCode string `json:"recovery_code"`
+		Mode         string `json:"mode"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil || (req.Code == "" && req.RecoveryCode == "") {
+		utils.ErrorHandler(c, http.StatusBadRequest, "mfa_token dan code/recovery_code wajib diisi")
+		return
+	}
+
+	user, err := services.NewLocalAuthService(database.DBPNBP).VerifySecondFactor(req.MFAToken, req.Code, req.RecoveryCode)
+	if err != nil {
+		respondLocalAuthError(c, err)
+		return
+	}
+
+	issueLocalSession(c, user, req.Mode)
+}
+
+// ForgotPasswordHandler POST /password/forgot
+// Selalu 200 agar tidak bisa dipakai mengecek email terdaftar
+func ForgotPasswordHandler(c *gin.Context) {
+	var req struct {
+		Email string `json:"email" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorHandler(c, http.StatusBadRequest, "email wajib diisi")
+		return
+	}
+
+	if err := services.NewLocalAuthService(database.DBPNBP).RequestPasswordReset(req.Email); err != nil {
+		utils.LogCtx(c.Request.Context()).Errorw("Gagal mengirim link reset password", "error", err)
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Jika email terdaftar, link reset password telah dikirim"})
+}
+
+// ResetPasswordHandler POST /password/reset
+// Body: {"token", "password", "password_confirmation"}
+func ResetPasswordHandler(c *gin.Context) {
+	var req struct {
+		Token                string `json:"token" binding:"required"`
+		Password             string `json:"password" binding:"required"`
+		PasswordConfirmation string `json:"password_confirmation" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorHandler(c, http.StatusBadRequest, "token, password dan password_confirmation wajib diisi")
+		return
+	}
+	if req.Password != req.PasswordConfirmation {
+		utils.ErrorHandler(c, http.StatusBadRequest, "Konfirmasi password tidak sama")
+		return
+	}
+
+	if err := services.NewLocalAuthService(database.DBPNBP).ResetPassword(req.Token, req.Password); err != nil {
+		respondLocalAuthError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Password berhasil diganti, silakan login ulang"})
+}
+
+// TwoFactorStatusHandler GET /auth/2fa
+func TwoFactorStatusHandler(c *gin.Context) {
+	user, ok := currentLocalUser(c)
+	if !ok {
+		return
+	}
+	enabled, remaining, err := services.NewLocalAuthService(database.DBPNBP).TOTPStatus(user)
+	if err != nil {
+		respondLocalAuthError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"enabled": enabled, "recovery_codes_remaining": remaining})
+}
+
+// TwoFactorSetupHandler POST /auth/2fa/setup
+// Mengembalikan secret + otpauth URL (QR); 2FA baru aktif setelah POST /auth/2fa/enable
+func TwoFactorSetupHandler(c *gin.Context) {
+	user, ok := currentLocalUser(c)
+	if !ok {
+		return
+	}
+	enrollment, err := services.NewLocalAuthService(database.DBPNBP).BeginTOTPEnrollment(user)
+	if err != nil {
+		respondLocalAuthError(c, err)
+		return
+	}
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, enrollment)
+}
+
+// TwoFactorEnableHandler POST /auth/2fa/enable
+// Body: {"code"}. Recovery code hanya ditampilkan sekali di respon ini.
+func TwoFactorEnableHandler(c *gin.Context) {
+	user, ok := currentLocalUser(c)
+	if !ok {
+		return
+	}
+	var req struct {
+		Code string `json:"code" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorHandler(c, http.StatusBadRequest, "code wajib diisi")
+		return
+	}
+
+	codes, err := services.NewLocalAuthService(database.DBPNBP).EnableTOTP(user, req.Code)
+	if err != nil {
+		respondLocalAuthError(c, err)
+		return
+	}
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, gin.H{"enabled": true, "recovery_codes": codes})
+}
+
+// TwoFactorDisableHandler POST /auth/2fa/disable
+// Body: {"password", "code"}
+func TwoFactorDisableHandler(c *gin.Context) {
+	user, ok := currentLocalUser(c)
+	if !ok {
+		return
+	}
+	var req struct {
+		Password string `json:"password" binding:"required"`
+		Code     string `json:"code" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorHandler(c, http.StatusBadRequest, "password dan code wajib diisi")
+		return
+	}
+
+	if err := services.NewLocalAuthService(database.DBPNBP).DisableTOTP(user, req.Password, req.Code); err != nil {
+		respondLocalAuthError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"enabled": false})
+}
+
+// TwoFactorRecoveryCodesHandler POST /auth/2fa/recovery-codes
+// Body: {"code"}. Membuat ulang recovery code; kode lama tidak berlaku lagi.
+func TwoFactorRecoveryCodesHandler(c *gin.Context) {
+	user, ok := currentLocalUser(c)
+	if !ok {
+		return
+	}
+	var req struct {
+		Code string `json:"code" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorHandler(c, http.StatusBadRequest, "code wajib diisi")
+		return
+	}
+
+	codes, err := services.NewLocalAuthService(database.DBPNBP).RegenerateRecoveryCodes(user, req.Code)
+	if err != nil {
+		respondLocalAuthError(c, err)
+		return
+	}
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, gin.H{"recovery_codes": codes})
+}
+
+// issueLocalSession menerbitkan JWT internal + refresh token (sesi tersimpan seperti login SSO)
+func issueLocalSession(c *gin.Context, user *models.User, mode string) {
+	token, session, err := sessionService(c).CreateLocalSession(user)
+	if err != nil {
+		utils.LogCtx(c.Request.Context()).Errorw("Gagal menyimpan sesi login lokal",
+			"user_id", user.ID.String(),
+			"error", err,
+		)
+		utils.ErrorHandler(c, http.StatusInternalServerError, "Gagal menyimpan sesi login")
+		return
+	}
+	utils.LogCtx(c.Request.Context()).Infow("Login lokal berhasil", "user_id", user.ID.String())
+	respondWithSession(c, mode, token.AccessToken, token.RefreshToken, session.ExpiresAt, session.RefreshExpiresAt)
+}
+
+// currentLocalUser user lokal dari token; 2FA hanya untuk akun yang login dengan password
+func currentLocalUser(c *gin.Context) (*models.User, bool) {
+	userRepo := repositories.UserRepository{DB: database.DBPNBP}
+	user, err := userRepo.FindByEmail(c.GetString("email"))
+	if err != nil || !user.IsActive {
+		utils.ErrorHandler(c, http.StatusForbidden, "Akun lokal tidak ditemukan")
+		return nil, false
+	}
+	if user.Password == nil || *user.Password == "" {
+		respondLocalAuthError(c, services.ErrLocalAccountRequired)
+		return nil, false
+	}
+	return user, true
+}
+
+func respondLocalAuthError(c *gin.Context, err error) {
+	var lockedErr *services.AccountLockedError
+	switch {
+	case errors.As(err, &lockedErr):
+		c.Header("Retry-After", strconv.Itoa(lockedErr.RetryAfter()))
+		c.JSON(http.StatusLocked, gin.H{"error": lockedErr.Error(), "locked_until": lockedErr.Until})
+	case errors.Is(err, services.ErrInvalidCredentials),
+		errors.Is(err, services.ErrMFAInvalid),
+		errors.Is(err, services.ErrMFAChallengeInvalid):
+		utils.ErrorHandler(c, http.StatusUnauthorized, err.Error())
+	case errors.Is(err, services.ErrTOTPAlreadyEnabled),
+		errors.Is(err, services.ErrTOTPNotEnabled),
+		errors.Is(err, services.ErrTOTPSetupRequired),
+		errors.Is(err, services.ErrPasswordResetInvalid),
+		errors.Is(err, services.ErrPasswordTooWeak):
+		utils.ErrorHandler(c, http.StatusBadRequest, err.Error())
+	case errors.Is(err, services.ErrLocalAccountRequired), errors.Is(err, services.ErrAccountInactive):
+		utils.ErrorHandler(c, http.StatusForbidden, err.Error())
+	default:
+		utils.LogCtx(c.Request.Context()).Errorw("Login lokal gagal", "error", err)
+		utils.ErrorHandler(c, http.StatusInternalServerError, "Terjadi kesalahan, coba lagi")
+	}
+}
@@ -0,0 +1,79 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/dedegunawan/backend-ujian-telp-v5/database"
+	"github.com/dedegunawan/backend-ujian-telp-v5/services"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// GetNotifications GET /api/v1/notifications
+// Query params: status, event, npm, limit
+func GetNotifications(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if limit < 1 || limit > 200 {
+		limit = 50
+	}
+
+	rows, err := services.NewNotificationService(database.DBPNBP).List(c.Query("status"), c.Query("event"), c.Query("npm"), limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengambil notifikasi"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"notifications": rows})
+}
+
+// RetryNotification POST /api/v1/notifications/:id/retry
+func RetryNotification(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID notifikasi tidak valid"})
+		return
+	}
+
+	row, err := services.NewNotificationService(database.DBPNBP).Retry(uint(id))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Notifikasi tidak ditemukan"})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, row)
+}
+
+// RunPaymentReminders POST /api/v1/notifications/reminders/run
+// Menjalankan job pengingat sekarang (tetap menghormati quiet hours & dedupe)
+func RunPaymentReminders(c *gin.Context) {
+	run, err := services.NewReminderService(database.DBPNBP).Run(time.Now())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error(), "run": run})
+		return
+	}
+
+	c.JSON(http.StatusOK, run)
+}
+
+// GetReminderRuns GET /api/v1/notifications/reminders/runs
+func GetReminderRuns(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if limit < 1 || limit > 200 {
+		limit = 20
+	}
+
+	runs, err := services.NewReminderService(database.DBPNBP).Runs(limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengambil riwayat pengingat"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"runs": runs})
+}
@@ -2,14 +2,19 @@ package controllers
 
 import (
 	"encoding/json"
+	"fmt"
+	"github.com/dedegunawan/backend-ujian-telp-v5/database"
+	"github.com/dedegunawan/backend-ujian-telp-v5/metrics"
+	"github.com/dedegunawan/backend-ujian-telp-v5/services"
 	"github.com/dedegunawan/backend-ujian-telp-v5/utils"
 	"github.com/gin-gonic/gin"
 	"io/ioutil"
 	"net/http"
+	"strconv"
 )
 
 func PaymentCallbackHandler(c *gin.Context) {
-	utils.Log.Info("PaymentCallbackHandler")
+	utils.LogCtx(c.Request.Context()).Info("PaymentCallbackHandler")
 	// === 1. Ambil Header ===
 	headers := make(map[string]string)
 	for k, v := range c.Request.Header {
@@ -29,6 +34,7 @@ func PaymentCallbackHandler(c *gin.Context) {
 	// === 3. Ambil Body ===
 	bodyBytes, err := ioutil.ReadAll(c.Request.Body)
 	if err != nil {
+		metrics.Callbacks.WithLabelValues("rejected", "unreadable_body").Inc()
 		c.JSON(http.StatusBadRequest, gin.H{"error": "cannot read body"})
 		return
 	}
@@ -46,6 +52,51 @@ func PaymentCallbackHandler(c *gin.Context) {
 
 	// Tidak menyimpan ke database - hanya consume data dari DBPNBP (read-only)
 
+	// === 5. Dorong status terbaru ke halaman mahasiswa (SSE) ===
+	invoiceID := callbackInvoiceID(bodyData, queryParams)
+	if invoiceID == 0 {
+		// Tetap dijawab ok agar provider tidak retry, tapi dicatat karena tidak bisa dicocokkan
+		metrics.Callbacks.WithLabelValues("ignored", "missing_invoice_id").Inc()
+	} else {
+		metrics.Callbacks.WithLabelValues("accepted", "").Inc()
+		key := fmt.Sprintf("callback:%d:%v", invoiceID, callbackField(bodyData, queryParams, "status"))
+		if err := services.NewPaymentEventService(database.DBPNBP).PublishInvoice(invoiceID, "callback", key); err != nil {
+			utils.LogCtx(c.Request.Context()).Warnw("PaymentCallbackHandler: gagal publish status pembayaran",
+				"invoice_id", invoiceID,
+				"error", err,
+			)
+		}
+	}
+
 	// === 7. Kirim response ke provider ===
 	c.JSON(http.StatusOK, responseData)
 }
+
+// callbackField ambil field dari body JSON (termasuk objek "data") atau query param
+func callbackField(bodyData interface{}, queryParams map[string]string, field string) interface{} {
+	if body, ok := bodyData.(map[string]interface{}); ok {
+		if v, ok := body[field]; ok && v != nil {
+			return v
+		}
+		if data, ok := body["data"].(map[string]interface{}); ok {
+			if v, ok := data[field]; ok && v != nil {
+				return v
+			}
+		}
+	}
+	if v, ok := queryParams[field]; ok {
+		return v
+	}
+	return ""
+}
+
+func callbackInvoiceID(bodyData interface{}, queryParams map[string]string) uint {
+	switch v := callbackField(bodyData, queryParams, "invoice_id").(type) {
+	case float64:
+		return uint(v)
+	case string:
+		id, _ := strconv.ParseUint(v, 10, 64)
+		return uint(id)
+	}
+	return 0
+}
@@ -26,7 +26,7 @@ func TriggerPaymentIdentifierWorker(c *gin.Context) {
 	// Run worker secara synchronous untuk testing
 	go func() {
 		if err := worker.CheckAndUpdatePaymentByIdentifier(); err != nil {
-			utils.Log.Errorf("Error in manual trigger: %v", err)
+			utils.LogCtx(c.Request.Context()).Errorf("Error in manual trigger: %v", err)
 		}
 	}()
 
@@ -8,6 +8,7 @@ import (
 
 	"github.com/dedegunawan/backend-ujian-telp-v5/database"
 	"github.com/dedegunawan/backend-ujian-telp-v5/models"
+	"github.com/dedegunawan/backend-ujian-telp-v5/services"
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
 )
@@ -106,17 +107,11 @@ func GetPaymentStatus(c *gin.Context) {
 	offset := (page - 1) * limit
 
 	// Query builder
-	query := database.DBPNBP.Model(&models.StudentBill{})
-
-	// Filter by student_id
-	if studentID != "" {
-		query = query.Where("student_id = ?", studentID)
-	}
-
-	// Filter by academic_year
-	if academicYear != "" {
-		query = query.Where("academic_year = ?", academicYear)
-	}
+	query := services.PaymentStatusBaseQuery(database.DBPNBP, models.PaymentStatusFilter{
+		StudentID:    studentID,
+		AcademicYear: academicYear,
+		Status:       status,
+	})
 
 	// Get total count
 	var totalCount int64
@@ -130,12 +125,7 @@ func GetPaymentStatus(c *gin.Context) {
 		Offset(offset)
 
 	// Filter by status
-	if status == "paid" {
-		query = query.Where("(quantity * amount) - paid_amount <= 0")
-	} else if status == "unpaid" {
-		query = query.Where("(quantity * amount) - paid_amount > 0")
-	}
-	// else "all" - no additional filter
+	query = services.ApplyPaymentStatus(query, status)
 
 	if err := query.Find(&bills).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengambil data pembayaran"})
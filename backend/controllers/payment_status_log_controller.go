@@ -81,7 +81,7 @@ func GetAllPaymentStatusLogs(c *gin.Context) {
 	// Get total count
 	var totalCount int64
 	if err := query.Count(&totalCount).Error; err != nil {
-		utils.Log.Error("GetAllPaymentStatusLogs: Failed to count logs", "error", err.Error())
+		utils.LogCtx(c.Request.Context()).Errorw("GetAllPaymentStatusLogs: Failed to count logs", "error", err)
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error": "Gagal menghitung total log",
 			"message": err.Error(),
@@ -96,7 +96,7 @@ func GetAllPaymentStatusLogs(c *gin.Context) {
 		Limit(limit).
 		Offset(offset).
 		Find(&logs).Error; err != nil {
-		utils.Log.Error("GetAllPaymentStatusLogs: Failed to fetch logs", "error", err.Error())
+		utils.LogCtx(c.Request.Context()).Errorw("GetAllPaymentStatusLogs: Failed to fetch logs", "error", err)
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error": "Gagal mengambil data log",
 			"message": err.Error(),
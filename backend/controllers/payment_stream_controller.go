@@ -0,0 +1,111 @@
+package controllers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/dedegunawan/backend-ujian-telp-v5/config"
+	"github.com/dedegunawan/backend-ujian-telp-v5/realtime"
+	"github.com/dedegunawan/backend-ujian-telp-v5/utils"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	defaultHeartbeatInterval = 20 * time.Second
+	sseRetryMillis           = 5000
+)
+
+// StreamPaymentStatus GET /api/v1/payment-status/stream
+// Server-Sent Events perubahan status pembayaran untuk NPM pemilik token.
+// EventSource tidak bisa mengirim header Authorization, jadi token dibaca dari cookie access_token.
+// Saat reconnect, browser mengirim header Last-Event-ID (atau query last_event_id) dan event yang terlewat dikirim ulang.
+func StreamPaymentStatus(c *gin.Context) {
+	mhswMaster, mustreturn := getMahasiswa(c)
+	if mustreturn {
+		return
+	}
+	npm := mhswMaster.StudentID
+
+	flusher, ok := c.Writer.(http.Flusher)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Streaming tidak didukung"})
+		return
+	}
+
+	lastEventID := c.GetHeader("Last-Event-ID")
+	if lastEventID == "" {
+		lastEventID = c.Query("last_event_id")
+	}
+	lastID, _ := strconv.ParseInt(lastEventID, 10, 64)
+
+	heartbeat := defaultHeartbeatInterval
+	if raw := config.GetEnv("REALTIME_HEARTBEAT_INTERVAL"); raw != "" {
+		if parsed, err := time.ParseDuration(raw); err == nil && parsed > 0 {
+			heartbeat = parsed
+		}
+	}
+
+	hub := realtime.Default()
+	// Subscribe dulu sebelum replay agar tidak ada event yang jatuh di antara keduanya
+	events, cancel := hub.Subscribe(npm)
+	defer cancel()
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no") // nginx: jangan buffer stream
+	c.Status(http.StatusOK)
+
+	fmt.Fprintf(c.Writer, "retry: %d\n: connected %s\n\n", sseRetryMillis, npm)
+	if lastID > 0 {
+		for _, event := range hub.Since(npm, lastID) {
+			writeSSEEvent(c, event)
+			lastID = event.ID
+		}
+	}
+	flusher.Flush()
+
+	utils.LogCtx(c.Request.Context()).Infow("SSE status pembayaran terhubung",
+		"npm", npm,
+		"last_event_id", lastEventID,
+	)
+
+	ticker := time.NewTicker(heartbeat)
+	defer ticker.Stop()
+
+	ctx := c.Request.Context()
+	for {
+		select {
+		case <-ctx.Done():
+			utils.LogCtx(c.Request.Context()).Infow("SSE status pembayaran ditutup", "npm", npm)
+			return
+		case <-ticker.C:
+			fmt.Fprintf(c.Writer, ": heartbeat %d\n\n", time.Now().Unix())
+			flusher.Flush()
+		case event, ok := <-events:
+			if !ok {
+				// Hub ditutup karena server shutdown
+				return
+			}
+			if event.ID <= lastID {
+				// Sudah terkirim saat replay
+				continue
+			}
+			writeSSEEvent(c, event)
+			lastID = event.ID
+			flusher.Flush()
+		}
+	}
+}
+
+func writeSSEEvent(c *gin.Context, event realtime.Event) {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		utils.LogCtx(c.Request.Context()).Errorf("SSE: gagal encode event %d: %v", event.ID, err)
+		return
+	}
+	fmt.Fprintf(c.Writer, "id: %d\nevent: %s\ndata: %s\n\n", event.ID, event.Type, payload)
+}
@@ -0,0 +1,192 @@
+package controllers
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/dedegunawan/backend-ujian-telp-v5/database"
+	"github.com/dedegunawan/backend-ujian-telp-v5/models"
+	"github.com/dedegunawan/backend-ujian-telp-v5/services"
+	"github.com/dedegunawan/backend-ujian-telp-v5/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/xuri/excelize/v2"
+)
+
+func revenueFilterFromQuery(c *gin.Context) models.RevenueFilter {
+	var groupBy []string
+	if raw := c.Query("group_by"); raw != "" {
+		groupBy = strings.Split(raw, ",")
+	}
+
+	return models.RevenueFilter{
+		TahunID:    c.Query("tahun_id"),
+		FakultasID: c.Query("fakultas_id"),
+		ProdiID:    c.Query("prodi_id"),
+		Angkatan:   c.Query("angkatan"),
+		KelUKT:     c.Query("kel_ukt"),
+		GroupBy:    groupBy,
+	}
+}
+
+// GetRevenueSummary GET /api/v1/finance/revenue
+// Query params:
+//   - group_by: kombinasi fakultas,prodi,angkatan,ukt,tahun_id (dipisah koma)
+//   - tahun_id, fakultas_id, prodi_id, angkatan, kel_ukt: filter
+func GetRevenueSummary(c *gin.Context) {
+	summary, err := services.NewRevenueService(database.DBPNBP).Summary(revenueFilterFromQuery(c))
+	if err != nil {
+		utils.LogCtx(c.Request.Context()).Errorw("GetRevenueSummary: gagal mengambil data", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengambil data pendapatan"})
+		return
+	}
+
+	c.JSON(http.StatusOK, summary)
+}
+
+// GetRevenueTimeSeries GET /api/v1/finance/revenue/timeseries
+// Query params:
+//   - interval: day (default) atau week
+//   - from, to: tanggal YYYY-MM-DD (default 30 hari terakhir, to inklusif)
+//   - tahun_id, fakultas_id, prodi_id, angkatan, kel_ukt: filter
+func GetRevenueTimeSeries(c *gin.Context) {
+	interval := c.DefaultQuery("interval", "day")
+	if interval != "day" && interval != "week" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "interval harus day atau week"})
+		return
+	}
+
+	to := time.Now()
+	from := to.AddDate(0, 0, -30)
+	if raw := c.Query("from"); raw != "" {
+		parsed, err := time.ParseInLocation("2006-01-02", raw, time.Local)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Format from harus YYYY-MM-DD"})
+			return
+		}
+		from = parsed
+	}
+	if raw := c.Query("to"); raw != "" {
+		parsed, err := time.ParseInLocation("2006-01-02", raw, time.Local)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Format to harus YYYY-MM-DD"})
+			return
+		}
+		to = parsed.AddDate(0, 0, 1)
+	}
+
+	points, err := services.NewRevenueService(database.DBPNBP).TimeSeries(revenueFilterFromQuery(c), interval, from, to)
+	if err != nil {
+		utils.LogCtx(c.Request.Context()).Errorw("GetRevenueTimeSeries: gagal mengambil data", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengambil time-series pendapatan"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"interval": interval,
+		"from":     from.Format("2006-01-02"),
+		"to":       to.AddDate(0, 0, -1).Format("2006-01-02"),
+		"points":   points,
+	})
+}
+
+// ExportRevenue GET /api/v1/finance/revenue/export
+// Filter & group_by sama dengan GetRevenueSummary, hasil XLSX di-upload ke MinIO
+func ExportRevenue(c *gin.Context) {
+	summary, err := services.NewRevenueService(database.DBPNBP).Summary(revenueFilterFromQuery(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	excel := excelize.NewFile()
+	defer excel.Close()
+	sheet := "Pendapatan"
+	excel.SetSheetName("Sheet1", sheet)
+
+	var headers []string
+	for _, g := range summary.GroupBy {
+		switch g {
+		case "fakultas":
+			headers = append(headers, "Fakultas")
+		case "prodi":
+			headers = append(headers, "Prodi")
+		case "angkatan":
+			headers = append(headers, "Angkatan")
+		case "ukt":
+			headers = append(headers, "Kelompok UKT")
+		case "tahun_id":
+			headers = append(headers, "Tahun ID")
+		}
+	}
+	headers = append(headers, "Mahasiswa", "Target", "Beasiswa", "Terkumpul", "Sisa", "Persentase (%)")
+
+	for i, h := range headers {
+		cell, _ := excelize.CoordinatesToCellName(i+1, 1)
+		excel.SetCellValue(sheet, cell, h)
+	}
+
+	writeRow := func(rowNum int, row models.RevenueRow, label string) {
+		values := revenueGroupValues(summary.GroupBy, row)
+		if label != "" && len(values) > 0 {
+			values[0] = label
+		}
+		values = append(values, row.Students, row.Expected, row.Scholarship, row.Collected, row.Outstanding, row.CollectionRate)
+		for i, v := range values {
+			cell, _ := excelize.CoordinatesToCellName(i+1, rowNum)
+			excel.SetCellValue(sheet, cell, v)
+		}
+	}
+
+	for i, row := range summary.Rows {
+		writeRow(i+2, row, "")
+	}
+	writeRow(len(summary.Rows)+2, summary.Total, "TOTAL")
+
+	var buffer bytes.Buffer
+	if err := excel.Write(&buffer); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	filename := fmt.Sprintf("exports/pendapatan_%d.xlsx", time.Now().Unix())
+	url, err := utils.UploadObjectToMinio(filename, buffer.Bytes(), "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"url": url})
+}
+
+func revenueGroupValues(groupBy []string, row models.RevenueRow) []interface{} {
+	values := make([]interface{}, 0, len(groupBy))
+	for _, g := range groupBy {
+		switch g {
+		case "fakultas":
+			values = append(values, derefString(row.NamaFakultas))
+		case "prodi":
+			values = append(values, derefString(row.NamaProdi))
+		case "angkatan":
+			if row.Angkatan != nil {
+				values = append(values, *row.Angkatan)
+			} else {
+				values = append(values, "")
+			}
+		case "ukt":
+			values = append(values, derefString(row.KelUKT))
+		case "tahun_id":
+			values = append(values, derefString(row.TahunID))
+		}
+	}
+	return values
+}
+
+func derefString(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/dedegunawan/backend-ujian-telp-v5/database"
 	"github.com/dedegunawan/backend-ujian-telp-v5/models"
+	"github.com/dedegunawan/backend-ujian-telp-v5/services"
 	"github.com/dedegunawan/backend-ujian-telp-v5/utils"
 	"github.com/gin-gonic/gin"
 )
@@ -78,36 +79,17 @@ func GetAllStudentBills(c *gin.Context) {
 
 	// Query builder - hanya ambil dari finance year yang aktif
 	// Join dengan mahasiswa untuk search by name
-	baseQuery := database.DBPNBP.Model(&models.StudentBill{}).
-		Joins("INNER JOIN finance_years ON finance_years.academic_year = student_bills.academic_year").
-		Joins("LEFT JOIN mahasiswas ON mahasiswas.mhsw_id = student_bills.student_id").
-		Where("finance_years.is_active = ?", true)
-
-	// Filter by student_id
-	if studentID != "" {
-		baseQuery = baseQuery.Where("student_bills.student_id = ?", studentID)
-	}
-
-	// Filter by academic_year
-	if academicYear != "" {
-		baseQuery = baseQuery.Where("student_bills.academic_year = ?", academicYear)
-	}
-
-	// Search functionality
-	if search != "" {
-		searchPattern := "%" + search + "%"
-		baseQuery = baseQuery.Where(
-			"student_bills.student_id ILIKE ? OR "+
-				"student_bills.name ILIKE ? OR "+
-				"COALESCE(mahasiswas.nama, '') ILIKE ?",
-			searchPattern, searchPattern, searchPattern,
-		)
-	}
+	baseQuery := services.StudentBillsBaseQuery(database.DBPNBP, models.StudentBillFilter{
+		StudentID:    studentID,
+		AcademicYear: academicYear,
+		Status:       status,
+		Search:       search,
+	})
 
 	// Get total count (before status filter for accurate count)
 	var totalCount int64
 	if err := baseQuery.Count(&totalCount).Error; err != nil {
-		utils.Log.Error("GetAllStudentBills: Failed to count bills", "error", err.Error())
+		utils.LogCtx(c.Request.Context()).Errorw("GetAllStudentBills: Failed to count bills", "error", err)
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error": "Gagal menghitung total tagihan",
 			"message": err.Error(),
@@ -127,18 +109,10 @@ func GetAllStudentBills(c *gin.Context) {
 
 	// Note: Status filtering akan dilakukan setelah data diambil karena perlu menghitung NetAmount() dan Remaining()
 	// Filter sederhana berdasarkan paid_amount untuk optimasi
-	if status == "paid" {
-		// Approximate: jika paid_amount >= amount (tanpa discount), kemungkinan sudah lunas
-		query = query.Where("student_bills.paid_amount >= student_bills.quantity * student_bills.amount")
-	} else if status == "unpaid" {
-		query = query.Where("student_bills.paid_amount = 0 AND (student_bills.quantity * student_bills.amount) > 0")
-	} else if status == "partial" {
-		query = query.Where("student_bills.paid_amount > 0 AND student_bills.paid_amount < student_bills.quantity * student_bills.amount")
-	}
-	// else "all" - no additional filter
+	query = services.ApplyStudentBillStatus(query, status)
 
 	if err := query.Find(&bills).Error; err != nil {
-		utils.Log.Error("GetAllStudentBills: Failed to fetch bills", "error", err.Error())
+		utils.LogCtx(c.Request.Context()).Errorw("GetAllStudentBills: Failed to fetch bills", "error", err)
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error": "Gagal mengambil data tagihan",
 			"message": err.Error(),
@@ -217,7 +191,7 @@ func GetAllStudentBills(c *gin.Context) {
 	// JANGAN apply status filter untuk summary - summary harus dari semua data
 	
 	if err := summaryQuery.Find(&allBillsForSummary).Error; err != nil {
-		utils.Log.Error("GetAllStudentBills: Failed to fetch bills for summary", "error", err.Error())
+		utils.LogCtx(c.Request.Context()).Errorw("GetAllStudentBills: Failed to fetch bills for summary", "error", err)
 		// Continue dengan data yang sudah ada, tapi summary akan 0
 		allBillsForSummary = []models.StudentBill{}
 	}
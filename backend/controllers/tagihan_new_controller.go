@@ -25,12 +25,22 @@ func GetStudentBillStatusNew(c *gin.Context) {
 		return
 	}
 
-	tagihanRepo := repositories.NewTagihanRepository(database.DBPNBP, database.DBPNBP)
+	// Cek kelayakan penagihan berdasarkan status akademik
+	eligibility := services.NewEligibilityService(database.WithContext(c.Request.Context())).EvaluateMaster(mhswMaster)
+	if eligibility.IsBlocked() {
+		c.JSON(http.StatusForbidden, gin.H{
+			"error":       "Tagihan tidak dapat ditampilkan untuk status mahasiswa ini",
+			"eligibility": eligibility,
+		})
+		return
+	}
+
+	tagihanRepo := repositories.NewTagihanRepository(database.WithContext(c.Request.Context()), database.WithContext(c.Request.Context()))
 
 	// Ambil FinanceYear aktif (tidak perlu override karena tidak ada mahasiswa lokal)
 	activeYear, err := tagihanRepo.GetActiveFinanceYear()
 	if err != nil {
-		utils.Log.Error("Gagal mengambil finance year aktif", "error", err.Error())
+		utils.LogCtx(c.Request.Context()).Errorw("Gagal mengambil finance year aktif", "error", err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Tahun aktif tidak ditemukan"})
 		return
 	}
@@ -47,7 +57,7 @@ func GetStudentBillStatusNew(c *gin.Context) {
 	// Ambil tagihan yang harus dibayar dari cicilan atau registrasi
 	tagihanList, err := tagihanNewService.GetTagihanMahasiswa(dummyMahasiswa, activeYear)
 	if err != nil {
-		utils.Log.Error("Gagal mengambil tagihan", "error", err.Error())
+		utils.LogCtx(c.Request.Context()).Errorw("Gagal mengambil tagihan", "error", err)
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error":   "Gagal mengambil tagihan",
 			"message": err.Error(),
@@ -58,7 +68,7 @@ func GetStudentBillStatusNew(c *gin.Context) {
 	// Ambil riwayat pembayaran dari registrasi_mahasiswa dan detail_cicilan
 	historyList, err := tagihanNewService.GetHistoryTagihanMahasiswa(dummyMahasiswa, activeYear)
 	if err != nil {
-		utils.Log.Error("Gagal mengambil riwayat pembayaran", "error", err.Error())
+		utils.LogCtx(c.Request.Context()).Errorw("Gagal mengambil riwayat pembayaran", "error", err)
 		// Jangan return error, cukup log dan gunakan history kosong
 		historyList = []models.TagihanResponse{}
 	}
@@ -67,7 +77,19 @@ func GetStudentBillStatusNew(c *gin.Context) {
 	var tagihanHarusDibayar []models.TagihanResponse
 	allPaid := true
 
+	// no_bill (mis. cuti, lulus) tidak ditagih: dilaporkan lewat isNotBilled, bukan sebagai lunas
+	notBilled := eligibility.IsNotBilled()
+
 	for _, t := range tagihanList {
+		if notBilled {
+			continue
+		}
+
+		// Terapkan aturan kelayakan (bill_reduced -> persentase)
+		billed := eligibility.ApplyToAmount(t.RemainingAmount)
+		t.PotonganStatus = t.RemainingAmount - billed
+		t.RemainingAmount = billed
+
 		if t.RemainingAmount > 0 {
 			tagihanHarusDibayar = append(tagihanHarusDibayar, t)
 			allPaid = false
@@ -75,7 +97,7 @@ func GetStudentBillStatusNew(c *gin.Context) {
 	}
 
 	isGenerated := len(tagihanList) > 0 || len(historyList) > 0
-	if !isGenerated {
+	if !isGenerated || notBilled {
 		allPaid = false
 	}
 
@@ -83,8 +105,11 @@ func GetStudentBillStatusNew(c *gin.Context) {
 		Tahun:               *activeYear,
 		IsPaid:              allPaid,
 		IsGenerated:         isGenerated,
+		IsNotBilled:         notBilled,
 		TagihanHarusDibayar: tagihanHarusDibayar,
 		HistoryTagihan:      historyList,
+		Eligibility:         eligibility,
+		Impersonation:       impersonationBanner(c),
 	}
 
 	c.JSON(http.StatusOK, response)
@@ -99,6 +124,31 @@ func GenerateUrlPembayaranNew(c *gin.Context) {
 	detailCicilanID := c.Query("detail_cicilan_id")
 	registrasiMahasiswaID := c.Query("registrasi_mahasiswa_id")
 
+	mhswMaster, mustreturn := getMahasiswa(c)
+	if mustreturn {
+		return
+	}
+
+	// VA hanya boleh dibuat untuk tagihan penuh: EPNBP membuat VA sebesar nominal penuh, sehingga
+	// untuk bill_reduced nominal VA akan berbeda dari remaining_amount yang ditampilkan
+	eligibility := services.NewEligibilityService(database.WithContext(c.Request.Context())).EvaluateMaster(mhswMaster)
+	if !eligibility.CanGenerateVA {
+		message := "Pembuatan VA tidak diperbolehkan untuk status mahasiswa ini"
+		if eligibility.CanBill {
+			message = "Tagihan mendapat potongan status akademik; VA dibuatkan oleh bagian keuangan"
+		}
+		utils.LogCtx(c.Request.Context()).Warnw("Generate payment URL ditolak oleh aturan kelayakan",
+			"mhswID", mhswMaster.StudentID,
+			"outcome", eligibility.Outcome,
+			"reason", eligibility.Reason,
+		)
+		c.JSON(http.StatusForbidden, gin.H{
+			"error":       message,
+			"eligibility": eligibility,
+		})
+		return
+	}
+
 	// Ambil EPNBP_URL dari environment variable
 	epnbpURL := os.Getenv("EPNBP_URL")
 	if epnbpURL == "" {
@@ -111,10 +161,10 @@ func GenerateUrlPembayaranNew(c *gin.Context) {
 	if detailCicilanID != "" {
 		// URL: EPNBP_URL + "/api/generate-va?detail_cicilan_id=" + id
 		redirectURL = epnbpURL + "/api/generate-va?detail_cicilan_id=" + detailCicilanID
-		utils.Log.Info("Generate payment URL untuk cicilan", map[string]interface{}{
-			"detail_cicilan_id": detailCicilanID,
-			"redirect_url":      redirectURL,
-		})
+		utils.LogCtx(c.Request.Context()).Infow("Generate payment URL untuk cicilan",
+			"detail_cicilan_id", detailCicilanID,
+			"redirect_url", redirectURL,
+		)
 		c.Redirect(http.StatusFound, redirectURL)
 		return
 	}
@@ -123,19 +173,19 @@ func GenerateUrlPembayaranNew(c *gin.Context) {
 	if registrasiMahasiswaID != "" {
 		// URL: EPNBP_URL + "/api/generate-va?registrasi_mahasiswa_id=" + id
 		redirectURL = epnbpURL + "/api/generate-va?registrasi_mahasiswa_id=" + registrasiMahasiswaID
-		utils.Log.Info("Generate payment URL untuk registrasi", map[string]interface{}{
-			"registrasi_mahasiswa_id": registrasiMahasiswaID,
-			"redirect_url":             redirectURL,
-		})
+		utils.LogCtx(c.Request.Context()).Infow("Generate payment URL untuk registrasi",
+			"registrasi_mahasiswa_id", registrasiMahasiswaID,
+			"redirect_url", redirectURL,
+		)
 		c.Redirect(http.StatusFound, redirectURL)
 		return
 	}
 
 	// Jika tidak ada parameter yang valid
-	utils.Log.Warn("Generate payment URL: parameter tidak valid", map[string]interface{}{
-		"detail_cicilan_id":       detailCicilanID,
-		"registrasi_mahasiswa_id": registrasiMahasiswaID,
-	})
+	utils.LogCtx(c.Request.Context()).Warnw("Generate payment URL: parameter tidak valid",
+		"detail_cicilan_id", detailCicilanID,
+		"registrasi_mahasiswa_id", registrasiMahasiswaID,
+	)
 	c.JSON(http.StatusBadRequest, gin.H{
 		"error":   "Parameter tidak valid",
 		"message": "Harus menyertakan detail_cicilan_id atau registrasi_mahasiswa_id",
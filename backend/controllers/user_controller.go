@@ -12,6 +12,7 @@ import (
 
 	"github.com/dedegunawan/backend-ujian-telp-v5/config"
 	"github.com/dedegunawan/backend-ujian-telp-v5/database"
+	"github.com/dedegunawan/backend-ujian-telp-v5/middleware"
 	"github.com/dedegunawan/backend-ujian-telp-v5/models"
 	"github.com/dedegunawan/backend-ujian-telp-v5/repositories"
 	"github.com/dedegunawan/backend-ujian-telp-v5/services"
@@ -25,27 +26,30 @@ func getMahasiswa(c *gin.Context) (*models.MahasiswaMaster, bool) {
 	ssoID := c.GetString("sso_id")
 	name := c.GetString("name")
 
-	utils.Log.Info("getMahasiswa", map[string]interface{}{
-		"sso_id": ssoID,
-		"email":  email,
-		"name":   name,
-	})
+	utils.LogCtx(c.Request.Context()).Infow("getMahasiswa",
+		"sso_id", ssoID,
+		"email", email,
+		"name", name,
+	)
+
+	// Mode impersonasi: NPM dari header staf (izin & audit sudah dicek middleware)
+	if npm := c.GetString(middleware.ImpersonatedNPMKey); npm != "" {
+		return findMahasiswaMaster(c, npm, email)
+	}
 
 	// Validasi email tidak kosong
 	if email == "" {
-		utils.Log.Error("Email kosong dari context", map[string]interface{}{
-			"sso_id": ssoID,
-		})
+		utils.LogCtx(c.Request.Context()).Errorw("Email kosong dari context", "sso_id", ssoID)
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Email tidak ditemukan dalam token"})
 		return nil, true
 	}
 
 	// Validasi email suffix
 	if !config.ValidateEmailSuffix(email) {
-		utils.Log.Error("Email suffix tidak valid", map[string]interface{}{
-			"email":           email,
-			"required_suffix": config.GetEmailSuffix(),
-		})
+		utils.LogCtx(c.Request.Context()).Errorw("Email suffix tidak valid",
+			"email", email,
+			"required_suffix", config.GetEmailSuffix(),
+		)
 		c.JSON(http.StatusUnauthorized, gin.H{"error": fmt.Sprintf("Email harus menggunakan domain %s", config.GetEmailSuffix())})
 		return nil, true
 	}
@@ -53,47 +57,49 @@ func getMahasiswa(c *gin.Context) (*models.MahasiswaMaster, bool) {
 	// Ambil studentID dari email
 	studentID := utils.GetEmailPrefix(email)
 	if studentID == "" {
-		utils.Log.Error("StudentID kosong dari email", map[string]interface{}{
-			"email": email,
-		})
+		utils.LogCtx(c.Request.Context()).Errorw("StudentID kosong dari email", "email", email)
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Tidak dapat mengambil NPM dari email"})
 		return nil, true
 	}
 
+	return findMahasiswaMaster(c, studentID, email)
+}
+
+func findMahasiswaMaster(c *gin.Context, studentID, email string) (*models.MahasiswaMaster, bool) {
 	// Ambil data langsung dari mahasiswa_masters (tidak perlu query ke users)
 	var mhswMaster models.MahasiswaMaster
-	err := database.DBPNBP.Preload("MasterTagihan").Where("student_id = ?", studentID).First(&mhswMaster).Error
+	err := database.WithContext(c.Request.Context()).Preload("MasterTagihan").Where("student_id = ?", studentID).First(&mhswMaster).Error
 	if err != nil {
-		utils.Log.Error("Gagal mengambil data dari mahasiswa_masters", map[string]interface{}{
-			"studentID": studentID,
-			"email":     email,
-			"error":     err.Error(),
-		})
+		utils.LogCtx(c.Request.Context()).Errorw("Gagal mengambil data dari mahasiswa_masters",
+			"studentID", studentID,
+			"email", email,
+			"error", err,
+		)
 		c.JSON(http.StatusNotFound, gin.H{"error": "Data mahasiswa tidak ditemukan"})
 		return nil, true
 	}
 
-	utils.Log.Info("Mahasiswa ditemukan dari mahasiswa_masters", map[string]interface{}{
-		"studentID": studentID,
-		"email":     email,
-		"nama":      mhswMaster.NamaLengkap,
-	})
+	utils.LogCtx(c.Request.Context()).Infow("Mahasiswa ditemukan dari mahasiswa_masters",
+		"studentID", studentID,
+		"email", email,
+		"nama", mhswMaster.NamaLengkap,
+	)
 
 	return &mhswMaster, false
 }
 
 func Me(c *gin.Context) {
-	utils.Log.Info("Endpoint /me dipanggil")
+	utils.LogCtx(c.Request.Context()).Info("Endpoint /me dipanggil")
 
 	// Ambil data langsung dari mahasiswa_masters (tidak perlu query ke users)
 	mhswMaster, mustreturn := getMahasiswa(c)
 	if mustreturn {
-		utils.Log.Warn("Endpoint /me: getMahasiswa mengembalikan mustreturn=true")
+		utils.LogCtx(c.Request.Context()).Warn("Endpoint /me: getMahasiswa mengembalikan mustreturn=true")
 		return
 	}
 
 	if mhswMaster == nil {
-		utils.Log.Warn("Endpoint /me: Mahasiswa tidak ditemukan")
+		utils.LogCtx(c.Request.Context()).Warn("Endpoint /me: Mahasiswa tidak ditemukan")
 		c.JSON(http.StatusNotFound, gin.H{"error": "Data mahasiswa tidak ditemukan"})
 		return
 	}
@@ -102,48 +108,36 @@ func Me(c *gin.Context) {
 	var prodiPnbp models.ProdiPnbp
 	var fakultasPnbp models.FakultasPnbp
 	if mhswMaster.ProdiID > 0 {
-		database.DBPNBP.Where("id = ?", mhswMaster.ProdiID).First(&prodiPnbp)
+		database.WithContext(c.Request.Context()).Where("id = ?", mhswMaster.ProdiID).First(&prodiPnbp)
 		if prodiPnbp.ID > 0 && prodiPnbp.FakultasID > 0 {
-			database.DBPNBP.Where("id = ?", prodiPnbp.FakultasID).First(&fakultasPnbp)
+			database.WithContext(c.Request.Context()).Where("id = ?", prodiPnbp.FakultasID).First(&fakultasPnbp)
 		}
 	}
 
 	// Ambil status mahasiswa dari mahasiswa_masters via status_akademiks
 	var statusMahasiswa string = "Non-Aktif"
 	var statusKode string = "N"
-	if mhswMaster.StatusAkademikID > 0 {
-		var statusAkademik models.StatusAkademik
-		errStatus := database.DBPNBP.Where("id = ?", mhswMaster.StatusAkademikID).First(&statusAkademik).Error
-		if errStatus == nil && statusAkademik.Kode != "" {
-			statusKode = statusAkademik.Kode
-			statusMahasiswa = statusAkademik.Nama
-			utils.Log.Info("Endpoint /me: Status mahasiswa diambil dari status_akademiks", map[string]interface{}{
-				"mhswID":           mhswMaster.StudentID,
-				"StatusAkademikID": mhswMaster.StatusAkademikID,
-				"Kode":             statusAkademik.Kode,
-				"Nama":             statusAkademik.Nama,
-			})
-		} else {
-			utils.Log.Warn("Endpoint /me: Status akademik tidak ditemukan", map[string]interface{}{
-				"mhswID":           mhswMaster.StudentID,
-				"StatusAkademikID": mhswMaster.StatusAkademikID,
-				"error":            errStatus,
-			})
+	eligibility := services.NewEligibilityService(database.WithContext(c.Request.Context())).EvaluateMaster(mhswMaster)
+	if eligibility.StatusKode != "" {
+		statusKode = eligibility.StatusKode
+		if eligibility.StatusNama != "" {
+			statusMahasiswa = eligibility.StatusNama
 		}
 	} else {
-		utils.Log.Warn("Endpoint /me: StatusAkademikID = 0", map[string]interface{}{
-			"mhswID": mhswMaster.StudentID,
-		})
+		utils.LogCtx(c.Request.Context()).Warnw("Endpoint /me: Status akademik tidak ditemukan",
+			"mhswID", mhswMaster.StudentID,
+			"StatusAkademikID", mhswMaster.StatusAkademikID,
+		)
 	}
 
 	// Hitung semester dari mahasiswa_masters
 	semester := 0
 	semester, err := semesterSaatIniMahasiswaFromMaster(mhswMaster, nil)
 	if err != nil {
-		utils.Log.Error("Endpoint /me: Gagal menghitung semester", map[string]interface{}{
-			"mhswID": mhswMaster.StudentID,
-			"error":  err.Error(),
-		})
+		utils.LogCtx(c.Request.Context()).Errorw("Endpoint /me: Gagal menghitung semester",
+			"mhswID", mhswMaster.StudentID,
+			"error", err,
+		)
 		semester = 0
 	}
 
@@ -153,22 +147,22 @@ func Me(c *gin.Context) {
 	// Ambil UKTNominal dari detail_tagihan
 	nominalUKTFromDetail := int64(0)
 	if mhswMaster.MasterTagihanID == 0 {
-		utils.Log.Warn("Endpoint /me: MasterTagihanID adalah 0, tidak bisa mengambil UKTNominal", map[string]interface{}{
-			"mhswID": mhswMaster.StudentID,
-			"UKT":    mhswMaster.UKT,
-		})
+		utils.LogCtx(c.Request.Context()).Warnw("Endpoint /me: MasterTagihanID adalah 0, tidak bisa mengambil UKTNominal",
+			"mhswID", mhswMaster.StudentID,
+			"UKT", mhswMaster.UKT,
+		)
 	} else if mhswMaster.UKT == 0 {
-		utils.Log.Warn("Endpoint /me: UKT adalah 0, tidak bisa mengambil UKTNominal", map[string]interface{}{
-			"mhswID":          mhswMaster.StudentID,
-			"masterTagihanID": mhswMaster.MasterTagihanID,
-		})
+		utils.LogCtx(c.Request.Context()).Warnw("Endpoint /me: UKT adalah 0, tidak bisa mengambil UKTNominal",
+			"mhswID", mhswMaster.StudentID,
+			"masterTagihanID", mhswMaster.MasterTagihanID,
+		)
 	} else {
 		// UKT dan MasterTagihanID valid, ambil dari detail_tagihan
 		var detailTagihan models.DetailTagihan
 		UKTStr := strconv.Itoa(int(mhswMaster.UKT))
 
 		// Coba query dengan format int sebagai string
-		errDetail := database.DBPNBP.Where("master_tagihan_id = ? AND kel_ukt = ?", mhswMaster.MasterTagihanID, UKTStr).
+		errDetail := database.WithContext(c.Request.Context()).Where("master_tagihan_id = ? AND kel_ukt = ?", mhswMaster.MasterTagihanID, UKTStr).
 			First(&detailTagihan).Error
 
 		if errDetail == nil {
@@ -176,14 +170,14 @@ func Me(c *gin.Context) {
 		} else {
 			// Fallback: coba format float dengan 2 desimal
 			UKTFloat := fmt.Sprintf("%.2f", mhswMaster.UKT)
-			errDetail = database.DBPNBP.Where("master_tagihan_id = ? AND kel_ukt = ?", mhswMaster.MasterTagihanID, UKTFloat).
+			errDetail = database.WithContext(c.Request.Context()).Where("master_tagihan_id = ? AND kel_ukt = ?", mhswMaster.MasterTagihanID, UKTFloat).
 				First(&detailTagihan).Error
 			if errDetail == nil {
 				nominalUKTFromDetail = detailTagihan.Nominal
 			} else {
 				// Fallback: coba tanpa desimal
 				UKTNoDecimal := fmt.Sprintf("%.0f", mhswMaster.UKT)
-				errDetail = database.DBPNBP.Where("master_tagihan_id = ? AND kel_ukt = ?", mhswMaster.MasterTagihanID, UKTNoDecimal).
+				errDetail = database.WithContext(c.Request.Context()).Where("master_tagihan_id = ? AND kel_ukt = ?", mhswMaster.MasterTagihanID, UKTNoDecimal).
 					First(&detailTagihan).Error
 				if errDetail == nil {
 					nominalUKTFromDetail = detailTagihan.Nominal
@@ -213,6 +207,7 @@ func Me(c *gin.Context) {
 		"tahun_masuk": mhswMaster.TahunMasuk,
 		"status":      statusMahasiswa, // Status dari mahasiswa_masters
 		"status_kode": statusKode,      // Kode status (A, N, dll)
+		"eligibility": eligibility,
 		"parsed":      parsedData,
 		"prodi": gin.H{
 			"kode_prodi":  prodiPnbp.KodeProdi,
@@ -236,11 +231,14 @@ func Me(c *gin.Context) {
 		"mahasiswa": mahasiswaResponse,
 		"semester":  semester,
 	}
+	if banner := impersonationBanner(c); banner != nil {
+		response["impersonation"] = banner
+	}
 
-	utils.Log.Info("Endpoint /me: Response berhasil", map[string]interface{}{
-		"mhswID":   mhswMaster.StudentID,
-		"semester": semester,
-	})
+	utils.LogCtx(c.Request.Context()).Infow("Endpoint /me: Response berhasil",
+		"mhswID", mhswMaster.StudentID,
+		"semester", semester,
+	)
 
 	c.JSON(200, response)
 }
@@ -254,10 +252,10 @@ func semesterSaatIniMahasiswa(mahasiswa *models.Mahasiswa) (int, error) {
 		return 0, fmt.Errorf("MhswID kosong")
 	}
 
-	utils.Log.Info("semesterSaatIniMahasiswa: Memulai perhitungan semester", map[string]interface{}{
-		"mhswID": mahasiswa.MhswID,
-		"nama":   mahasiswa.Nama,
-	})
+	utils.Log.Infow("semesterSaatIniMahasiswa: Memulai perhitungan semester",
+		"mhswID", mahasiswa.MhswID,
+		"nama", mahasiswa.Nama,
+	)
 
 	tagihanRepo := repositories.NewTagihanRepository(database.DBPNBP, database.DBPNBP)
 	masterTagihanagihanRepo := repositories.MasterTagihanRepository{DB: database.DBPNBP}
@@ -266,19 +264,19 @@ func semesterSaatIniMahasiswa(mahasiswa *models.Mahasiswa) (int, error) {
 	// Panggil repository untuk ambil FinanceYear aktif (berisi budget_periods.kode)
 	activeYear, err := tagihanRepo.GetActiveFinanceYearWithOverride(*mahasiswa)
 	if err != nil {
-		utils.Log.Error("semesterSaatIniMahasiswa: Gagal ambil FinanceYear aktif", map[string]interface{}{
-			"mhswID": mahasiswa.MhswID,
-			"error":  err.Error(),
-		})
+		utils.Log.Errorw("semesterSaatIniMahasiswa: Gagal ambil FinanceYear aktif",
+			"mhswID", mahasiswa.MhswID,
+			"error", err,
+		)
 		return 0, fmt.Errorf("Tahun aktif tidak ditemukan: %w", err)
 	}
 
-	utils.Log.Info("semesterSaatIniMahasiswa: FinanceYear aktif ditemukan", map[string]interface{}{
-		"mhswID":       mahasiswa.MhswID,
-		"academicYear": activeYear.AcademicYear,
-		"code":         activeYear.Code,
-		"description":  activeYear.Description,
-	})
+	utils.Log.Infow("semesterSaatIniMahasiswa: FinanceYear aktif ditemukan",
+		"mhswID", mahasiswa.MhswID,
+		"academicYear", activeYear.AcademicYear,
+		"code", activeYear.Code,
+		"description", activeYear.Description,
+	)
 
 	// Ambil SemesterMasukID dari mahasiswa_masters di database PNBP
 	// SemesterMasukID adalah referensi ke budget_periods.id, bukan semester masuk (1/2)
@@ -294,85 +292,80 @@ func semesterSaatIniMahasiswa(mahasiswa *models.Mahasiswa) (int, error) {
 		errBudgetPeriod := database.DBPNBP.Where("id = ?", mhswMaster.SemesterMasukID).First(&budgetPeriod).Error
 		if errBudgetPeriod == nil && budgetPeriod.Kode != "" {
 			tahunIDAwal = budgetPeriod.Kode
-			utils.Log.Info("semesterSaatIniMahasiswa: TahunID awal diambil dari budget_periods berdasarkan SemesterMasukID", map[string]interface{}{
-				"mhswID":           mahasiswa.MhswID,
-				"SemesterMasukID":  mhswMaster.SemesterMasukID,
-				"budgetPeriodID":   budgetPeriod.ID,
-				"budgetPeriodKode": budgetPeriod.Kode,
-				"tahunIDAwal":      tahunIDAwal,
-			})
+			utils.Log.Infow("semesterSaatIniMahasiswa: TahunID awal diambil dari budget_periods berdasarkan SemesterMasukID",
+				"mhswID", mahasiswa.MhswID,
+				"SemesterMasukID", mhswMaster.SemesterMasukID,
+				"budgetPeriodID", budgetPeriod.ID,
+				"budgetPeriodKode", budgetPeriod.Kode,
+				"tahunIDAwal", tahunIDAwal,
+			)
 		} else {
-			utils.Log.Warn("semesterSaatIniMahasiswa: Budget period tidak ditemukan berdasarkan SemesterMasukID, fallback ke TahunMasuk", map[string]interface{}{
-				"mhswID":          mahasiswa.MhswID,
-				"SemesterMasukID": mhswMaster.SemesterMasukID,
-				"error":           errBudgetPeriod,
-			})
+			utils.Log.Warnw("semesterSaatIniMahasiswa: Budget period tidak ditemukan berdasarkan SemesterMasukID, fallback ke TahunMasuk",
+				"mhswID", mahasiswa.MhswID,
+				"SemesterMasukID", mhswMaster.SemesterMasukID,
+				"error", errBudgetPeriod,
+			)
 			// Fallback: gunakan TahunMasuk dengan default semester 1
 			if mhswMaster.TahunMasuk > 0 {
 				tahunIDAwal = fmt.Sprintf("%d1", mhswMaster.TahunMasuk)
-				utils.Log.Info("semesterSaatIniMahasiswa: TahunID awal dibuat dari TahunMasuk (fallback)", map[string]interface{}{
-					"mhswID":      mahasiswa.MhswID,
-					"TahunMasuk":  mhswMaster.TahunMasuk,
-					"tahunIDAwal": tahunIDAwal,
-				})
+				utils.Log.Infow("semesterSaatIniMahasiswa: TahunID awal dibuat dari TahunMasuk (fallback)",
+					"mhswID", mahasiswa.MhswID,
+					"TahunMasuk", mhswMaster.TahunMasuk,
+					"tahunIDAwal", tahunIDAwal,
+				)
 			} else {
-				utils.Log.Error("semesterSaatIniMahasiswa: TahunMasuk juga tidak ditemukan", map[string]interface{}{
-					"mhswID": mahasiswa.MhswID,
-				})
+				utils.Log.Errorw("semesterSaatIniMahasiswa: TahunMasuk juga tidak ditemukan", "mhswID", mahasiswa.MhswID)
 				return 0, fmt.Errorf("tahun masuk tidak ditemukan untuk mahasiswa %s", mahasiswa.MhswID)
 			}
 		}
 	} else {
-		utils.Log.Warn("semesterSaatIniMahasiswa: Mahasiswa tidak ditemukan di mahasiswa_masters atau SemesterMasukID = 0, fallback ke FullData", map[string]interface{}{
-			"mhswID": mahasiswa.MhswID,
-			"error":  errMhswMaster,
-		})
+		utils.Log.Warnw("semesterSaatIniMahasiswa: Mahasiswa tidak ditemukan di mahasiswa_masters atau SemesterMasukID = 0, fallback ke FullData",
+			"mhswID", mahasiswa.MhswID,
+			"error", errMhswMaster,
+		)
 		// Fallback: ambil dari FullData
 		if tahunIDData, ok := mahasiswa.ParseFullData()["TahunID"].(string); ok && tahunIDData != "" {
 			tahunIDAwal = tahunIDData
-			utils.Log.Info("semesterSaatIniMahasiswa: TahunID awal diambil dari FullData", map[string]interface{}{
-				"mhswID":      mahasiswa.MhswID,
-				"tahunIDAwal": tahunIDAwal,
-			})
+			utils.Log.Infow("semesterSaatIniMahasiswa: TahunID awal diambil dari FullData",
+				"mhswID", mahasiswa.MhswID,
+				"tahunIDAwal", tahunIDAwal,
+			)
 		} else if tahunMasukData, ok := mahasiswa.ParseFullData()["TahunMasuk"].(float64); ok {
 			// Fallback: buat dari TahunMasuk dengan default semester 1
 			tahunIDAwal = fmt.Sprintf("%.0f1", tahunMasukData)
-			utils.Log.Info("semesterSaatIniMahasiswa: TahunID awal dibuat dari TahunMasuk di FullData", map[string]interface{}{
-				"mhswID":      mahasiswa.MhswID,
-				"TahunMasuk":  tahunMasukData,
-				"tahunIDAwal": tahunIDAwal,
-			})
+			utils.Log.Infow("semesterSaatIniMahasiswa: TahunID awal dibuat dari TahunMasuk di FullData",
+				"mhswID", mahasiswa.MhswID,
+				"TahunMasuk", tahunMasukData,
+				"tahunIDAwal", tahunIDAwal,
+			)
 		} else {
-			utils.Log.Error("semesterSaatIniMahasiswa: TahunID atau TahunMasuk tidak ditemukan di FullData", map[string]interface{}{
-				"mhswID":   mahasiswa.MhswID,
-				"fullData": mahasiswa.ParseFullData(),
-			})
+			utils.Log.Errorw("semesterSaatIniMahasiswa: TahunID atau TahunMasuk tidak ditemukan di FullData", "mhswID", mahasiswa.MhswID)
 			return 0, fmt.Errorf("tahun masuk tidak ditemukan untuk mahasiswa %s", mahasiswa.MhswID)
 		}
 	}
-	utils.Log.Info("semesterSaatIniMahasiswa: Memanggil HitungSemesterSaatIni", map[string]interface{}{
-		"mhswID":       mahasiswa.MhswID,
-		"tahunIDAwal":  tahunIDAwal,
-		"academicYear": activeYear.AcademicYear,
-	})
+	utils.Log.Infow("semesterSaatIniMahasiswa: Memanggil HitungSemesterSaatIni",
+		"mhswID", mahasiswa.MhswID,
+		"tahunIDAwal", tahunIDAwal,
+		"academicYear", activeYear.AcademicYear,
+	)
 
 	semester, err := tagihanService.HitungSemesterSaatIni(tahunIDAwal, activeYear.AcademicYear)
 	if err != nil {
-		utils.Log.Error("semesterSaatIniMahasiswa: Gagal hitung semester", map[string]interface{}{
-			"mhswID":       mahasiswa.MhswID,
-			"tahunIDAwal":  tahunIDAwal,
-			"academicYear": activeYear.AcademicYear,
-			"error":        err.Error(),
-		})
+		utils.Log.Errorw("semesterSaatIniMahasiswa: Gagal hitung semester",
+			"mhswID", mahasiswa.MhswID,
+			"tahunIDAwal", tahunIDAwal,
+			"academicYear", activeYear.AcademicYear,
+			"error", err,
+		)
 		return 0, err
 	}
 
-	utils.Log.Info("semesterSaatIniMahasiswa: Semester berhasil dihitung", map[string]interface{}{
-		"mhswID":       mahasiswa.MhswID,
-		"tahunIDAwal":  tahunIDAwal,
-		"academicYear": activeYear.AcademicYear,
-		"semester":     semester,
-	})
+	utils.Log.Infow("semesterSaatIniMahasiswa: Semester berhasil dihitung",
+		"mhswID", mahasiswa.MhswID,
+		"tahunIDAwal", tahunIDAwal,
+		"academicYear", activeYear.AcademicYear,
+		"semester", semester,
+	)
 
 	return semester, nil
 }
@@ -393,10 +386,10 @@ func semesterSaatIniMahasiswaFromMaster(mhswMaster *models.MahasiswaMaster, maha
 		mahasiswa = &dummyMahasiswa
 	}
 
-	utils.Log.Info("semesterSaatIniMahasiswaFromMaster: Memulai perhitungan semester", map[string]interface{}{
-		"mhswID": mhswMaster.StudentID,
-		"nama":   mhswMaster.NamaLengkap,
-	})
+	utils.Log.Infow("semesterSaatIniMahasiswaFromMaster: Memulai perhitungan semester",
+		"mhswID", mhswMaster.StudentID,
+		"nama", mhswMaster.NamaLengkap,
+	)
 
 	tagihanRepo := repositories.NewTagihanRepository(database.DBPNBP, database.DBPNBP)
 	masterTagihanRepo := repositories.MasterTagihanRepository{DB: database.DBPNBP}
@@ -405,19 +398,19 @@ func semesterSaatIniMahasiswaFromMaster(mhswMaster *models.MahasiswaMaster, maha
 	// Ambil FinanceYear aktif langsung dari budget_periods (tidak perlu override karena tidak ada mahasiswa lokal)
 	activeYear, err := tagihanRepo.GetActiveFinanceYear()
 	if err != nil {
-		utils.Log.Error("semesterSaatIniMahasiswaFromMaster: Gagal ambil FinanceYear aktif", map[string]interface{}{
-			"mhswID": mhswMaster.StudentID,
-			"error":  err.Error(),
-		})
+		utils.Log.Errorw("semesterSaatIniMahasiswaFromMaster: Gagal ambil FinanceYear aktif",
+			"mhswID", mhswMaster.StudentID,
+			"error", err,
+		)
 		return 0, fmt.Errorf("Tahun aktif tidak ditemukan: %w", err)
 	}
 
-	utils.Log.Info("semesterSaatIniMahasiswaFromMaster: FinanceYear aktif ditemukan", map[string]interface{}{
-		"mhswID":       mhswMaster.StudentID,
-		"academicYear": activeYear.AcademicYear,
-		"code":         activeYear.Code,
-		"description":  activeYear.Description,
-	})
+	utils.Log.Infow("semesterSaatIniMahasiswaFromMaster: FinanceYear aktif ditemukan",
+		"mhswID", mhswMaster.StudentID,
+		"academicYear", activeYear.AcademicYear,
+		"code", activeYear.Code,
+		"description", activeYear.Description,
+	)
 
 	var tahunIDAwal string
 
@@ -428,31 +421,29 @@ func semesterSaatIniMahasiswaFromMaster(mhswMaster *models.MahasiswaMaster, maha
 		errBudgetPeriod := database.DBPNBP.Where("id = ?", mhswMaster.SemesterMasukID).First(&budgetPeriod).Error
 		if errBudgetPeriod == nil && budgetPeriod.Kode != "" {
 			tahunIDAwal = budgetPeriod.Kode
-			utils.Log.Info("semesterSaatIniMahasiswaFromMaster: TahunID awal diambil dari budget_periods berdasarkan SemesterMasukID", map[string]interface{}{
-				"mhswID":           mhswMaster.StudentID,
-				"SemesterMasukID":  mhswMaster.SemesterMasukID,
-				"budgetPeriodID":   budgetPeriod.ID,
-				"budgetPeriodKode": budgetPeriod.Kode,
-				"tahunIDAwal":      tahunIDAwal,
-			})
+			utils.Log.Infow("semesterSaatIniMahasiswaFromMaster: TahunID awal diambil dari budget_periods berdasarkan SemesterMasukID",
+				"mhswID", mhswMaster.StudentID,
+				"SemesterMasukID", mhswMaster.SemesterMasukID,
+				"budgetPeriodID", budgetPeriod.ID,
+				"budgetPeriodKode", budgetPeriod.Kode,
+				"tahunIDAwal", tahunIDAwal,
+			)
 		} else {
-			utils.Log.Warn("semesterSaatIniMahasiswaFromMaster: Budget period tidak ditemukan berdasarkan SemesterMasukID, fallback ke TahunMasuk", map[string]interface{}{
-				"mhswID":          mhswMaster.StudentID,
-				"SemesterMasukID": mhswMaster.SemesterMasukID,
-				"error":           errBudgetPeriod,
-			})
+			utils.Log.Warnw("semesterSaatIniMahasiswaFromMaster: Budget period tidak ditemukan berdasarkan SemesterMasukID, fallback ke TahunMasuk",
+				"mhswID", mhswMaster.StudentID,
+				"SemesterMasukID", mhswMaster.SemesterMasukID,
+				"error", errBudgetPeriod,
+			)
 			// Fallback: gunakan TahunMasuk dengan default semester 1
 			if mhswMaster.TahunMasuk > 0 {
 				tahunIDAwal = fmt.Sprintf("%d1", mhswMaster.TahunMasuk)
-				utils.Log.Info("semesterSaatIniMahasiswaFromMaster: TahunID awal dibuat dari TahunMasuk (fallback)", map[string]interface{}{
-					"mhswID":      mhswMaster.StudentID,
-					"TahunMasuk":  mhswMaster.TahunMasuk,
-					"tahunIDAwal": tahunIDAwal,
-				})
+				utils.Log.Infow("semesterSaatIniMahasiswaFromMaster: TahunID awal dibuat dari TahunMasuk (fallback)",
+					"mhswID", mhswMaster.StudentID,
+					"TahunMasuk", mhswMaster.TahunMasuk,
+					"tahunIDAwal", tahunIDAwal,
+				)
 			} else {
-				utils.Log.Error("semesterSaatIniMahasiswaFromMaster: TahunMasuk juga tidak ditemukan", map[string]interface{}{
-					"mhswID": mhswMaster.StudentID,
-				})
+				utils.Log.Errorw("semesterSaatIniMahasiswaFromMaster: TahunMasuk juga tidak ditemukan", "mhswID", mhswMaster.StudentID)
 				return 0, fmt.Errorf("tahun masuk tidak ditemukan untuk mahasiswa %s", mhswMaster.StudentID)
 			}
 		}
@@ -460,42 +451,40 @@ func semesterSaatIniMahasiswaFromMaster(mhswMaster *models.MahasiswaMaster, maha
 		// Fallback: gunakan TahunMasuk dengan default semester 1
 		if mhswMaster.TahunMasuk > 0 {
 			tahunIDAwal = fmt.Sprintf("%d1", mhswMaster.TahunMasuk)
-			utils.Log.Info("semesterSaatIniMahasiswaFromMaster: TahunID awal dibuat dari TahunMasuk (SemesterMasukID tidak ada)", map[string]interface{}{
-				"mhswID":      mhswMaster.StudentID,
-				"TahunMasuk":  mhswMaster.TahunMasuk,
-				"tahunIDAwal": tahunIDAwal,
-			})
+			utils.Log.Infow("semesterSaatIniMahasiswaFromMaster: TahunID awal dibuat dari TahunMasuk (SemesterMasukID tidak ada)",
+				"mhswID", mhswMaster.StudentID,
+				"TahunMasuk", mhswMaster.TahunMasuk,
+				"tahunIDAwal", tahunIDAwal,
+			)
 		} else {
-			utils.Log.Error("semesterSaatIniMahasiswaFromMaster: TahunMasuk tidak ditemukan", map[string]interface{}{
-				"mhswID": mhswMaster.StudentID,
-			})
+			utils.Log.Errorw("semesterSaatIniMahasiswaFromMaster: TahunMasuk tidak ditemukan", "mhswID", mhswMaster.StudentID)
 			return 0, fmt.Errorf("tahun masuk tidak ditemukan untuk mahasiswa %s", mhswMaster.StudentID)
 		}
 	}
 
-	utils.Log.Info("semesterSaatIniMahasiswaFromMaster: Memanggil HitungSemesterSaatIni", map[string]interface{}{
-		"mhswID":       mhswMaster.StudentID,
-		"tahunIDAwal":  tahunIDAwal,
-		"academicYear": activeYear.AcademicYear,
-	})
+	utils.Log.Infow("semesterSaatIniMahasiswaFromMaster: Memanggil HitungSemesterSaatIni",
+		"mhswID", mhswMaster.StudentID,
+		"tahunIDAwal", tahunIDAwal,
+		"academicYear", activeYear.AcademicYear,
+	)
 
 	semester, err := tagihanService.HitungSemesterSaatIni(tahunIDAwal, activeYear.AcademicYear)
 	if err != nil {
-		utils.Log.Error("semesterSaatIniMahasiswaFromMaster: Gagal hitung semester", map[string]interface{}{
-			"mhswID":       mhswMaster.StudentID,
-			"tahunIDAwal":  tahunIDAwal,
-			"academicYear": activeYear.AcademicYear,
-			"error":        err.Error(),
-		})
+		utils.Log.Errorw("semesterSaatIniMahasiswaFromMaster: Gagal hitung semester",
+			"mhswID", mhswMaster.StudentID,
+			"tahunIDAwal", tahunIDAwal,
+			"academicYear", activeYear.AcademicYear,
+			"error", err,
+		)
 		return 0, err
 	}
 
-	utils.Log.Info("semesterSaatIniMahasiswaFromMaster: Semester berhasil dihitung", map[string]interface{}{
-		"mhswID":       mhswMaster.StudentID,
-		"tahunIDAwal":  tahunIDAwal,
-		"academicYear": activeYear.AcademicYear,
-		"semester":     semester,
-	})
+	utils.Log.Infow("semesterSaatIniMahasiswaFromMaster: Semester berhasil dihitung",
+		"mhswID", mhswMaster.StudentID,
+		"tahunIDAwal", tahunIDAwal,
+		"academicYear", activeYear.AcademicYear,
+		"semester", semester,
+	)
 
 	return semester, nil
 }
@@ -523,7 +512,7 @@ func getTahunIDFromMahasiswaMasters(mhswID string) string {
 
 	if mhswMaster.TahunMasuk > 0 {
 		TahunID := fmt.Sprintf("%d%d", mhswMaster.TahunMasuk, semesterMasuk)
-		utils.Log.Info("TahunID diambil dari mahasiswa_masters", "mhswID", mhswID, "TahunMasuk", mhswMaster.TahunMasuk, "SemesterMasukID", mhswMaster.SemesterMasukID, "TahunID", TahunID)
+		utils.Log.Infow("TahunID diambil dari mahasiswa_masters", "mhswID", mhswID, "TahunMasuk", mhswMaster.TahunMasuk, "SemesterMasukID", mhswMaster.SemesterMasukID, "TahunID", TahunID)
 		return TahunID
 	}
 
@@ -545,7 +534,7 @@ func getTahunIDFormParsed(mahasiswa *models.Mahasiswa) string {
 		case int:
 			TahunID = strconv.Itoa(v)
 		default:
-			utils.Log.Info("TahunID ditemukan tapi tipe tidak dikenali", "value", tahunRaw)
+			utils.Log.Infow("TahunID ditemukan tapi tipe tidak dikenali", "value", tahunRaw)
 			return ""
 		}
 		if TahunID != "" {
@@ -556,11 +545,11 @@ func getTahunIDFormParsed(mahasiswa *models.Mahasiswa) string {
 	// Fallback: coba ambil dari TahunMasuk jika ada
 	if tahunMasuk, ok := data["TahunMasuk"].(float64); ok {
 		TahunID := fmt.Sprintf("%.0f1", tahunMasuk) // Default semester 1
-		utils.Log.Info("TahunID dibuat dari TahunMasuk", "TahunMasuk", tahunMasuk, "TahunID", TahunID)
+		utils.Log.Infow("TahunID dibuat dari TahunMasuk", "TahunMasuk", tahunMasuk, "TahunID", TahunID)
 		return TahunID
 	}
 
-	utils.Log.Info("Field TahunID tidak ditemukan pada data mahasiswa", "data", data)
+	utils.Log.Infow("Field TahunID tidak ditemukan pada data mahasiswa", "data", data)
 	return ""
 
 }
@@ -587,27 +576,6 @@ func RegenerateCurrentBill(c *gin.Context) {
 	return
 }
 
-func GetIsMahasiswaAktifFromFullData(mahasiswa models.Mahasiswa) bool {
-	full_data := mahasiswa.ParseFullData()
-	statusMhswID, ok := full_data["StatusMhswID"].(string)
-	if !ok || statusMhswID == "" {
-		statusMhswID = "-"
-	}
-
-	var statusAkademikId int
-	if v, ok := full_data["StatusAkademikID"].(float64); ok {
-		statusAkademikId = int(v)
-	}
-	utils.Log.Info("GetIsMahasiswaAktifFromFullData", map[string]string{
-		"MhswID":       statusMhswID,
-		"AkademikID":   fmt.Sprintf("%d", statusAkademikId),
-		"statusMhswID": statusMhswID,
-		"full_data":    fmt.Sprintf("%v", full_data),
-		"lastOk":       fmt.Sprintf("%t", ok),
-	})
-	return statusMhswID == "A" || statusAkademikId == 1
-}
-
 func GenerateCurrentBill(c *gin.Context) {
 	// Endpoint ini deprecated - tidak perlu generate tagihan lagi
 	c.JSON(http.StatusNotFound, gin.H{"error": "Endpoint deprecated, tagihan langsung dari cicilan/registrasi"})
@@ -615,8 +583,8 @@ func GenerateCurrentBill(c *gin.Context) {
 }
 
 func GenerateCurrentBillPascasarjana(c *gin.Context, mahasiswa models.Mahasiswa) {
-	utils.Log.Info("GenerateCurrentBillPascasarjana")
-	tagihanRepo := repositories.NewTagihanRepository(database.DBPNBP, database.DBPNBP)
+	utils.LogCtx(c.Request.Context()).Info("GenerateCurrentBillPascasarjana")
+	tagihanRepo := repositories.NewTagihanRepository(database.WithContext(c.Request.Context()), database.WithContext(c.Request.Context()))
 
 	// Panggil repository untuk ambil FinanceYear aktif
 	activeYear, err := tagihanRepo.GetActiveFinanceYearWithOverride(mahasiswa)
@@ -625,17 +593,27 @@ func GenerateCurrentBillPascasarjana(c *gin.Context, mahasiswa models.Mahasiswa)
 		return
 	}
 
-	// Panggil repository untuk ambil FinanceYear aktif
-	if !GetIsMahasiswaAktifFromFullData(mahasiswa) {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Pembuatan tagihan baru untuk tahun aktif, hanya diperboleh untuk mahasiswa aktif"})
+	// Cek kelayakan penagihan berdasarkan status akademik (mahasiswa_masters -> status_akademiks)
+	// Tagihan baru dibuat sebesar nominal penuh, jadi hanya untuk bill_full (lihat CanGenerateVA)
+	var mhswMaster *models.MahasiswaMaster
+	var master models.MahasiswaMaster
+	if err := database.WithContext(c.Request.Context()).Where("student_id = ?", mahasiswa.MhswID).First(&master).Error; err == nil {
+		mhswMaster = &master
+	}
+	eligibility := services.NewEligibilityService(database.WithContext(c.Request.Context())).EvaluateMaster(mhswMaster)
+	if !eligibility.CanGenerateVA {
+		c.JSON(http.StatusForbidden, gin.H{
+			"error":       "Pembuatan tagihan baru untuk tahun aktif tidak diperbolehkan untuk status mahasiswa ini",
+			"eligibility": eligibility,
+		})
 		return
 	}
 
-	masterTagihanagihanRepo := repositories.MasterTagihanRepository{DB: database.DBPNBP}
+	masterTagihanagihanRepo := repositories.MasterTagihanRepository{DB: database.WithContext(c.Request.Context())}
 	tagihanService := services.NewTagihanService(*tagihanRepo, masterTagihanagihanRepo)
 
 	if err := tagihanService.CreateNewTagihanPasca(&mahasiswa, activeYear); err != nil {
-		utils.Log.Info("Gagal membuat tagihan", "error", err)
+		utils.LogCtx(c.Request.Context()).Errorw("Gagal membuat tagihan", "error", err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal membuat tagihan"})
 		return
 	}
@@ -646,12 +624,12 @@ func GenerateCurrentBillPascasarjana(c *gin.Context, mahasiswa models.Mahasiswa)
 func GenerateUrlPembayaran(c *gin.Context) {
 	studentBillID := c.Param("StudentBillID")
 	if studentBillID == "" {
-		utils.Log.Info("StudentBillID not found ", studentBillID)
+		utils.LogCtx(c.Request.Context()).Infow("StudentBillID not found", "student_bill_id", studentBillID)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal membuat URL Pembayaran"})
 		return
 	}
 
-	epnbpRepo := repositories.NewEpnbpRepository(database.DBPNBP)
+	epnbpRepo := repositories.NewEpnbpRepository(database.WithContext(c.Request.Context()))
 
 	payUrl, _ := epnbpRepo.FindNotExpiredByStudentBill(studentBillID)
 	if payUrl != nil && payUrl.PayUrl != "" {
@@ -667,7 +645,7 @@ func GenerateUrlPembayaran(c *gin.Context) {
 func ConfirmPembayaran(c *gin.Context) {
 	studentBillID := c.Param("StudentBillID")
 	if studentBillID == "" {
-		utils.Log.Info("StudentBillID not found ", studentBillID)
+		utils.LogCtx(c.Request.Context()).Infow("StudentBillID not found", "student_bill_id", studentBillID)
 		c.JSON(http.StatusBadRequest, gin.H{"error": "StudentBillID wajib diisi"})
 		return
 	}
@@ -682,7 +660,7 @@ func ConfirmPembayaran(c *gin.Context) {
 	}
 
 	// Validasi student bill (opsional)
-	tagihanRepo := repositories.NewTagihanRepository(database.DBPNBP, database.DBPNBP)
+	tagihanRepo := repositories.NewTagihanRepository(database.WithContext(c.Request.Context()), database.WithContext(c.Request.Context()))
 	studentBill, err := tagihanRepo.FindStudentBillByID(studentBillID)
 	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Tagihan tidak ditemukan"})
@@ -694,7 +672,7 @@ func ConfirmPembayaran(c *gin.Context) {
 		return
 	}
 
-	masterTagihanagihanRepo := repositories.MasterTagihanRepository{DB: database.DBPNBP}
+	masterTagihanagihanRepo := repositories.MasterTagihanRepository{DB: database.WithContext(c.Request.Context())}
 
 	// Simpan ke database (opsional, sesuaikan dengan struktur Anda)
 	paymentConfirmation, err := services.NewTagihanService(*tagihanRepo, masterTagihanagihanRepo).SavePaymentConfirmation(*studentBill, vaNumber, paymentDate, fileURL)
@@ -743,7 +721,7 @@ func handleUpload(c *gin.Context, filename string) (string, bool) {
 	// Upload ke MinIO
 	_, err = utils.UploadObjectToMinio(objectName, fileBytes, fileHeader.Header.Get("Content-Type"))
 	if err != nil {
-		utils.Log.Error("Gagal upload ke MinIO", "error", err)
+		utils.LogCtx(c.Request.Context()).Errorw("Gagal upload ke MinIO", "error", err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengunggah file ke storage"})
 		return "", false
 	}
@@ -758,7 +736,7 @@ func BackToSintesys(c *gin.Context) {
 		return
 	}
 
-	tagihanRepo := repositories.NewTagihanRepository(database.DBPNBP, database.DBPNBP)
+	tagihanRepo := repositories.NewTagihanRepository(database.WithContext(c.Request.Context()), database.WithContext(c.Request.Context()))
 	year, err := tagihanRepo.GetActiveFinanceYear()
 
 	if err != nil {
@@ -766,6 +744,18 @@ func BackToSintesys(c *gin.Context) {
 		return
 	}
 
+	// Mahasiswa yang diblokir atau tidak ditagih (mis. cuti/lulus) tidak dikirim ke Sintesys:
+	// callback membuka pengisian KRS (beserta max_sks) untuk tahun aktif
+	eligibility := services.NewEligibilityService(database.WithContext(c.Request.Context())).EvaluateMaster(mhswMaster)
+	if eligibility.IsBlocked() || eligibility.IsNotBilled() {
+		utils.LogCtx(c.Request.Context()).Warnw("BackToSintesys: callback tidak dikirim karena aturan kelayakan",
+			"mhswID", mhswMaster.StudentID,
+			"reason", eligibility.Reason,
+		)
+		RedirectSintesys(c)
+		return
+	}
+
 	UKTStr := strconv.Itoa(int(mhswMaster.UKT))
 	if UKTStr == "0" {
 		hitAndBack(c, mhswMaster.StudentID, year.AcademicYear, UKTStr)
@@ -780,7 +770,7 @@ func BackToSintesys(c *gin.Context) {
 
 func hitAndBack(c *gin.Context, studentId string, academicYear string, ukt string) {
 	sintesysService := services.NewSintesys()
-	sintesysService.SendCallback(studentId, academicYear, ukt)
+	sintesysService.SendCallback(c.Request.Context(), studentId, academicYear, ukt)
 	RedirectSintesys(c)
 	return
 }
@@ -0,0 +1,176 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/dedegunawan/backend-ujian-telp-v5/database"
+	"github.com/dedegunawan/backend-ujian-telp-v5/services"
+	"github.com/dedegunawan/backend-ujian-telp-v5/webhook"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+func webhookIDParam(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID tidak valid"})
+		return 0, false
+	}
+	return uint(id), true
+}
+
+func webhookError(c *gin.Context, err error, notFound string) {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": notFound})
+		return
+	}
+	c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+}
+
+// GetWebhookEventTypes GET /api/v1/webhooks/event-types
+func GetWebhookEventTypes(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"event_types": webhook.EventTypes})
+}
+
+// GetWebhookSubscriptions GET /api/v1/webhooks/subscriptions
+func GetWebhookSubscriptions(c *gin.Context) {
+	rows, err := services.NewWebhookService(database.DBPNBP).ListSubscriptions()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengambil subscription webhook"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"subscriptions": rows})
+}
+
+// CreateWebhookSubscription POST /api/v1/webhooks/subscriptions
+// Secret hanya dikembalikan sekali di respons ini
+func CreateWebhookSubscription(c *gin.Context) {
+	var input services.WebhookSubscriptionInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Body tidak valid"})
+		return
+	}
+
+	row, secret, err := services.NewWebhookService(database.DBPNBP).CreateSubscription(input, c.GetString("email"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"subscription": row, "secret": secret})
+}
+
+// UpdateWebhookSubscription PUT /api/v1/webhooks/subscriptions/:id
+func UpdateWebhookSubscription(c *gin.Context) {
+	id, ok := webhookIDParam(c)
+	if !ok {
+		return
+	}
+	var input services.WebhookSubscriptionInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Body tidak valid"})
+		return
+	}
+
+	row, err := services.NewWebhookService(database.DBPNBP).UpdateSubscription(id, input)
+	if err != nil {
+		webhookError(c, err, "Subscription tidak ditemukan")
+		return
+	}
+	c.JSON(http.StatusOK, row)
+}
+
+// DeleteWebhookSubscription DELETE /api/v1/webhooks/subscriptions/:id
+func DeleteWebhookSubscription(c *gin.Context) {
+	id, ok := webhookIDParam(c)
+	if !ok {
+		return
+	}
+	if err := services.NewWebhookService(database.DBPNBP).DeleteSubscription(id); err != nil {
+		webhookError(c, err, "Subscription tidak ditemukan")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Subscription dihapus"})
+}
+
+// RotateWebhookSecret POST /api/v1/webhooks/subscriptions/:id/rotate-secret
+func RotateWebhookSecret(c *gin.Context) {
+	id, ok := webhookIDParam(c)
+	if !ok {
+		return
+	}
+	secret, err := services.NewWebhookService(database.DBPNBP).RotateSecret(id)
+	if err != nil {
+		webhookError(c, err, "Subscription tidak ditemukan")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"secret": secret})
+}
+
+// ReplayWebhookSubscription POST /api/v1/webhooks/subscriptions/:id/replay
+// Body: {"since": "2025-01-01T00:00:00Z", "until": "...", "event_type": "payment.paid"}; until default sekarang
+func ReplayWebhookSubscription(c *gin.Context) {
+	id, ok := webhookIDParam(c)
+	if !ok {
+		return
+	}
+	var req struct {
+		Since     time.Time  `json:"since" binding:"required"`
+		Until     *time.Time `json:"until"`
+		EventType string     `json:"event_type"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "since wajib diisi (RFC3339)"})
+		return
+	}
+	until := time.Now()
+	if req.Until != nil {
+		until = *req.Until
+	}
+
+	count, err := services.NewWebhookService(database.DBPNBP).ReplaySubscription(id, req.Since, until, req.EventType)
+	if err != nil {
+		webhookError(c, err, "Subscription tidak ditemukan")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"replayed": count})
+}
+
+// GetWebhookDeliveries GET /api/v1/webhooks/deliveries
+// Query params: subscription_id, status, event_type, event_id, limit
+func GetWebhookDeliveries(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if limit < 1 || limit > 200 {
+		limit = 50
+	}
+	subscriptionID, _ := strconv.ParseUint(c.Query("subscription_id"), 10, 64)
+
+	rows, err := services.NewWebhookService(database.DBPNBP).ListDeliveries(services.WebhookDeliveryFilter{
+		SubscriptionID: uint(subscriptionID),
+		Status:         c.Query("status"),
+		EventType:      c.Query("event_type"),
+		EventID:        c.Query("event_id"),
+		Limit:          limit,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengambil log webhook"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"deliveries": rows})
+}
+
+// ReplayWebhookDelivery POST /api/v1/webhooks/deliveries/:id/replay
+func ReplayWebhookDelivery(c *gin.Context) {
+	id, ok := webhookIDParam(c)
+	if !ok {
+		return
+	}
+	row, err := services.NewWebhookService(database.DBPNBP).Replay(id)
+	if err != nil {
+		webhookError(c, err, "Delivery tidak ditemukan")
+		return
+	}
+	c.JSON(http.StatusOK, row)
+}
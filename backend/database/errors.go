@@ -0,0 +1,22 @@
+package database
+
+import (
+	"errors"
+
+	"github.com/go-sql-driver/mysql"
+	"gorm.io/gorm"
+)
+
+// mysqlErrDuplicateEntry ER_DUP_ENTRY
+const mysqlErrDuplicateEntry = 1062
+
+// IsDuplicateKey true jika insert ditolak unique index. Dipakai untuk dedupe yang langsung
+// insert lalu menganggap duplikat sebagai "sudah ada", tanpa Count lebih dulu yang bisa balapan
+// antar replika.
+func IsDuplicateKey(err error) bool {
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return true
+	}
+	var mysqlErr *mysql.MySQLError
+	return errors.As(err, &mysqlErr) && mysqlErr.Number == mysqlErrDuplicateEntry
+}
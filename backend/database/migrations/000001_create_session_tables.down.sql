@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS user_recovery_codes;
+DROP TABLE IF EXISTS user_login_securities;
+DROP TABLE IF EXISTS auth_codes;
+DROP TABLE IF EXISTS user_tokens;
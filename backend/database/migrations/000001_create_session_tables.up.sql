@@ -0,0 +1,75 @@
+-- Sesi login (SSO & lokal), kode login sekali pakai dan keamanan login lokal staf.
+-- IF NOT EXISTS: database lama yang tabelnya sudah dibuat AutoMigrate cukup tercatat sebagai baseline.
+-- jwt_type VARCHAR, bukan ENUM Postgres: DBPNBP adalah MySQL (nilai: keycloak, internal).
+
+CREATE TABLE IF NOT EXISTS user_tokens (
+    id CHAR(36) NOT NULL,
+    session_id CHAR(36) NULL,
+    user_id CHAR(36) NULL,
+    subject VARCHAR(255) NULL,
+    email VARCHAR(150) NULL,
+    access_token_hash CHAR(64) NULL,
+    refresh_token_hash CHAR(64) NULL,
+    expires_at DATETIME(3) NULL,
+    refresh_expires_at DATETIME(3) NULL,
+    token_type VARCHAR(20) NULL,
+    jwt_type VARCHAR(20) NULL DEFAULT 'keycloak',
+    fingerprint VARCHAR(64) NULL,
+    user_agent TEXT NULL,
+    ip_address VARCHAR(45) NULL,
+    last_used_at DATETIME(3) NULL,
+    revoked_at DATETIME(3) NULL,
+    revoked_reason VARCHAR(20) NULL,
+    replaced_by_id CHAR(36) NULL,
+    created_at DATETIME(3) NULL,
+    PRIMARY KEY (id),
+    UNIQUE INDEX idx_user_tokens_access_token_hash (access_token_hash),
+    UNIQUE INDEX idx_user_tokens_refresh_token_hash (refresh_token_hash),
+    INDEX idx_user_tokens_session_id (session_id),
+    INDEX idx_user_tokens_user_id (user_id),
+    INDEX idx_user_tokens_subject (subject),
+    INDEX idx_user_tokens_email (email),
+    INDEX idx_user_tokens_revoked_at (revoked_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS auth_codes (
+    code_hash CHAR(64) NOT NULL,
+    session_id CHAR(36) NULL,
+    access_token TEXT NULL,
+    refresh_token TEXT NULL,
+    expires_at DATETIME(3) NULL,
+    used_at DATETIME(3) NULL,
+    created_at DATETIME(3) NULL,
+    token_expires_at DATETIME(3) NULL,
+    refresh_expires_at DATETIME(3) NULL,
+    PRIMARY KEY (code_hash),
+    INDEX idx_auth_codes_session_id (session_id),
+    INDEX idx_auth_codes_expires_at (expires_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS user_login_securities (
+    user_id CHAR(36) NOT NULL,
+    failed_attempts BIGINT NULL DEFAULT 0,
+    last_failed_at DATETIME(3) NULL,
+    locked_until DATETIME(3) NULL,
+    last_login_at DATETIME(3) NULL,
+    totp_secret TEXT NULL,
+    totp_pending_secret TEXT NULL,
+    totp_enabled_at DATETIME(3) NULL,
+    totp_last_step BIGINT NULL,
+    password_changed_at DATETIME(3) NULL,
+    created_at DATETIME(3) NULL,
+    updated_at DATETIME(3) NULL,
+    PRIMARY KEY (user_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS user_recovery_codes (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+    user_id CHAR(36) NULL,
+    code_hash CHAR(64) NULL,
+    used_at DATETIME(3) NULL,
+    created_at DATETIME(3) NULL,
+    PRIMARY KEY (id),
+    UNIQUE INDEX idx_user_recovery_codes_code_hash (code_hash),
+    INDEX idx_user_recovery_codes_user_id (user_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
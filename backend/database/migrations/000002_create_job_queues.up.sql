@@ -0,0 +1,15 @@
+-- Antrean job bersama untuk notifikasi dan webhook (services/worker_service.go)
+
+CREATE TABLE IF NOT EXISTS job_queues (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+    type TEXT NULL,
+    payload JSON NULL,
+    status TEXT NULL,
+    retries BIGINT NULL,
+    max_retries BIGINT NULL,
+    run_at DATETIME(3) NULL,
+    last_error LONGTEXT NULL,
+    created_at DATETIME(3) NULL,
+    updated_at DATETIME(3) NULL,
+    PRIMARY KEY (id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
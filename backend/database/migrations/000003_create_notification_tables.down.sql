@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS reminder_runs;
+DROP TABLE IF EXISTS notification_cursors;
+DROP TABLE IF EXISTS notification_deliveries;
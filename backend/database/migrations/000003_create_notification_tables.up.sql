@@ -0,0 +1,49 @@
+-- Notifikasi mahasiswa (email/WhatsApp), cursor watcher dan ringkasan job pengingat.
+-- notification_cursors juga dipakai watcher webhook.
+
+CREATE TABLE IF NOT EXISTS notification_deliveries (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+    event VARCHAR(50) NULL,
+    channel VARCHAR(20) NULL,
+    locale VARCHAR(5) NULL,
+    npm VARCHAR(50) NULL,
+    recipient VARCHAR(255) NULL,
+    subject VARCHAR(255) NULL,
+    body TEXT NULL,
+    dedupe_key VARCHAR(191) NULL,
+    status VARCHAR(20) NULL,
+    attempts BIGINT NULL,
+    max_attempts BIGINT NULL,
+    last_error TEXT NULL,
+    provider_message_id VARCHAR(191) NULL,
+    sent_at DATETIME(3) NULL,
+    created_at DATETIME(3) NULL,
+    updated_at DATETIME(3) NULL,
+    PRIMARY KEY (id),
+    UNIQUE INDEX idx_notification_deliveries_dedupe_key (dedupe_key),
+    INDEX idx_notification_deliveries_event (event),
+    INDEX idx_notification_deliveries_npm (npm),
+    INDEX idx_notification_deliveries_status (status)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS notification_cursors (
+    name VARCHAR(50) NOT NULL,
+    last_id BIGINT UNSIGNED NULL,
+    updated_at DATETIME(3) NULL,
+    PRIMARY KEY (name)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS reminder_runs (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+    started_at DATETIME(3) NULL,
+    finished_at DATETIME(3) NULL,
+    candidates BIGINT NULL,
+    sent BIGINT NULL,
+    deduplicated BIGINT NULL,
+    no_contact BIGINT NULL,
+    failed BIGINT NULL,
+    skipped_quiet_hours BOOLEAN NULL,
+    per_offset TEXT NULL,
+    error TEXT NULL,
+    PRIMARY KEY (id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
@@ -0,0 +1,43 @@
+-- Webhook keluar untuk sistem kampus lain; cursor watcher ada di notification_cursors (000003)
+
+CREATE TABLE IF NOT EXISTS webhook_subscriptions (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+    name VARCHAR(100) NULL,
+    url VARCHAR(500) NULL,
+    secret VARCHAR(255) NULL,
+    event_types VARCHAR(500) NULL,
+    active BOOLEAN NULL DEFAULT TRUE,
+    max_attempts BIGINT NULL,
+    created_by VARCHAR(255) NULL,
+    created_at DATETIME(3) NULL,
+    updated_at DATETIME(3) NULL,
+    PRIMARY KEY (id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+    subscription_id BIGINT UNSIGNED NULL,
+    event_id VARCHAR(150) NULL,
+    event_type VARCHAR(50) NULL,
+    payload TEXT NULL,
+    dedupe_key VARCHAR(191) NULL,
+    replay_of BIGINT UNSIGNED NULL,
+    status VARCHAR(20) NULL,
+    attempts BIGINT NULL,
+    max_attempts BIGINT NULL,
+    next_attempt_at DATETIME(3) NULL,
+    response_status BIGINT NULL,
+    response_body TEXT NULL,
+    duration_ms BIGINT NULL,
+    last_error TEXT NULL,
+    delivered_at DATETIME(3) NULL,
+    created_at DATETIME(3) NULL,
+    updated_at DATETIME(3) NULL,
+    PRIMARY KEY (id),
+    UNIQUE INDEX idx_webhook_deliveries_dedupe_key (dedupe_key),
+    INDEX idx_webhook_deliveries_subscription_id (subscription_id),
+    INDEX idx_webhook_deliveries_event_id (event_id),
+    INDEX idx_webhook_deliveries_event_type (event_type),
+    INDEX idx_webhook_deliveries_status (status),
+    INDEX idx_webhook_deliveries_created_at (created_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
@@ -0,0 +1,22 @@
+-- Riwayat & progress export (xlsx/csv) per user
+
+CREATE TABLE IF NOT EXISTS export_jobs (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+    user_id VARCHAR(100) NULL,
+    user_email VARCHAR(255) NULL,
+    type VARCHAR(50) NULL,
+    format VARCHAR(10) NULL,
+    filters JSON NULL,
+    status VARCHAR(20) NULL,
+    total_rows BIGINT NULL,
+    processed_rows BIGINT NULL,
+    object_name VARCHAR(255) NULL,
+    error TEXT NULL,
+    started_at DATETIME(3) NULL,
+    finished_at DATETIME(3) NULL,
+    created_at DATETIME(3) NULL,
+    updated_at DATETIME(3) NULL,
+    PRIMARY KEY (id),
+    INDEX idx_export_jobs_user_id (user_id),
+    INDEX idx_export_jobs_status (status)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
@@ -0,0 +1,48 @@
+-- Import mutasi bank (MT940/CSV) dan hasil pencocokannya dengan invoice EPNBP.
+-- invoice_id hanya angka referensi, tanpa foreign key ke tabel invoices milik EPNBP.
+
+CREATE TABLE IF NOT EXISTS bank_statement_imports (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+    filename VARCHAR(255) NULL,
+    format VARCHAR(10) NULL,
+    object_name VARCHAR(255) NULL,
+    uploaded_by VARCHAR(255) NULL,
+    total_credits BIGINT NULL,
+    total_amount BIGINT NULL,
+    matched_count BIGINT NULL,
+    proposed_count BIGINT NULL,
+    mismatch_count BIGINT NULL,
+    unmatched_count BIGINT NULL,
+    duplicate_count BIGINT NULL,
+    created_at DATETIME(3) NULL,
+    updated_at DATETIME(3) NULL,
+    PRIMARY KEY (id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS bank_statement_lines (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+    import_id BIGINT UNSIGNED NULL,
+    line_no BIGINT NULL,
+    value_date DATETIME(3) NULL,
+    amount BIGINT NULL,
+    bank_reference VARCHAR(100) NULL,
+    description TEXT NULL,
+    fingerprint VARCHAR(64) NULL,
+    match_status VARCHAR(20) NULL,
+    virtual_account VARCHAR(50) NULL,
+    invoice_id BIGINT UNSIGNED NULL,
+    invoice_status VARCHAR(50) NULL,
+    invoice_amount BIGINT NULL,
+    note VARCHAR(255) NULL,
+    proposal_status VARCHAR(20) NULL,
+    decided_by VARCHAR(255) NULL,
+    decided_at DATETIME(3) NULL,
+    created_at DATETIME(3) NULL,
+    updated_at DATETIME(3) NULL,
+    PRIMARY KEY (id),
+    INDEX idx_bank_statement_lines_import_id (import_id),
+    INDEX idx_bank_statement_lines_fingerprint (fingerprint),
+    INDEX idx_bank_statement_lines_match_status (match_status),
+    INDEX idx_bank_statement_lines_virtual_account (virtual_account),
+    INDEX idx_bank_statement_lines_invoice_id (invoice_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
@@ -0,0 +1,21 @@
+-- Audit staf helpdesk yang melihat data sebagai mahasiswa (termasuk request yang diblokir)
+
+CREATE TABLE IF NOT EXISTS impersonation_logs (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+    staff_email VARCHAR(150) NULL,
+    staff_sso_id VARCHAR(255) NULL,
+    student_id VARCHAR(50) NULL,
+    reason VARCHAR(255) NULL,
+    method VARCHAR(10) NULL,
+    path VARCHAR(255) NULL,
+    query TEXT NULL,
+    status BIGINT NULL,
+    blocked BOOLEAN NULL,
+    ip_address VARCHAR(45) NULL,
+    user_agent VARCHAR(255) NULL,
+    created_at DATETIME(3) NULL,
+    PRIMARY KEY (id),
+    INDEX idx_impersonation_logs_staff_email (staff_email),
+    INDEX idx_impersonation_logs_student_id (student_id),
+    INDEX idx_impersonation_logs_created_at (created_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
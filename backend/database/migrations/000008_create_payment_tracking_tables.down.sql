@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS payment_status_logs;
+DROP TABLE IF EXISTS payment_callbacks;
+DROP TABLE IF EXISTS payment_confirmations;
+DROP TABLE IF EXISTS pay_urls;
@@ -0,0 +1,66 @@
+-- Jejak pembayaran yang ditulis backend ini (dulu models.MigrateEpnbp): URL bayar per tagihan,
+-- konfirmasi manual, callback payment gateway dan log perubahan status pembayaran.
+-- student_bill_id / invoice_id hanya referensi ke tabel EPNBP, tanpa foreign key.
+
+CREATE TABLE IF NOT EXISTS pay_urls (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+    student_bill_id BIGINT UNSIGNED NULL,
+    pay_url LONGTEXT NULL,
+    invoice_id BIGINT UNSIGNED NULL,
+    nominal BIGINT UNSIGNED NULL,
+    expired_at DATETIME(3) NULL,
+    created_at DATETIME(3) NULL,
+    updated_at DATETIME(3) NULL,
+    PRIMARY KEY (id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS payment_confirmations (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+    student_bill_id BIGINT UNSIGNED NULL,
+    va_number LONGTEXT NULL,
+    payment_date LONGTEXT NULL,
+    object_name LONGTEXT NULL,
+    message TEXT NULL,
+    created_at DATETIME(3) NULL,
+    updated_at DATETIME(3) NULL,
+    PRIMARY KEY (id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS payment_callbacks (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+    student_bill_id BIGINT UNSIGNED NULL,
+    status LONGTEXT NULL,
+    try_count BIGINT UNSIGNED NULL DEFAULT 0,
+    request JSON NULL,
+    response JSON NULL,
+    response_from JSON NULL,
+    last_error TEXT NULL,
+    created_at DATETIME(3) NULL,
+    updated_at DATETIME(3) NULL,
+    last_updated_at DATETIME(3) NULL,
+    PRIMARY KEY (id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS payment_status_logs (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+    student_bill_id BIGINT UNSIGNED NULL,
+    student_id VARCHAR(20) NULL,
+    old_status VARCHAR(50) NULL,
+    new_status VARCHAR(50) NULL,
+    old_paid_amount BIGINT NULL,
+    new_paid_amount BIGINT NULL,
+    amount BIGINT NULL,
+    payment_date DATETIME(3) NULL,
+    invoice_id BIGINT UNSIGNED NULL,
+    virtual_account VARCHAR(50) NULL,
+    identifier VARCHAR(50) NULL,
+    time_difference BIGINT NULL,
+    source VARCHAR(50) NULL DEFAULT 'identifier_worker',
+    message TEXT NULL,
+    created_at DATETIME(3) NULL,
+    updated_at DATETIME(3) NULL,
+    PRIMARY KEY (id),
+    INDEX idx_payment_status_logs_student_bill_id (student_bill_id),
+    INDEX idx_payment_status_logs_student_id (student_id),
+    INDEX idx_payment_status_logs_identifier (identifier)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
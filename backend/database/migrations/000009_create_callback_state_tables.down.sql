@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS sintesys_callbacks;
+DROP TABLE IF EXISTS back_states;
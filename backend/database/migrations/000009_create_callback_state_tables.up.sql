@@ -0,0 +1,17 @@
+-- Tabel lama callback ke Sintesys (dulu models.MigrateBackState dan models.MigrateSintesys)
+
+CREATE TABLE IF NOT EXISTS back_states (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+    back_state LONGTEXT NULL,
+    created_at DATETIME(3) NULL,
+    updated_at DATETIME(3) NULL,
+    PRIMARY KEY (id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS sintesys_callbacks (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+    url LONGTEXT NULL,
+    data LONGTEXT NULL,
+    response LONGTEXT NULL,
+    PRIMARY KEY (id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
@@ -0,0 +1,176 @@
+package migrations
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// Migrasi awal memakai CREATE TABLE IF NOT EXISTS supaya database lama yang tabelnya sudah dibuat
+// AutoMigrate bisa langsung diadopsi. Tabel yang sudah ada tidak dibuat ulang, jadi sebelum
+// migrasi dijalankan & dicatat, kolom dan unique index tabel tersebut dicocokkan dengan definisi
+// di file migrasi; jika kurang, migrasi ditolak dan tabel harus disesuaikan manual.
+
+var (
+	// Grup 1 nama tabel, grup 2 isi definisi di antara kurung pertama dan kurung terakhir
+	createTableIfNotExistsPattern = regexp.MustCompile(`(?is)^create\s+table\s+if\s+not\s+exists\s+([^\s(;,]+)\s*\((.*)\)[^)]*$`)
+	constraintPrefixPattern       = regexp.MustCompile(`(?is)^constraint\s+(?:[^\s(]+\s+)?`)
+	uniqueKeyPattern              = regexp.MustCompile(`(?is)^(?:primary\s+key|unique(?:\s+(?:index|key))?(?:\s+[^\s(]+)?)\s*\((.*)\)`)
+	indexOnlyPattern              = regexp.MustCompile(`(?i)^(?:index|key|fulltext|spatial|foreign|check)\b`)
+)
+
+// tableDefinition kolom & unique index (termasuk primary key) yang didefinisikan CREATE TABLE
+type tableDefinition struct {
+	Table   string
+	Columns []string
+	Uniques []string // daftar kolom dipisah koma, mis. "user_id,code_hash"
+}
+
+// parseCreateTable membaca statement CREATE TABLE IF NOT EXISTS; ok false untuk statement lain
+func parseCreateTable(statement string) (tableDefinition, bool) {
+	match := createTableIfNotExistsPattern.FindStringSubmatch(strings.TrimSpace(statement))
+	if match == nil {
+		return tableDefinition{}, false
+	}
+
+	definition := tableDefinition{Table: unquoteIdentifier(match[1])}
+	for _, item := range splitTopLevel(match[2]) {
+		item = constraintPrefixPattern.ReplaceAllString(item, "")
+		if unique := uniqueKeyPattern.FindStringSubmatch(item); unique != nil {
+			definition.Uniques = append(definition.Uniques, keyColumns(unique[1]))
+			continue
+		}
+		if indexOnlyPattern.MatchString(item) {
+			continue
+		}
+		if fields := strings.Fields(item); len(fields) > 0 {
+			definition.Columns = append(definition.Columns, unquoteIdentifier(fields[0]))
+		}
+	}
+	return definition, true
+}
+
+// splitTopLevel memecah definisi per koma di luar kurung dan string, mis. DECIMAL(15,2) tetap utuh
+func splitTopLevel(body string) []string {
+	var items []string
+	var current strings.Builder
+	var quote rune
+	depth := 0
+
+	flush := func() {
+		if item := strings.TrimSpace(current.String()); item != "" {
+			items = append(items, item)
+		}
+		current.Reset()
+	}
+
+	for _, r := range body {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '\'' || r == '"' || r == '`':
+			quote = r
+		case r == '(':
+			depth++
+		case r == ')':
+			depth--
+		case r == ',' && depth == 0:
+			flush()
+			continue
+		}
+		current.WriteRune(r)
+	}
+	flush()
+	return items
+}
+
+// keyColumns menormalkan daftar kolom index: tanpa backtick, panjang prefix dan ASC/DESC
+func keyColumns(list string) string {
+	var columns []string
+	for _, part := range splitTopLevel(list) {
+		name := strings.Fields(part)[0]
+		if i := strings.Index(name, "("); i >= 0 {
+			name = name[:i]
+		}
+		columns = append(columns, unquoteIdentifier(name))
+	}
+	return strings.Join(columns, ",")
+}
+
+func unquoteIdentifier(name string) string {
+	return strings.ToLower(strings.Trim(name, "`"))
+}
+
+// checkExistingTables menolak migrasi yang CREATE TABLE IF NOT EXISTS-nya akan dilewati MySQL
+// karena tabelnya sudah ada, sementara kolom atau unique index tabel itu kurang dari definisi
+// migrasi (mis. pay_urls / back_states lama hasil AutoMigrate). Tipe kolom tidak dibandingkan.
+func checkExistingTables(conn *gorm.DB, migration Migration) error {
+	var problems []string
+	for _, statement := range splitStatements(migration.Up) {
+		definition, ok := parseCreateTable(statement)
+		if !ok || !conn.Migrator().HasTable(definition.Table) {
+			continue
+		}
+
+		var existingColumns []string
+		err := conn.Raw(`SELECT LOWER(column_name) FROM information_schema.columns
+			WHERE table_schema = DATABASE() AND table_name = ?`, definition.Table).
+			Scan(&existingColumns).Error
+		if err != nil {
+			return fmt.Errorf("gagal membaca kolom %s: %w", definition.Table, err)
+		}
+		hasColumn := map[string]bool{}
+		for _, column := range existingColumns {
+			hasColumn[column] = true
+		}
+
+		var indexColumns []struct {
+			IndexName  string
+			ColumnName string
+		}
+		err = conn.Raw(`SELECT index_name AS index_name, LOWER(column_name) AS column_name
+			FROM information_schema.statistics
+			WHERE table_schema = DATABASE() AND table_name = ? AND non_unique = 0
+			ORDER BY index_name, seq_in_index`, definition.Table).
+			Scan(&indexColumns).Error
+		if err != nil {
+			return fmt.Errorf("gagal membaca index %s: %w", definition.Table, err)
+		}
+		uniqueIndexes := map[string][]string{}
+		for _, row := range indexColumns {
+			uniqueIndexes[row.IndexName] = append(uniqueIndexes[row.IndexName], row.ColumnName)
+		}
+		hasUnique := map[string]bool{}
+		for _, columns := range uniqueIndexes {
+			hasUnique[strings.Join(columns, ",")] = true
+		}
+
+		var missing []string
+		for _, column := range definition.Columns {
+			if !hasColumn[column] {
+				missing = append(missing, "kolom "+column)
+			}
+		}
+		for _, unique := range definition.Uniques {
+			if !hasUnique[unique] {
+				missing = append(missing, "unique index ("+unique+")")
+			}
+		}
+		if len(missing) > 0 {
+			sort.Strings(missing)
+			problems = append(problems, fmt.Sprintf("%s tidak punya %s", definition.Table, strings.Join(missing, ", ")))
+		}
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("tabel lama tidak sesuai definisi migrasi: %s; sesuaikan tabelnya secara manual "+
+			"(ALTER TABLE sesuai file %s.up.sql, atau rename/hapus tabel kosong) lalu jalankan ulang migrate up",
+			strings.Join(problems, "; "), migration.ID())
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+package migrations
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseCreateTable(t *testing.T) {
+	tests := []struct {
+		name      string
+		statement string
+		want      tableDefinition
+		ok        bool
+	}{
+		{
+			name: "kolom, primary key dan unique index",
+			statement: "CREATE TABLE IF NOT EXISTS `webhook_deliveries` (\n" +
+				"    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,\n" +
+				"    `dedupe_key` VARCHAR(191) NOT NULL,\n" +
+				"    amount DECIMAL(15,2) NULL DEFAULT '0,00',\n" +
+				"    PRIMARY KEY (id),\n" +
+				"    UNIQUE INDEX idx_webhook_deliveries_dedupe_key (dedupe_key),\n" +
+				"    INDEX idx_webhook_deliveries_status (status, next_attempt_at)\n" +
+				") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4",
+			want: tableDefinition{
+				Table:   "webhook_deliveries",
+				Columns: []string{"id", "dedupe_key", "amount"},
+				Uniques: []string{"id", "dedupe_key"},
+			},
+			ok: true,
+		},
+		{
+			name: "unique komposit dengan constraint, prefix dan urutan",
+			statement: "create table if not exists user_recovery_codes (\n" +
+				"    user_id CHAR(36) NOT NULL,\n" +
+				"    code_hash CHAR(64) NOT NULL,\n" +
+				"    key_label VARCHAR(20) NULL,\n" +
+				"    CONSTRAINT uq_recovery UNIQUE KEY (`user_id`, code_hash(32) DESC),\n" +
+				"    KEY idx_key_label (key_label)\n" +
+				")",
+			want: tableDefinition{
+				Table:   "user_recovery_codes",
+				Columns: []string{"user_id", "code_hash", "key_label"},
+				Uniques: []string{"user_id,code_hash"},
+			},
+			ok: true,
+		},
+		{
+			name:      "create table tanpa if not exists",
+			statement: "CREATE TABLE job_queues (id BIGINT NOT NULL, PRIMARY KEY (id))",
+			ok:        false,
+		},
+		{
+			name:      "bukan create table",
+			statement: "ALTER TABLE job_queues ADD COLUMN priority INT NULL",
+			ok:        false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := parseCreateTable(tt.statement)
+			if ok != tt.ok {
+				t.Fatalf("ok = %v, want %v", ok, tt.ok)
+			}
+			if ok && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseCreateTable() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseCreateTableEmbeddedMigrations(t *testing.T) {
+	migrations, err := Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, migration := range migrations {
+		for _, statement := range splitStatements(migration.Up) {
+			definition, ok := parseCreateTable(statement)
+			if !ok {
+				continue
+			}
+			if len(definition.Columns) == 0 || len(definition.Uniques) == 0 {
+				t.Errorf("%s: %s tanpa kolom / primary key: %+v", migration.ID(), definition.Table, definition)
+			}
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package migrations
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+var migrationNamePattern = regexp.MustCompile(`^[a-z0-9_]+$`)
+
+// Create membuat pasangan file up/down dengan versi berikutnya di dir (folder source, bukan
+// hasil embed). File baru ikut ter-embed setelah binary di-build ulang.
+func Create(dir, name string) (upPath, downPath string, err error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if !migrationNamePattern.MatchString(name) {
+		return "", "", fmt.Errorf("nama migrasi hanya boleh huruf kecil, angka dan underscore: %q", name)
+	}
+
+	existing, err := load(os.DirFS(dir))
+	if err != nil {
+		return "", "", err
+	}
+	version := uint64(1)
+	if len(existing) > 0 {
+		version = existing[len(existing)-1].Version + 1
+	}
+
+	base := Migration{Version: version, Name: name}.ID()
+	upPath = filepath.Join(dir, base+".up.sql")
+	downPath = filepath.Join(dir, base+".down.sql")
+
+	header := "-- Hanya tabel di OwnedTables (ownership.go) yang boleh disentuh; tabel connector Laravel tidak pernah diubah.\n"
+	if err := writeNew(upPath, header); err != nil {
+		return "", "", err
+	}
+	if err := writeNew(downPath, header); err != nil {
+		os.Remove(upPath)
+		return "", "", err
+	}
+	return upPath, downPath, nil
+}
+
+func writeNew(path, content string) error {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	_, err = file.WriteString(content)
+	return err
+}
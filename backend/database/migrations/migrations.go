@@ -0,0 +1,168 @@
+// Package migrations mengelola skema tabel milik backend ini lewat file SQL berversi.
+//
+// Nama file mengikuti format golang-migrate yang dipakai backend2 (000001_nama.up.sql dan
+// 000001_nama.down.sql) dan di-embed ke binary. Versi yang sudah diterapkan dicatat di
+// schema_migrations beserta checksum isi filenya, sehingga file yang diubah setelah diterapkan
+// terdeteksi sebagai drift. Tabel milik connector Laravel / aplikasi EPNBP tidak boleh disentuh
+// dari sini (lihat ownership.go).
+package migrations
+
+import (
+	"crypto/sha256"
+	"embed"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+//go:embed *.sql
+var files embed.FS
+
+// Migration satu pasangan file up/down
+type Migration struct {
+	Version  uint64
+	Name     string
+	Up       string
+	Down     string
+	Checksum string // SHA-256 dari isi up + down
+}
+
+// ID versi + nama seperti nama filenya, mis. "000001_create_session_tables"
+func (m Migration) ID() string {
+	return fmt.Sprintf("%06d_%s", m.Version, m.Name)
+}
+
+var fileNamePattern = regexp.MustCompile(`^(\d+)_([a-z0-9_]+)\.(up|down)\.sql$`)
+
+// Load membaca migrasi yang di-embed ke binary, terurut berdasarkan versi
+func Load() ([]Migration, error) {
+	return load(files)
+}
+
+func load(fsys fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, fmt.Errorf("gagal membaca folder migrasi: %w", err)
+	}
+
+	byVersion := map[uint64]*Migration{}
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".sql") {
+			continue
+		}
+		match := fileNamePattern.FindStringSubmatch(entry.Name())
+		if match == nil {
+			return nil, fmt.Errorf("nama file migrasi tidak valid: %s (format: 000001_nama.up.sql)", entry.Name())
+		}
+		version, err := strconv.ParseUint(match[1], 10, 64)
+		if err != nil || version == 0 {
+			return nil, fmt.Errorf("versi migrasi tidak valid: %s", entry.Name())
+		}
+		content, err := fs.ReadFile(fsys, entry.Name())
+		if err != nil {
+			return nil, fmt.Errorf("gagal membaca %s: %w", entry.Name(), err)
+		}
+
+		migration, ok := byVersion[version]
+		if !ok {
+			migration = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = migration
+		}
+		if migration.Name != match[2] {
+			return nil, fmt.Errorf("versi %06d dipakai dua migrasi: %s dan %s", version, migration.Name, match[2])
+		}
+		if match[3] == "up" {
+			migration.Up = string(content)
+		} else {
+			migration.Down = string(content)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		if migration.Up == "" || migration.Down == "" {
+			return nil, fmt.Errorf("migrasi %s harus punya file .up.sql dan .down.sql yang tidak kosong", migration.ID())
+		}
+		if err := checkOwnership(*migration); err != nil {
+			return nil, err
+		}
+		sum := sha256.Sum256([]byte(migration.Up + "\x00" + migration.Down))
+		migration.Checksum = hex.EncodeToString(sum[:])
+		migrations = append(migrations, *migration)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	if err := checkOwnedTablesCreated(migrations); err != nil {
+		return nil, err
+	}
+	return migrations, nil
+}
+
+// splitStatements memecah isi file menjadi statement per ";" di luar string dan komentar.
+// Driver MySQL tidak menjalankan multi-statement tanpa multiStatements=true di DSN.
+func splitStatements(sql string) []string {
+	var statements []string
+	var current strings.Builder
+	var quote rune
+	lineComment, blockComment := false, false
+
+	flush := func() {
+		if statement := strings.TrimSpace(current.String()); statement != "" {
+			statements = append(statements, statement)
+		}
+		current.Reset()
+	}
+
+	runes := []rune(sql)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		next := rune(0)
+		if i+1 < len(runes) {
+			next = runes[i+1]
+		}
+
+		switch {
+		case lineComment:
+			if r == '\n' {
+				lineComment = false
+				current.WriteRune(r)
+			}
+			continue
+		case blockComment:
+			if r == '*' && next == '/' {
+				blockComment = false
+				i++
+			}
+			continue
+		case quote != 0:
+			current.WriteRune(r)
+			if r == '\\' && quote != '`' && next != 0 {
+				current.WriteRune(next)
+				i++
+			} else if r == quote {
+				quote = 0
+			}
+			continue
+		}
+
+		switch {
+		case r == '-' && next == '-', r == '#':
+			lineComment = true
+		case r == '/' && next == '*':
+			blockComment = true
+			i++
+		case r == '\'' || r == '"' || r == '`':
+			quote = r
+			current.WriteRune(r)
+		case r == ';':
+			flush()
+		default:
+			current.WriteRune(r)
+		}
+	}
+	flush()
+	return statements
+}
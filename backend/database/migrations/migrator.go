@@ -0,0 +1,243 @@
+package migrations
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// Terpisah dari tabel "migrations" milik Laravel di database yang sama
+	schemaTable = "schema_migrations"
+	// Advisory lock MySQL supaya beberapa replika yang start bersamaan tidak migrasi paralel
+	lockName           = "epnbp_backend_schema_migrations"
+	lockTimeoutSeconds = 60
+)
+
+// Status satu migrasi
+const (
+	StatePending  = "pending"
+	StateApplied  = "applied"
+	StateModified = "modified" // Sudah diterapkan tapi isi filenya berubah (checksum beda)
+	StateMissing  = "missing"  // Tercatat di schema_migrations tapi filenya tidak ada
+)
+
+// ErrDrift file migrasi yang sudah diterapkan berubah atau hilang; up/down ditolak sampai diperbaiki
+var ErrDrift = errors.New("skema database tidak sesuai dengan file migrasi")
+
+type schemaMigration struct {
+	Version   uint64    `gorm:"column:version;primaryKey"`
+	Name      string    `gorm:"column:name"`
+	Checksum  string    `gorm:"column:checksum"`
+	AppliedAt time.Time `gorm:"column:applied_at"`
+}
+
+func (schemaMigration) TableName() string {
+	return schemaTable
+}
+
+// MigrationStatus baris hasil Status
+type MigrationStatus struct {
+	Version   uint64
+	Name      string
+	State     string
+	AppliedAt *time.Time
+}
+
+type Migrator struct {
+	db         *gorm.DB
+	migrations []Migration
+}
+
+func New(db *gorm.DB) (*Migrator, error) {
+	migrations, err := Load()
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{db: db, migrations: migrations}, nil
+}
+
+// Status semua migrasi (file + yang tercatat di database), terurut berdasarkan versi
+func (m *Migrator) Status() ([]MigrationStatus, error) {
+	applied, err := m.applied(m.db)
+	if err != nil {
+		return nil, err
+	}
+	return m.status(applied), nil
+}
+
+// Up menerapkan migrasi yang belum diterapkan; steps <= 0 berarti semua.
+// DDL MySQL tidak transaksional: jika satu statement gagal, statement sebelumnya di file yang
+// sama tetap berlaku dan versinya tidak dicatat, jadi perbaiki manual sebelum menjalankan ulang.
+// Tabel yang sudah ada sebelum migrasi pembuatnya dicek dulu kolomnya (lihat checkExistingTables).
+func (m *Migrator) Up(steps int) ([]Migration, error) {
+	var done []Migration
+	err := m.withLock(func(conn *gorm.DB) error {
+		applied, err := m.prepare(conn)
+		if err != nil {
+			return err
+		}
+
+		for _, migration := range m.migrations {
+			if steps > 0 && len(done) >= steps {
+				break
+			}
+			if _, ok := applied[migration.Version]; ok {
+				continue
+			}
+			if err := checkExistingTables(conn, migration); err != nil {
+				return fmt.Errorf("migrasi %s ditolak: %w", migration.ID(), err)
+			}
+			if err := execute(conn, migration.Up); err != nil {
+				return fmt.Errorf("migrasi %s gagal: %w", migration.ID(), err)
+			}
+			row := schemaMigration{
+				Version:   migration.Version,
+				Name:      migration.Name,
+				Checksum:  migration.Checksum,
+				AppliedAt: time.Now(),
+			}
+			if err := conn.Create(&row).Error; err != nil {
+				return fmt.Errorf("migrasi %s sudah dijalankan tapi gagal dicatat: %w", migration.ID(), err)
+			}
+			done = append(done, migration)
+		}
+		return nil
+	})
+	return done, err
+}
+
+// Down membatalkan migrasi terakhir yang diterapkan; steps <= 0 berarti satu migrasi
+func (m *Migrator) Down(steps int) ([]Migration, error) {
+	if steps <= 0 {
+		steps = 1
+	}
+
+	var done []Migration
+	err := m.withLock(func(conn *gorm.DB) error {
+		applied, err := m.prepare(conn)
+		if err != nil {
+			return err
+		}
+
+		for i := len(m.migrations) - 1; i >= 0 && len(done) < steps; i-- {
+			migration := m.migrations[i]
+			if _, ok := applied[migration.Version]; !ok {
+				continue
+			}
+			if err := execute(conn, migration.Down); err != nil {
+				return fmt.Errorf("rollback %s gagal: %w", migration.ID(), err)
+			}
+			if err := conn.Delete(&schemaMigration{}, "version = ?", migration.Version).Error; err != nil {
+				return fmt.Errorf("rollback %s sudah dijalankan tapi gagal dicatat: %w", migration.ID(), err)
+			}
+			done = append(done, migration)
+		}
+		return nil
+	})
+	return done, err
+}
+
+// prepare membuat schema_migrations jika belum ada lalu menolak lanjut bila ada drift
+func (m *Migrator) prepare(conn *gorm.DB) (map[uint64]schemaMigration, error) {
+	err := conn.Exec(`CREATE TABLE IF NOT EXISTS ` + schemaTable + ` (
+    version BIGINT UNSIGNED NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    checksum CHAR(64) NOT NULL,
+    applied_at DATETIME(3) NOT NULL,
+    PRIMARY KEY (version)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4`).Error
+	if err != nil {
+		return nil, fmt.Errorf("gagal membuat tabel %s: %w", schemaTable, err)
+	}
+
+	applied, err := m.applied(conn)
+	if err != nil {
+		return nil, err
+	}
+
+	var drifted []string
+	for _, status := range m.status(applied) {
+		if status.State == StateModified || status.State == StateMissing {
+			drifted = append(drifted, fmt.Sprintf("%06d_%s (%s)", status.Version, status.Name, status.State))
+		}
+	}
+	if len(drifted) > 0 {
+		return nil, fmt.Errorf("%w: %s", ErrDrift, strings.Join(drifted, ", "))
+	}
+	return applied, nil
+}
+
+func (m *Migrator) applied(conn *gorm.DB) (map[uint64]schemaMigration, error) {
+	applied := map[uint64]schemaMigration{}
+	if !conn.Migrator().HasTable(schemaTable) {
+		return applied, nil
+	}
+
+	var rows []schemaMigration
+	if err := conn.Order("version").Find(&rows).Error; err != nil {
+		return nil, fmt.Errorf("gagal membaca %s: %w", schemaTable, err)
+	}
+	for _, row := range rows {
+		applied[row.Version] = row
+	}
+	return applied, nil
+}
+
+func (m *Migrator) status(applied map[uint64]schemaMigration) []MigrationStatus {
+	statuses := make([]MigrationStatus, 0, len(m.migrations))
+	known := map[uint64]bool{}
+	for _, migration := range m.migrations {
+		known[migration.Version] = true
+		status := MigrationStatus{Version: migration.Version, Name: migration.Name, State: StatePending}
+		if row, ok := applied[migration.Version]; ok {
+			appliedAt := row.AppliedAt
+			status.AppliedAt = &appliedAt
+			status.State = StateApplied
+			if row.Checksum != migration.Checksum {
+				status.State = StateModified
+			}
+		}
+		statuses = append(statuses, status)
+	}
+
+	for version, row := range applied {
+		if known[version] {
+			continue
+		}
+		appliedAt := row.AppliedAt
+		statuses = append(statuses, MigrationStatus{Version: version, Name: row.Name, State: StateMissing, AppliedAt: &appliedAt})
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Version < statuses[j].Version })
+	return statuses
+}
+
+// withLock menjalankan fn di satu koneksi yang memegang GET_LOCK (lock MySQL terikat ke koneksi)
+func (m *Migrator) withLock(fn func(conn *gorm.DB) error) error {
+	return m.db.Connection(func(conn *gorm.DB) error {
+		var locked sql.NullInt64
+		if err := conn.Raw("SELECT GET_LOCK(?, ?)", lockName, lockTimeoutSeconds).Scan(&locked).Error; err != nil {
+			return fmt.Errorf("gagal mengambil lock migrasi: %w", err)
+		}
+		if !locked.Valid || locked.Int64 != 1 {
+			return fmt.Errorf("lock migrasi dipegang proses lain lebih dari %d detik", lockTimeoutSeconds)
+		}
+		defer conn.Exec("SELECT RELEASE_LOCK(?)", lockName)
+
+		return fn(conn)
+	})
+}
+
+func execute(conn *gorm.DB, content string) error {
+	for _, statement := range splitStatements(content) {
+		if err := conn.Exec(statement).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
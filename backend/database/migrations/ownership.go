@@ -0,0 +1,206 @@
+package migrations
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// OwnedTables tabel yang skemanya dimiliki backend ini. Migrasi hanya boleh membuat, mengubah,
+// menghapus atau mengisi tabel di daftar ini; tambahkan tabel baru di sini bersamaan dengan
+// migrasi yang membuatnya (dicek saat migrasi dimuat, lihat checkOwnedTablesCreated).
+var OwnedTables = []string{
+	// Sesi login & keamanan login lokal
+	"user_tokens",
+	"auth_codes",
+	"user_login_securities",
+	"user_recovery_codes",
+
+	// Worker, notifikasi dan webhook
+	"job_queues",
+	"notification_deliveries",
+	"notification_cursors",
+	"reminder_runs",
+	"webhook_subscriptions",
+	"webhook_deliveries",
+
+	// Export, import mutasi bank, audit impersonasi
+	"export_jobs",
+	"bank_statement_imports",
+	"bank_statement_lines",
+	"impersonation_logs",
+
+	// Jejak pembayaran & callback yang ditulis backend ini
+	"pay_urls",
+	"payment_confirmations",
+	"payment_callbacks",
+	"payment_status_logs",
+	"back_states",
+	"sintesys_callbacks",
+}
+
+// ConnectorTables tabel milik connector Laravel (sinkronisasi SIMAK) dan aplikasi EPNBP.
+// Backend ini hanya membaca / mengisi datanya; skemanya diubah lewat migrasi Laravel, tidak
+// pernah dari sini. Daftar ini dipakai untuk pesan error yang jelas, bukan sebagai allow-list.
+var ConnectorTables = []string{
+	// Laravel: auth, cache, queue, riwayat migrasinya sendiri
+	"migrations", "users", "password_reset_tokens", "sessions", "cache", "cache_locks",
+	"jobs", "job_batches", "failed_jobs",
+	"roles", "permissions", "user_roles", "role_permissions",
+
+	// Data akademik hasil sinkronisasi SIMAK
+	"mahasiswa_masters", "mahasiswas", "prodi", "fakultas", "status_akademiks", "registrasi_mahasiswa",
+	"budget_periods", "bipot",
+
+	// Tagihan, beasiswa, cicilan dan deposit EPNBP
+	"finance_years", "bill_templates", "bill_template_items", "bill_discounts",
+	"student_bills", "student_bill_discounts", "student_payments", "student_payment_allocations",
+	"student_ukt_histories", "student_bill_installments", "student_bill_postponements",
+	"master_tagihan", "detail_tagihan", "cicilans", "detail_cicilans",
+	"beasiswa", "detail_beasiswa", "bantuan_ukt", "deposit_ledger_entries",
+
+	// Invoice & virtual account EPNBP
+	"invoices", "invoice_relations", "payments", "virtual_accounts", "customers",
+}
+
+var (
+	// Statement yang menulis / mengubah satu tabel; grup 1 adalah nama tabel pertama
+	writeStatementPattern = regexp.MustCompile(`(?is)^(?:create\s+(?:temporary\s+)?table(?:\s+if\s+not\s+exists)?|alter\s+(?:ignore\s+)?table|drop\s+(?:temporary\s+)?table(?:\s+if\s+exists)?|rename\s+table|truncate(?:\s+table)?|insert\s+(?:ignore\s+)?into|replace\s+into|update(?:\s+ignore)?|delete\s+from|create\s+(?:unique\s+|fulltext\s+|spatial\s+)?index\s+\S+\s+on|drop\s+index\s+\S+\s+on)\s+([^\s(;,]+)(.*)$`)
+	// Statement yang tidak mengubah tabel apa pun
+	harmlessStatementPattern = regexp.MustCompile(`(?is)^(?:set|select|do)\b`)
+	referencesPattern        = regexp.MustCompile(`(?i)\breferences\s+([^\s(]+)`)
+	renameTableTargetPattern = regexp.MustCompile(`(?i)\bto\s+([^\s,;]+)`)
+	alterRenameTargetPattern = regexp.MustCompile(`(?i)\brename\s+(?:to|as)\s+([^\s,;]+)`)
+	createTablePattern       = regexp.MustCompile(`(?is)^create\s+table(?:\s+if\s+not\s+exists)?\s+([^\s(;,]+)`)
+)
+
+// checkOwnership menolak migrasi yang menyentuh tabel di luar OwnedTables, termasuk foreign
+// key ke tabel connector (constraint seperti itu akan menghalangi migrasi Laravel).
+func checkOwnership(migration Migration) error {
+	owned := map[string]bool{}
+	for _, table := range OwnedTables {
+		owned[table] = true
+	}
+
+	for _, sql := range []string{migration.Up, migration.Down} {
+		for _, statement := range splitStatements(sql) {
+			tables, err := statementTables(statement)
+			if err != nil {
+				return fmt.Errorf("migrasi %s: %w", migration.ID(), err)
+			}
+			for _, table := range tables {
+				if !owned[table] {
+					return fmt.Errorf("migrasi %s menyentuh tabel %q yang %s", migration.ID(), table, ownerOf(table))
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// checkOwnedTablesCreated menolak tabel di OwnedTables yang tidak dibuat oleh migrasi mana pun.
+// Fitur yang menambah tabel wajib membawa migrasinya sendiri; tanpa ini aplikasi bisa start
+// dengan tabel yang tidak pernah dibuat dan baru gagal saat endpoint-nya dipanggil.
+func checkOwnedTablesCreated(migrations []Migration) error {
+	created := map[string]bool{}
+	for _, migration := range migrations {
+		for _, statement := range splitStatements(migration.Up) {
+			if match := createTablePattern.FindStringSubmatch(statement); match != nil {
+				created[normalizeTable(match[1])] = true
+			}
+		}
+	}
+	for _, table := range OwnedTables {
+		if !created[table] {
+			return fmt.Errorf("tabel %q terdaftar di OwnedTables tapi tidak dibuat oleh migrasi mana pun; tambahkan migrasinya (migrate create)", table)
+		}
+	}
+	return nil
+}
+
+// statementTables mengembalikan semua tabel yang ditulis / dirujuk oleh satu statement
+func statementTables(statement string) ([]string, error) {
+	statement = stripStringLiterals(statement)
+	if harmlessStatementPattern.MatchString(statement) {
+		return nil, nil
+	}
+	match := writeStatementPattern.FindStringSubmatch(statement)
+	if match == nil {
+		return nil, fmt.Errorf("jenis statement tidak dikenali pemeriksa kepemilikan tabel: %.60s", statement)
+	}
+
+	tables := []string{normalizeTable(match[1])}
+	rest := match[2]
+	lower := strings.ToLower(statement)
+	switch {
+	case strings.HasPrefix(lower, "drop"):
+		// DROP TABLE a, b
+		for _, name := range strings.Split(rest, ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				tables = append(tables, normalizeTable(strings.Fields(name)[0]))
+			}
+		}
+	case strings.HasPrefix(lower, "rename"):
+		// RENAME TABLE a TO b, c TO d
+		for _, target := range renameTableTargetPattern.FindAllStringSubmatch(rest, -1) {
+			tables = append(tables, normalizeTable(target[1]))
+		}
+		for _, source := range strings.Split(rest, ",")[1:] {
+			if fields := strings.Fields(source); len(fields) > 0 {
+				tables = append(tables, normalizeTable(fields[0]))
+			}
+		}
+	case strings.HasPrefix(lower, "alter"):
+		// ALTER TABLE a RENAME TO b
+		for _, target := range alterRenameTargetPattern.FindAllStringSubmatch(rest, -1) {
+			tables = append(tables, normalizeTable(target[1]))
+		}
+	}
+	for _, reference := range referencesPattern.FindAllStringSubmatch(rest, -1) {
+		tables = append(tables, normalizeTable(reference[1]))
+	}
+	return tables, nil
+}
+
+// stripStringLiterals mengosongkan isi literal '...' dan "..." (mis. COMMENT / DEFAULT) supaya
+// teks di dalamnya tidak terbaca sebagai nama tabel
+func stripStringLiterals(statement string) string {
+	var out strings.Builder
+	var quote rune
+	escaped := false
+	for _, r := range statement {
+		switch {
+		case quote == 0:
+			if r == '\'' || r == '"' {
+				quote = r
+			}
+			out.WriteRune(r)
+		case escaped:
+			escaped = false
+		case r == '\\':
+			escaped = true
+		case r == quote:
+			quote = 0
+			out.WriteRune(r)
+		}
+	}
+	return out.String()
+}
+
+// normalizeTable membuang backtick dan prefix nama database
+func normalizeTable(name string) string {
+	name = strings.ToLower(strings.ReplaceAll(name, "`", ""))
+	if idx := strings.LastIndex(name, "."); idx != -1 {
+		name = name[idx+1:]
+	}
+	return name
+}
+
+func ownerOf(table string) string {
+	for _, connector := range ConnectorTables {
+		if connector == table {
+			return "dimiliki connector Laravel / EPNBP dan tidak boleh diubah dari backend ini"
+		}
+	}
+	return "tidak terdaftar di OwnedTables (database/migrations/ownership.go)"
+}
@@ -1,7 +1,10 @@
 package database
 
 import (
+	"context"
 	"fmt"
+	"github.com/dedegunawan/backend-ujian-telp-v5/metrics"
+	"github.com/dedegunawan/backend-ujian-telp-v5/tracing"
 	"github.com/dedegunawan/backend-ujian-telp-v5/utils"
 	"os"
 
@@ -25,10 +28,28 @@ func ConnectDatabasePnbp() {
 		os.Getenv("EPNBP_DB_HOST"), os.Getenv("EPNBP_DB_PORT"),
 		os.Getenv("EPNBP_DB_NAME"), os.Getenv("EPNBP_DB_USER"))
 
-	dbpnbp, err := gorm.Open(mysql.Open(dsn), &gorm.Config{})
+	var dbpnbp *gorm.DB
+	err := utils.RetryStartup("Connect MySQL PNBP", utils.StartupTimeout(), func() error {
+		var err error
+		dbpnbp, err = gorm.Open(mysql.Open(dsn), &gorm.Config{})
+		return err
+	})
 	if err != nil {
-		utils.Log.Fatal("Failed to connect to MySQL database:", err)
+		utils.Log.Fatalw("Failed to connect to MySQL database", "error", err)
+	}
+
+	if err := dbpnbp.Use(metrics.NewGormPlugin("pnbp")); err != nil {
+		utils.Log.Warnw("Gagal memasang metrik query database", "error", err)
+	}
+	if err := dbpnbp.Use(tracing.NewGormPlugin("pnbp")); err != nil {
+		utils.Log.Warnw("Gagal memasang tracing query database", "error", err)
 	}
 
 	DBPNBP = dbpnbp
 }
+
+// WithContext DBPNBP yang membawa ctx request (span trace, pembatalan saat client putus).
+// Pakai di controller: database.WithContext(c.Request.Context()).
+func WithContext(ctx context.Context) *gorm.DB {
+	return DBPNBP.WithContext(ctx)
+}
@@ -0,0 +1,59 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"gorm.io/gorm"
+)
+
+// jobQueueCollector menghitung isi job_queues per status saat di-scrape
+type jobQueueCollector struct {
+	db    *gorm.DB
+	depth *prometheus.Desc
+}
+
+// RegisterJobQueue mendaftarkan gauge epnbp_job_queue_depth{status} dari tabel job_queues
+func RegisterJobQueue(db *gorm.DB) error {
+	return Registry.Register(&jobQueueCollector{
+		db: db,
+		depth: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "job_queue_depth"),
+			"Jumlah job di job_queues per status (queued, processing, failed).",
+			[]string{"status"}, nil,
+		),
+	})
+}
+
+func (c *jobQueueCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.depth
+}
+
+func (c *jobQueueCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	var rows []struct {
+		Status string
+		Total  int64
+	}
+	// Job "done" tidak dihitung: terus bertambah dan bukan antrian
+	err := c.db.WithContext(ctx).Table("job_queues").
+		Select("status, COUNT(*) AS total").
+		Where("status <> ?", "done").
+		Group("status").
+		Scan(&rows).Error
+	if err != nil {
+		ch <- prometheus.NewInvalidMetric(c.depth, err)
+		return
+	}
+
+	counts := map[string]int64{"queued": 0, "processing": 0, "failed": 0}
+	for _, row := range rows {
+		counts[row.Status] = row.Total
+	}
+	for status, total := range counts {
+		ch <- prometheus.MustNewConstMetric(c.depth, prometheus.GaugeValue, float64(total), status)
+	}
+}
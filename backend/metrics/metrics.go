@@ -0,0 +1,155 @@
+// Package metrics metrik Prometheus untuk HTTP, database, panggilan upstream, worker dan bisnis.
+// Package ini tidak bergantung ke utils/services agar bisa dipanggil dari mana saja tanpa import cycle.
+package metrics
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "epnbp"
+
+// Nama upstream untuk label "upstream"
+const (
+	UpstreamEpnbp    = "epnbp"
+	UpstreamSintesys = "sintesys"
+	UpstreamMinio    = "minio"
+)
+
+// Nilai label "outcome"
+const (
+	OutcomeSuccess = "success"
+	OutcomeError   = "error"
+)
+
+// Registry terpisah dari prometheus.DefaultRegisterer supaya isi /metrics terkendali
+var Registry = prometheus.NewRegistry()
+
+var (
+	HTTPRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "http_requests_total",
+		Help:      "Jumlah request HTTP per route, method dan status.",
+	}, []string{"method", "route", "status"})
+
+	HTTPDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "Latensi request HTTP per route, method dan status.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	DBQueryDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "db_query_duration_seconds",
+		Help:      "Durasi query GORM per database, tabel dan operasi.",
+		Buckets:   []float64{.001, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5},
+	}, []string{"database", "table", "operation", "outcome"})
+
+	UpstreamRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "upstream_requests_total",
+		Help:      "Jumlah panggilan ke sistem luar (EPNBP, Sintesys, MinIO).",
+	}, []string{"upstream", "operation", "outcome"})
+
+	UpstreamDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "upstream_request_duration_seconds",
+		Help:      "Latensi panggilan ke sistem luar (EPNBP, Sintesys, MinIO).",
+		Buckets:   []float64{.025, .05, .1, .25, .5, 1, 2.5, 5, 10, 30},
+	}, []string{"upstream", "operation", "outcome"})
+
+	WorkerRunDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "worker_run_duration_seconds",
+		Help:      "Durasi satu putaran worker/scheduler.",
+		Buckets:   []float64{.01, .05, .1, .5, 1, 5, 10, 30, 60, 300},
+	}, []string{"worker", "outcome"})
+
+	JobDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "job_duration_seconds",
+		Help:      "Durasi pemrosesan job_queues per tipe job.",
+		Buckets:   []float64{.01, .05, .1, .5, 1, 5, 10, 30},
+	}, []string{"type", "outcome"})
+
+	VAGenerations = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "va_generations_total",
+		Help:      "Jumlah pembuatan invoice/VA ke EPNBP.",
+	}, []string{"outcome"})
+
+	PaymentsProcessed = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "payments_processed_total",
+		Help:      "Jumlah pembayaran baru yang diproses (dipublikasikan ke mahasiswa).",
+	}, []string{"source"})
+
+	Callbacks = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "payment_callbacks_total",
+		Help:      "Jumlah callback pembayaran yang diterima atau ditolak.",
+	}, []string{"result", "reason"})
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		HTTPRequests, HTTPDuration,
+		DBQueryDuration,
+		UpstreamRequests, UpstreamDuration,
+		WorkerRunDuration, JobDuration,
+		VAGenerations, PaymentsProcessed, Callbacks,
+	)
+}
+
+// Outcome label "success"/"error" dari err
+func Outcome(err error) string {
+	if err != nil {
+		return OutcomeError
+	}
+	return OutcomeSuccess
+}
+
+// ObserveUpstream catat satu panggilan ke sistem luar yang dimulai pada start
+func ObserveUpstream(upstream, operation string, start time.Time, err error) {
+	outcome := Outcome(err)
+	UpstreamRequests.WithLabelValues(upstream, operation, outcome).Inc()
+	UpstreamDuration.WithLabelValues(upstream, operation, outcome).Observe(time.Since(start).Seconds())
+}
+
+// ObserveWorkerRun catat satu putaran worker/scheduler yang dimulai pada start
+func ObserveWorkerRun(worker string, start time.Time, err error) {
+	WorkerRunDuration.WithLabelValues(worker, Outcome(err)).Observe(time.Since(start).Seconds())
+}
+
+// ObserveJob catat satu job dari job_queues yang dimulai pada start
+func ObserveJob(jobType string, start time.Time, err error) {
+	JobDuration.WithLabelValues(jobType, Outcome(err)).Observe(time.Since(start).Seconds())
+}
+
+// Handler endpoint /metrics. Jika METRICS_TOKEN diisi, scraper wajib mengirim
+// "Authorization: Bearer <token>"; kosong berarti terbuka (batasi lewat jaringan).
+func Handler() http.Handler {
+	next := promhttp.HandlerFor(Registry, promhttp.HandlerOpts{})
+	token := os.Getenv("METRICS_TOKEN")
+	if token == "" {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		given := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if subtle.ConstantTimeCompare([]byte(given), []byte(token)) != 1 {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
@@ -7,6 +7,9 @@ import (
 	"strings"
 
 	"github.com/dedegunawan/backend-ujian-telp-v5/auth"
+	"github.com/dedegunawan/backend-ujian-telp-v5/config"
+	"github.com/dedegunawan/backend-ujian-telp-v5/database"
+	"github.com/dedegunawan/backend-ujian-telp-v5/services"
 	"github.com/dedegunawan/backend-ujian-telp-v5/utils"
 	"github.com/gin-gonic/gin"
 )
@@ -26,17 +29,25 @@ func RequireAuthFromTokenDB() gin.HandlerFunc {
 			return
 		}
 
-		// Verifikasi token langsung dari JWT - tidak perlu cek database (read-only)
-		// Coba verifikasi sebagai Keycloak token dulu (SSO)
-		claims, err := checkKeycloackToken(tokenStr)
+		claims, err := verifyAccessToken(c, tokenStr)
 		if err != nil {
-			// Jika bukan Keycloak token, coba sebagai internal JWT
-			claims, err = checkInternalToken(tokenStr)
-			if err != nil {
-				c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token"})
-				c.Abort()
-				return
-			}
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token"})
+			c.Abort()
+			return
+		}
+
+		// JWT masih valid tapi sesinya bisa saja tidak tercatat, sudah dicabut (logout) atau usernya dinonaktifkan
+		revoked, err := services.NewUserTokenService(database.WithContext(c.Request.Context()), nil).IsRevoked(tokenStr)
+		if err != nil {
+			utils.LogCtx(c.Request.Context()).Errorw("Auth middleware - Gagal cek sesi", "error", err)
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Tidak dapat memverifikasi sesi"})
+			c.Abort()
+			return
+		}
+		if revoked {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Session revoked"})
+			c.Abort()
+			return
 		}
 
 		// Tambahkan context dari claims
@@ -44,25 +55,58 @@ func RequireAuthFromTokenDB() gin.HandlerFunc {
 		c.Set("email", claims.Email)
 		c.Set("name", claims.Name)
 
-		// Log untuk debugging
-		utils.Log.Info("Auth middleware - Token verified", map[string]interface{}{
-			"sso_id": claims.Sub,
-			"email":  claims.Email,
-			"name":   claims.Name,
-		})
-
 		// Untuk internal JWT, user_id ada di Sub
 		if claims.Sub != "" {
 			c.Set("user_id", claims.Sub)
 		}
 
+		// Semua log berikutnya di request ini membawa user_id dan npm (utils.LogCtx)
+		logFields := []interface{}{"user_id", claims.Sub}
+		if claims.Email != "" && config.ValidateEmailSuffix(claims.Email) {
+			logFields = append(logFields, "npm", utils.GetEmailPrefix(claims.Email))
+		}
+		c.Request = c.Request.WithContext(utils.ContextWithLogFields(c.Request.Context(), logFields...))
+		utils.LogCtx(c.Request.Context()).Debugw("Auth middleware - Token verified")
+
+		// Staf helpdesk melihat sebagai mahasiswa: izin, read-only dan audit diurus serveImpersonated
+		if npm := strings.TrimSpace(c.GetHeader(ImpersonateHeader)); npm != "" {
+			serveImpersonated(c, npm)
+			return
+		}
+
 		c.Next()
 	}
 }
 
+// verifiedClaimsKey cache hasil verifikasi tanda tangan per request (dipakai RateLimit dan auth)
+const verifiedClaimsKey = "verified_token_claims"
+
+type verifiedClaims struct {
+	token  string
+	claims *Claims
+	err    error
+}
+
+// verifyAccessToken memverifikasi tanda tangan JWT (Keycloak dulu, lalu JWT internal) tanpa
+// cek database. Hasilnya di-cache di context supaya limiter global dan RequireAuthFromTokenDB
+// tidak memverifikasi token yang sama dua kali.
+func verifyAccessToken(c *gin.Context, tokenStr string) (*Claims, error) {
+	if cached, ok := c.Get(verifiedClaimsKey); ok {
+		if v, ok := cached.(verifiedClaims); ok && v.token == tokenStr {
+			return v.claims, v.err
+		}
+	}
+
+	claims, err := checkKeycloackToken(tokenStr)
+	if err != nil {
+		claims, err = checkInternalToken(tokenStr)
+	}
+	c.Set(verifiedClaimsKey, verifiedClaims{token: tokenStr, claims: claims, err: err})
+	return claims, err
+}
+
 func checkKeycloackToken(tokenStr string) (*Claims, error) {
 	idToken, err := auth.Verifier.Verify(context.Background(), tokenStr)
-	utils.Log.Info("token")
 	if err != nil {
 		return nil, errors.New("Invalid token")
 	}
@@ -70,7 +114,6 @@ func checkKeycloackToken(tokenStr string) (*Claims, error) {
 	// 2. Ambil SSO sub dari token (bisa juga name/email jika perlu)
 	var claims Claims
 	if err := idToken.Claims(&claims); err != nil {
-		utils.Log.Info("Invalid token")
 		return nil, errors.New("Invalid claims")
 	}
 	return &claims, nil
@@ -81,6 +124,10 @@ func checkInternalToken(tokenStr string) (*Claims, error) {
 	if err != nil {
 		return nil, err
 	}
+	if claims.TokenType != utils.TokenTypeAccess {
+		// Refresh token internal tidak boleh dipakai sebagai access token
+		return nil, errors.New("Invalid token")
+	}
 	return &Claims{
 		Sub:   claims.UserID.String(),
 		Email: claims.Email,
@@ -89,6 +136,11 @@ func checkInternalToken(tokenStr string) (*Claims, error) {
 
 }
 
+// ExtractAccessToken token dari header Authorization atau cookie access_token
+func ExtractAccessToken(c *gin.Context) string {
+	return extractAccessToken(c)
+}
+
 func extractAccessToken(c *gin.Context) string {
 	authHeader := c.GetHeader("Authorization")
 	if strings.HasPrefix(authHeader, "Bearer ") {
@@ -1,12 +1,35 @@
 package middleware
 
-import "github.com/gin-gonic/gin"
+import (
+	"os"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
 
 func LoadCors() gin.HandlerFunc {
+	// Cookie auth lintas origin butuh origin eksplisit + Allow-Credentials (tidak boleh "*")
+	allowedOrigins := map[string]bool{}
+	for _, origin := range strings.Split(os.Getenv("CORS_ALLOWED_ORIGINS"), ",") {
+		if origin = strings.TrimSpace(origin); origin != "" {
+			allowedOrigins[origin] = true
+		}
+	}
+
 	return func(c *gin.Context) {
-		c.Writer.Header().Set("Access-Control-Allow-Origin", "*")
+		origin := c.GetHeader("Origin")
+		if len(allowedOrigins) > 0 {
+			if allowedOrigins[origin] {
+				c.Writer.Header().Set("Access-Control-Allow-Origin", origin)
+				c.Writer.Header().Set("Access-Control-Allow-Credentials", "true")
+			}
+			c.Writer.Header().Add("Vary", "Origin")
+		} else {
+			c.Writer.Header().Set("Access-Control-Allow-Origin", "*")
+		}
 		c.Writer.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, x-app-id, x-app-key")
+		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, x-app-id, x-app-key, "+CSRFHeaderName+", "+ImpersonateHeader+", "+ImpersonateReasonHeader)
+		c.Writer.Header().Set("Access-Control-Expose-Headers", ImpersonatingHeader)
 
 		// Jika request OPTIONS, langsung balas 200
 		if c.Request.Method == "OPTIONS" {
@@ -0,0 +1,65 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	"github.com/dedegunawan/backend-ujian-telp-v5/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// Double-submit cookie: csrf_token (bisa dibaca JS) harus dikirim ulang di header X-CSRF-Token
+const (
+	CSRFCookieName = "csrf_token"
+	CSRFHeaderName = "X-CSRF-Token"
+)
+
+// Path yang tidak dicek CSRF: endpoint login/penukaran kode (belum ada csrf cookie) dan callback server-to-server
+var csrfExemptPaths = map[string]bool{
+	"/auth/exchange":           true,
+	"/login":                   true,
+	"/login/2fa":               true,
+	"/password/forgot":         true,
+	"/password/reset":          true,
+	"/api/v1/payment-callback": true,
+}
+
+// CSRFProtect menolak request yang mengubah state dan diautentikasi lewat cookie
+// tanpa header X-CSRF-Token yang sama dengan cookie csrf_token.
+// Request dengan header Authorization: Bearer tidak dicek (browser lain tidak bisa memalsukan header).
+func CSRFProtect() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		switch c.Request.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+			c.Next()
+			return
+		}
+		if csrfExemptPaths[c.Request.URL.Path] || strings.HasPrefix(c.GetHeader("Authorization"), "Bearer ") {
+			c.Next()
+			return
+		}
+
+		_, accessErr := c.Cookie("access_token")
+		_, refreshErr := c.Cookie("refresh_token")
+		if accessErr != nil && refreshErr != nil {
+			// Tidak memakai cookie auth
+			c.Next()
+			return
+		}
+
+		cookie, err := c.Cookie(CSRFCookieName)
+		header := c.GetHeader(CSRFHeaderName)
+		if err != nil || cookie == "" || subtle.ConstantTimeCompare([]byte(cookie), []byte(header)) != 1 {
+			utils.Log.Warnw("CSRF token tidak valid",
+				"path", c.Request.URL.Path,
+				"method", c.Request.Method,
+			)
+			c.JSON(http.StatusForbidden, gin.H{"error": "CSRF token tidak valid"})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,116 @@
+package middleware
+
+import (
+	"net/http"
+	"regexp"
+	"time"
+
+	"github.com/dedegunawan/backend-ujian-telp-v5/database"
+	"github.com/dedegunawan/backend-ujian-telp-v5/models"
+	"github.com/dedegunawan/backend-ujian-telp-v5/services"
+	"github.com/dedegunawan/backend-ujian-telp-v5/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// Staf helpdesk mengirim X-Impersonate-NPM (dan opsional X-Impersonate-Reason, mis. nomor tiket)
+// untuk melihat endpoint mahasiswa sebagai NPM tersebut.
+const (
+	ImpersonateHeader       = "X-Impersonate-NPM"
+	ImpersonateReasonHeader = "X-Impersonate-Reason"
+	ImpersonatingHeader     = "X-Impersonating"
+
+	ImpersonatedNPMKey   = "impersonated_npm"
+	ImpersonatorEmailKey = "impersonator_email"
+)
+
+// Hanya endpoint baca ini yang boleh diakses saat impersonasi; semua route lain (termasuk
+// generate pembayaran yang memakai GET) ditolak.
+var impersonationReadOnlyRoutes = map[string]bool{
+	"/api/v1/me":               true,
+	"/api/v1/student-bill-new": true,
+	"/api/v1/invoice-pdf":      true,
+}
+
+var npmPattern = regexp.MustCompile(`^[A-Za-z0-9._-]{1,50}$`)
+
+// serveImpersonated dipanggil RequireAuthFromTokenDB saat header X-Impersonate-NPM ada.
+// Setiap request (diizinkan maupun diblokir) dicatat ke impersonation_logs sebelum diproses.
+func serveImpersonated(c *gin.Context, npm string) {
+	service := services.NewImpersonationService(database.DBPNBP)
+	entry := &models.ImpersonationLog{
+		StaffEmail: c.GetString("email"),
+		StaffSSOID: c.GetString("sso_id"),
+		StudentID:  npm,
+		Reason:     truncate(c.GetHeader(ImpersonateReasonHeader), 255),
+		Method:     c.Request.Method,
+		Path:       truncate(c.Request.URL.Path, 255),
+		Query:      c.Request.URL.RawQuery,
+		IPAddress:  c.ClientIP(),
+		UserAgent:  truncate(c.Request.UserAgent(), 255),
+		CreatedAt:  time.Now(),
+	}
+
+	allowed, err := service.HasPermission(entry.StaffEmail, models.PermissionImpersonateStudent)
+	if err != nil {
+		utils.LogCtx(c.Request.Context()).Errorw("Impersonasi - Gagal cek permission", "error", err)
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Tidak dapat memverifikasi izin impersonasi"})
+		c.Abort()
+		return
+	}
+
+	blockStatus, blockMessage := 0, ""
+	switch {
+	case !allowed:
+		blockStatus, blockMessage = http.StatusForbidden, "Tidak memiliki izin impersonasi mahasiswa"
+	case !npmPattern.MatchString(npm):
+		blockStatus, blockMessage = http.StatusBadRequest, "NPM impersonasi tidak valid"
+	case c.Request.Method != http.MethodGet || !impersonationReadOnlyRoutes[c.FullPath()]:
+		blockStatus, blockMessage = http.StatusForbidden, "Mode impersonasi hanya baca, aksi ini tidak diizinkan"
+	}
+
+	if blockStatus != 0 {
+		entry.Status = blockStatus
+		entry.Blocked = true
+		if err := service.Record(entry); err != nil {
+			utils.LogCtx(c.Request.Context()).Errorw("Impersonasi - Gagal menulis audit", "error", err)
+		}
+		utils.LogCtx(c.Request.Context()).Warnw("Impersonasi diblokir",
+			"staff_email", entry.StaffEmail,
+			"student_id", npm,
+			"method", entry.Method,
+			"path", entry.Path,
+			"reason", blockMessage,
+		)
+		c.JSON(blockStatus, gin.H{"error": blockMessage})
+		c.Abort()
+		return
+	}
+
+	// Tanpa jejak audit, request tidak dilayani
+	if err := service.Record(entry); err != nil {
+		utils.LogCtx(c.Request.Context()).Errorw("Impersonasi - Gagal menulis audit", "error", err)
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Gagal mencatat audit impersonasi"})
+		c.Abort()
+		return
+	}
+
+	c.Set(ImpersonatedNPMKey, npm)
+	c.Set(ImpersonatorEmailKey, entry.StaffEmail)
+	c.Request = c.Request.WithContext(utils.ContextWithLogFields(c.Request.Context(),
+		"npm", npm,
+		"impersonator", entry.StaffEmail,
+	))
+	c.Header(ImpersonatingHeader, npm)
+	c.Header("Cache-Control", "no-store")
+
+	c.Next()
+
+	database.DBPNBP.Model(entry).Update("status", c.Writer.Status())
+}
+
+func truncate(value string, max int) string {
+	if len(value) > max {
+		return value[:max]
+	}
+	return value
+}
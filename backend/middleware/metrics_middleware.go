@@ -0,0 +1,28 @@
+package middleware
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/dedegunawan/backend-ujian-telp-v5/metrics"
+	"github.com/gin-gonic/gin"
+)
+
+// Metrics mencatat jumlah dan latensi request per route template (mis. /api/v1/generate/:StudentBillID),
+// bukan path mentah, supaya cardinality label tetap kecil.
+func Metrics() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		status := strconv.Itoa(c.Writer.Status())
+		method := c.Request.Method
+
+		metrics.HTTPRequests.WithLabelValues(method, route, status).Inc()
+		metrics.HTTPDuration.WithLabelValues(method, route, status).Observe(time.Since(start).Seconds())
+	}
+}
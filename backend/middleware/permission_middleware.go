@@ -0,0 +1,40 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/dedegunawan/backend-ujian-telp-v5/database"
+	"github.com/dedegunawan/backend-ujian-telp-v5/repositories"
+	"github.com/dedegunawan/backend-ujian-telp-v5/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// RequirePermission dipasang setelah RequireAuthFromTokenDB; cek permission user lokal berdasarkan email token
+func RequirePermission(permission string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		email := c.GetString("email")
+		if email == "" {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Tidak memiliki izin " + permission})
+			c.Abort()
+			return
+		}
+
+		userRepo := repositories.UserRepository{DB: database.DBPNBP}
+		allowed, err := userRepo.HasPermission(email, permission)
+		if err != nil {
+			utils.Log.Errorw("Permission middleware - Gagal cek permission",
+				"permission", permission,
+				"error", err,
+			)
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Tidak dapat memverifikasi izin"})
+			c.Abort()
+			return
+		}
+		if !allowed {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Tidak memiliki izin " + permission})
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,71 @@
+package middleware
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/dedegunawan/backend-ujian-telp-v5/ratelimit"
+	"github.com/dedegunawan/backend-ujian-telp-v5/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// RateLimit membatasi request per user (jika membawa token yang valid) atau per IP dengan budget policy.
+func RateLimit(policy string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		limiter := ratelimit.Default()
+		if !limiter.Enabled || limiter.Allowed(c.ClientIP()) {
+			c.Next()
+			return
+		}
+
+		limit := limiter.Limit(policy)
+		decision, err := limiter.Store.Take(c.Request.Context(), policy+":"+rateLimitKey(c), limit)
+		if err != nil {
+			// store bermasalah jangan sampai menutup layanan (fail open)
+			utils.Log.Errorw("RateLimit middleware - Gagal cek limit",
+				"policy", policy,
+				"error", err,
+			)
+			c.Next()
+			return
+		}
+
+		c.Header("RateLimit-Limit", strconv.Itoa(decision.Limit))
+		c.Header("RateLimit-Remaining", strconv.Itoa(decision.Remaining))
+		c.Header("RateLimit-Reset", strconv.Itoa(ceilSeconds(decision.Reset)))
+		c.Header("RateLimit-Policy", strconv.Itoa(limit.Requests)+";w="+strconv.Itoa(int(limit.Per.Seconds())))
+
+		if !decision.Allowed {
+			c.Header("Retry-After", strconv.Itoa(ceilSeconds(decision.RetryAfter)))
+			c.JSON(http.StatusTooManyRequests, gin.H{"error": "Terlalu banyak permintaan, coba lagi nanti"})
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// rateLimitKey user_id / email dari token, fallback ke IP client.
+// Limiter global berjalan sebelum RequireAuthFromTokenDB, jadi user diambil dari token yang tanda
+// tangannya valid; tanpa itu semua mahasiswa di balik NAT kampus berbagi satu budget IP. Token
+// palsu atau kedaluwarsa tetap dihitung per IP. Sesi yang sudah dicabut tetap ditolak oleh auth.
+func rateLimitKey(c *gin.Context) string {
+	if userID := c.GetString("user_id"); userID != "" {
+		return "user:" + userID
+	}
+	if tokenStr := extractAccessToken(c); tokenStr != "" {
+		if claims, err := verifyAccessToken(c, tokenStr); err == nil && claims.Sub != "" {
+			return "user:" + claims.Sub
+		}
+	}
+	if email := c.GetString("email"); email != "" {
+		return "email:" + email
+	}
+	return "ip:" + c.ClientIP()
+}
+
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
@@ -0,0 +1,110 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/dedegunawan/backend-ujian-telp-v5/ratelimit"
+	"github.com/gin-gonic/gin"
+)
+
+func TestRateLimit(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	type request struct {
+		ip         string
+		userID     string // di-set handler sebelumnya, seperti setelah RequireAuthFromTokenDB
+		wantStatus int
+	}
+	tests := []struct {
+		name     string
+		disabled bool
+		requests []request
+	}{
+		{
+			name: "per IP, request ketiga ditolak",
+			requests: []request{
+				{ip: "192.0.2.1", wantStatus: http.StatusOK},
+				{ip: "192.0.2.1", wantStatus: http.StatusOK},
+				{ip: "192.0.2.1", wantStatus: http.StatusTooManyRequests},
+				{ip: "192.0.2.2", wantStatus: http.StatusOK},
+			},
+		},
+		{
+			name: "per user, tidak berbagi budget IP",
+			requests: []request{
+				{ip: "192.0.2.1", userID: "u1", wantStatus: http.StatusOK},
+				{ip: "192.0.2.1", userID: "u1", wantStatus: http.StatusOK},
+				{ip: "192.0.2.1", userID: "u2", wantStatus: http.StatusOK},
+				{ip: "192.0.2.1", userID: "u1", wantStatus: http.StatusTooManyRequests},
+				{ip: "192.0.2.1", wantStatus: http.StatusOK},
+			},
+		},
+		{
+			name: "allow-list tidak dibatasi",
+			requests: []request{
+				{ip: "10.1.2.3", wantStatus: http.StatusOK},
+				{ip: "10.1.2.3", wantStatus: http.StatusOK},
+				{ip: "10.1.2.3", wantStatus: http.StatusOK},
+			},
+		},
+		{
+			name:     "limiter dimatikan",
+			disabled: true,
+			requests: []request{
+				{ip: "192.0.2.1", wantStatus: http.StatusOK},
+				{ip: "192.0.2.1", wantStatus: http.StatusOK},
+				{ip: "192.0.2.1", wantStatus: http.StatusOK},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			limiter := ratelimit.NewLimiter(ratelimit.NewMemoryStore(), map[string]ratelimit.Limit{
+				ratelimit.PolicyDefault: {Requests: 100, Per: time.Minute},
+				ratelimit.PolicyPayment: {Requests: 2, Per: time.Minute},
+			}, []string{"10.0.0.0/8"})
+			limiter.Enabled = !tt.disabled
+			ratelimit.SetDefault(limiter)
+
+			router := gin.New()
+			router.POST("/payment", func(c *gin.Context) {
+				if userID := c.GetHeader("X-Test-User"); userID != "" {
+					c.Set("user_id", userID)
+				}
+			}, RateLimit(ratelimit.PolicyPayment), func(c *gin.Context) {
+				c.Status(http.StatusOK)
+			})
+
+			for i, r := range tt.requests {
+				req := httptest.NewRequest(http.MethodPost, "/payment", nil)
+				req.RemoteAddr = r.ip + ":40000"
+				if r.userID != "" {
+					req.Header.Set("X-Test-User", r.userID)
+				}
+				rec := httptest.NewRecorder()
+				router.ServeHTTP(rec, req)
+
+				if rec.Code != r.wantStatus {
+					t.Fatalf("request %d: status = %d, want %d", i, rec.Code, r.wantStatus)
+				}
+				if r.wantStatus == http.StatusTooManyRequests {
+					if got := rec.Header().Get("Retry-After"); got != "30" {
+						t.Errorf("request %d: Retry-After = %q, want 30", i, got)
+					}
+					if got := rec.Header().Get("RateLimit-Remaining"); got != "0" {
+						t.Errorf("request %d: RateLimit-Remaining = %q, want 0", i, got)
+					}
+				}
+				if !tt.disabled && r.ip != "10.1.2.3" {
+					if got := rec.Header().Get("RateLimit-Policy"); got != "2;w=60" {
+						t.Errorf("request %d: RateLimit-Policy = %q, want 2;w=60", i, got)
+					}
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,57 @@
+package middleware
+
+import (
+	"net/http"
+	"regexp"
+	"time"
+
+	"github.com/dedegunawan/backend-ujian-telp-v5/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const RequestIDHeader = "X-Request-ID"
+
+var requestIDPattern = regexp.MustCompile(`^[A-Za-z0-9._-]{1,64}$`)
+
+// RequestLogger memberi setiap request sebuah request id (dari header X-Request-ID proxy /
+// backend2, atau dibuat baru), menyimpannya di ctx untuk utils.LogCtx, lalu menulis satu
+// access log setelah request selesai. Menggantikan logger bawaan gin yang mencetak query
+// string apa adanya (termasuk kode login SSO).
+func RequestLogger() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+
+		requestID := c.GetHeader(RequestIDHeader)
+		if !requestIDPattern.MatchString(requestID) {
+			requestID = uuid.NewString()
+			c.Request.Header.Set(RequestIDHeader, requestID)
+		}
+		c.Header(RequestIDHeader, requestID)
+		c.Set("request_id", requestID)
+		c.Request = c.Request.WithContext(utils.ContextWithLogFields(c.Request.Context(), "request_id", requestID))
+
+		c.Next()
+
+		// c.Request sudah diganti middleware berikutnya, jadi user_id, npm dan trace_id ikut terbawa
+		status := c.Writer.Status()
+		entry := utils.LogCtx(c.Request.Context()).With(
+			"method", c.Request.Method,
+			"route", c.FullPath(),
+			"path", c.Request.URL.Path,
+			"query", c.Request.URL.RawQuery,
+			"status", status,
+			"latency_ms", time.Since(start).Milliseconds(),
+			"client_ip", c.ClientIP(),
+			"bytes", c.Writer.Size(),
+		)
+		switch {
+		case status >= http.StatusInternalServerError:
+			entry.Errorw("HTTP request", "error", c.Errors.String())
+		case status >= http.StatusBadRequest:
+			entry.Warnw("HTTP request")
+		default:
+			entry.Infow("HTTP request")
+		}
+	}
+}
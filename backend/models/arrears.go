@@ -0,0 +1,71 @@
+package models
+
+import "time"
+
+// Bucket umur tunggakan, dihitung dari selisih semester terhadap tahun_id aktif
+const (
+	ArrearsBucketCurrent     = "current"
+	ArrearsBucketOneSemester = "1_semester"
+	ArrearsBucketTwoSemester = "2_semester"
+	ArrearsBucketOlder       = "older"
+)
+
+var ArrearsBuckets = []string{ArrearsBucketCurrent, ArrearsBucketOneSemester, ArrearsBucketTwoSemester, ArrearsBucketOlder}
+
+// ArrearsFilter filter laporan tunggakan
+type ArrearsFilter struct {
+	FakultasID   string
+	ProdiID      string
+	StatusKode   string // status_akademiks.kode
+	NPM          string // Drill-down satu mahasiswa
+	IncludeItems bool
+}
+
+// ArrearsItem satu tagihan yang belum lunas (registrasi_mahasiswa atau detail_cicilans)
+type ArrearsItem struct {
+	Source          string     `gorm:"column:source" json:"source"` // "cicilan" atau "registrasi"
+	ItemID          uint       `gorm:"column:item_id" json:"item_id"`
+	NPM             string     `gorm:"column:npm" json:"npm"`
+	TahunID         string     `gorm:"column:tahun_id" json:"tahun_id"`
+	SequenceNo      *int       `gorm:"column:sequence_no" json:"sequence_no,omitempty"`
+	DueDate         *time.Time `gorm:"column:due_date" json:"due_date,omitempty"`
+	Amount          int64      `gorm:"column:amount" json:"amount"`
+	PaidAmount      int64      `gorm:"column:paid_amount" json:"paid_amount"`
+	Beasiswa        int64      `gorm:"column:beasiswa" json:"beasiswa"`
+	RemainingAmount int64      `gorm:"column:remaining_amount" json:"remaining_amount"`
+	NamaLengkap     string     `gorm:"column:nama_lengkap" json:"-"`
+	NamaProdi       string     `gorm:"column:nama_prodi" json:"-"`
+	NamaFakultas    string     `gorm:"column:nama_fakultas" json:"-"`
+	StatusKode      string     `gorm:"column:status_kode" json:"-"`
+	Bucket          string     `gorm:"-" json:"bucket"`
+	SemesterAge     int        `gorm:"-" json:"semester_age"`
+}
+
+// ArrearsBucketTotal total per bucket
+type ArrearsBucketTotal struct {
+	Items    int64 `json:"items"`
+	Students int64 `json:"students"`
+	Amount   int64 `json:"amount"`
+}
+
+// ArrearsStudent ringkasan tunggakan satu mahasiswa
+type ArrearsStudent struct {
+	NPM          string           `json:"npm"`
+	NamaLengkap  string           `json:"nama_lengkap"`
+	NamaProdi    string           `json:"nama_prodi"`
+	NamaFakultas string           `json:"nama_fakultas"`
+	StatusKode   string           `json:"status_kode"`
+	Buckets      map[string]int64 `json:"buckets"`
+	Total        int64            `json:"total"`
+	OldestTahun  string           `json:"oldest_tahun_id"`
+	Items        []ArrearsItem    `json:"items,omitempty"`
+}
+
+// ArrearsReport laporan umur tunggakan
+type ArrearsReport struct {
+	TahunAktif  string                        `json:"tahun_aktif"`
+	GeneratedAt time.Time                     `json:"generated_at"`
+	Totals      map[string]ArrearsBucketTotal `json:"totals"`
+	GrandTotal  int64                         `json:"grand_total"`
+	Students    []ArrearsStudent              `json:"students"`
+}
@@ -0,0 +1,26 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AuthCode kode sekali pakai berumur pendek yang dikirim ke frontend setelah login SSO,
+// menggantikan ?token= di URL. Token disimpan terenkripsi dan dihapus saat ditukar.
+type AuthCode struct {
+	CodeHash     string    `gorm:"type:char(64);primaryKey"`
+	SessionID    uuid.UUID `gorm:"type:char(36);index"`
+	AccessToken  string    `gorm:"type:text"` // terenkripsi (AES-GCM)
+	RefreshToken string    `gorm:"type:text"` // terenkripsi (AES-GCM)
+	ExpiresAt    time.Time `gorm:"index"`     // Kedaluwarsa kode (bukan token)
+	UsedAt       *time.Time
+	CreatedAt    time.Time
+
+	TokenExpiresAt   time.Time
+	RefreshExpiresAt *time.Time
+}
+
+func (AuthCode) TableName() string {
+	return "auth_codes"
+}
@@ -1,7 +1,6 @@
 package models
 
 import (
-	"gorm.io/gorm"
 	"time"
 )
 
@@ -11,9 +10,3 @@ type BackState struct {
 	CreatedAt time.Time `gorm:"column:created_at" json:"created_at"`
 	UpdatedAt time.Time `gorm:"column:updated_at" json:"updated_at"`
 }
-
-func MigrateBackState(db *gorm.DB) {
-	db.AutoMigrate(
-		&BackState{},
-	)
-}
@@ -0,0 +1,96 @@
+package models
+
+import (
+	"time"
+)
+
+// PermissionManageFinance mengimpor mutasi bank dan memutuskan proposal pencocokan (menandai invoice lunas)
+const PermissionManageFinance = "finance.manage"
+
+// Status pencocokan satu baris kredit mutasi bank
+const (
+	BankMatchMatched        = "matched"         // VA & nominal cocok, invoice sudah Paid
+	BankMatchProposed       = "proposed"        // VA & nominal cocok, invoice belum Paid -> perlu konfirmasi manual
+	BankMatchAmountMismatch = "amount_mismatch" // VA ditemukan tapi nominal berbeda
+	BankMatchUnmatched      = "unmatched"       // Tidak ada VA yang cocok
+	BankMatchDuplicate      = "duplicate"       // Kredit yang sama sudah pernah diimpor / invoice sudah dicocokkan baris lain
+)
+
+// Status keputusan atas proposal
+const (
+	BankProposalPending   = "pending"
+	BankProposalConfirmed = "confirmed"
+	BankProposalRejected  = "rejected"
+)
+
+// BankStatementImport satu file mutasi bank yang diimpor
+type BankStatementImport struct {
+	ID             uint      `gorm:"primaryKey" json:"id"`
+	Filename       string    `gorm:"column:filename;size:255" json:"filename"`
+	Format         string    `gorm:"column:format;size:10" json:"format"` // "mt940" atau "csv"
+	ObjectName     string    `gorm:"column:object_name;size:255" json:"-"`
+	UploadedBy     string    `gorm:"column:uploaded_by;size:255" json:"uploaded_by"`
+	TotalCredits   int64     `gorm:"column:total_credits" json:"total_credits"`
+	TotalAmount    int64     `gorm:"column:total_amount" json:"total_amount"`
+	MatchedCount   int64     `gorm:"column:matched_count" json:"matched_count"`
+	ProposedCount  int64     `gorm:"column:proposed_count" json:"proposed_count"`
+	MismatchCount  int64     `gorm:"column:mismatch_count" json:"mismatch_count"`
+	UnmatchedCount int64     `gorm:"column:unmatched_count" json:"unmatched_count"`
+	DuplicateCount int64     `gorm:"column:duplicate_count" json:"duplicate_count"`
+	CreatedAt      time.Time `gorm:"column:created_at" json:"created_at"`
+	UpdatedAt      time.Time `gorm:"column:updated_at" json:"updated_at"`
+}
+
+func (BankStatementImport) TableName() string {
+	return "bank_statement_imports"
+}
+
+// BankStatementLine satu kredit dari mutasi bank beserta hasil pencocokannya
+type BankStatementLine struct {
+	ID             uint       `gorm:"primaryKey" json:"id"`
+	ImportID       uint       `gorm:"column:import_id;index" json:"import_id"`
+	LineNo         int        `gorm:"column:line_no" json:"line_no"`
+	ValueDate      time.Time  `gorm:"column:value_date" json:"value_date"`
+	Amount         int64      `gorm:"column:amount" json:"amount"`
+	BankReference  string     `gorm:"column:bank_reference;size:100" json:"bank_reference"`
+	Description    string     `gorm:"column:description;type:text" json:"description"`
+	Fingerprint    string     `gorm:"column:fingerprint;size:64;index" json:"-"`
+	MatchStatus    string     `gorm:"column:match_status;size:20;index" json:"match_status"`
+	VirtualAccount string     `gorm:"column:virtual_account;size:50;index" json:"virtual_account,omitempty"`
+	InvoiceID      *uint      `gorm:"column:invoice_id;index" json:"invoice_id,omitempty"`
+	InvoiceStatus  string     `gorm:"column:invoice_status;size:50" json:"invoice_status,omitempty"`
+	InvoiceAmount  int64      `gorm:"column:invoice_amount" json:"invoice_amount,omitempty"`
+	Note           string     `gorm:"column:note;size:255" json:"note,omitempty"`
+	ProposalStatus string     `gorm:"column:proposal_status;size:20" json:"proposal_status,omitempty"`
+	DecidedBy      string     `gorm:"column:decided_by;size:255" json:"decided_by,omitempty"`
+	DecidedAt      *time.Time `gorm:"column:decided_at" json:"decided_at,omitempty"`
+	CreatedAt      time.Time  `gorm:"column:created_at" json:"created_at"`
+	UpdatedAt      time.Time  `gorm:"column:updated_at" json:"updated_at"`
+}
+
+func (BankStatementLine) TableName() string {
+	return "bank_statement_lines"
+}
+
+// BankStatementCredit hasil parsing satu baris kredit (belum dicocokkan)
+type BankStatementCredit struct {
+	LineNo        int
+	ValueDate     time.Time
+	Amount        int64
+	BankReference string
+	Description   string
+}
+
+// BankMatchSummary ringkasan per status pencocokan
+type BankMatchSummary struct {
+	Status string `json:"status"`
+	Count  int64  `json:"count"`
+	Amount int64  `json:"amount"`
+}
+
+// BankStatementReport laporan hasil pencocokan satu import
+type BankStatementReport struct {
+	Import  BankStatementImport `json:"import"`
+	Summary []BankMatchSummary  `json:"summary"`
+	Lines   []BankStatementLine `json:"lines"`
+}
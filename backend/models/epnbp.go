@@ -2,7 +2,6 @@ package models
 
 import (
 	"gorm.io/datatypes"
-	"gorm.io/gorm"
 	"time"
 )
 
@@ -41,12 +40,3 @@ type PaymentCallback struct {
 	UpdatedAt     time.Time      `gorm:"column:updated_at" json:"updated_at"`
 	LastUpdatedAt time.Time      `gorm:"column:last_updated_at" json:"last_updated_at"`
 }
-
-func MigrateEpnbp(db *gorm.DB) {
-	db.AutoMigrate(&PayUrl{},
-		&PaymentCallback{},
-		&PaymentConfirmation{},
-		&PaymentCallback{},
-		&PaymentStatusLog{},
-	)
-}
@@ -0,0 +1,78 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/datatypes"
+)
+
+// PermissionExportFinance membuat dan mengunduh export tagihan / status pembayaran seluruh mahasiswa
+const PermissionExportFinance = "finance.export"
+
+// Jenis export yang didukung
+const (
+	ExportTypeStudentBills  = "student_bills"
+	ExportTypePaymentStatus = "payment_status"
+)
+
+// Status ExportJob
+const (
+	ExportStatusQueued     = "queued"
+	ExportStatusProcessing = "processing"
+	ExportStatusDone       = "done"
+	ExportStatusFailed     = "failed"
+)
+
+// StudentBillFilter filter yang dipakai bersama oleh GET /student-bills dan export-nya
+type StudentBillFilter struct {
+	StudentID    string `json:"student_id,omitempty"`
+	AcademicYear string `json:"academic_year,omitempty"`
+	Status       string `json:"status,omitempty"` // "paid", "unpaid", "partial", "all"
+	Search       string `json:"search,omitempty"`
+}
+
+// PaymentStatusFilter filter yang dipakai bersama oleh GET /payment-status dan export-nya
+type PaymentStatusFilter struct {
+	StudentID    string `json:"student_id,omitempty"`
+	AcademicYear string `json:"academic_year,omitempty"`
+	Status       string `json:"status,omitempty"` // "paid", "unpaid", "all"
+}
+
+// ExportJob riwayat & progress export per user
+type ExportJob struct {
+	ID            uint           `gorm:"primaryKey" json:"id"`
+	UserID        string         `gorm:"column:user_id;size:100;index" json:"user_id"` // sso_id dari token
+	UserEmail     string         `gorm:"column:user_email;size:255" json:"user_email"`
+	Type          string         `gorm:"column:type;size:50" json:"type"`
+	Format        string         `gorm:"column:format;size:10" json:"format"` // "xlsx" atau "csv"
+	Filters       datatypes.JSON `gorm:"column:filters;type:json" json:"filters"`
+	Status        string         `gorm:"column:status;size:20;index" json:"status"`
+	TotalRows     int64          `gorm:"column:total_rows" json:"total_rows"`
+	ProcessedRows int64          `gorm:"column:processed_rows" json:"processed_rows"`
+	ObjectName    string         `gorm:"column:object_name;size:255" json:"-"`
+	Error         *string        `gorm:"column:error;type:text" json:"error,omitempty"`
+	StartedAt     *time.Time     `gorm:"column:started_at" json:"started_at,omitempty"`
+	FinishedAt    *time.Time     `gorm:"column:finished_at" json:"finished_at,omitempty"`
+	CreatedAt     time.Time      `gorm:"column:created_at" json:"created_at"`
+	UpdatedAt     time.Time      `gorm:"column:updated_at" json:"updated_at"`
+
+	Progress float64 `gorm:"-" json:"progress"`      // 0-100
+	URL      string  `gorm:"-" json:"url,omitempty"` // Signed URL, hanya jika status done
+}
+
+func (ExportJob) TableName() string {
+	return "export_jobs"
+}
+
+// ComputeProgress mengisi Progress dari ProcessedRows / TotalRows
+func (j *ExportJob) ComputeProgress() {
+	switch {
+	case j.Status == ExportStatusDone:
+		j.Progress = 100
+	case j.TotalRows <= 0:
+		j.Progress = 0
+	default:
+		rate := float64(j.ProcessedRows) / float64(j.TotalRows) * 100
+		j.Progress = float64(int64(rate*100)) / 100
+	}
+}
@@ -0,0 +1,40 @@
+package models
+
+import (
+	"time"
+)
+
+// Permission yang dicek lewat roles -> role_permissions -> permissions
+const (
+	PermissionImpersonateStudent = "student.impersonate"
+	PermissionViewImpersonation  = "impersonation.log.view"
+)
+
+// ImpersonationLog satu request staf helpdesk yang melihat data sebagai mahasiswa (termasuk yang diblokir)
+type ImpersonationLog struct {
+	ID         uint      `gorm:"primaryKey" json:"id"`
+	StaffEmail string    `gorm:"column:staff_email;size:150;index" json:"staff_email"`
+	StaffSSOID string    `gorm:"column:staff_sso_id;size:255" json:"staff_sso_id"`
+	StudentID  string    `gorm:"column:student_id;size:50;index" json:"student_id"`
+	Reason     string    `gorm:"column:reason;size:255" json:"reason"`
+	Method     string    `gorm:"column:method;size:10" json:"method"`
+	Path       string    `gorm:"column:path;size:255" json:"path"`
+	Query      string    `gorm:"column:query;type:text" json:"query"`
+	Status     int       `gorm:"column:status" json:"status"`
+	Blocked    bool      `gorm:"column:blocked" json:"blocked"`
+	IPAddress  string    `gorm:"column:ip_address;size:45" json:"ip_address"`
+	UserAgent  string    `gorm:"column:user_agent;size:255" json:"user_agent"`
+	CreatedAt  time.Time `gorm:"column:created_at;index" json:"created_at"`
+}
+
+func (ImpersonationLog) TableName() string {
+	return "impersonation_logs"
+}
+
+// ImpersonationBanner penanda di response agar frontend menampilkan banner "mode lihat sebagai mahasiswa"
+type ImpersonationBanner struct {
+	Active     bool   `json:"active"`
+	ReadOnly   bool   `json:"read_only"`
+	StudentID  string `json:"student_id"`
+	StaffEmail string `json:"staff_email"`
+}
@@ -0,0 +1,79 @@
+package models
+
+import "time"
+
+// Jenis sumber transaksi jurnal
+const (
+	JournalSourcePayment        = "payment"
+	JournalSourceScholarship    = "scholarship"
+	JournalSourceDepositIn      = "deposit_in"
+	JournalSourceDepositApplied = "deposit_applied"
+)
+
+// JournalAccount satu akun pada bagan akun
+type JournalAccount struct {
+	Code string `json:"code"`
+	Name string `json:"name"`
+}
+
+// JournalAccountMap pemetaan transaksi ke kode akun aplikasi akuntansi
+type JournalAccountMap struct {
+	Bank                  JournalAccount            `json:"bank"`                   // Kas/Bank penerimaan
+	Revenue               JournalAccount            `json:"revenue"`                // Pendapatan PNBP default
+	RevenueBySource       map[string]JournalAccount `json:"revenue_by_source"`      // Pendapatan per REVENUE_SOURCE_ID
+	ScholarshipReceivable JournalAccount            `json:"scholarship_receivable"` // Piutang beasiswa
+	DepositLiability      JournalAccount            `json:"deposit_liability"`      // Titipan/deposit mahasiswa
+}
+
+// RevenueAccount akun pendapatan untuk revenue source tertentu, fallback ke Revenue
+func (m JournalAccountMap) RevenueAccount(revenueSourceID string) JournalAccount {
+	if account, ok := m.RevenueBySource[revenueSourceID]; ok && account.Code != "" {
+		return account
+	}
+	return m.Revenue
+}
+
+// JournalLine satu baris debit atau kredit
+type JournalLine struct {
+	EntryNo     string    `json:"entry_no"`
+	Date        time.Time `json:"date"`
+	AccountCode string    `json:"account_code"`
+	AccountName string    `json:"account_name"`
+	Debit       int64     `json:"debit"`
+	Credit      int64     `json:"credit"`
+	Description string    `json:"description"`
+	Reference   string    `json:"reference"`
+	NPM         string    `json:"npm"`
+	TahunID     string    `json:"tahun_id"`
+	Source      string    `json:"source"`
+}
+
+// JournalEntry satu transaksi double-entry (minimal satu debit & satu kredit)
+type JournalEntry struct {
+	EntryNo string        `json:"entry_no"`
+	Date    time.Time     `json:"date"`
+	Source  string        `json:"source"`
+	Lines   []JournalLine `json:"lines"`
+}
+
+// IsBalanced total debit sama dengan total kredit
+func (e JournalEntry) IsBalanced() bool {
+	var debit, credit int64
+	for _, line := range e.Lines {
+		debit += line.Debit
+		credit += line.Credit
+	}
+	return debit == credit && debit > 0
+}
+
+// JournalBatch kumpulan jurnal untuk satu rentang tanggal
+type JournalBatch struct {
+	BatchNo     string         `json:"batch_no"`
+	From        string         `json:"from"`
+	To          string         `json:"to"`
+	TotalDebit  int64          `json:"total_debit"`
+	TotalCredit int64          `json:"total_credit"`
+	Balanced    bool           `json:"balanced"`
+	Errors      []string       `json:"errors,omitempty"`
+	Entries     []JournalEntry `json:"entries"`
+}
@@ -0,0 +1,51 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// UserLoginSecurity status keamanan login lokal (email/password) per user staf:
+// penguncian akibat gagal login dan TOTP 2FA. Dipisah dari tabel users agar kolomnya tidak berubah.
+type UserLoginSecurity struct {
+	UserID         uuid.UUID `gorm:"type:char(36);primaryKey"`
+	FailedAttempts int       `gorm:"default:0"`
+	LastFailedAt   *time.Time
+	LockedUntil    *time.Time
+	LastLoginAt    *time.Time
+
+	TOTPSecret        string     `gorm:"column:totp_secret;type:text"`         // terenkripsi (AES-GCM)
+	TOTPPendingSecret string     `gorm:"column:totp_pending_secret;type:text"` // terenkripsi, menunggu konfirmasi kode pertama
+	TOTPEnabledAt     *time.Time `gorm:"column:totp_enabled_at"`
+	TOTPLastStep      int64      `gorm:"column:totp_last_step"` // Kode TOTP yang sama tidak boleh dipakai dua kali
+
+	PasswordChangedAt *time.Time
+	CreatedAt         time.Time
+	UpdatedAt         time.Time
+}
+
+func (UserLoginSecurity) TableName() string {
+	return "user_login_securities"
+}
+
+func (s *UserLoginSecurity) TOTPEnabled() bool {
+	return s.TOTPEnabledAt != nil && s.TOTPSecret != ""
+}
+
+func (s *UserLoginSecurity) Locked(now time.Time) bool {
+	return s.LockedUntil != nil && s.LockedUntil.After(now)
+}
+
+// UserRecoveryCode kode cadangan 2FA sekali pakai; hanya hash yang disimpan
+type UserRecoveryCode struct {
+	ID        uint      `gorm:"primaryKey"`
+	UserID    uuid.UUID `gorm:"type:char(36);index"`
+	CodeHash  string    `gorm:"type:char(64);uniqueIndex"`
+	UsedAt    *time.Time
+	CreatedAt time.Time
+}
+
+func (UserRecoveryCode) TableName() string {
+	return "user_recovery_codes"
+}
@@ -0,0 +1,72 @@
+package models
+
+import (
+	"time"
+)
+
+// PermissionManageNotification melihat riwayat pengiriman (email / nomor HP mahasiswa) dan memicu pengiriman ulang
+const PermissionManageNotification = "notification.manage"
+
+// Status Notification
+const (
+	NotificationQueued   = "queued"
+	NotificationRetrying = "retrying"
+	NotificationSent     = "sent"
+	NotificationFailed   = "failed"
+	NotificationSkipped  = "skipped" // Channel tidak aktif / penerima tanpa alamat
+)
+
+// Notification satu pesan ke satu penerima melalui satu channel, sekaligus catatan pengirimannya
+type Notification struct {
+	ID                uint       `gorm:"primaryKey" json:"id"`
+	Event             string     `gorm:"column:event;size:50;index" json:"event"`
+	Channel           string     `gorm:"column:channel;size:20" json:"channel"`
+	Locale            string     `gorm:"column:locale;size:5" json:"locale"`
+	NPM               string     `gorm:"column:npm;size:50;index" json:"npm"`
+	Recipient         string     `gorm:"column:recipient;size:255" json:"recipient"`
+	Subject           string     `gorm:"column:subject;size:255" json:"subject"`
+	Body              string     `gorm:"column:body;type:text" json:"body"`
+	DedupeKey         string     `gorm:"column:dedupe_key;size:191;uniqueIndex" json:"dedupe_key"`
+	Status            string     `gorm:"column:status;size:20;index" json:"status"`
+	Attempts          int        `gorm:"column:attempts" json:"attempts"`
+	MaxAttempts       int        `gorm:"column:max_attempts" json:"max_attempts"`
+	LastError         *string    `gorm:"column:last_error;type:text" json:"last_error,omitempty"`
+	ProviderMessageID string     `gorm:"column:provider_message_id;size:191" json:"provider_message_id,omitempty"`
+	SentAt            *time.Time `gorm:"column:sent_at" json:"sent_at,omitempty"`
+	CreatedAt         time.Time  `gorm:"column:created_at" json:"created_at"`
+	UpdatedAt         time.Time  `gorm:"column:updated_at" json:"updated_at"`
+}
+
+func (Notification) TableName() string {
+	return "notification_deliveries"
+}
+
+// NotificationCursor posisi terakhir (id) yang sudah diproses watcher per sumber event
+type NotificationCursor struct {
+	Name      string    `gorm:"column:name;size:50;primaryKey"`
+	LastID    uint      `gorm:"column:last_id"`
+	UpdatedAt time.Time `gorm:"column:updated_at"`
+}
+
+func (NotificationCursor) TableName() string {
+	return "notification_cursors"
+}
+
+// ReminderRun ringkasan satu kali eksekusi job pengingat pembayaran
+type ReminderRun struct {
+	ID                uint       `gorm:"primaryKey" json:"id"`
+	StartedAt         time.Time  `gorm:"column:started_at" json:"started_at"`
+	FinishedAt        *time.Time `gorm:"column:finished_at" json:"finished_at,omitempty"`
+	Candidates        int        `gorm:"column:candidates" json:"candidates"`     // Item belum lunas yang jatuh pada salah satu offset
+	Sent              int        `gorm:"column:sent" json:"sent"`                 // Notifikasi baru yang diantrekan
+	Deduplicated      int        `gorm:"column:deduplicated" json:"deduplicated"` // Sudah pernah dikirim untuk offset yang sama
+	NoContact         int        `gorm:"column:no_contact" json:"no_contact"`     // Mahasiswa tanpa email/nomor HP
+	Failed            int        `gorm:"column:failed" json:"failed"`             // Gagal publish
+	SkippedQuietHours bool       `gorm:"column:skipped_quiet_hours" json:"skipped_quiet_hours"`
+	PerOffset         string     `gorm:"column:per_offset;type:text" json:"per_offset"` // JSON: {"H-7": 10, ...}
+	Error             *string    `gorm:"column:error;type:text" json:"error,omitempty"`
+}
+
+func (ReminderRun) TableName() string {
+	return "reminder_runs"
+}
@@ -0,0 +1,45 @@
+package models
+
+// PermissionViewFinance laporan keuangan (pendapatan, tunggakan, jurnal) yang memuat data seluruh mahasiswa
+const PermissionViewFinance = "finance.view"
+
+// RevenueFilter filter untuk dashboard pendapatan
+type RevenueFilter struct {
+	TahunID    string
+	FakultasID string
+	ProdiID    string
+	Angkatan   string
+	KelUKT     string
+	GroupBy    []string // Kombinasi: fakultas, prodi, angkatan, ukt, tahun_id
+}
+
+// RevenueRow satu baris agregat pendapatan untuk kombinasi group tertentu
+type RevenueRow struct {
+	FakultasID     *uint   `gorm:"column:fakultas_id" json:"fakultas_id,omitempty"`
+	NamaFakultas   *string `gorm:"column:nama_fakultas" json:"nama_fakultas,omitempty"`
+	ProdiID        *uint   `gorm:"column:prodi_id" json:"prodi_id,omitempty"`
+	NamaProdi      *string `gorm:"column:nama_prodi" json:"nama_prodi,omitempty"`
+	Angkatan       *int    `gorm:"column:angkatan" json:"angkatan,omitempty"`
+	KelUKT         *string `gorm:"column:kel_ukt" json:"kel_ukt,omitempty"`
+	TahunID        *string `gorm:"column:tahun_id" json:"tahun_id,omitempty"`
+	Students       int64   `gorm:"column:students" json:"students"`
+	Expected       int64   `gorm:"column:expected" json:"expected"`       // Total nominal tagihan
+	Scholarship    int64   `gorm:"column:scholarship" json:"scholarship"` // Ditanggung beasiswa
+	Collected      int64   `gorm:"column:collected" json:"collected"`     // Sudah dibayar
+	Outstanding    int64   `gorm:"column:outstanding" json:"outstanding"` // Sisa yang belum dibayar
+	CollectionRate float64 `gorm:"-" json:"collection_rate"`              // collected / (expected - scholarship), 0-100
+}
+
+// RevenueSummaryResponse response dashboard pendapatan
+type RevenueSummaryResponse struct {
+	GroupBy []string     `json:"group_by"`
+	Total   RevenueRow   `json:"total"`
+	Rows    []RevenueRow `json:"rows"`
+}
+
+// RevenuePoint satu titik time-series pendapatan yang diterima
+type RevenuePoint struct {
+	Period    string `gorm:"column:period" json:"period"` // YYYY-MM-DD (awal minggu untuk interval week)
+	Payments  int64  `gorm:"column:payments" json:"payments"`
+	Collected int64  `gorm:"column:collected" json:"collected"`
+}
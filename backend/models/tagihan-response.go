@@ -15,6 +15,7 @@ type TagihanResponse struct {
 	RemainingAmount   int64     `json:"remaining_amount"`   // Sisa yang harus dibayar
 	Beasiswa          int64     `json:"beasiswa"`           // Nominal beasiswa (untuk registrasi)
 	BantuanUKT        int64     `json:"bantuan_ukt"`        // Nominal bantuan UKT (untuk registrasi)
+	PotonganStatus    int64     `json:"potongan_status,omitempty"` // Potongan dari aturan kelayakan status akademik
 	Status            string     `json:"status"`              // "paid", "unpaid", "partial"
 	PaymentStartDate  time.Time  `json:"payment_start_date"` // Tanggal mulai pembayaran (due_date untuk cicilan)
 	PaymentEndDate    *time.Time `json:"payment_end_date,omitempty"`   // Batas akhir pembayaran (hanya untuk registrasi, tidak ada untuk cicilan)
@@ -36,6 +37,9 @@ type TagihanListResponse struct {
 	Tahun               FinanceYear      `json:"tahun"`
 	IsPaid              bool             `json:"isPaid"`
 	IsGenerated         bool             `json:"isGenerated"`
+	IsNotBilled         bool             `json:"isNotBilled"` // Status akademik no_bill: tidak ditagih, bukan lunas
 	TagihanHarusDibayar []TagihanResponse `json:"tagihanHarusDibayar"`
 	HistoryTagihan      []TagihanResponse `json:"historyTagihan"`
+	Eligibility         *EligibilityResult `json:"eligibility,omitempty"`
+	Impersonation       *ImpersonationBanner `json:"impersonation,omitempty"`
 }
@@ -1,8 +1,6 @@
 package models
 
 import (
-	"github.com/dedegunawan/backend-ujian-telp-v5/utils"
-	"gorm.io/gorm"
 	"time"
 )
 
@@ -205,32 +203,3 @@ type StudentBillPostponement struct {
 	CreatedAt time.Time
 	UpdatedAt time.Time
 }
-
-func MigrateTagihan(db *gorm.DB) {
-	utils.Log.Info("Migrating tagihan")
-	for _, model := range []interface{}{
-		&FinanceYear{},
-		&BillTemplate{},
-		&BillTemplateItem{},
-		&BillDiscount{},
-		&StudentBill{},
-		&StudentBillDiscount{},
-		&StudentPayment{},
-		&StudentPaymentAllocation{},
-		&StudentUktHistory{},
-		&StudentBillInstallment{},
-		&StudentBillPostponement{},
-	} {
-		err := db.AutoMigrate(model)
-		if err != nil {
-			utils.Log.Printf("❌ Failed migrating %T: %v", model, err)
-		} else {
-			utils.Log.Printf("✅ Migrated: %T", model)
-		}
-	}
-
-	var count int64
-	db.Model(&StudentBill{}).Count(&count)
-	utils.Log.Infof("Student Bills migrated: %d", count)
-
-}
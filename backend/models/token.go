@@ -1,8 +1,10 @@
 package models
 
 import (
-	"github.com/google/uuid"
 	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
 const (
@@ -10,17 +12,52 @@ const (
 	JWTTypeInternal = "internal"
 )
 
+// Alasan pencabutan sesi
+const (
+	RevokeReasonLogout      = "logout"
+	RevokeReasonRotated     = "rotated"     // Refresh token sudah ditukar dengan yang baru
+	RevokeReasonReuse       = "reuse"       // Refresh token lama dipakai lagi -> seluruh sesi dicabut
+	RevokeReasonDeactivated = "deactivated" // User dinonaktifkan / dihapus
+	RevokeReasonAdmin       = "admin"
+)
+
+// UserToken satu pasangan access/refresh token dari satu sesi login.
+// Token disimpan sebagai hash SHA-256 (bukan token asli); setiap refresh membuat baris baru
+// dengan SessionID yang sama dan mencabut baris lama (rotasi).
 type UserToken struct {
-	ID           uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
-	UserID       uuid.UUID `gorm:"type:uuid;index"`
-	AccessToken  string    `gorm:"type:text"`
-	RefreshToken string    `gorm:"type:text"`
-	ExpiresAt    time.Time
-	TokenType    string  `gorm:"type:varchar(20)"`
-	JwtType      string  `gorm:"type:jwt_type_enum;default:'keycloak'"` // pakai enum dari DB
-	Fingerprint  string  `gorm:"type:text"`
-	UserAgent    string  `gorm:"type:text"`
-	IPAddress    *string `gorm:"type:inet"`
+	ID               uuid.UUID  `gorm:"type:char(36);primaryKey"`
+	SessionID        uuid.UUID  `gorm:"type:char(36);index"`
+	UserID           uuid.UUID  `gorm:"type:char(36);index"`     // uuid.Nil untuk mahasiswa (tidak ada di tabel users)
+	Subject          string     `gorm:"type:varchar(255);index"` // sub dari SSO
+	Email            string     `gorm:"type:varchar(150);index"`
+	AccessTokenHash  string     `gorm:"type:char(64);uniqueIndex"`
+	RefreshTokenHash *string    `gorm:"type:char(64);uniqueIndex"`
+	ExpiresAt        time.Time  // Kedaluwarsa access token
+	RefreshExpiresAt *time.Time // Kedaluwarsa refresh token (jika diberikan provider)
+	TokenType        string     `gorm:"type:varchar(20)"`
+	JwtType          string     `gorm:"type:varchar(20);default:'keycloak'"`
+	Fingerprint      string     `gorm:"type:varchar(64)"`
+	UserAgent        string     `gorm:"type:text"`
+	IPAddress        *string    `gorm:"type:varchar(45)"`
+	LastUsedAt       *time.Time
+	RevokedAt        *time.Time `gorm:"index"`
+	RevokedReason    string     `gorm:"type:varchar(20)"`
+	ReplacedByID     *uuid.UUID `gorm:"type:char(36)"`
 
 	CreatedAt time.Time
 }
+
+func (t *UserToken) BeforeCreate(tx *gorm.DB) error {
+	if t.ID == uuid.Nil {
+		t.ID = uuid.New()
+	}
+	if t.SessionID == uuid.Nil {
+		t.SessionID = t.ID
+	}
+	return nil
+}
+
+// Revoked true jika sesi sudah dicabut
+func (t UserToken) Revoked() bool {
+	return t.RevokedAt != nil
+}
@@ -0,0 +1,73 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+// PermissionManageWebhook mengelola subscription (URL tujuan, secret) dan replay delivery
+const PermissionManageWebhook = "webhook.manage"
+
+// Status WebhookDelivery
+const (
+	WebhookDeliveryQueued    = "queued"
+	WebhookDeliveryRetrying  = "retrying"
+	WebhookDeliveryDelivered = "delivered"
+	WebhookDeliveryFailed    = "failed"
+	WebhookDeliverySkipped   = "skipped" // Subscription dinonaktifkan / dihapus sebelum terkirim
+)
+
+// WebhookSubscription sistem kampus lain (perpustakaan, wisuda, asrama, ...) yang ingin menerima event
+type WebhookSubscription struct {
+	ID          uint      `gorm:"primaryKey" json:"id"`
+	Name        string    `gorm:"column:name;size:100" json:"name"`
+	URL         string    `gorm:"column:url;size:500" json:"url"`
+	Secret      string    `gorm:"column:secret;size:255" json:"-"`
+	EventTypes  string    `gorm:"column:event_types;size:500" json:"event_types"` // Dipisah koma, "*" untuk semua event
+	Active      bool      `gorm:"column:active;default:true" json:"active"`
+	MaxAttempts int       `gorm:"column:max_attempts" json:"max_attempts"`
+	CreatedBy   string    `gorm:"column:created_by;size:255" json:"created_by"`
+	CreatedAt   time.Time `gorm:"column:created_at" json:"created_at"`
+	UpdatedAt   time.Time `gorm:"column:updated_at" json:"updated_at"`
+}
+
+func (WebhookSubscription) TableName() string {
+	return "webhook_subscriptions"
+}
+
+// Subscribes true jika subscription menerima eventType
+func (s WebhookSubscription) Subscribes(eventType string) bool {
+	for _, t := range strings.Split(s.EventTypes, ",") {
+		t = strings.TrimSpace(t)
+		if t == "*" || t == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// WebhookDelivery satu event untuk satu subscription, sekaligus log pengirimannya
+type WebhookDelivery struct {
+	ID             uint       `gorm:"primaryKey" json:"id"`
+	SubscriptionID uint       `gorm:"column:subscription_id;index" json:"subscription_id"`
+	EventID        string     `gorm:"column:event_id;size:150;index" json:"event_id"`
+	EventType      string     `gorm:"column:event_type;size:50;index" json:"event_type"`
+	Payload        string     `gorm:"column:payload;type:text" json:"payload"`
+	DedupeKey      *string    `gorm:"column:dedupe_key;size:191;uniqueIndex" json:"-"` // NULL untuk replay
+	ReplayOf       *uint      `gorm:"column:replay_of" json:"replay_of,omitempty"`
+	Status         string     `gorm:"column:status;size:20;index" json:"status"`
+	Attempts       int        `gorm:"column:attempts" json:"attempts"`
+	MaxAttempts    int        `gorm:"column:max_attempts" json:"max_attempts"`
+	NextAttemptAt  *time.Time `gorm:"column:next_attempt_at" json:"next_attempt_at,omitempty"`
+	ResponseStatus int        `gorm:"column:response_status" json:"response_status"`
+	ResponseBody   string     `gorm:"column:response_body;type:text" json:"response_body,omitempty"`
+	DurationMs     int64      `gorm:"column:duration_ms" json:"duration_ms"`
+	LastError      *string    `gorm:"column:last_error;type:text" json:"last_error,omitempty"`
+	DeliveredAt    *time.Time `gorm:"column:delivered_at" json:"delivered_at,omitempty"`
+	CreatedAt      time.Time  `gorm:"column:created_at;index" json:"created_at"`
+	UpdatedAt      time.Time  `gorm:"column:updated_at" json:"updated_at"`
+}
+
+func (WebhookDelivery) TableName() string {
+	return "webhook_deliveries"
+}
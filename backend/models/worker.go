@@ -2,14 +2,13 @@ package models
 
 import (
 	"gorm.io/datatypes"
-	"gorm.io/gorm"
 	"time"
 )
 
 type JobQueue struct {
 	ID         uint           `gorm:"primaryKey"`
 	Type       string         `gorm:"type:text"`
-	Payload    datatypes.JSON `gorm:"type:jsonb"`
+	Payload    datatypes.JSON `gorm:"type:json"` // DBPNBP adalah MySQL
 	Status     string         `gorm:"type:text"`
 	Retries    int
 	MaxRetries int
@@ -18,8 +17,3 @@ type JobQueue struct {
 	CreatedAt  time.Time
 	UpdatedAt  time.Time
 }
-
-func MigrateJob(db *gorm.DB) {
-	db.AutoMigrate(&JobQueue{})
-
-}
@@ -0,0 +1,23 @@
+package notification
+
+import "context"
+
+// Nama channel pengiriman
+const (
+	ChannelEmail    = "email"
+	ChannelWhatsApp = "whatsapp"
+)
+
+// Message satu pesan yang siap dikirim ke satu penerima
+type Message struct {
+	To      string // alamat email atau nomor WhatsApp
+	Subject string // diabaikan oleh channel yang tidak punya subjek
+	Body    string
+}
+
+// Channel media pengiriman notifikasi.
+// Send mengembalikan ID pesan dari provider (boleh kosong).
+type Channel interface {
+	Name() string
+	Send(ctx context.Context, msg Message) (string, error)
+}
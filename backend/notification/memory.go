@@ -0,0 +1,42 @@
+package notification
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// MemorySink channel untuk pengujian: pesan hanya disimpan di memori
+type MemorySink struct {
+	name     string
+	mu       sync.Mutex
+	messages []Message
+}
+
+func NewMemorySink(name string) *MemorySink {
+	return &MemorySink{name: name}
+}
+
+func (m *MemorySink) Name() string {
+	return m.name
+}
+
+func (m *MemorySink) Send(ctx context.Context, msg Message) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.messages = append(m.messages, msg)
+	return fmt.Sprintf("memory-%s-%d", m.name, len(m.messages)), nil
+}
+
+// Messages salinan semua pesan yang sudah "dikirim"
+func (m *MemorySink) Messages() []Message {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]Message(nil), m.messages...)
+}
+
+func (m *MemorySink) Reset() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.messages = nil
+}
@@ -0,0 +1,69 @@
+package notification
+
+import (
+	"os"
+	"sync"
+)
+
+// Registry kumpulan channel yang aktif, berdasarkan nama
+type Registry struct {
+	channels map[string]Channel
+}
+
+func NewRegistry(channels ...Channel) *Registry {
+	r := &Registry{channels: make(map[string]Channel)}
+	for _, ch := range channels {
+		r.Register(ch)
+	}
+	return r
+}
+
+func (r *Registry) Register(ch Channel) {
+	r.channels[ch.Name()] = ch
+}
+
+func (r *Registry) Get(name string) (Channel, bool) {
+	ch, ok := r.channels[name]
+	return ch, ok
+}
+
+// Names nama channel aktif, urutan tetap (email dulu)
+func (r *Registry) Names() []string {
+	var names []string
+	for _, name := range []string{ChannelEmail, ChannelWhatsApp} {
+		if _, ok := r.channels[name]; ok {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+var (
+	defaultRegistry *Registry
+	defaultOnce     sync.Once
+)
+
+// Default registry dari env. NOTIFICATION_SINK=memory mengganti semua channel dengan MemorySink.
+func Default() *Registry {
+	defaultOnce.Do(func() {
+		if os.Getenv("NOTIFICATION_SINK") == "memory" {
+			defaultRegistry = NewRegistry(NewMemorySink(ChannelEmail), NewMemorySink(ChannelWhatsApp))
+			return
+		}
+
+		defaultRegistry = NewRegistry()
+		if smtpChannel := NewSMTPChannelFromEnv(); smtpChannel != nil {
+			defaultRegistry.Register(smtpChannel)
+		}
+		if waChannel := NewWhatsAppChannelFromEnv(); waChannel != nil {
+			defaultRegistry.Register(waChannel)
+		}
+	})
+	return defaultRegistry
+}
+
+// SetDefault mengganti registry default (untuk pengujian)
+func SetDefault(r *Registry) {
+	defaultOnce.Do(func() {})
+	defaultRegistry = r
+}
@@ -0,0 +1,85 @@
+package notification
+
+import (
+	"context"
+	"fmt"
+	"net/smtp"
+	"os"
+	"strings"
+	"time"
+)
+
+// SMTPChannel mengirim email plain-text lewat server SMTP
+type SMTPChannel struct {
+	Host     string
+	Port     string
+	Username string
+	Password string
+	From     string
+}
+
+// NewSMTPChannelFromEnv membaca SMTP_HOST, SMTP_PORT, SMTP_USERNAME, SMTP_PASSWORD, SMTP_FROM.
+// Return nil jika SMTP_HOST tidak di-set.
+func NewSMTPChannelFromEnv() *SMTPChannel {
+	host := os.Getenv("SMTP_HOST")
+	if host == "" {
+		return nil
+	}
+	port := os.Getenv("SMTP_PORT")
+	if port == "" {
+		port = "587"
+	}
+	from := os.Getenv("SMTP_FROM")
+	if from == "" {
+		from = os.Getenv("SMTP_USERNAME")
+	}
+	return &SMTPChannel{
+		Host:     host,
+		Port:     port,
+		Username: os.Getenv("SMTP_USERNAME"),
+		Password: os.Getenv("SMTP_PASSWORD"),
+		From:     from,
+	}
+}
+
+func (s *SMTPChannel) Name() string {
+	return ChannelEmail
+}
+
+func (s *SMTPChannel) Send(ctx context.Context, msg Message) (string, error) {
+	if msg.To == "" {
+		return "", fmt.Errorf("alamat email kosong")
+	}
+
+	messageID := fmt.Sprintf("<%d.%s>", time.Now().UnixNano(), s.From)
+	headers := []string{
+		"From: " + s.From,
+		"To: " + msg.To,
+		"Subject: " + msg.Subject,
+		"Message-ID: " + messageID,
+		"Date: " + time.Now().Format(time.RFC1123Z),
+		"MIME-Version: 1.0",
+		"Content-Type: text/plain; charset=UTF-8",
+	}
+	body := strings.Join(headers, "\r\n") + "\r\n\r\n" + msg.Body
+
+	var auth smtp.Auth
+	if s.Username != "" {
+		auth = smtp.PlainAuth("", s.Username, s.Password, s.Host)
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- smtp.SendMail(s.Host+":"+s.Port, auth, s.From, []string{msg.To}, []byte(body))
+	}()
+
+	select {
+	case <-ctx.Done():
+		return "", ctx.Err()
+	case err := <-errCh:
+		if err != nil {
+			return "", err
+		}
+		return messageID, nil
+	}
+}
@@ -0,0 +1,172 @@
+package notification
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+// Event yang memicu notifikasi
+const (
+	EventBillIssued          = "bill_issued"
+	EventVACreated           = "va_created"
+	EventPaymentReceived     = "payment_received"
+	EventDeadlineApproaching = "deadline_approaching"
+)
+
+// Bahasa template
+const (
+	LocaleID = "id"
+	LocaleEN = "en"
+)
+
+type messageTemplate struct {
+	Subject string
+	Body    string
+}
+
+// Data yang tersedia di template: .Nama, .NPM, .TahunID, .Amount, .VirtualAccount,
+// .DueDate, .InvoiceID, .PaymentDate, .DaysLeft
+var messageTemplates = map[string]map[string]messageTemplate{
+	EventBillIssued: {
+		LocaleID: {
+			Subject: "Tagihan UKT {{.TahunID}} telah terbit",
+			Body: "Yth. {{.Nama}} ({{.NPM}}),\n\n" +
+				"Tagihan UKT semester {{.TahunID}} sebesar {{rupiah .Amount}} telah terbit." +
+				"{{if .DueDate}} Batas pembayaran: {{.DueDate}}.{{end}}\n\n" +
+				"Silakan buat Virtual Account melalui portal pembayaran.",
+		},
+		LocaleEN: {
+			Subject: "Tuition bill for {{.TahunID}} has been issued",
+			Body: "Dear {{.Nama}} ({{.NPM}}),\n\n" +
+				"Your tuition (UKT) bill for semester {{.TahunID}} of {{rupiah .Amount}} has been issued." +
+				"{{if .DueDate}} Payment deadline: {{.DueDate}}.{{end}}\n\n" +
+				"Please create a Virtual Account on the payment portal.",
+		},
+	},
+	EventVACreated: {
+		LocaleID: {
+			Subject: "Virtual Account pembayaran UKT {{.TahunID}}",
+			Body: "Yth. {{.Nama}} ({{.NPM}}),\n\n" +
+				"Virtual Account Anda: {{.VirtualAccount}}\n" +
+				"Nominal: {{rupiah .Amount}}\n" +
+				"{{if .DueDate}}Berlaku sampai: {{.DueDate}}\n{{end}}" +
+				"\nLakukan pembayaran sesuai nominal sebelum batas waktu.",
+		},
+		LocaleEN: {
+			Subject: "Virtual Account for tuition payment {{.TahunID}}",
+			Body: "Dear {{.Nama}} ({{.NPM}}),\n\n" +
+				"Your Virtual Account: {{.VirtualAccount}}\n" +
+				"Amount: {{rupiah .Amount}}\n" +
+				"{{if .DueDate}}Valid until: {{.DueDate}}\n{{end}}" +
+				"\nPlease pay the exact amount before the deadline.",
+		},
+	},
+	EventPaymentReceived: {
+		LocaleID: {
+			Subject: "Pembayaran UKT {{.TahunID}} diterima",
+			Body: "Yth. {{.Nama}} ({{.NPM}}),\n\n" +
+				"Pembayaran sebesar {{rupiah .Amount}}{{if .VirtualAccount}} melalui VA {{.VirtualAccount}}{{end}} telah kami terima" +
+				"{{if .PaymentDate}} pada {{.PaymentDate}}{{end}}.\n\nTerima kasih.",
+		},
+		LocaleEN: {
+			Subject: "Tuition payment for {{.TahunID}} received",
+			Body: "Dear {{.Nama}} ({{.NPM}}),\n\n" +
+				"We have received your payment of {{rupiah .Amount}}{{if .VirtualAccount}} via VA {{.VirtualAccount}}{{end}}" +
+				"{{if .PaymentDate}} on {{.PaymentDate}}{{end}}.\n\nThank you.",
+		},
+	},
+	EventDeadlineApproaching: {
+		LocaleID: {
+			Subject: "Pengingat pembayaran UKT {{.TahunID}}",
+			Body: "Yth. {{.Nama}} ({{.NPM}}),\n\n" +
+				"{{if lt .DaysLeft 0}}Tagihan sebesar {{rupiah .Amount}} telah melewati batas pembayaran {{.DueDate}}." +
+				"{{else if eq .DaysLeft 0}}Hari ini adalah batas pembayaran tagihan sebesar {{rupiah .Amount}}." +
+				"{{else}}Batas pembayaran tagihan sebesar {{rupiah .Amount}} tinggal {{.DaysLeft}} hari lagi ({{.DueDate}}).{{end}}" +
+				"{{if .VirtualAccount}}\nVirtual Account: {{.VirtualAccount}}{{end}}\n\n" +
+				"Abaikan pesan ini jika Anda sudah membayar.",
+		},
+		LocaleEN: {
+			Subject: "Tuition payment reminder {{.TahunID}}",
+			Body: "Dear {{.Nama}} ({{.NPM}}),\n\n" +
+				"{{if lt .DaysLeft 0}}Your bill of {{rupiah .Amount}} is past its due date {{.DueDate}}." +
+				"{{else if eq .DaysLeft 0}}Today is the due date for your bill of {{rupiah .Amount}}." +
+				"{{else}}Your bill of {{rupiah .Amount}} is due in {{.DaysLeft}} day(s) ({{.DueDate}}).{{end}}" +
+				"{{if .VirtualAccount}}\nVirtual Account: {{.VirtualAccount}}{{end}}\n\n" +
+				"Please ignore this message if you have already paid.",
+		},
+	},
+}
+
+var templateFuncs = template.FuncMap{
+	"rupiah": FormatRupiah,
+}
+
+// TemplateData isi template notifikasi
+type TemplateData struct {
+	Nama           string
+	NPM            string
+	TahunID        string
+	Amount         int64
+	VirtualAccount string
+	DueDate        string
+	InvoiceID      uint
+	PaymentDate    string
+	DaysLeft       int
+}
+
+// Render subjek & isi pesan untuk event dan bahasa tertentu (fallback ke bahasa Indonesia)
+func Render(event, locale string, data TemplateData) (string, string, error) {
+	byLocale, ok := messageTemplates[event]
+	if !ok {
+		return "", "", fmt.Errorf("template untuk event %s tidak ada", event)
+	}
+	tpl, ok := byLocale[locale]
+	if !ok {
+		tpl = byLocale[LocaleID]
+	}
+
+	subject, err := execute(event+".subject", tpl.Subject, data)
+	if err != nil {
+		return "", "", err
+	}
+	body, err := execute(event+".body", tpl.Body, data)
+	if err != nil {
+		return "", "", err
+	}
+	return subject, body, nil
+}
+
+func execute(name, text string, data TemplateData) (string, error) {
+	t, err := template.New(name).Funcs(templateFuncs).Parse(text)
+	if err != nil {
+		return "", err
+	}
+	var buffer bytes.Buffer
+	if err := t.Execute(&buffer, data); err != nil {
+		return "", err
+	}
+	return buffer.String(), nil
+}
+
+// FormatRupiah 1500000 -> "Rp 1.500.000"
+func FormatRupiah(amount int64) string {
+	negative := amount < 0
+	if negative {
+		amount = -amount
+	}
+	digits := strconv.FormatInt(amount, 10)
+	var parts []string
+	for len(digits) > 3 {
+		parts = append([]string{digits[len(digits)-3:]}, parts...)
+		digits = digits[:len(digits)-3]
+	}
+	parts = append([]string{digits}, parts...)
+	result := "Rp " + strings.Join(parts, ".")
+	if negative {
+		result = "-" + result
+	}
+	return result
+}
@@ -0,0 +1,109 @@
+package notification
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+)
+
+// WhatsAppChannel mengirim pesan lewat HTTP API gateway WhatsApp generik:
+// POST {WHATSAPP_GATEWAY_URL} dengan body JSON {<phone field>: "628...", <message field>: "..."}
+type WhatsAppChannel struct {
+	URL          string
+	Token        string
+	PhoneField   string
+	MessageField string
+	client       *resty.Client
+}
+
+// NewWhatsAppChannelFromEnv membaca WHATSAPP_GATEWAY_URL, WHATSAPP_GATEWAY_TOKEN,
+// WHATSAPP_GATEWAY_PHONE_FIELD (default "phone"), WHATSAPP_GATEWAY_MESSAGE_FIELD (default "message").
+// Return nil jika WHATSAPP_GATEWAY_URL tidak di-set.
+func NewWhatsAppChannelFromEnv() *WhatsAppChannel {
+	url := os.Getenv("WHATSAPP_GATEWAY_URL")
+	if url == "" {
+		return nil
+	}
+	phoneField := os.Getenv("WHATSAPP_GATEWAY_PHONE_FIELD")
+	if phoneField == "" {
+		phoneField = "phone"
+	}
+	messageField := os.Getenv("WHATSAPP_GATEWAY_MESSAGE_FIELD")
+	if messageField == "" {
+		messageField = "message"
+	}
+	return &WhatsAppChannel{
+		URL:          url,
+		Token:        os.Getenv("WHATSAPP_GATEWAY_TOKEN"),
+		PhoneField:   phoneField,
+		MessageField: messageField,
+		client:       resty.New().SetTimeout(15 * time.Second),
+	}
+}
+
+func (w *WhatsAppChannel) Name() string {
+	return ChannelWhatsApp
+}
+
+func (w *WhatsAppChannel) Send(ctx context.Context, msg Message) (string, error) {
+	phone := NormalizePhone(msg.To)
+	if phone == "" {
+		return "", fmt.Errorf("nomor WhatsApp kosong atau tidak valid")
+	}
+
+	var result map[string]interface{}
+	req := w.client.R().
+		SetContext(ctx).
+		SetHeader("Content-Type", "application/json").
+		SetBody(map[string]string{
+			w.PhoneField:   phone,
+			w.MessageField: msg.Body,
+		}).
+		SetResult(&result)
+	if w.Token != "" {
+		req.SetHeader("Authorization", "Bearer "+w.Token)
+	}
+
+	resp, err := req.Post(w.URL)
+	if err != nil {
+		return "", err
+	}
+	if resp.IsError() {
+		return "", fmt.Errorf("gateway WhatsApp HTTP %d: %s", resp.StatusCode(), resp.String())
+	}
+
+	for _, key := range []string{"id", "message_id", "messageId"} {
+		if id, ok := result[key]; ok {
+			return fmt.Sprint(id), nil
+		}
+	}
+	return "", nil
+}
+
+// NormalizePhone mengubah 08xx / +628xx / 628xx menjadi 628xx; return "" jika bukan nomor
+func NormalizePhone(raw string) string {
+	var digits strings.Builder
+	for _, r := range raw {
+		if r >= '0' && r <= '9' {
+			digits.WriteRune(r)
+		}
+	}
+	phone := digits.String()
+	switch {
+	case strings.HasPrefix(phone, "62"):
+	case strings.HasPrefix(phone, "0"):
+		phone = "62" + phone[1:]
+	case strings.HasPrefix(phone, "8"):
+		phone = "62" + phone
+	default:
+		return ""
+	}
+	if len(phone) < 10 {
+		return ""
+	}
+	return phone
+}
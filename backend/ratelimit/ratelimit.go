@@ -0,0 +1,145 @@
+// Package ratelimit token bucket per key (user atau IP) dengan penyimpanan Redis atau memori.
+package ratelimit
+
+import (
+	"net"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/dedegunawan/backend-ujian-telp-v5/utils"
+)
+
+// Nama budget per route; nilainya bisa diubah lewat env RATE_LIMIT_<NAMA>, mis. RATE_LIMIT_PAYMENT=5/m
+const (
+	PolicyDefault = "default" // semua request, per user jika membawa token valid, selain itu per IP
+	PolicyPayment = "payment" // generate tagihan / URL pembayaran (memanggil sistem pembayaran)
+	PolicyInvoice = "invoice" // render PDF invoice
+)
+
+var defaultLimits = map[string]string{
+	PolicyDefault: "1200/m",
+	PolicyPayment: "10/m",
+	PolicyInvoice: "10/m",
+}
+
+// Limiter menggabungkan store, budget per policy dan allow-list
+type Limiter struct {
+	Store     Store
+	Enabled   bool
+	limits    map[string]Limit
+	allowNets []*net.IPNet
+}
+
+func NewLimiter(store Store, limits map[string]Limit, allowList []string) *Limiter {
+	l := &Limiter{Store: store, Enabled: true, limits: limits}
+	for _, entry := range allowList {
+		if ipNet := parseAllowEntry(entry); ipNet != nil {
+			l.allowNets = append(l.allowNets, ipNet)
+		} else {
+			utils.Log.Warnf("RateLimit: entry allow-list tidak valid: %s", entry)
+		}
+	}
+	return l
+}
+
+// Limit budget policy; fallback ke PolicyDefault jika policy tidak dikenal
+func (l *Limiter) Limit(policy string) Limit {
+	if limit, ok := l.limits[policy]; ok {
+		return limit
+	}
+	return l.limits[PolicyDefault]
+}
+
+// Allowed true jika IP termasuk allow-list (caller internal tidak dibatasi)
+func (l *Limiter) Allowed(ip string) bool {
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return false
+	}
+	for _, ipNet := range l.allowNets {
+		if ipNet.Contains(parsed) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseAllowEntry menerima IP tunggal atau CIDR
+func parseAllowEntry(entry string) *net.IPNet {
+	entry = strings.TrimSpace(entry)
+	if entry == "" {
+		return nil
+	}
+	if !strings.Contains(entry, "/") {
+		ip := net.ParseIP(entry)
+		if ip == nil {
+			return nil
+		}
+		bits := 128
+		if ip.To4() != nil {
+			ip = ip.To4()
+			bits = 32
+		}
+		return &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)}
+	}
+	_, ipNet, err := net.ParseCIDR(entry)
+	if err != nil {
+		return nil
+	}
+	return ipNet
+}
+
+var (
+	defaultLimiter *Limiter
+	defaultOnce    sync.Once
+)
+
+// Default limiter dari env:
+//
+//	RATE_LIMIT_ENABLED=false     matikan limiter
+//	RATE_LIMIT_STORE=redis       bucket di REDIS_URL (bersama antar replika), selain itu memori
+//	RATE_LIMIT_<POLICY>=10/m     budget per policy (DEFAULT, PAYMENT, INVOICE)
+//	RATE_LIMIT_ALLOWLIST=...     IP/CIDR caller internal, pisahkan dengan koma
+func Default() *Limiter {
+	defaultOnce.Do(func() {
+		var store Store = NewMemoryStore()
+		if os.Getenv("RATE_LIMIT_STORE") == "redis" {
+			redisStore, err := NewRedisStore(os.Getenv("REDIS_URL"))
+			if err != nil {
+				utils.Log.Errorf("RateLimit: Redis tidak tersedia, memakai memori: %v", err)
+			} else {
+				store = redisStore
+			}
+		}
+
+		limits := make(map[string]Limit, len(defaultLimits))
+		for policy, fallback := range defaultLimits {
+			if raw := os.Getenv("RATE_LIMIT_" + strings.ToUpper(policy)); raw != "" {
+				limit, err := ParseLimit(raw)
+				if err == nil {
+					limits[policy] = limit
+					continue
+				}
+				utils.Log.Warnf("RateLimit: %v, memakai %s", err, fallback)
+			}
+			limits[policy], _ = ParseLimit(fallback)
+		}
+
+		var allowList []string
+		if raw := os.Getenv("RATE_LIMIT_ALLOWLIST"); raw != "" {
+			allowList = strings.Split(raw, ",")
+		}
+
+		defaultLimiter = NewLimiter(store, limits, allowList)
+		defaultLimiter.Enabled = os.Getenv("RATE_LIMIT_ENABLED") != "false"
+		utils.Log.Infof("RateLimit: store %s, enabled %v", store.Name(), defaultLimiter.Enabled)
+	})
+	return defaultLimiter
+}
+
+// SetDefault mengganti limiter default (untuk pengujian)
+func SetDefault(l *Limiter) {
+	defaultOnce.Do(func() {})
+	defaultLimiter = l
+}
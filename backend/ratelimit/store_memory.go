@@ -0,0 +1,73 @@
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+type bucket struct {
+	tokens float64
+	at     time.Time
+}
+
+// MemoryStore bucket di memori proses; cukup untuk satu replika
+type MemoryStore struct {
+	mu      sync.Mutex
+	buckets map[string]*bucket
+	now     func() time.Time
+	sweptAt time.Time
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{buckets: make(map[string]*bucket), now: time.Now}
+}
+
+func (s *MemoryStore) Name() string {
+	return "memory"
+}
+
+func (s *MemoryStore) Take(_ context.Context, key string, limit Limit) (Decision, error) {
+	now := s.now()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.sweep(now, limit.Per)
+
+	b, ok := s.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(limit.Requests), at: now}
+		s.buckets[key] = b
+	}
+
+	elapsed := float64(now.Sub(b.at).Milliseconds())
+	if elapsed > 0 {
+		b.tokens = math.Min(float64(limit.Requests), b.tokens+elapsed*limit.ratePerMs())
+		b.at = now
+	}
+
+	allowed := b.tokens >= 1
+	if allowed {
+		b.tokens--
+	}
+	return decide(limit, allowed, b.tokens), nil
+}
+
+// sweep membuang bucket yang sudah lama tidak dipakai (pasti sudah penuh lagi) supaya map tidak terus membesar
+func (s *MemoryStore) sweep(now time.Time, per time.Duration) {
+	if now.Sub(s.sweptAt) < time.Minute {
+		return
+	}
+	s.sweptAt = now
+	idle := per
+	if idle < time.Hour {
+		idle = time.Hour
+	}
+	for key, b := range s.buckets {
+		if now.Sub(b.at) > idle {
+			delete(s.buckets, key)
+		}
+	}
+}
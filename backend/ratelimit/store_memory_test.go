@@ -0,0 +1,132 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestMemoryStoreTake(t *testing.T) {
+	limit := Limit{Requests: 3, Per: 3 * time.Second} // 1 token per detik
+
+	type take struct {
+		advance        time.Duration // maju jam sebelum Take
+		key            string
+		wantAllowed    bool
+		wantRemaining  int
+		wantRetryAfter time.Duration
+		wantReset      time.Duration
+	}
+	tests := []struct {
+		name  string
+		takes []take
+	}{
+		{
+			name: "budget habis lalu ditolak",
+			takes: []take{
+				{key: "a", wantAllowed: true, wantRemaining: 2, wantReset: time.Second},
+				{key: "a", wantAllowed: true, wantRemaining: 1, wantReset: 2 * time.Second},
+				{key: "a", wantAllowed: true, wantRemaining: 0, wantReset: 3 * time.Second},
+				{key: "a", wantAllowed: false, wantRemaining: 0, wantRetryAfter: time.Second, wantReset: 3 * time.Second},
+			},
+		},
+		{
+			name: "token terisi sebagian setelah jeda",
+			takes: []take{
+				{key: "a", wantAllowed: true, wantRemaining: 2, wantReset: time.Second},
+				{key: "a", wantAllowed: true, wantRemaining: 1, wantReset: 2 * time.Second},
+				{key: "a", wantAllowed: true, wantRemaining: 0, wantReset: 3 * time.Second},
+				{advance: 400 * time.Millisecond, key: "a", wantAllowed: false, wantRemaining: 0, wantRetryAfter: 600 * time.Millisecond, wantReset: 2600 * time.Millisecond},
+				{advance: 600 * time.Millisecond, key: "a", wantAllowed: true, wantRemaining: 0, wantReset: 3 * time.Second},
+			},
+		},
+		{
+			name: "bucket tidak melebihi kapasitas",
+			takes: []take{
+				{key: "a", wantAllowed: true, wantRemaining: 2, wantReset: time.Second},
+				{advance: time.Hour, key: "a", wantAllowed: true, wantRemaining: 2, wantReset: time.Second},
+			},
+		},
+		{
+			name: "key berbeda punya bucket sendiri",
+			takes: []take{
+				{key: "a", wantAllowed: true, wantRemaining: 2, wantReset: time.Second},
+				{key: "a", wantAllowed: true, wantRemaining: 1, wantReset: 2 * time.Second},
+				{key: "a", wantAllowed: true, wantRemaining: 0, wantReset: 3 * time.Second},
+				{key: "b", wantAllowed: true, wantRemaining: 2, wantReset: time.Second},
+				{key: "a", wantAllowed: false, wantRemaining: 0, wantRetryAfter: time.Second, wantReset: 3 * time.Second},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			now := time.Date(2024, 1, 1, 8, 0, 0, 0, time.UTC)
+			store := NewMemoryStore()
+			store.now = func() time.Time { return now }
+
+			for i, step := range tt.takes {
+				now = now.Add(step.advance)
+				decision, err := store.Take(context.Background(), step.key, limit)
+				if err != nil {
+					t.Fatalf("take %d: %v", i, err)
+				}
+				want := Decision{
+					Allowed:    step.wantAllowed,
+					Limit:      limit.Requests,
+					Remaining:  step.wantRemaining,
+					RetryAfter: step.wantRetryAfter,
+					Reset:      step.wantReset,
+				}
+				if decision != want {
+					t.Errorf("take %d: %+v, want %+v", i, decision, want)
+				}
+			}
+		})
+	}
+}
+
+func TestMemoryStoreSweepsIdleBuckets(t *testing.T) {
+	now := time.Date(2024, 1, 1, 8, 0, 0, 0, time.UTC)
+	store := NewMemoryStore()
+	store.now = func() time.Time { return now }
+	limit := Limit{Requests: 10, Per: time.Minute}
+
+	store.Take(context.Background(), "lama", limit)
+	now = now.Add(2 * time.Hour)
+	store.Take(context.Background(), "baru", limit)
+
+	if _, ok := store.buckets["lama"]; ok {
+		t.Error("bucket yang tidak dipakai lebih dari satu jam tidak dibuang")
+	}
+	if _, ok := store.buckets["baru"]; !ok {
+		t.Error("bucket aktif ikut dibuang")
+	}
+}
+
+func TestParseLimit(t *testing.T) {
+	tests := []struct {
+		raw     string
+		want    Limit
+		wantErr bool
+	}{
+		{raw: "10/m", want: Limit{Requests: 10, Per: time.Minute}},
+		{raw: " 5 / s ", want: Limit{Requests: 5, Per: time.Second}},
+		{raw: "1200/h", want: Limit{Requests: 1200, Per: time.Hour}},
+		{raw: "10", wantErr: true},
+		{raw: "0/m", wantErr: true},
+		{raw: "-1/m", wantErr: true},
+		{raw: "10/d", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.raw, func(t *testing.T) {
+			got, err := ParseLimit(tt.raw)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseLimit(%q) = %+v, want %+v", tt.raw, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,80 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const defaultRedisPrefix = "ratelimit:"
+
+// takeScript token bucket atomik di Redis; waktu diambil dari server Redis supaya sama untuk semua replika
+var takeScript = redis.NewScript(`
+local capacity = tonumber(ARGV[1])
+local rate = tonumber(ARGV[2])
+local t = redis.call('TIME')
+local now = tonumber(t[1]) * 1000 + math.floor(tonumber(t[2]) / 1000)
+
+local data = redis.call('HMGET', KEYS[1], 'tokens', 'ts')
+local tokens = tonumber(data[1])
+local ts = tonumber(data[2])
+if tokens == nil then
+  tokens = capacity
+  ts = now
+end
+
+tokens = math.min(capacity, tokens + math.max(0, now - ts) * rate)
+local allowed = 0
+if tokens >= 1 then
+  tokens = tokens - 1
+  allowed = 1
+end
+
+redis.call('HSET', KEYS[1], 'tokens', tostring(tokens), 'ts', now)
+redis.call('PEXPIRE', KEYS[1], math.ceil(capacity / rate))
+return {allowed, tostring(tokens)}
+`)
+
+// RedisStore bucket bersama untuk semua replika backend
+type RedisStore struct {
+	client *redis.Client
+	prefix string
+}
+
+func NewRedisStore(redisURL string) (*RedisStore, error) {
+	opts, err := redis.ParseURL(redisURL)
+	if err != nil {
+		return nil, fmt.Errorf("REDIS_URL tidak valid: %w", err)
+	}
+
+	client := redis.NewClient(opts)
+	if err := client.Ping(context.Background()).Err(); err != nil {
+		client.Close()
+		return nil, fmt.Errorf("gagal terhubung ke Redis: %w", err)
+	}
+	return &RedisStore{client: client, prefix: defaultRedisPrefix}, nil
+}
+
+func (s *RedisStore) Name() string {
+	return "redis"
+}
+
+func (s *RedisStore) Take(ctx context.Context, key string, limit Limit) (Decision, error) {
+	raw, err := takeScript.Run(ctx, s.client, []string{s.prefix + key}, limit.Requests, limit.ratePerMs()).Slice()
+	if err != nil {
+		return Decision{}, err
+	}
+	if len(raw) != 2 {
+		return Decision{}, fmt.Errorf("respon script rate limit tidak valid: %v", raw)
+	}
+
+	allowed, _ := raw[0].(int64)
+	tokensStr, _ := raw[1].(string)
+	tokens, err := strconv.ParseFloat(tokensStr, 64)
+	if err != nil {
+		return Decision{}, fmt.Errorf("sisa token tidak valid %q: %w", tokensStr, err)
+	}
+	return decide(limit, allowed == 1, tokens), nil
+}
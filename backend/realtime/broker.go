@@ -0,0 +1,68 @@
+package realtime
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Tipe event status pembayaran
+const (
+	EventPaymentStatus = "payment_status"
+)
+
+// Event satu perubahan yang dikirim ke browser mahasiswa.
+// ID dibuat saat publish (unix nano) sehingga sama di semua replika dan bisa dipakai sebagai Last-Event-ID.
+type Event struct {
+	ID   int64                  `json:"id"`
+	Key  string                 `json:"key"` // kunci dedupe, mis. "payment:123"
+	NPM  string                 `json:"npm"`
+	Type string                 `json:"type"`
+	Data map[string]interface{} `json:"data"`
+	At   time.Time              `json:"at"`
+}
+
+// Broker penyalur event antar replika backend.
+// Subscribe memanggil handler untuk setiap event yang dipublish (termasuk dari replika lain).
+type Broker interface {
+	Name() string
+	Publish(ctx context.Context, event Event) error
+	Subscribe(handler func(Event)) (unsubscribe func(), err error)
+}
+
+// InProcessBroker broker default: event hanya tersebar di dalam proses ini
+type InProcessBroker struct {
+	mu       sync.RWMutex
+	nextID   int
+	handlers map[int]func(Event)
+}
+
+func NewInProcessBroker() *InProcessBroker {
+	return &InProcessBroker{handlers: make(map[int]func(Event))}
+}
+
+func (b *InProcessBroker) Name() string {
+	return "memory"
+}
+
+func (b *InProcessBroker) Publish(ctx context.Context, event Event) error {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	for _, handler := range b.handlers {
+		handler(event)
+	}
+	return nil
+}
+
+func (b *InProcessBroker) Subscribe(handler func(Event)) (func(), error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.nextID++
+	id := b.nextID
+	b.handlers[id] = handler
+	return func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		delete(b.handlers, id)
+	}, nil
+}
@@ -0,0 +1,68 @@
+package realtime
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/dedegunawan/backend-ujian-telp-v5/utils"
+	"github.com/redis/go-redis/v9"
+)
+
+const defaultRedisChannel = "payment-status-events"
+
+// RedisBroker menyebarkan event ke semua replika lewat Redis pub/sub
+type RedisBroker struct {
+	client  *redis.Client
+	channel string
+}
+
+func NewRedisBroker(redisURL, channel string) (*RedisBroker, error) {
+	opts, err := redis.ParseURL(redisURL)
+	if err != nil {
+		return nil, fmt.Errorf("REDIS_URL tidak valid: %w", err)
+	}
+	if channel == "" {
+		channel = defaultRedisChannel
+	}
+
+	client := redis.NewClient(opts)
+	if err := client.Ping(context.Background()).Err(); err != nil {
+		client.Close()
+		return nil, fmt.Errorf("gagal terhubung ke Redis: %w", err)
+	}
+	return &RedisBroker{client: client, channel: channel}, nil
+}
+
+func (b *RedisBroker) Name() string {
+	return "redis"
+}
+
+func (b *RedisBroker) Publish(ctx context.Context, event Event) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	return b.client.Publish(ctx, b.channel, payload).Err()
+}
+
+func (b *RedisBroker) Subscribe(handler func(Event)) (func(), error) {
+	pubsub := b.client.Subscribe(context.Background(), b.channel)
+	if _, err := pubsub.Receive(context.Background()); err != nil {
+		pubsub.Close()
+		return nil, fmt.Errorf("gagal subscribe channel %s: %w", b.channel, err)
+	}
+
+	go func() {
+		for msg := range pubsub.Channel() {
+			var event Event
+			if err := json.Unmarshal([]byte(msg.Payload), &event); err != nil {
+				utils.Log.Warnf("RedisBroker: payload event tidak valid: %v", err)
+				continue
+			}
+			handler(event)
+		}
+	}()
+
+	return func() { pubsub.Close() }, nil
+}
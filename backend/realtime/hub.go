@@ -0,0 +1,245 @@
+package realtime
+
+import (
+	"context"
+	"os"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/dedegunawan/backend-ujian-telp-v5/utils"
+)
+
+const (
+	defaultHistorySize = 50
+	defaultHistoryTTL  = 10 * time.Minute
+	subscriberBuffer   = 16
+)
+
+// Hub membagikan event dari broker ke koneksi SSE per NPM.
+// Setiap NPM punya riwayat pendek (ring buffer) untuk replay Last-Event-ID saat browser reconnect.
+// Riwayat NPM yang tidak menerima event selama historyTTL (jendela reconnect) dibuang supaya
+// memori tidak bertambah untuk setiap mahasiswa yang pernah membayar.
+type Hub struct {
+	broker      Broker
+	historySize int
+	historyTTL  time.Duration
+	lastID      int64
+
+	mu        sync.RWMutex
+	closed    bool
+	nextSub   int
+	subs      map[string]map[int]chan Event
+	history   map[string][]Event
+	touched   map[string]time.Time // waktu event terakhir diterima per NPM
+	lastSweep time.Time
+}
+
+func NewHub(broker Broker, historySize int, historyTTL time.Duration) (*Hub, error) {
+	if historySize <= 0 {
+		historySize = defaultHistorySize
+	}
+	if historyTTL <= 0 {
+		historyTTL = defaultHistoryTTL
+	}
+	h := &Hub{
+		broker:      broker,
+		historySize: historySize,
+		historyTTL:  historyTTL,
+		subs:        make(map[string]map[int]chan Event),
+		history:     make(map[string][]Event),
+		touched:     make(map[string]time.Time),
+		lastSweep:   time.Now(),
+	}
+	if _, err := broker.Subscribe(h.dispatch); err != nil {
+		return nil, err
+	}
+	return h, nil
+}
+
+// BrokerName nama broker yang dipakai hub
+func (h *Hub) BrokerName() string {
+	return h.broker.Name()
+}
+
+// Publish mengirim event ke broker; hub (di semua replika) akan menerimanya lewat dispatch
+func (h *Hub) Publish(ctx context.Context, event Event) error {
+	if event.NPM == "" {
+		return nil
+	}
+	if event.ID == 0 {
+		event.ID = h.newID()
+	}
+	if event.At.IsZero() {
+		event.At = time.Now()
+	}
+	return h.broker.Publish(ctx, event)
+}
+
+// newID unix nano yang selalu naik di proses ini
+func (h *Hub) newID() int64 {
+	for {
+		last := atomic.LoadInt64(&h.lastID)
+		next := time.Now().UnixNano()
+		if next <= last {
+			next = last + 1
+		}
+		if atomic.CompareAndSwapInt64(&h.lastID, last, next) {
+			return next
+		}
+	}
+}
+
+func (h *Hub) dispatch(event Event) {
+	now := time.Now()
+	h.mu.Lock()
+	if now.Sub(h.lastSweep) >= h.historyTTL {
+		h.evictLocked(now)
+	}
+
+	history := h.history[event.NPM]
+	if event.Key != "" {
+		for _, past := range history {
+			if past.Key == event.Key {
+				// Sudah pernah dikirim (mis. dari callback dan poller sekaligus)
+				h.mu.Unlock()
+				return
+			}
+		}
+	}
+	history = append(history, event)
+	if len(history) > h.historySize {
+		history = history[len(history)-h.historySize:]
+	}
+	h.history[event.NPM] = history
+	h.touched[event.NPM] = now
+
+	for _, ch := range h.subs[event.NPM] {
+		select {
+		case ch <- event:
+		default:
+			// Klien lambat: event dilewati, klien akan mengejar lewat Last-Event-ID saat reconnect
+			utils.Log.Warnw("Realtime hub: buffer subscriber penuh, event dilewati",
+				"npm", event.NPM,
+				"event_id", event.ID,
+			)
+		}
+	}
+	h.mu.Unlock()
+}
+
+// evictLocked membuang riwayat NPM yang event terakhirnya lebih lama dari historyTTL; h.mu harus dipegang
+func (h *Hub) evictLocked(now time.Time) {
+	for npm, touched := range h.touched {
+		if now.Sub(touched) >= h.historyTTL {
+			delete(h.history, npm)
+			delete(h.touched, npm)
+		}
+	}
+	h.lastSweep = now
+}
+
+// Subscribe mendaftarkan koneksi baru untuk NPM. Panggil cancel saat koneksi ditutup.
+func (h *Hub) Subscribe(npm string) (<-chan Event, func()) {
+	ch := make(chan Event, subscriberBuffer)
+
+	h.mu.Lock()
+	if h.closed {
+		h.mu.Unlock()
+		close(ch)
+		return ch, func() {}
+	}
+	h.nextSub++
+	id := h.nextSub
+	if h.subs[npm] == nil {
+		h.subs[npm] = make(map[int]chan Event)
+	}
+	h.subs[npm][id] = ch
+	h.mu.Unlock()
+
+	cancel := func() {
+		h.mu.Lock()
+		defer h.mu.Unlock()
+		delete(h.subs[npm], id)
+		if len(h.subs[npm]) == 0 {
+			delete(h.subs, npm)
+		}
+	}
+	return ch, cancel
+}
+
+// Close menutup semua channel subscriber (saat shutdown) supaya koneksi SSE selesai;
+// browser akan reconnect ke replika lain dengan Last-Event-ID.
+func (h *Hub) Close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.closed {
+		return
+	}
+	h.closed = true
+	for npm, subs := range h.subs {
+		for _, ch := range subs {
+			close(ch)
+		}
+		delete(h.subs, npm)
+	}
+}
+
+// Since event untuk NPM yang ID-nya lebih besar dari lastID (untuk replay setelah reconnect)
+func (h *Hub) Since(npm string, lastID int64) []Event {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	// Riwayat di luar jendela reconnect yang belum sempat dibuang evictLocked
+	if time.Since(h.touched[npm]) >= h.historyTTL {
+		return nil
+	}
+
+	var events []Event
+	for _, event := range h.history[npm] {
+		if event.ID > lastID {
+			events = append(events, event)
+		}
+	}
+	return events
+}
+
+var (
+	defaultHub  *Hub
+	defaultOnce sync.Once
+)
+
+// Default hub dari env. REALTIME_BROKER=redis memakai REDIS_URL agar event tersebar ke semua replika;
+// selain itu (atau jika Redis gagal) memakai broker in-process. REALTIME_HISTORY_TTL (default 10m)
+// adalah jendela reconnect untuk replay Last-Event-ID.
+func Default() *Hub {
+	defaultOnce.Do(func() {
+		historySize, _ := strconv.Atoi(os.Getenv("REALTIME_HISTORY_SIZE"))
+		historyTTL, _ := time.ParseDuration(os.Getenv("REALTIME_HISTORY_TTL"))
+
+		var broker Broker = NewInProcessBroker()
+		if os.Getenv("REALTIME_BROKER") == "redis" {
+			redisBroker, err := NewRedisBroker(os.Getenv("REDIS_URL"), os.Getenv("REALTIME_REDIS_CHANNEL"))
+			if err != nil {
+				utils.Log.Errorf("Realtime: Redis broker tidak tersedia, memakai in-process: %v", err)
+			} else {
+				broker = redisBroker
+			}
+		}
+
+		hub, err := NewHub(broker, historySize, historyTTL)
+		if err != nil {
+			utils.Log.Errorf("Realtime: gagal subscribe broker %s, memakai in-process: %v", broker.Name(), err)
+			hub, _ = NewHub(NewInProcessBroker(), historySize, historyTTL)
+		}
+		defaultHub = hub
+	})
+	return defaultHub
+}
+
+// SetDefault mengganti hub default (untuk pengujian)
+func SetDefault(h *Hub) {
+	defaultOnce.Do(func() {})
+	defaultHub = h
+}
@@ -62,7 +62,7 @@ func (r *mahasiswaRepository) FindByMhswID(mhswID string) (*models.Mahasiswa, er
 
 func (r *mahasiswaRepository) FindByEmailPattern(email string) (*models.Mahasiswa, error) {
 	mhswID := utils.GetEmailPrefix(email)
-	utils.Log.Info("Prefix :", mhswID)
+	utils.Log.Infow("Prefix", "mhsw_id", mhswID)
 	return r.FindByMhswID(mhswID)
 }
 
@@ -27,7 +27,7 @@ func (mtr *MasterTagihanRepository) GetNominalTagihanMahasiswa(mahasiswa models.
 	}
 
 	if err != nil {
-		utils.Log.Info("GetNominalTagihanMahasiswa: error finding master tagihan for mahasiswa: ", err)
+		utils.Log.Infow("GetNominalTagihanMahasiswa: error finding master tagihan for mahasiswa", "error", err)
 	}
 
 	return 0
@@ -48,12 +48,12 @@ func (mtr *MasterTagihanRepository) FindMasterTagihanMahasiswa(mahasiswa models.
 		
 		if err == nil && detailTagihan.KelUKT != nil {
 			// Berhasil menemukan detail_tagihan dengan nominal yang sesuai
-			utils.Log.Info("Using mahasiswa_masters data for tagihan lookup", map[string]interface{}{
-				"StudentID":      mhswMaster.StudentID,
-				"MasterTagihanID": mhswMaster.MasterTagihanID,
-				"NominalUKT":     mhswMaster.UKT,
-				"KelompokUKT":    *detailTagihan.KelUKT,
-			})
+			utils.Log.Infow("Using mahasiswa_masters data for tagihan lookup",
+				"StudentID", mhswMaster.StudentID,
+				"MasterTagihanID", mhswMaster.MasterTagihanID,
+				"NominalUKT", mhswMaster.UKT,
+				"KelompokUKT", *detailTagihan.KelUKT,
+			)
 			return &detailTagihan, nil
 		}
 		
@@ -62,12 +62,12 @@ func (mtr *MasterTagihanRepository) FindMasterTagihanMahasiswa(mahasiswa models.
 			First(&detailTagihan).Error
 		
 		if err == nil && detailTagihan.KelUKT != nil {
-			utils.Log.Warn("Detail tagihan ditemukan dengan fallback (tidak match nominal)", map[string]interface{}{
-				"StudentID":      mhswMaster.StudentID,
-				"MasterTagihanID": mhswMaster.MasterTagihanID,
-				"NominalUKT":     mhswMaster.UKT,
-				"KelompokUKT":    *detailTagihan.KelUKT,
-			})
+			utils.Log.Warnw("Detail tagihan ditemukan dengan fallback (tidak match nominal)",
+				"StudentID", mhswMaster.StudentID,
+				"MasterTagihanID", mhswMaster.MasterTagihanID,
+				"NominalUKT", mhswMaster.UKT,
+				"KelompokUKT", *detailTagihan.KelUKT,
+			)
 			return &detailTagihan, nil
 		}
 		
@@ -99,14 +99,14 @@ func (mtr *MasterTagihanRepository) FindMasterTagihanMahasiswa(mahasiswa models.
 		programID = "1 - Reg" // Default to program 1 if not specified
 	}
 
-	utils.Log.Info("Search by : ", map[string]interface{}{
-		"TahunID":      tahunIDString,
-		"ProdiID":      prodiIDString,
-		"ProgramID":    programID,
-		"RAWTahunID":   mahasiswa.ParseFullData()["TahunID"],
-		"RAWProdiID":   mahasiswa.ParseFullData()["ProdiID"],
-		"RAWProgramID": mahasiswa.ParseFullData()["ProgramID"],
-	})
+	utils.Log.Infow("Search by",
+		"TahunID", tahunIDString,
+		"ProdiID", prodiIDString,
+		"ProgramID", programID,
+		"RAWTahunID", mahasiswa.ParseFullData()["TahunID"],
+		"RAWProdiID", mahasiswa.ParseFullData()["ProdiID"],
+		"RAWProgramID", mahasiswa.ParseFullData()["ProgramID"],
+	)
 
 	if tahun == "" || prodiIDString == "" || programID == "" {
 		return nil, errors.New("invalid mahasiswa data: TahunID, ProdiID, or ProgramID is missing")
@@ -139,11 +139,11 @@ func (mtr *MasterTagihanRepository) FindMasterTagihanMahasiswa(mahasiswa models.
 			First(&detailTagihanByNominal).Error
 		if errNominal == nil && detailTagihanByNominal.KelUKT != nil {
 			UKTString = *detailTagihanByNominal.KelUKT
-			utils.Log.Info("Kelompok UKT diambil dari detail_tagihan berdasarkan nominal", "nominalUKT", mhswMaster.UKT, "kelompokUKT", UKTString)
+			utils.Log.Infow("Kelompok UKT diambil dari detail_tagihan berdasarkan nominal", "nominalUKT", mhswMaster.UKT, "kelompokUKT", UKTString)
 		}
 	}
 
-	utils.Log.Info("Querying detail tagihan for MasterTagihanID: ", tagihan.ID, " with UKT: ", UKTString)
+	utils.Log.Infow("Querying detail tagihan", "master_tagihan_id", tagihan.ID, "ukt", UKTString)
 
 	var detailTagihan models.DetailTagihan
 	err = mtr.DB.Where("master_tagihan_id = ? and kel_ukt = ?", tagihan.ID, UKTString).
@@ -61,7 +61,7 @@ func (r *TagihanRepository) OverrideFinanceYear(financeYear *models.FinanceYear,
 	utils.Log.Info("Mencoba melakukan override")
 
 	if err != nil {
-		utils.Log.Info("Tidak melakukan override finance year karena tidak ada budget period aktif:", err)
+		utils.Log.Infow("Tidak melakukan override finance year karena tidak ada budget period aktif", "error", err)
 		return nil
 	}
 
@@ -79,7 +79,7 @@ func (r *TagihanRepository) OverrideFinanceYear(financeYear *models.FinanceYear,
 		return nil
 	}
 
-	utils.Log.Info("Override Finance Year untuk mahasiswa:", mahasiswa.MhswID)
+	utils.Log.Infow("Override Finance Year untuk mahasiswa", "mhsw_id", mahasiswa.MhswID)
 
 	// Ambil prodi langsung dari mahasiswa_masters di database PNBP
 	var mhswMaster models.MahasiswaMaster
@@ -101,19 +101,19 @@ func (r *TagihanRepository) OverrideFinanceYear(financeYear *models.FinanceYear,
 			if errFakultas == nil {
 				fakultas = &fakultasData
 			}
-			utils.Log.Info("Prodi diambil dari mahasiswa_masters di database PNBP", map[string]interface{}{
-				"mhswID":       mahasiswa.MhswID,
-				"ProdiID":      mhswMaster.ProdiID,
-				"KodeProdi":    prodi.KodeProdi,
-				"FakultasID":   prodi.FakultasID,
-				"KodeFakultas": fakultas.KodeFakultas,
-			})
+			utils.Log.Infow("Prodi diambil dari mahasiswa_masters di database PNBP",
+				"mhswID", mahasiswa.MhswID,
+				"ProdiID", mhswMaster.ProdiID,
+				"KodeProdi", prodi.KodeProdi,
+				"FakultasID", prodi.FakultasID,
+				"KodeFakultas", fakultas.KodeFakultas,
+			)
 		} else {
-			utils.Log.Warn("Gagal ambil prodi dari mahasiswa_masters, fallback ke kode prodi", map[string]interface{}{
-				"mhswID":  mahasiswa.MhswID,
-				"ProdiID": mhswMaster.ProdiID,
-				"error":   errProdi,
-			})
+			utils.Log.Warnw("Gagal ambil prodi dari mahasiswa_masters, fallback ke kode prodi",
+				"mhswID", mahasiswa.MhswID,
+				"ProdiID", mhswMaster.ProdiID,
+				"error", errProdi,
+			)
 			// Fallback: coba ambil dari kode_prodi jika ada di FullData
 			if prodiIDString := utils.GetStringFromAny(mahasiswa.ParseFullData()["ProdiID"]); prodiIDString != "" {
 				prodi, fakultas, errProdi, errFakultas = r.GetValidProdiPnbp(prodiIDString)
@@ -126,27 +126,27 @@ func (r *TagihanRepository) OverrideFinanceYear(financeYear *models.FinanceYear,
 			prodiIDString = mahasiswa.Prodi.KodeProdi
 		}
 		if prodiIDString != "" {
-			utils.Log.Info("Fallback: ambil prodi dari kode_prodi", "mhswID", mahasiswa.MhswID, "kodeProdi", prodiIDString)
+			utils.Log.Infow("Fallback: ambil prodi dari kode_prodi", "mhswID", mahasiswa.MhswID, "kodeProdi", prodiIDString)
 			prodi, fakultas, errProdi, errFakultas = r.GetValidProdiPnbp(prodiIDString)
 		} else {
-			utils.Log.Warn("Tidak dapat mengambil prodi dari mahasiswa_masters maupun kode_prodi", "mhswID", mahasiswa.MhswID)
+			utils.Log.Warnw("Tidak dapat mengambil prodi dari mahasiswa_masters maupun kode_prodi", "mhswID", mahasiswa.MhswID)
 			errProdi = gorm.ErrRecordNotFound
 			errFakultas = gorm.ErrRecordNotFound
 		}
 	}
 
 	if prodi != nil {
-		utils.Log.Info("Prodi:", prodi.KodeProdi, " - ", prodi.NamaProdi)
+		utils.Log.Infow("Prodi", "kode_prodi", prodi.KodeProdi, "nama_prodi", prodi.NamaProdi)
 	} else {
 		utils.Log.Warn("Prodi nil")
 	}
 	if fakultas != nil {
-		utils.Log.Info("Fakultas:", fakultas.KodeFakultas, " - ", fakultas.NamaFakultas)
+		utils.Log.Infow("Fakultas", "kode_fakultas", fakultas.KodeFakultas, "nama_fakultas", fakultas.NamaFakultas)
 	} else {
 		utils.Log.Warn("Fakultas nil")
 	}
-	utils.Log.Info("Err Prodi:", errProdi)
-	utils.Log.Info("Err Fakultas:", errFakultas)
+	utils.Log.Infow("Err Prodi", "error", errProdi)
+	utils.Log.Infow("Err Fakultas", "error", errFakultas)
 
 	if errProdi != nil {
 		skippedProdi = true
@@ -160,7 +160,7 @@ func (r *TagihanRepository) OverrideFinanceYear(financeYear *models.FinanceYear,
 
 	// cek apakah ada fakultas mahasiswa di daftar override
 	if !skippedFakultas && fakultas != nil {
-		utils.Log.Info("Lakukan override fakultas:", fakultas.KodeFakultas, " - ", fakultas.NamaFakultas)
+		utils.Log.Infow("Lakukan override fakultas", "kode_fakultas", fakultas.KodeFakultas, "nama_fakultas", fakultas.NamaFakultas)
 		overrideFakultas, err := r.GetBudgetOverride("fakultas", strconv.Itoa(int(fakultas.ID)), strconv.Itoa(int(budgetPeriod.ID)))
 		if err == nil && overrideFakultas != nil {
 			defaultPaymentStartDate = overrideFakultas.PaymentStartDate
@@ -170,7 +170,7 @@ func (r *TagihanRepository) OverrideFinanceYear(financeYear *models.FinanceYear,
 
 	// cek apakah ada prodi mahasiswa di daftar override
 	if !skippedProdi && prodi != nil {
-		utils.Log.Info("Lakukan override prodi:", prodi.KodeProdi, " - ", prodi.NamaProdi)
+		utils.Log.Infow("Lakukan override prodi", "kode_prodi", prodi.KodeProdi, "nama_prodi", prodi.NamaProdi)
 		overrideProdi, err := r.GetBudgetOverride("prodi", strconv.Itoa(int(prodi.ID)), strconv.Itoa(int(budgetPeriod.ID)))
 		if err == nil && overrideProdi != nil {
 			defaultPaymentStartDate = overrideProdi.PaymentStartDate
@@ -184,9 +184,9 @@ func (r *TagihanRepository) OverrideFinanceYear(financeYear *models.FinanceYear,
 	if err == nil && findMahasiswaPnbp != nil {
 		overrideIndividual, err = r.GetBudgetOverride("individual", strconv.Itoa(int(findMahasiswaPnbp.ID)), strconv.Itoa(int(budgetPeriod.ID)))
 	}
-	utils.Log.Info("Err override individual:", err, overrideIndividual)
+	utils.Log.Infow("Override individual", "error", err, "override", overrideIndividual)
 	if err == nil && overrideIndividual != nil {
-		utils.Log.Info("Lakukan override individual:", mahasiswa.MhswID)
+		utils.Log.Infow("Lakukan override individual", "mhsw_id", mahasiswa.MhswID)
 		defaultPaymentStartDate = overrideIndividual.PaymentStartDate
 		defaultPaymentEndDate = overrideIndividual.PaymentEndDate
 	}
@@ -224,7 +224,7 @@ func (r *TagihanRepository) GetBudgetOverride(scope string, scopeID string, budg
 }
 
 func (r *TagihanRepository) GetValidProdiPnbp(ProdiID string) (*models.ProdiPnbp, *models.FakultasPnbp, error, error) {
-	utils.Log.Info("ProdiID:", ProdiID)
+	utils.Log.Infow("ProdiID", "prodi_id", ProdiID)
 	var prodi models.ProdiPnbp
 	var fakultas models.FakultasPnbp
 	err := r.DBPNBP.
@@ -237,7 +237,7 @@ func (r *TagihanRepository) GetValidProdiPnbp(ProdiID string) (*models.ProdiPnbp
 		err2 = r.DBPNBP.
 			Where("kode_fakultas = ?", kodeFakultas).First(&fakultas).Error
 	} else {
-		utils.Log.Warn("ProdiID terlalu pendek untuk mengambil kode fakultas", "ProdiID", ProdiID, "length", len(ProdiID))
+		utils.Log.Warnw("ProdiID terlalu pendek untuk mengambil kode fakultas", "ProdiID", ProdiID, "length", len(ProdiID))
 		err2 = gorm.ErrRecordNotFound
 	}
 
@@ -345,7 +345,7 @@ func (r *TagihanRepository) GetBeasiswaByMahasiswaTahun(studentID string, academ
 		Scan(&total).Error
 
 	if err != nil {
-		utils.Log.Info("Error saat ambil total nominal_beasiswa:", err)
+		utils.Log.Errorw("Error saat ambil total nominal_beasiswa", "error", err)
 		return 0
 	}
 
@@ -92,3 +92,16 @@ func (r *UserRepository) FindUsers(query *gorm.DB, offset, limit int) ([]models.
 	err := query.Offset(offset).Limit(limit).Find(&users).Error
 	return users, err
 }
+
+// HasPermission true jika user aktif dengan email tsb punya permission lewat salah satu rolenya
+func (r *UserRepository) HasPermission(email, permission string) (bool, error) {
+	var total int64
+	err := r.DB.Table("users").
+		Joins("JOIN user_roles ur ON ur.user_id = users.id").
+		Joins("JOIN role_permissions rp ON rp.role_id = ur.role_id").
+		Joins("JOIN permissions p ON p.id = rp.permission_id AND p.deleted_at IS NULL").
+		Where("users.email = ? AND users.is_active = ? AND users.deleted_at IS NULL", email, true).
+		Where("p.name = ?", permission).
+		Count(&total).Error
+	return total > 0, err
+}
@@ -17,6 +17,10 @@ func (r *UserTokenRepository) Create(token *models.UserToken) error {
 	return r.DB.Create(token).Error
 }
 
+func (r *UserTokenRepository) Update(token *models.UserToken) error {
+	return r.DB.Save(token).Error
+}
+
 // Dapatkan token aktif terakhir user (bisa digunakan untuk refresh/logout)
 func (r *UserTokenRepository) FindLatestByUserID(userID uuid.UUID) (*models.UserToken, error) {
 	var token models.UserToken
@@ -27,21 +31,54 @@ func (r *UserTokenRepository) FindLatestByUserID(userID uuid.UUID) (*models.User
 	return &token, err
 }
 
-// Dapatkan token aktif terakhir user (bisa digunakan untuk refresh/logout)
-func (r *UserTokenRepository) FindByAccessToken(accessToken string) (*models.UserToken, error) {
+// Cari sesi berdasarkan hash access token
+func (r *UserTokenRepository) FindByAccessTokenHash(hash string) (*models.UserToken, error) {
 	var token models.UserToken
 	err := r.DB.
-		Where("access_token = ?", accessToken).
+		Where("access_token_hash = ?", hash).
 		First(&token).Error
 	return &token, err
 }
 
+// Cari sesi berdasarkan hash refresh token
+func (r *UserTokenRepository) FindByRefreshTokenHash(hash string) (*models.UserToken, error) {
+	var token models.UserToken
+	err := r.DB.
+		Where("refresh_token_hash = ?", hash).
+		First(&token).Error
+	return &token, err
+}
+
+// Cabut semua token dalam satu sesi (semua hasil rotasi)
+func (r *UserTokenRepository) RevokeSession(sessionID uuid.UUID, reason string) error {
+	return r.DB.Model(&models.UserToken{}).
+		Where("session_id = ? AND revoked_at IS NULL", sessionID).
+		Updates(map[string]interface{}{"revoked_at": time.Now(), "revoked_reason": reason}).Error
+}
+
+// Cabut semua sesi milik user (berdasarkan user_id lokal atau email SSO)
+func (r *UserTokenRepository) RevokeByUser(userID uuid.UUID, email string, reason string) (int64, error) {
+	query := r.DB.Model(&models.UserToken{}).Where("revoked_at IS NULL")
+	if userID != uuid.Nil && email != "" {
+		query = query.Where("user_id = ? OR email = ?", userID, email)
+	} else if userID != uuid.Nil {
+		query = query.Where("user_id = ?", userID)
+	} else {
+		query = query.Where("email = ?", email)
+	}
+	result := query.Updates(map[string]interface{}{"revoked_at": time.Now(), "revoked_reason": reason})
+	return result.RowsAffected, result.Error
+}
+
 // Hapus semua token user (untuk logout semua sesi)
 func (r *UserTokenRepository) DeleteByUserID(userID uuid.UUID) error {
 	return r.DB.Where("user_id = ?", userID).Delete(&models.UserToken{}).Error
 }
 
-// Hapus token expired
+// Hapus token yang access dan refresh token-nya sudah kedaluwarsa
 func (r *UserTokenRepository) DeleteExpiredTokens() error {
-	return r.DB.Where("expires_at < ?", time.Now()).Delete(&models.UserToken{}).Error
+	now := time.Now()
+	return r.DB.
+		Where("expires_at < ? AND (refresh_expires_at IS NULL OR refresh_expires_at < ?)", now, now).
+		Delete(&models.UserToken{}).Error
 }
@@ -8,7 +8,11 @@ import (
 
 func RegisterAdministrator(r *gin.RouterGroup) {
 	RegisterUserRoutes(r)
-
+	RegisterFinanceRoutes(r)
+	RegisterExportRoutes(r)
+	RegisterNotificationRoutes(r)
+	RegisterWebhookRoutes(r)
+	RegisterImpersonationRoutes(r)
 }
 
 func RegisterUserRoutes(r *gin.RouterGroup) {
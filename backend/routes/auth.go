@@ -2,12 +2,26 @@ package routes
 
 import (
 	"github.com/dedegunawan/backend-ujian-telp-v5/controllers"
+	"github.com/dedegunawan/backend-ujian-telp-v5/middleware"
 	"github.com/gin-gonic/gin"
 )
 
 func RegisterAuthRoutes(r gin.IRoutes) {
 	r.GET("/sso-login", controllers.SsoLoginHandler)
 	r.GET("/sso-logout", controllers.SsoLogoutHandler)
+	r.POST("/logout", controllers.LogoutHandler)
 	r.POST("/login", controllers.LoginHandler)
+	r.POST("/login/2fa", controllers.LoginSecondFactorHandler)
+	r.POST("/password/forgot", controllers.ForgotPasswordHandler)
+	r.POST("/password/reset", controllers.ResetPasswordHandler)
+	r.POST("/refresh", controllers.RefreshHandler)
 	r.GET("/callback", controllers.CallbackHandler)
+	r.POST("/auth/exchange", controllers.ExchangeAuthCodeHandler)
+
+	// TOTP 2FA untuk akun login lokal (email/password)
+	r.GET("/auth/2fa", middleware.RequireAuthFromTokenDB(), controllers.TwoFactorStatusHandler)
+	r.POST("/auth/2fa/setup", middleware.RequireAuthFromTokenDB(), controllers.TwoFactorSetupHandler)
+	r.POST("/auth/2fa/enable", middleware.RequireAuthFromTokenDB(), controllers.TwoFactorEnableHandler)
+	r.POST("/auth/2fa/disable", middleware.RequireAuthFromTokenDB(), controllers.TwoFactorDisableHandler)
+	r.POST("/auth/2fa/recovery-codes", middleware.RequireAuthFromTokenDB(), controllers.TwoFactorRecoveryCodesHandler)
 }
@@ -0,0 +1,19 @@
+package routes
+
+import (
+	"github.com/dedegunawan/backend-ujian-telp-v5/controllers"
+	"github.com/dedegunawan/backend-ujian-telp-v5/middleware"
+	"github.com/dedegunawan/backend-ujian-telp-v5/models"
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterExportRoutes(r *gin.RouterGroup) {
+	exports := r.Group("/exports")
+	exports.Use(middleware.RequireAuthFromTokenDB(), middleware.RequirePermission(models.PermissionExportFinance))
+	{
+		exports.GET("", controllers.GetExportHistory)
+		exports.GET("/:id", controllers.GetExport)
+		exports.POST("/student-bills", controllers.ExportStudentBills)
+		exports.POST("/payment-status", controllers.ExportPaymentStatus)
+	}
+}
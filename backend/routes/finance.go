@@ -0,0 +1,37 @@
+package routes
+
+import (
+	"github.com/dedegunawan/backend-ujian-telp-v5/controllers"
+	"github.com/dedegunawan/backend-ujian-telp-v5/middleware"
+	"github.com/dedegunawan/backend-ujian-telp-v5/models"
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterFinanceRoutes(r *gin.RouterGroup) {
+	finance := r.Group("/finance")
+	finance.Use(middleware.RequireAuthFromTokenDB())
+
+	reports := finance.Group("", middleware.RequirePermission(models.PermissionViewFinance))
+	{
+		reports.GET("/revenue", controllers.GetRevenueSummary)
+		reports.GET("/revenue/timeseries", controllers.GetRevenueTimeSeries)
+		reports.GET("/revenue/export", controllers.ExportRevenue)
+
+		reports.GET("/arrears", controllers.GetArrearsReport)
+		reports.GET("/arrears/export", controllers.ExportArrears)
+		reports.GET("/arrears/:npm", controllers.GetArrearsStudent)
+
+		reports.GET("/journal", controllers.GetJournal)
+		reports.GET("/journal/accounts", controllers.GetJournalAccounts)
+		reports.GET("/journal/export", controllers.ExportJournal)
+
+		reports.GET("/bank-statements", controllers.GetBankStatementImports)
+		reports.GET("/bank-statements/:id", controllers.GetBankStatementReport)
+	}
+
+	manage := finance.Group("", middleware.RequirePermission(models.PermissionManageFinance))
+	{
+		manage.POST("/bank-statements", controllers.ImportBankStatement)
+		manage.POST("/bank-statements/lines/:id/decision", controllers.DecideBankStatementProposal)
+	}
+}
@@ -0,0 +1,12 @@
+package routes
+
+import (
+	"github.com/dedegunawan/backend-ujian-telp-v5/controllers"
+	"github.com/gin-gonic/gin"
+)
+
+// RegisterHealthRoutes probe liveness/readiness; tanpa auth, CSRF maupun rate limit
+func RegisterHealthRoutes(r *gin.Engine) {
+	r.GET("/healthz", controllers.Healthz)
+	r.GET("/readyz", controllers.Readyz)
+}
@@ -0,0 +1,16 @@
+package routes
+
+import (
+	"github.com/dedegunawan/backend-ujian-telp-v5/controllers"
+	"github.com/dedegunawan/backend-ujian-telp-v5/middleware"
+	"github.com/dedegunawan/backend-ujian-telp-v5/models"
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterImpersonationRoutes(r *gin.RouterGroup) {
+	logs := r.Group("/impersonation-logs")
+	logs.Use(middleware.RequireAuthFromTokenDB(), middleware.RequirePermission(models.PermissionViewImpersonation))
+	{
+		logs.GET("", controllers.GetImpersonationLogs)
+	}
+}
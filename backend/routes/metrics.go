@@ -0,0 +1,11 @@
+package routes
+
+import (
+	"github.com/dedegunawan/backend-ujian-telp-v5/metrics"
+	"github.com/gin-gonic/gin"
+)
+
+// RegisterMetricsRoutes endpoint scrape Prometheus; dilindungi METRICS_TOKEN jika diisi
+func RegisterMetricsRoutes(r *gin.Engine) {
+	r.GET("/metrics", gin.WrapH(metrics.Handler()))
+}
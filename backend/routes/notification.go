@@ -0,0 +1,19 @@
+package routes
+
+import (
+	"github.com/dedegunawan/backend-ujian-telp-v5/controllers"
+	"github.com/dedegunawan/backend-ujian-telp-v5/middleware"
+	"github.com/dedegunawan/backend-ujian-telp-v5/models"
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterNotificationRoutes(r *gin.RouterGroup) {
+	notifications := r.Group("/notifications")
+	notifications.Use(middleware.RequireAuthFromTokenDB(), middleware.RequirePermission(models.PermissionManageNotification))
+	{
+		notifications.GET("", controllers.GetNotifications)
+		notifications.POST("/:id/retry", controllers.RetryNotification)
+		notifications.GET("/reminders/runs", controllers.GetReminderRuns)
+		notifications.POST("/reminders/run", controllers.RunPaymentReminders)
+	}
+}
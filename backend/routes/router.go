@@ -2,17 +2,40 @@ package routes
 
 import (
 	"net/http"
+	"os"
+	"strings"
 
 	"github.com/dedegunawan/backend-ujian-telp-v5/auth"
 	"github.com/dedegunawan/backend-ujian-telp-v5/controllers"
 	"github.com/dedegunawan/backend-ujian-telp-v5/middleware"
+	"github.com/dedegunawan/backend-ujian-telp-v5/ratelimit"
+	"github.com/dedegunawan/backend-ujian-telp-v5/utils"
 	"github.com/gin-gonic/gin"
 )
 
 func SetupRouter() *gin.Engine {
-	r := gin.Default()
+	// Tanpa logger bawaan gin: access log ditulis middleware.RequestLogger (terstruktur dan diredaksi)
+	r := gin.New()
+	r.Use(gin.Recovery())
 
+	// Proxy yang boleh mengisi X-Forwarded-For (dipakai rate limit & allow-list); tanpa ini IP client bisa dipalsukan
+	if raw := os.Getenv("TRUSTED_PROXIES"); raw != "" {
+		if err := r.SetTrustedProxies(strings.Split(strings.ReplaceAll(raw, " ", ""), ",")); err != nil {
+			utils.Log.Fatalw("❌ TRUSTED_PROXIES tidak valid", "error", err)
+		}
+	}
+
+	// Didaftarkan sebelum r.Use supaya probe dan scrape tidak terkena CORS, rate limit dan CSRF
+	RegisterHealthRoutes(r)
+	RegisterMetricsRoutes(r)
+
+	// Metrics paling luar supaya request yang ditolak CORS/rate limit/CSRF ikut tercatat
+	r.Use(middleware.Metrics())
+	r.Use(middleware.RequestLogger())
+	r.Use(middleware.Tracing())
 	r.Use(middleware.LoadCors())
+	r.Use(middleware.RateLimit(ratelimit.PolicyDefault))
+	r.Use(middleware.CSRFProtect())
 
 	r.GET("/", func(context *gin.Context) {
 		context.Redirect(http.StatusMovedPermanently, "/sso-login")
@@ -27,12 +50,13 @@ func SetupRouter() *gin.Engine {
 		v1.GET("/me", middleware.RequireAuthFromTokenDB(), controllers.Me)
 		v1.GET("/student-bill", middleware.RequireAuthFromTokenDB(), controllers.GetStudentBillStatus)
 		v1.GET("/student-bill-new", middleware.RequireAuthFromTokenDB(), controllers.GetStudentBillStatusNew)
-		v1.POST("/student-bill", middleware.RequireAuthFromTokenDB(), controllers.GenerateCurrentBill)
-		v1.POST("/regenerate-student-bill", middleware.RequireAuthFromTokenDB(), controllers.RegenerateCurrentBill)
-		v1.GET("/generate/:StudentBillID", middleware.RequireAuthFromTokenDB(), controllers.GenerateUrlPembayaran)
-		v1.GET("/generate-payment-new", middleware.RequireAuthFromTokenDB(), controllers.GenerateUrlPembayaranNew)
-		v1.GET("/invoice-pdf", middleware.RequireAuthFromTokenDB(), controllers.GenerateInvoicePDF)
-		v1.POST("/confirm-payment/:StudentBillID", middleware.RequireAuthFromTokenDB(), controllers.ConfirmPembayaran)
+		v1.GET("/payment-status/stream", middleware.RequireAuthFromTokenDB(), controllers.StreamPaymentStatus)
+		v1.POST("/student-bill", middleware.RequireAuthFromTokenDB(), middleware.RateLimit(ratelimit.PolicyPayment), controllers.GenerateCurrentBill)
+		v1.POST("/regenerate-student-bill", middleware.RequireAuthFromTokenDB(), middleware.RateLimit(ratelimit.PolicyPayment), controllers.RegenerateCurrentBill)
+		v1.GET("/generate/:StudentBillID", middleware.RequireAuthFromTokenDB(), middleware.RateLimit(ratelimit.PolicyPayment), controllers.GenerateUrlPembayaran)
+		v1.GET("/generate-payment-new", middleware.RequireAuthFromTokenDB(), middleware.RateLimit(ratelimit.PolicyPayment), controllers.GenerateUrlPembayaranNew)
+		v1.GET("/invoice-pdf", middleware.RequireAuthFromTokenDB(), middleware.RateLimit(ratelimit.PolicyInvoice), controllers.GenerateInvoicePDF)
+		v1.POST("/confirm-payment/:StudentBillID", middleware.RequireAuthFromTokenDB(), middleware.RateLimit(ratelimit.PolicyPayment), controllers.ConfirmPembayaran)
 		v1.GET("/back-to-sintesys", middleware.RequireAuthFromTokenDB(), controllers.BackToSintesys)
 
 		// Payment status endpoints
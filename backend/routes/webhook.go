@@ -0,0 +1,24 @@
+package routes
+
+import (
+	"github.com/dedegunawan/backend-ujian-telp-v5/controllers"
+	"github.com/dedegunawan/backend-ujian-telp-v5/middleware"
+	"github.com/dedegunawan/backend-ujian-telp-v5/models"
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterWebhookRoutes(r *gin.RouterGroup) {
+	webhooks := r.Group("/webhooks")
+	webhooks.Use(middleware.RequireAuthFromTokenDB(), middleware.RequirePermission(models.PermissionManageWebhook))
+	{
+		webhooks.GET("/event-types", controllers.GetWebhookEventTypes)
+		webhooks.GET("/subscriptions", controllers.GetWebhookSubscriptions)
+		webhooks.POST("/subscriptions", controllers.CreateWebhookSubscription)
+		webhooks.PUT("/subscriptions/:id", controllers.UpdateWebhookSubscription)
+		webhooks.DELETE("/subscriptions/:id", controllers.DeleteWebhookSubscription)
+		webhooks.POST("/subscriptions/:id/rotate-secret", controllers.RotateWebhookSecret)
+		webhooks.POST("/subscriptions/:id/replay", controllers.ReplayWebhookSubscription)
+		webhooks.GET("/deliveries", controllers.GetWebhookDeliveries)
+		webhooks.POST("/deliveries/:id/replay", controllers.ReplayWebhookDelivery)
+	}
+}
@@ -0,0 +1,30 @@
+package services
+
+import (
+	"database/sql"
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// withAdvisoryLock menjalankan fn hanya jika GET_LOCK name bisa diambil tanpa menunggu, supaya
+// scheduler yang berjalan di setiap replika tidak mengerjakan hal yang sama bersamaan.
+// Lock MySQL terikat ke koneksi, jadi diambil dan dilepas di satu db.Connection (sama seperti
+// database/migrations). Return false jika lock sedang dipegang replika lain.
+func withAdvisoryLock(db *gorm.DB, name string, fn func() error) (bool, error) {
+	acquired := false
+	err := db.Connection(func(conn *gorm.DB) error {
+		var locked sql.NullInt64
+		if err := conn.Raw("SELECT GET_LOCK(?, 0)", name).Scan(&locked).Error; err != nil {
+			return fmt.Errorf("gagal mengambil lock %s: %w", name, err)
+		}
+		if !locked.Valid || locked.Int64 != 1 {
+			return nil
+		}
+		acquired = true
+		defer conn.Exec("SELECT RELEASE_LOCK(?)", name)
+
+		return fn()
+	})
+	return acquired, err
+}
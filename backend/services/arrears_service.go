@@ -0,0 +1,384 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dedegunawan/backend-ujian-telp-v5/metrics"
+
+	"github.com/dedegunawan/backend-ujian-telp-v5/models"
+	"github.com/dedegunawan/backend-ujian-telp-v5/repositories"
+	"github.com/dedegunawan/backend-ujian-telp-v5/utils"
+	"github.com/xuri/excelize/v2"
+	"gorm.io/gorm"
+)
+
+type ArrearsService interface {
+	GenerateReport(filter models.ArrearsFilter) (*models.ArrearsReport, error)
+	BuildWorkbook(report *models.ArrearsReport) (*bytes.Buffer, error)
+	ExportToMinio(filter models.ArrearsFilter) (string, string, error)
+	StartScheduler(ctx context.Context, workerName string, interval time.Duration)
+}
+
+type arrearsService struct {
+	db *gorm.DB
+}
+
+func NewArrearsService(db *gorm.DB) ArrearsService {
+	return &arrearsService{db: db}
+}
+
+// arrearsReportLockName GET_LOCK scheduler laporan tunggakan (satu replika per jadwal)
+const arrearsReportLockName = "epnbp_backend_arrears_report"
+
+// arrearsItemsSQL semua item yang belum lunas dan sudah lewat jatuh tempo sampai tahun_id aktif.
+// Aturan sumber sama dengan tagihanNewService: tahun yang punya cicilan hanya diambil dari
+// detail_cicilans, dan pembayaran cicilan dihitung dari invoice Paid yang terkait (invoice_relations)
+// sehingga angsuran yang baru dibayar sebagian hanya dihitung sisanya.
+// Parameter: tahun aktif, waktu sekarang (jatuh tempo cicilan), tahun aktif, tahun aktif,
+// dan apakah batas bayar registrasi tahun aktif (FinanceYear.EndDate) sudah lewat.
+const arrearsItemsSQL = `
+	SELECT 'cicilan' AS source, dc.id AS item_id, c.npm, c.tahun_id, dc.sequence_no, dc.due_date,
+		CAST(dc.amount AS SIGNED) AS amount,
+		CAST(COALESCE(pa.paid_amount, 0) AS SIGNED) AS paid_amount,
+		0 AS beasiswa,
+		CAST(GREATEST(dc.amount - COALESCE(pa.paid_amount, 0), 0) AS SIGNED) AS remaining_amount
+	FROM detail_cicilans dc
+	INNER JOIN cicilans c ON c.id = dc.cicilan_id
+	LEFT JOIN (
+		SELECT ir.detail_cicilan_id, SUM(p.amount) AS paid_amount
+		FROM invoice_relations ir
+		INNER JOIN invoices inv ON inv.id = ir.invoice_id AND inv.status = 'Paid'
+		INNER JOIN payments p ON p.invoice_id = inv.id
+		WHERE ir.detail_cicilan_id IS NOT NULL
+		GROUP BY ir.detail_cicilan_id
+	) pa ON pa.detail_cicilan_id = dc.id
+	WHERE (dc.status IS NULL OR dc.status <> 'paid') AND dc.amount > 0 AND c.tahun_id <= ?
+		AND dc.due_date < ?
+		AND dc.amount - COALESCE(pa.paid_amount, 0) > 0
+	UNION ALL
+	SELECT 'registrasi' AS source, rm.id AS item_id, rm.npm, rm.tahun_id, NULL AS sequence_no, NULL AS due_date,
+		CAST(COALESCE(rm.nominal_ukt, 0) AS SIGNED) AS amount,
+		CAST(COALESCE(rm.nominal_bayar, 0) AS SIGNED) AS paid_amount,
+		CAST(COALESCE(b.nominal, 0) AS SIGNED) AS beasiswa,
+		CAST(GREATEST(COALESCE(rm.nominal_ukt, 0) - COALESCE(b.nominal, 0) - COALESCE(rm.nominal_bayar, 0), 0) AS SIGNED) AS remaining_amount
+	FROM registrasi_mahasiswa rm
+	LEFT JOIN (` + scholarshipRowsSQL + `) b ON b.npm = rm.npm AND b.tahun_id = rm.tahun_id
+	WHERE (rm.tahun_id < ? OR (rm.tahun_id = ? AND ?))
+		AND NOT EXISTS (SELECT 1 FROM cicilans c2 WHERE c2.npm = rm.npm AND c2.tahun_id = rm.tahun_id)
+		AND COALESCE(rm.nominal_ukt, 0) - COALESCE(b.nominal, 0) - COALESCE(rm.nominal_bayar, 0) > 0`
+
+func (s *arrearsService) GenerateReport(filter models.ArrearsFilter) (*models.ArrearsReport, error) {
+	tagihanRepo := repositories.NewTagihanRepository(s.db, s.db)
+	activeYear, err := tagihanRepo.GetActiveFinanceYear()
+	if err != nil {
+		return nil, fmt.Errorf("tahun aktif tidak ditemukan: %w", err)
+	}
+	current := activeYear.AcademicYear
+	now := time.Now()
+
+	where := []string{"1 = 1"}
+	args := []interface{}{current, now, current, current, now.After(activeYear.EndDate)}
+	if filter.FakultasID != "" {
+		where = append(where, "p.fakultas_id = ?")
+		args = append(args, filter.FakultasID)
+	}
+	if filter.ProdiID != "" {
+		where = append(where, "mm.prodi_id = ?")
+		args = append(args, filter.ProdiID)
+	}
+	if filter.StatusKode != "" {
+		where = append(where, "sa.kode = ?")
+		args = append(args, filter.StatusKode)
+	}
+	if filter.NPM != "" {
+		where = append(where, "i.npm = ?")
+		args = append(args, filter.NPM)
+	}
+
+	query := fmt.Sprintf(`
+		SELECT i.*, COALESCE(mm.nama_lengkap, '') AS nama_lengkap,
+			COALESCE(p.nama_prodi, '') AS nama_prodi,
+			COALESCE(f.nama_fakultas, '') AS nama_fakultas,
+			COALESCE(sa.kode, '') AS status_kode
+		FROM (%s) i
+		LEFT JOIN mahasiswa_masters mm ON mm.student_id = i.npm AND mm.deleted_at IS NULL
+		LEFT JOIN prodi p ON p.id = mm.prodi_id
+		LEFT JOIN fakultas f ON f.id = p.fakultas_id
+		LEFT JOIN status_akademiks sa ON sa.id = mm.status_akademik_id
+		WHERE %s
+		ORDER BY i.npm ASC, i.tahun_id ASC, i.sequence_no ASC`,
+		arrearsItemsSQL, strings.Join(where, " AND "))
+
+	var items []models.ArrearsItem
+	if err := s.db.Raw(query, args...).Scan(&items).Error; err != nil {
+		return nil, fmt.Errorf("gagal mengambil data tunggakan: %w", err)
+	}
+
+	report := &models.ArrearsReport{
+		TahunAktif:  current,
+		GeneratedAt: time.Now(),
+		Totals:      make(map[string]models.ArrearsBucketTotal),
+		Students:    []models.ArrearsStudent{},
+	}
+	for _, bucket := range models.ArrearsBuckets {
+		report.Totals[bucket] = models.ArrearsBucketTotal{}
+	}
+
+	studentIndex := make(map[string]int)
+	bucketStudents := make(map[string]map[string]struct{})
+
+	for _, item := range items {
+		item.SemesterAge = semesterAge(item.TahunID, current)
+		item.Bucket = arrearsBucket(item.SemesterAge)
+
+		idx, ok := studentIndex[item.NPM]
+		if !ok {
+			report.Students = append(report.Students, models.ArrearsStudent{
+				NPM:          item.NPM,
+				NamaLengkap:  item.NamaLengkap,
+				NamaProdi:    item.NamaProdi,
+				NamaFakultas: item.NamaFakultas,
+				StatusKode:   item.StatusKode,
+				Buckets:      make(map[string]int64),
+				OldestTahun:  item.TahunID,
+			})
+			idx = len(report.Students) - 1
+			studentIndex[item.NPM] = idx
+		}
+
+		student := &report.Students[idx]
+		student.Buckets[item.Bucket] += item.RemainingAmount
+		student.Total += item.RemainingAmount
+		if item.TahunID < student.OldestTahun {
+			student.OldestTahun = item.TahunID
+		}
+		if filter.IncludeItems || filter.NPM != "" {
+			student.Items = append(student.Items, item)
+		}
+
+		total := report.Totals[item.Bucket]
+		total.Items++
+		total.Amount += item.RemainingAmount
+		if bucketStudents[item.Bucket] == nil {
+			bucketStudents[item.Bucket] = make(map[string]struct{})
+		}
+		if _, seen := bucketStudents[item.Bucket][item.NPM]; !seen {
+			bucketStudents[item.Bucket][item.NPM] = struct{}{}
+			total.Students++
+		}
+		report.Totals[item.Bucket] = total
+		report.GrandTotal += item.RemainingAmount
+	}
+
+	// Tunggakan terbesar di atas
+	sort.SliceStable(report.Students, func(i, j int) bool {
+		return report.Students[i].Total > report.Students[j].Total
+	})
+
+	return report, nil
+}
+
+// BuildWorkbook membuat XLSX dengan sheet ringkasan per mahasiswa dan rincian item
+func (s *arrearsService) BuildWorkbook(report *models.ArrearsReport) (*bytes.Buffer, error) {
+	excel := excelize.NewFile()
+	defer excel.Close()
+
+	summary := "Ringkasan"
+	excel.SetSheetName("Sheet1", summary)
+	headers := []string{"NPM", "Nama", "Fakultas", "Prodi", "Status", "Semester Ini", "1 Semester", "2 Semester", "Lebih Lama", "Total", "Tahun Tertua"}
+	for i, h := range headers {
+		cell, _ := excelize.CoordinatesToCellName(i+1, 1)
+		excel.SetCellValue(summary, cell, h)
+	}
+	for r, st := range report.Students {
+		values := []interface{}{
+			st.NPM, st.NamaLengkap, st.NamaFakultas, st.NamaProdi, st.StatusKode,
+			st.Buckets[models.ArrearsBucketCurrent],
+			st.Buckets[models.ArrearsBucketOneSemester],
+			st.Buckets[models.ArrearsBucketTwoSemester],
+			st.Buckets[models.ArrearsBucketOlder],
+			st.Total, st.OldestTahun,
+		}
+		for i, v := range values {
+			cell, _ := excelize.CoordinatesToCellName(i+1, r+2)
+			excel.SetCellValue(summary, cell, v)
+		}
+	}
+	totalRow := len(report.Students) + 2
+	totalValues := []interface{}{"TOTAL", "", "", "", "",
+		report.Totals[models.ArrearsBucketCurrent].Amount,
+		report.Totals[models.ArrearsBucketOneSemester].Amount,
+		report.Totals[models.ArrearsBucketTwoSemester].Amount,
+		report.Totals[models.ArrearsBucketOlder].Amount,
+		report.GrandTotal, "",
+	}
+	for i, v := range totalValues {
+		cell, _ := excelize.CoordinatesToCellName(i+1, totalRow)
+		excel.SetCellValue(summary, cell, v)
+	}
+
+	detail := "Rincian"
+	if _, err := excel.NewSheet(detail); err != nil {
+		return nil, err
+	}
+	detailHeaders := []string{"NPM", "Sumber", "ID", "Tahun ID", "Angsuran", "Jatuh Tempo", "Nominal", "Dibayar", "Beasiswa", "Sisa", "Umur (Semester)", "Bucket"}
+	for i, h := range detailHeaders {
+		cell, _ := excelize.CoordinatesToCellName(i+1, 1)
+		excel.SetCellValue(detail, cell, h)
+	}
+	row := 2
+	for _, st := range report.Students {
+		for _, item := range st.Items {
+			sequence := ""
+			if item.SequenceNo != nil {
+				sequence = strconv.Itoa(*item.SequenceNo)
+			}
+			dueDate := ""
+			if item.DueDate != nil {
+				dueDate = item.DueDate.Format("2006-01-02")
+			}
+			values := []interface{}{
+				item.NPM, item.Source, item.ItemID, item.TahunID, sequence, dueDate,
+				item.Amount, item.PaidAmount, item.Beasiswa, item.RemainingAmount, item.SemesterAge, item.Bucket,
+			}
+			for i, v := range values {
+				cell, _ := excelize.CoordinatesToCellName(i+1, row)
+				excel.SetCellValue(detail, cell, v)
+			}
+			row++
+		}
+	}
+
+	var buffer bytes.Buffer
+	if err := excel.Write(&buffer); err != nil {
+		return nil, err
+	}
+	return &buffer, nil
+}
+
+// ExportToMinio membuat laporan lengkap (dengan rincian) dan meng-upload-nya ke MinIO
+// Return: object name dan signed URL
+func (s *arrearsService) ExportToMinio(filter models.ArrearsFilter) (string, string, error) {
+	return s.exportToMinio(filter, time.Now())
+}
+
+// exportToMinio stamp dipakai di nama object (waktu export, atau slot jadwal untuk scheduler)
+func (s *arrearsService) exportToMinio(filter models.ArrearsFilter, stamp time.Time) (string, string, error) {
+	filter.IncludeItems = true
+	report, err := s.GenerateReport(filter)
+	if err != nil {
+		return "", "", err
+	}
+
+	buffer, err := s.BuildWorkbook(report)
+	if err != nil {
+		return "", "", fmt.Errorf("gagal membuat workbook: %w", err)
+	}
+
+	objectName := arrearsObjectName(report.TahunAktif, stamp)
+	url, err := utils.UploadObjectToMinio(objectName, buffer.Bytes(), "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet")
+	if err != nil {
+		return "", "", fmt.Errorf("gagal upload laporan ke MinIO: %w", err)
+	}
+	return objectName, url, nil
+}
+
+func arrearsObjectName(tahunAktif string, stamp time.Time) string {
+	return fmt.Sprintf("reports/tunggakan/tunggakan_%s_%s.xlsx", tahunAktif, stamp.Format("20060102-150405"))
+}
+
+// StartScheduler membuat laporan tunggakan lengkap secara berkala dan menyimpannya di MinIO.
+// Scheduler berjalan di setiap replika; satu laporan per slot jadwal (waktu dibulatkan ke
+// interval) dijamin lewat GET_LOCK dan pengecekan object slot yang sudah ada.
+func (s *arrearsService) StartScheduler(ctx context.Context, workerName string, interval time.Duration) {
+	utils.Log.Infof("[%s] Arrears report scheduler started, interval %s", workerName, interval)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			utils.Log.Infof("[%s] Arrears report scheduler stopped", workerName)
+			return
+		case <-ticker.C:
+			start := time.Now()
+			objectName, err := s.runScheduled(start.Truncate(interval))
+			metrics.ObserveWorkerRun(workerName, start, err)
+			if err != nil {
+				utils.Log.Errorf("[%s] Error generating arrears report: %v", workerName, err)
+				continue
+			}
+			if objectName == "" {
+				utils.Log.Debugf("[%s] Arrears report slot sudah dikerjakan replika lain", workerName)
+				continue
+			}
+			utils.Log.Infof("[%s] Arrears report stored at %s", workerName, objectName)
+		}
+	}
+}
+
+// runScheduled mengembalikan object name kosong jika slot sudah / sedang dikerjakan replika lain
+func (s *arrearsService) runScheduled(slot time.Time) (string, error) {
+	var objectName string
+	_, err := withAdvisoryLock(s.db, arrearsReportLockName, func() error {
+		activeYear, err := repositories.NewTagihanRepository(s.db, s.db).GetActiveFinanceYear()
+		if err != nil {
+			return fmt.Errorf("tahun aktif tidak ditemukan: %w", err)
+		}
+		if _, err := utils.MinioUrlExistsOrError(arrearsObjectName(activeYear.AcademicYear, slot)); err == nil {
+			return nil
+		}
+
+		objectName, _, err = s.exportToMinio(models.ArrearsFilter{}, slot)
+		return err
+	})
+	return objectName, err
+}
+
+// semesterAge selisih semester antara tahunID (YYYYS) dan tahun aktif
+func semesterAge(tahunID string, current string) int {
+	if len(tahunID) != 5 || len(current) != 5 {
+		return 0
+	}
+	tahun, err1 := strconv.Atoi(tahunID[:4])
+	semester, err2 := strconv.Atoi(tahunID[4:])
+	tahunSekarang, err3 := strconv.Atoi(current[:4])
+	semesterSekarang, err4 := strconv.Atoi(current[4:])
+	if err1 != nil || err2 != nil || err3 != nil || err4 != nil {
+		return 0
+	}
+
+	// Semester pendek (3) dihitung sama dengan semester genap
+	if semester > 2 {
+		semester = 2
+	}
+	if semesterSekarang > 2 {
+		semesterSekarang = 2
+	}
+
+	age := (tahunSekarang-tahun)*2 + semesterSekarang - semester
+	if age < 0 {
+		return 0
+	}
+	return age
+}
+
+func arrearsBucket(age int) string {
+	switch {
+	case age <= 0:
+		return models.ArrearsBucketCurrent
+	case age == 1:
+		return models.ArrearsBucketOneSemester
+	case age == 2:
+		return models.ArrearsBucketTwoSemester
+	default:
+		return models.ArrearsBucketOlder
+	}
+}
@@ -0,0 +1,131 @@
+package services
+
+import (
+	"errors"
+	"os"
+	"time"
+
+	"github.com/dedegunawan/backend-ujian-telp-v5/models"
+	"github.com/dedegunawan/backend-ujian-telp-v5/repositories"
+	"github.com/dedegunawan/backend-ujian-telp-v5/utils"
+	"golang.org/x/oauth2"
+	"gorm.io/gorm"
+)
+
+const defaultAuthCodeTTL = 60 * time.Second
+
+var (
+	ErrAuthCodeInvalid = errors.New("kode login tidak valid atau sudah kedaluwarsa")
+	ErrAuthCodeReused  = errors.New("kode login sudah dipakai, sesi dicabut")
+)
+
+// AuthCodeGrant token hasil penukaran kode login
+type AuthCodeGrant struct {
+	AccessToken      string
+	RefreshToken     string
+	ExpiresAt        time.Time
+	RefreshExpiresAt *time.Time
+}
+
+// AuthCodeService kode sekali pakai antara callback SSO dan frontend
+type AuthCodeService interface {
+	Issue(session *models.UserToken, token *oauth2.Token) (string, error)
+	Exchange(code string) (*AuthCodeGrant, error)
+}
+
+type authCodeService struct {
+	db *gorm.DB
+}
+
+func NewAuthCodeService(db *gorm.DB) AuthCodeService {
+	return &authCodeService{db: db}
+}
+
+func authCodeTTL() time.Duration {
+	if raw := os.Getenv("AUTH_CODE_TTL"); raw != "" {
+		if parsed, err := time.ParseDuration(raw); err == nil && parsed > 0 {
+			return parsed
+		}
+	}
+	return defaultAuthCodeTTL
+}
+
+// Issue menyimpan token terenkripsi dan mengembalikan kode opaque untuk redirect
+func (s *authCodeService) Issue(session *models.UserToken, token *oauth2.Token) (string, error) {
+	code, err := utils.RandomToken(32)
+	if err != nil {
+		return "", err
+	}
+	accessToken, err := utils.EncryptString(token.AccessToken)
+	if err != nil {
+		return "", err
+	}
+	refreshToken := ""
+	if token.RefreshToken != "" {
+		if refreshToken, err = utils.EncryptString(token.RefreshToken); err != nil {
+			return "", err
+		}
+	}
+
+	now := time.Now()
+	row := models.AuthCode{
+		CodeHash:         utils.HashToken(code),
+		SessionID:        session.SessionID,
+		AccessToken:      accessToken,
+		RefreshToken:     refreshToken,
+		ExpiresAt:        now.Add(authCodeTTL()),
+		TokenExpiresAt:   session.ExpiresAt,
+		RefreshExpiresAt: session.RefreshExpiresAt,
+		CreatedAt:        now,
+	}
+	if err := s.db.Create(&row).Error; err != nil {
+		return "", err
+	}
+
+	// Bersihkan kode lama (terpakai atau kedaluwarsa) sekalian
+	s.db.Where("expires_at < ?", now.Add(-time.Hour)).Delete(&models.AuthCode{})
+	return code, nil
+}
+
+// Exchange menukar kode tepat satu kali. Kode yang dipakai ulang mencabut sesinya
+// (kemungkinan kode bocor lewat history/log).
+func (s *authCodeService) Exchange(code string) (*AuthCodeGrant, error) {
+	hash := utils.HashToken(code)
+	now := time.Now()
+
+	result := s.db.Model(&models.AuthCode{}).
+		Where("code_hash = ? AND used_at IS NULL AND expires_at > ?", hash, now).
+		Update("used_at", now)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	var row models.AuthCode
+	if err := s.db.Where("code_hash = ?", hash).First(&row).Error; err != nil {
+		return nil, ErrAuthCodeInvalid
+	}
+	if result.RowsAffected == 0 {
+		if row.UsedAt != nil {
+			tokenRepo := repositories.UserTokenRepository{DB: s.db}
+			tokenRepo.RevokeSession(row.SessionID, models.RevokeReasonReuse)
+			utils.Log.Warnw("Kode login dipakai ulang, sesi dicabut", "session_id", row.SessionID.String())
+			return nil, ErrAuthCodeReused
+		}
+		return nil, ErrAuthCodeInvalid
+	}
+
+	grant := &AuthCodeGrant{ExpiresAt: row.TokenExpiresAt, RefreshExpiresAt: row.RefreshExpiresAt}
+	var err error
+	if grant.AccessToken, err = utils.DecryptString(row.AccessToken); err != nil {
+		return nil, err
+	}
+	if row.RefreshToken != "" {
+		if grant.RefreshToken, err = utils.DecryptString(row.RefreshToken); err != nil {
+			return nil, err
+		}
+	}
+
+	// Token tidak perlu disimpan lagi setelah ditukar
+	s.db.Model(&row).Updates(map[string]interface{}{"access_token": "", "refresh_token": ""})
+	return grant, nil
+}
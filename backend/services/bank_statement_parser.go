@@ -0,0 +1,292 @@
+package services
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dedegunawan/backend-ujian-telp-v5/models"
+)
+
+// DetectBankStatementFormat menebak format file: MT940 jika ada tag :20:/:61:, selain itu CSV
+func DetectBankStatementFormat(data []byte) string {
+	if bytes.Contains(data, []byte(":61:")) || bytes.Contains(data, []byte(":20:")) {
+		return "mt940"
+	}
+	return "csv"
+}
+
+// ParseBankStatement mem-parse file mutasi dan mengembalikan baris kredit saja
+func ParseBankStatement(format string, data []byte) ([]models.BankStatementCredit, error) {
+	switch format {
+	case "mt940":
+		return ParseMT940(data)
+	case "csv":
+		return ParseBankCSV(data)
+	}
+	return nil, fmt.Errorf("format mutasi tidak dikenal: %s", format)
+}
+
+// :61:YYMMDD[MMDD]{C|D|RC|RD}[funds code]amount N/F + type code + reference[//bank reference]
+var mt940StatementLine = regexp.MustCompile(`^(\d{6})(\d{4})?(RC|RD|C|D)([A-Z])?(\d+,\d{0,2}|\d+)[NF]?([A-Z0-9]{3})?([^/]*)(?://(.*))?`)
+
+// ParseMT940 mem-parse tag :61: (transaksi) dan :86: (keterangan) dari file MT940
+func ParseMT940(data []byte) ([]models.BankStatementCredit, error) {
+	var credits []models.BankStatementCredit
+	var current *models.BankStatementCredit
+	var currentTag string
+
+	flush := func() {
+		if current != nil {
+			current.Description = strings.TrimSpace(current.Description)
+			credits = append(credits, *current)
+			current = nil
+		}
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if line == "" || line == "-" {
+			continue
+		}
+
+		if strings.HasPrefix(line, ":") {
+			end := strings.Index(line[1:], ":")
+			if end < 0 {
+				continue
+			}
+			currentTag = line[1 : end+1]
+			value := line[end+2:]
+
+			switch currentTag {
+			case "61":
+				flush()
+				match := mt940StatementLine.FindStringSubmatch(value)
+				if match == nil {
+					return nil, fmt.Errorf("baris %d: format :61: tidak dikenali", lineNo)
+				}
+				// Hanya kredit; reversal debit (RD) juga menambah saldo tapi tidak dianggap pembayaran
+				if match[3] != "C" {
+					continue
+				}
+				valueDate, err := time.ParseInLocation("060102", match[1], time.Local)
+				if err != nil {
+					return nil, fmt.Errorf("baris %d: tanggal tidak valid", lineNo)
+				}
+				amount, err := parseBankAmount(match[5])
+				if err != nil {
+					return nil, fmt.Errorf("baris %d: %w", lineNo, err)
+				}
+				reference := strings.TrimSpace(match[7])
+				if match[8] != "" {
+					reference = strings.TrimSpace(match[8])
+				}
+				current = &models.BankStatementCredit{
+					LineNo:        lineNo,
+					ValueDate:     valueDate,
+					Amount:        amount,
+					BankReference: reference,
+				}
+			case "86":
+				if current != nil {
+					current.Description += value
+				}
+			default:
+				flush()
+			}
+			continue
+		}
+
+		// Baris lanjutan dari tag sebelumnya
+		if current != nil && currentTag == "86" {
+			current.Description += " " + strings.TrimSpace(line)
+		}
+	}
+	flush()
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return credits, nil
+}
+
+var bankCSVDateLayouts = []string{"2006-01-02", "02/01/2006", "02-01-2006", "2006/01/02", "02/01/06", "02-01-06", "2006-01-02 15:04:05", "02/01/2006 15:04:05"}
+
+// Nama kolom yang dikenali pada export CSV bank (huruf kecil)
+var bankCSVColumns = map[string][]string{
+	"date":        {"tanggal", "tgl", "date", "tanggal transaksi", "value date", "posting date"},
+	"description": {"keterangan", "description", "uraian", "remark", "remarks", "deskripsi"},
+	"credit":      {"kredit", "credit", "cr"},
+	"amount":      {"nominal", "amount", "jumlah", "mutasi"},
+	"type":        {"d/k", "db/cr", "dk", "type", "jenis"},
+	"reference":   {"referensi", "reference", "no referensi", "ref", "no. ref"},
+}
+
+// ParseBankCSV mem-parse CSV mutasi bank; kolom dikenali dari header,
+// delimiter ; atau , dideteksi otomatis
+func ParseBankCSV(data []byte) ([]models.BankStatementCredit, error) {
+	delimiter := ','
+	firstLine := data
+	if idx := bytes.IndexByte(data, '\n'); idx >= 0 {
+		firstLine = data[:idx]
+	}
+	if bytes.Count(firstLine, []byte(";")) > bytes.Count(firstLine, []byte(",")) {
+		delimiter = ';'
+	}
+
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.Comma = delimiter
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("CSV tidak valid: %w", err)
+	}
+
+	// Cari baris header (bank sering menaruh info rekening di beberapa baris awal)
+	headerRow := -1
+	columns := make(map[string]int)
+	for i, record := range records {
+		found := make(map[string]int)
+		for col, name := range record {
+			name = strings.ToLower(strings.TrimSpace(name))
+			for key, aliases := range bankCSVColumns {
+				for _, alias := range aliases {
+					if name == alias {
+						if _, exists := found[key]; !exists {
+							found[key] = col
+						}
+					}
+				}
+			}
+		}
+		_, hasDate := found["date"]
+		_, hasCredit := found["credit"]
+		_, hasAmount := found["amount"]
+		if hasDate && (hasCredit || hasAmount) {
+			headerRow = i
+			columns = found
+			break
+		}
+	}
+	if headerRow < 0 {
+		return nil, fmt.Errorf("header CSV tidak dikenali (butuh kolom tanggal dan kredit/nominal)")
+	}
+
+	get := func(record []string, key string) string {
+		col, ok := columns[key]
+		if !ok || col >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[col])
+	}
+
+	var credits []models.BankStatementCredit
+	for i := headerRow + 1; i < len(records); i++ {
+		record := records[i]
+		rawDate := get(record, "date")
+		if rawDate == "" {
+			continue
+		}
+
+		var raw string
+		if _, ok := columns["credit"]; ok {
+			raw = get(record, "credit")
+		} else {
+			raw = get(record, "amount")
+			kind := strings.ToUpper(get(record, "type"))
+			if kind != "" && kind != "CR" && kind != "C" && kind != "K" && kind != "KREDIT" && kind != "CREDIT" {
+				continue
+			}
+			if kind == "" && strings.HasPrefix(raw, "-") {
+				continue
+			}
+		}
+		raw = strings.TrimSuffix(strings.TrimSpace(raw), " CR")
+		if raw == "" || raw == "0" || raw == "-" {
+			continue
+		}
+
+		amount, err := parseBankAmount(raw)
+		if err != nil {
+			return nil, fmt.Errorf("baris %d: %w", i+1, err)
+		}
+		if amount <= 0 {
+			continue
+		}
+
+		var valueDate time.Time
+		parsed := false
+		for _, layout := range bankCSVDateLayouts {
+			if t, err := time.ParseInLocation(layout, rawDate, time.Local); err == nil {
+				valueDate = t
+				parsed = true
+				break
+			}
+		}
+		if !parsed {
+			return nil, fmt.Errorf("baris %d: format tanggal tidak dikenali: %s", i+1, rawDate)
+		}
+
+		credits = append(credits, models.BankStatementCredit{
+			LineNo:        i + 1,
+			ValueDate:     valueDate,
+			Amount:        amount,
+			BankReference: get(record, "reference"),
+			Description:   get(record, "description"),
+		})
+	}
+	return credits, nil
+}
+
+// parseBankAmount menerima "1.500.000,00", "1,500,000.00", "1500000,00" atau "1500000" dan
+// mengembalikan nominal rupiah (dibulatkan)
+func parseBankAmount(raw string) (int64, error) {
+	s := strings.TrimSpace(raw)
+	s = strings.TrimPrefix(s, "Rp")
+	s = strings.TrimPrefix(s, "IDR")
+	s = strings.ReplaceAll(strings.TrimSpace(s), " ", "")
+	s = strings.TrimPrefix(s, "+")
+
+	lastComma := strings.LastIndex(s, ",")
+	lastDot := strings.LastIndex(s, ".")
+	switch {
+	case lastComma >= 0 && lastDot >= 0:
+		if lastComma > lastDot {
+			// 1.500.000,00
+			s = strings.ReplaceAll(s, ".", "")
+			s = strings.Replace(s, ",", ".", 1)
+		} else {
+			// 1,500,000.00
+			s = strings.ReplaceAll(s, ",", "")
+		}
+	case lastComma >= 0:
+		// 1500000,00 (desimal) atau 1,500,000 (ribuan)
+		if strings.Count(s, ",") > 1 || len(s)-lastComma-1 == 3 {
+			s = strings.ReplaceAll(s, ",", "")
+		} else {
+			s = strings.Replace(s, ",", ".", 1)
+		}
+	case lastDot >= 0:
+		// 1.500.000 (ribuan) atau 1500000.00 (desimal)
+		if strings.Count(s, ".") > 1 || len(s)-lastDot-1 == 3 {
+			s = strings.ReplaceAll(s, ".", "")
+		}
+	}
+
+	value, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, fmt.Errorf("nominal tidak valid: %s", raw)
+	}
+	return int64(math.Round(value)), nil
+}
@@ -0,0 +1,251 @@
+package services
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dedegunawan/backend-ujian-telp-v5/models"
+)
+
+func bankDate(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.Local)
+}
+
+func TestDetectBankStatementFormat(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want string
+	}{
+		{"mt940 dengan :20:", ":20:STMT1\n:25:123\n", "mt940"},
+		{"mt940 hanya :61:", ":61:231016C1000,00NTRFREF\n", "mt940"},
+		{"csv", "tanggal;keterangan;kredit\n", "csv"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DetectBankStatementFormat([]byte(tt.data)); got != tt.want {
+				t.Errorf("DetectBankStatementFormat() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseMT940(t *testing.T) {
+	tests := []struct {
+		name    string
+		lines   []string
+		want    []models.BankStatementCredit
+		wantErr string
+	}{
+		{
+			name: "kredit dengan entry date, bank reference dan :86: multi baris",
+			lines: []string{
+				":20:STMT231016",
+				":25:1234567890",
+				":61:2310161016C1500000,00NTRF8800123456789//BANKREF1",
+				":86:PEMBAYARAN UKT VA 8800123456789",
+				" A.N. BUDI",
+				":62F:C231016IDR1500000,00",
+			},
+			want: []models.BankStatementCredit{
+				{LineNo: 3, ValueDate: bankDate(2023, 10, 16), Amount: 1500000, BankReference: "BANKREF1", Description: "PEMBAYARAN UKT VA 8800123456789 A.N. BUDI"},
+			},
+		},
+		{
+			name: "tanpa entry date, desimal kosong, reference dari field nasabah",
+			lines: []string{
+				":61:231016C250000,NTRFNONREF",
+			},
+			want: []models.BankStatementCredit{
+				{LineNo: 1, ValueDate: bankDate(2023, 10, 16), Amount: 250000, BankReference: "NONREF"},
+			},
+		},
+		{
+			name: "funds code dan pembulatan sen",
+			lines: []string{
+				":61:231017CR75000,49NMSCREF2",
+				":61:231017C75000,50NMSCREF3",
+			},
+			want: []models.BankStatementCredit{
+				{LineNo: 1, ValueDate: bankDate(2023, 10, 17), Amount: 75000, BankReference: "REF2"},
+				{LineNo: 2, ValueDate: bankDate(2023, 10, 17), Amount: 75001, BankReference: "REF3"},
+			},
+		},
+		{
+			name: "nominal tanpa koma dan CRLF",
+			lines: []string{
+				":61:231018C100000NTRFREF4\r",
+				":86:VA 8800999\r",
+			},
+			want: []models.BankStatementCredit{
+				{LineNo: 1, ValueDate: bankDate(2023, 10, 18), Amount: 100000, BankReference: "REF4", Description: "VA 8800999"},
+			},
+		},
+		{
+			name: "debit, reversal kredit dan reversal debit dilewati beserta :86:-nya",
+			lines: []string{
+				":61:231016D100000,00NTRFDEBIT",
+				":86:TARIK TUNAI",
+				":61:231016RC100000,00NTRFREVC",
+				":61:231016RD100000,00NTRFREVD",
+				":86:KOREKSI",
+				":61:231016C200000,00NTRFKREDIT",
+			},
+			want: []models.BankStatementCredit{
+				{LineNo: 6, ValueDate: bankDate(2023, 10, 16), Amount: 200000, BankReference: "KREDIT"},
+			},
+		},
+		{
+			name:    "format :61: tidak dikenali",
+			lines:   []string{":20:STMT", ":61:TANGGAL C1000"},
+			wantErr: "baris 2: format :61: tidak dikenali",
+		},
+		{
+			name:    "tanggal tidak valid",
+			lines:   []string{":61:231345C1000,00NTRFREF"},
+			wantErr: "baris 1: tanggal tidak valid",
+		},
+		{
+			name:  "tanpa transaksi",
+			lines: []string{":20:STMT", ":62F:C231016IDR0,00", "-"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseMT940([]byte(strings.Join(tt.lines, "\n")))
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseMT940() =\n%+v\nwant\n%+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseBankCSV(t *testing.T) {
+	tests := []struct {
+		name    string
+		lines   []string
+		want    []models.BankStatementCredit
+		wantErr string
+	}{
+		{
+			name: "delimiter titik koma, info rekening sebelum header, kolom kredit",
+			lines: []string{
+				"No. Rekening;1234567890",
+				"Periode;01/10/2023 - 31/10/2023",
+				"Tanggal;Keterangan;Debet;Kredit;No Referensi",
+				"16/10/2023;PEMBAYARAN VA 8800123456789;;1.500.000,00;REF1",
+				"16/10/2023;BIAYA ADMIN;5.000,00;;REF2",
+				"17/10/2023;SETORAN;;250.000,00 CR;REF3",
+			},
+			want: []models.BankStatementCredit{
+				{LineNo: 4, ValueDate: bankDate(2023, 10, 16), Amount: 1500000, BankReference: "REF1", Description: "PEMBAYARAN VA 8800123456789"},
+				{LineNo: 6, ValueDate: bankDate(2023, 10, 17), Amount: 250000, BankReference: "REF3", Description: "SETORAN"},
+			},
+		},
+		{
+			name: "delimiter koma, kolom nominal dengan D/K",
+			lines: []string{
+				"Date,Description,Amount,D/K,Reference",
+				`2023-10-16,VA 8800123456789,"1,500,000.00",K,REF1`,
+				`2023-10-16,TRANSFER KELUAR,"200,000.00",D,REF2`,
+				`2023-10-17,VA 8800999,"75,000.00",CR,REF3`,
+			},
+			want: []models.BankStatementCredit{
+				{LineNo: 2, ValueDate: bankDate(2023, 10, 16), Amount: 1500000, BankReference: "REF1", Description: "VA 8800123456789"},
+				{LineNo: 4, ValueDate: bankDate(2023, 10, 17), Amount: 75000, BankReference: "REF3", Description: "VA 8800999"},
+			},
+		},
+		{
+			name: "kolom nominal tanpa tipe: nilai negatif adalah debit",
+			lines: []string{
+				"tgl,uraian,mutasi",
+				"16-10-23,KREDIT,Rp 100.000",
+				"16-10-23,DEBIT,-50.000",
+				",BARIS KOSONG,0",
+			},
+			want: []models.BankStatementCredit{
+				{LineNo: 2, ValueDate: bankDate(2023, 10, 16), Amount: 100000, Description: "KREDIT"},
+			},
+		},
+		{
+			name:    "header tidak dikenali",
+			lines:   []string{"kolom1,kolom2", "a,b"},
+			wantErr: "header CSV tidak dikenali (butuh kolom tanggal dan kredit/nominal)",
+		},
+		{
+			name:    "tanggal tidak dikenali",
+			lines:   []string{"tanggal,kredit", "16 Okt 2023,1000"},
+			wantErr: "baris 2: format tanggal tidak dikenali: 16 Okt 2023",
+		},
+		{
+			name:    "nominal tidak valid",
+			lines:   []string{"tanggal,kredit", "2023-10-16,seribu"},
+			wantErr: "baris 2: nominal tidak valid: seribu",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseBankCSV([]byte(strings.Join(tt.lines, "\n")))
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseBankCSV() =\n%+v\nwant\n%+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseBankAmount(t *testing.T) {
+	tests := []struct {
+		raw     string
+		want    int64
+		wantErr bool
+	}{
+		{raw: "1.500.000,00", want: 1500000},
+		{raw: "1,500,000.00", want: 1500000},
+		{raw: "1500000,00", want: 1500000},
+		{raw: "1500000.00", want: 1500000},
+		{raw: "1.500.000", want: 1500000},
+		{raw: "1,500,000", want: 1500000},
+		{raw: "1,500", want: 1500},
+		{raw: "1500000", want: 1500000},
+		{raw: "250000,", want: 250000},
+		{raw: "Rp 1.500.000,50", want: 1500001},
+		{raw: "IDR 75.000", want: 75000},
+		{raw: "+75000", want: 75000},
+		{raw: "-50.000", want: -50000},
+		{raw: "", wantErr: true},
+		{raw: "seribu", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.raw, func(t *testing.T) {
+			got, err := parseBankAmount(tt.raw)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && got != tt.want {
+				t.Errorf("parseBankAmount(%q) = %d, want %d", tt.raw, got, tt.want)
+			}
+		})
+	}
+}
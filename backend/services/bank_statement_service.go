@@ -0,0 +1,307 @@
+package services
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"time"
+
+	"github.com/dedegunawan/backend-ujian-telp-v5/models"
+	"github.com/dedegunawan/backend-ujian-telp-v5/utils"
+	"gorm.io/gorm"
+)
+
+type BankStatementService interface {
+	Import(filename, format string, data []byte, uploadedBy string) (*models.BankStatementReport, error)
+	List(limit int) ([]models.BankStatementImport, error)
+	Report(importID uint, status string) (*models.BankStatementReport, error)
+	DecideProposal(lineID uint, confirm bool, decidedBy, note string) (*models.BankStatementLine, error)
+}
+
+type bankStatementService struct {
+	db *gorm.DB
+}
+
+func NewBankStatementService(db *gorm.DB) BankStatementService {
+	return &bankStatementService{db: db}
+}
+
+// Nomor VA di keterangan/referensi mutasi: deret angka 8-20 digit
+var vaCandidatePattern = regexp.MustCompile(`\d{8,20}`)
+
+type bankVARow struct {
+	VirtualAccount string `gorm:"column:virtual_account"`
+	InvoiceID      uint   `gorm:"column:invoice_id"`
+	Status         string `gorm:"column:status"`
+	TotalAmount    int64  `gorm:"column:total_amount"`
+}
+
+func bankCreditFingerprint(credit models.BankStatementCredit) string {
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%s|%d|%s|%s",
+		credit.ValueDate.Format("2006-01-02"), credit.Amount, credit.BankReference, credit.Description)))
+	return hex.EncodeToString(sum[:])
+}
+
+// Import mem-parse file, menyimpan file asli di MinIO, lalu mencocokkan setiap kredit.
+// Tidak ada perubahan pada invoices: kecocokan dengan invoice yang belum Paid hanya diusulkan.
+func (s *bankStatementService) Import(filename, format string, data []byte, uploadedBy string) (*models.BankStatementReport, error) {
+	if format == "" {
+		format = DetectBankStatementFormat(data)
+	}
+
+	credits, err := ParseBankStatement(format, data)
+	if err != nil {
+		return nil, err
+	}
+	if len(credits) == 0 {
+		return nil, fmt.Errorf("tidak ada transaksi kredit di file mutasi")
+	}
+
+	objectName := fmt.Sprintf("bank-statements/%s_%s", time.Now().Format("20060102-150405"), filepath.Base(filename))
+	if _, err := utils.UploadObjectToMinio(objectName, data, ""); err != nil {
+		return nil, fmt.Errorf("gagal menyimpan file mutasi: %w", err)
+	}
+
+	// Kandidat VA & fingerprint untuk lookup sekaligus
+	var candidates []string
+	var fingerprints []string
+	for _, credit := range credits {
+		candidates = append(candidates, vaCandidatePattern.FindAllString(credit.BankReference+" "+credit.Description, -1)...)
+		fingerprints = append(fingerprints, bankCreditFingerprint(credit))
+	}
+
+	vaMap := make(map[string]bankVARow)
+	if len(candidates) > 0 {
+		var rows []bankVARow
+		err := s.db.
+			Table("virtual_accounts").
+			Select("virtual_accounts.virtual_account, invoices.id AS invoice_id, invoices.status, CAST(invoices.total_amount AS SIGNED) AS total_amount").
+			Joins("INNER JOIN invoices ON invoices.id = virtual_accounts.invoice_id").
+			Where("virtual_accounts.virtual_account IN ?", candidates).
+			Order("virtual_accounts.created_at DESC").
+			Scan(&rows).Error
+		if err != nil {
+			return nil, fmt.Errorf("gagal mengambil virtual account: %w", err)
+		}
+		for _, row := range rows {
+			if _, exists := vaMap[row.VirtualAccount]; !exists {
+				vaMap[row.VirtualAccount] = row
+			}
+		}
+	}
+
+	// Kredit yang sudah pernah diimpor
+	var existing []string
+	s.db.Model(&models.BankStatementLine{}).Where("fingerprint IN ?", fingerprints).Pluck("fingerprint", &existing)
+
+	matcher := newBankCreditMatcher(vaMap, existing, s.invoiceAlreadyMatched)
+
+	importRow := models.BankStatementImport{
+		Filename:   filepath.Base(filename),
+		Format:     format,
+		ObjectName: objectName,
+		UploadedBy: uploadedBy,
+	}
+
+	var lines []models.BankStatementLine
+	for i, credit := range credits {
+		importRow.TotalCredits++
+		importRow.TotalAmount += credit.Amount
+		lines = append(lines, matcher.match(credit, fingerprints[i]))
+	}
+
+	for _, line := range lines {
+		switch line.MatchStatus {
+		case models.BankMatchMatched:
+			importRow.MatchedCount++
+		case models.BankMatchProposed:
+			importRow.ProposedCount++
+		case models.BankMatchAmountMismatch:
+			importRow.MismatchCount++
+		case models.BankMatchDuplicate:
+			importRow.DuplicateCount++
+		default:
+			importRow.UnmatchedCount++
+		}
+	}
+
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&importRow).Error; err != nil {
+			return err
+		}
+		for i := range lines {
+			lines[i].ImportID = importRow.ID
+		}
+		return tx.CreateInBatches(&lines, 500).Error
+	})
+	if err != nil {
+		return nil, fmt.Errorf("gagal menyimpan hasil import: %w", err)
+	}
+
+	utils.Log.Infow("BankStatementService: import selesai",
+		"import_id", importRow.ID,
+		"credits", importRow.TotalCredits,
+		"matched", importRow.MatchedCount,
+		"proposed", importRow.ProposedCount,
+		"unmatched", importRow.UnmatchedCount,
+	)
+
+	return s.Report(importRow.ID, "")
+}
+
+// bankCreditMatcher mencocokkan kredit mutasi dengan VA yang sudah diambil dari database.
+// State-nya (fingerprint & invoice yang sudah dipakai) berlaku untuk satu kali import.
+type bankCreditMatcher struct {
+	vaMap            map[string]bankVARow
+	seenFingerprints map[string]bool
+	claimedInvoices  map[uint]bool
+	alreadyMatched   func(invoiceID uint) bool // invoice sudah dicocokkan di import sebelumnya
+}
+
+func newBankCreditMatcher(vaMap map[string]bankVARow, importedFingerprints []string, alreadyMatched func(invoiceID uint) bool) *bankCreditMatcher {
+	seen := make(map[string]bool)
+	for _, fp := range importedFingerprints {
+		seen[fp] = true
+	}
+	return &bankCreditMatcher{
+		vaMap:            vaMap,
+		seenFingerprints: seen,
+		claimedInvoices:  make(map[uint]bool),
+		alreadyMatched:   alreadyMatched,
+	}
+}
+
+func (m *bankCreditMatcher) match(credit models.BankStatementCredit, fingerprint string) models.BankStatementLine {
+	line := models.BankStatementLine{
+		LineNo:        credit.LineNo,
+		ValueDate:     credit.ValueDate,
+		Amount:        credit.Amount,
+		BankReference: credit.BankReference,
+		Description:   credit.Description,
+		Fingerprint:   fingerprint,
+		MatchStatus:   models.BankMatchUnmatched,
+	}
+
+	if m.seenFingerprints[fingerprint] {
+		line.MatchStatus = models.BankMatchDuplicate
+		line.Note = "Kredit yang sama sudah pernah diimpor"
+		return line
+	}
+	m.seenFingerprints[fingerprint] = true
+
+	var va *bankVARow
+	for _, candidate := range vaCandidatePattern.FindAllString(credit.BankReference+" "+credit.Description, -1) {
+		if row, ok := m.vaMap[candidate]; ok {
+			row := row
+			va = &row
+			break
+		}
+	}
+	if va == nil {
+		return line
+	}
+
+	invoiceID := va.InvoiceID
+	line.VirtualAccount = va.VirtualAccount
+	line.InvoiceID = &invoiceID
+	line.InvoiceStatus = va.Status
+	line.InvoiceAmount = va.TotalAmount
+
+	switch {
+	case va.TotalAmount != credit.Amount:
+		line.MatchStatus = models.BankMatchAmountMismatch
+		line.Note = fmt.Sprintf("Nominal invoice %d, kredit %d", va.TotalAmount, credit.Amount)
+	case m.claimedInvoices[invoiceID] || m.alreadyMatched(invoiceID):
+		line.MatchStatus = models.BankMatchDuplicate
+		line.Note = "Invoice sudah dicocokkan dengan kredit lain"
+	case va.Status == "Paid":
+		line.MatchStatus = models.BankMatchMatched
+		m.claimedInvoices[invoiceID] = true
+	default:
+		line.MatchStatus = models.BankMatchProposed
+		line.ProposalStatus = models.BankProposalPending
+		line.Note = "Invoice belum Paid di EPNBP, perlu konfirmasi manual"
+		m.claimedInvoices[invoiceID] = true
+	}
+	return line
+}
+
+func (s *bankStatementService) invoiceAlreadyMatched(invoiceID uint) bool {
+	var count int64
+	s.db.Model(&models.BankStatementLine{}).
+		Where("invoice_id = ? AND match_status IN ? AND (proposal_status IS NULL OR proposal_status <> ?)",
+			invoiceID, []string{models.BankMatchMatched, models.BankMatchProposed}, models.BankProposalRejected).
+		Count(&count)
+	return count > 0
+}
+
+func (s *bankStatementService) List(limit int) ([]models.BankStatementImport, error) {
+	var imports []models.BankStatementImport
+	err := s.db.Order("created_at DESC").Limit(limit).Find(&imports).Error
+	return imports, err
+}
+
+// Report ringkasan per status + baris (opsional difilter status)
+func (s *bankStatementService) Report(importID uint, status string) (*models.BankStatementReport, error) {
+	var importRow models.BankStatementImport
+	if err := s.db.First(&importRow, importID).Error; err != nil {
+		return nil, err
+	}
+
+	var summary []models.BankMatchSummary
+	err := s.db.Model(&models.BankStatementLine{}).
+		Select("match_status AS status, COUNT(*) AS count, CAST(COALESCE(SUM(amount), 0) AS SIGNED) AS amount").
+		Where("import_id = ?", importID).
+		Group("match_status").
+		Scan(&summary).Error
+	if err != nil {
+		return nil, err
+	}
+
+	query := s.db.Where("import_id = ?", importID)
+	if status != "" {
+		query = query.Where("match_status = ?", status)
+	}
+	var lines []models.BankStatementLine
+	if err := query.Order("line_no ASC").Find(&lines).Error; err != nil {
+		return nil, err
+	}
+
+	if summary == nil {
+		summary = []models.BankMatchSummary{}
+	}
+	if lines == nil {
+		lines = []models.BankStatementLine{}
+	}
+	return &models.BankStatementReport{Import: importRow, Summary: summary, Lines: lines}, nil
+}
+
+// DecideProposal mencatat keputusan manual atas kecocokan dengan invoice yang belum Paid.
+// Status invoice di EPNBP tetap tidak diubah dari sini.
+func (s *bankStatementService) DecideProposal(lineID uint, confirm bool, decidedBy, note string) (*models.BankStatementLine, error) {
+	var line models.BankStatementLine
+	if err := s.db.First(&line, lineID).Error; err != nil {
+		return nil, err
+	}
+	if line.MatchStatus != models.BankMatchProposed || line.ProposalStatus != models.BankProposalPending {
+		return nil, fmt.Errorf("baris %d bukan proposal yang menunggu keputusan", lineID)
+	}
+
+	now := time.Now()
+	line.ProposalStatus = models.BankProposalRejected
+	if confirm {
+		line.ProposalStatus = models.BankProposalConfirmed
+	}
+	line.DecidedBy = decidedBy
+	line.DecidedAt = &now
+	if note != "" {
+		line.Note = note
+	}
+
+	if err := s.db.Save(&line).Error; err != nil {
+		return nil, err
+	}
+	return &line, nil
+}
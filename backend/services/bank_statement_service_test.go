@@ -0,0 +1,137 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/dedegunawan/backend-ujian-telp-v5/models"
+)
+
+func TestBankCreditMatcher(t *testing.T) {
+	vaMap := map[string]bankVARow{
+		"8800000000000001": {VirtualAccount: "8800000000000001", InvoiceID: 1, Status: "Paid", TotalAmount: 1500000},
+		"8800000000000002": {VirtualAccount: "8800000000000002", InvoiceID: 2, Status: "Pending", TotalAmount: 750000},
+		"8800000000000003": {VirtualAccount: "8800000000000003", InvoiceID: 3, Status: "Paid", TotalAmount: 500000},
+		"8800000000000004": {VirtualAccount: "8800000000000004", InvoiceID: 4, Status: "Paid", TotalAmount: 100000},
+	}
+	// Invoice 4 sudah dicocokkan di import sebelumnya
+	alreadyMatched := func(invoiceID uint) bool { return invoiceID == 4 }
+
+	type step struct {
+		credit      models.BankStatementCredit
+		fingerprint string // kosong: dihitung dari kredit
+		wantStatus  string
+		wantVA      string
+		wantInvoice uint
+	}
+	tests := []struct {
+		name     string
+		imported []string // fingerprint yang sudah ada di database
+		steps    []step
+	}{
+		{
+			name: "VA di keterangan, nominal cocok, invoice Paid",
+			steps: []step{
+				{credit: models.BankStatementCredit{Amount: 1500000, Description: "PEMBAYARAN UKT VA 8800000000000001 BUDI"},
+					wantStatus: models.BankMatchMatched, wantVA: "8800000000000001", wantInvoice: 1},
+			},
+		},
+		{
+			name: "VA di referensi, invoice belum Paid diusulkan",
+			steps: []step{
+				{credit: models.BankStatementCredit{Amount: 750000, BankReference: "8800000000000002"},
+					wantStatus: models.BankMatchProposed, wantVA: "8800000000000002", wantInvoice: 2},
+			},
+		},
+		{
+			name: "nominal berbeda",
+			steps: []step{
+				{credit: models.BankStatementCredit{Amount: 499000, Description: "VA 8800000000000003"},
+					wantStatus: models.BankMatchAmountMismatch, wantVA: "8800000000000003", wantInvoice: 3},
+			},
+		},
+		{
+			name: "tidak ada VA yang dikenal, angka pendek bukan kandidat",
+			steps: []step{
+				{credit: models.BankStatementCredit{Amount: 1500000, Description: "SETORAN 1234567 VA 9900000000000009"},
+					wantStatus: models.BankMatchUnmatched},
+			},
+		},
+		{
+			name: "kandidat pertama yang dikenal dipakai",
+			steps: []step{
+				{credit: models.BankStatementCredit{Amount: 500000, BankReference: "12345678", Description: "VA 8800000000000003 / 8800000000000001"},
+					wantStatus: models.BankMatchMatched, wantVA: "8800000000000003", wantInvoice: 3},
+			},
+		},
+		{
+			name:     "kredit sudah pernah diimpor",
+			imported: []string{"fp-lama"},
+			steps: []step{
+				{credit: models.BankStatementCredit{Amount: 1500000, Description: "VA 8800000000000001"}, fingerprint: "fp-lama",
+					wantStatus: models.BankMatchDuplicate},
+			},
+		},
+		{
+			name: "kredit kembar di file yang sama",
+			steps: []step{
+				{credit: models.BankStatementCredit{Amount: 1500000, Description: "VA 8800000000000001"}, fingerprint: "fp-1",
+					wantStatus: models.BankMatchMatched, wantVA: "8800000000000001", wantInvoice: 1},
+				{credit: models.BankStatementCredit{Amount: 1500000, Description: "VA 8800000000000001"}, fingerprint: "fp-1",
+					wantStatus: models.BankMatchDuplicate},
+			},
+		},
+		{
+			name: "invoice dicocokkan dua kredit berbeda",
+			steps: []step{
+				{credit: models.BankStatementCredit{Amount: 750000, BankReference: "REF1", Description: "VA 8800000000000002"},
+					wantStatus: models.BankMatchProposed, wantVA: "8800000000000002", wantInvoice: 2},
+				{credit: models.BankStatementCredit{Amount: 750000, BankReference: "REF2", Description: "VA 8800000000000002"},
+					wantStatus: models.BankMatchDuplicate, wantVA: "8800000000000002", wantInvoice: 2},
+			},
+		},
+		{
+			name: "invoice sudah dicocokkan di import sebelumnya",
+			steps: []step{
+				{credit: models.BankStatementCredit{Amount: 100000, Description: "VA 8800000000000004"},
+					wantStatus: models.BankMatchDuplicate, wantVA: "8800000000000004", wantInvoice: 4},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			matcher := newBankCreditMatcher(vaMap, tt.imported, alreadyMatched)
+			for i, step := range tt.steps {
+				fingerprint := step.fingerprint
+				if fingerprint == "" {
+					fingerprint = bankCreditFingerprint(step.credit)
+				}
+				line := matcher.match(step.credit, fingerprint)
+
+				if line.MatchStatus != step.wantStatus {
+					t.Errorf("kredit %d: status = %q, want %q (%s)", i, line.MatchStatus, step.wantStatus, line.Note)
+				}
+				if line.VirtualAccount != step.wantVA {
+					t.Errorf("kredit %d: VA = %q, want %q", i, line.VirtualAccount, step.wantVA)
+				}
+				var invoiceID uint
+				if line.InvoiceID != nil {
+					invoiceID = *line.InvoiceID
+				}
+				if invoiceID != step.wantInvoice {
+					t.Errorf("kredit %d: invoice = %d, want %d", i, invoiceID, step.wantInvoice)
+				}
+				if line.Amount != step.credit.Amount || line.Fingerprint != fingerprint {
+					t.Errorf("kredit %d: baris tidak menyalin kredit: %+v", i, line)
+				}
+				wantProposal := ""
+				if step.wantStatus == models.BankMatchProposed {
+					wantProposal = models.BankProposalPending
+				}
+				if line.ProposalStatus != wantProposal {
+					t.Errorf("kredit %d: proposal = %q, want %q", i, line.ProposalStatus, wantProposal)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,70 @@
+package services
+
+import (
+	"github.com/dedegunawan/backend-ujian-telp-v5/models"
+	"gorm.io/gorm"
+)
+
+// StudentBillsBaseQuery query dasar GET /student-bills (tanpa filter status).
+// Hanya mengambil tagihan dari finance year yang aktif.
+func StudentBillsBaseQuery(db *gorm.DB, filter models.StudentBillFilter) *gorm.DB {
+	query := db.Model(&models.StudentBill{}).
+		Joins("INNER JOIN finance_years ON finance_years.academic_year = student_bills.academic_year").
+		Joins("LEFT JOIN mahasiswas ON mahasiswas.mhsw_id = student_bills.student_id").
+		Where("finance_years.is_active = ?", true)
+
+	if filter.StudentID != "" {
+		query = query.Where("student_bills.student_id = ?", filter.StudentID)
+	}
+	if filter.AcademicYear != "" {
+		query = query.Where("student_bills.academic_year = ?", filter.AcademicYear)
+	}
+	if filter.Search != "" {
+		searchPattern := "%" + filter.Search + "%"
+		query = query.Where(
+			"student_bills.student_id ILIKE ? OR "+
+				"student_bills.name ILIKE ? OR "+
+				"COALESCE(mahasiswas.nama, '') ILIKE ?",
+			searchPattern, searchPattern, searchPattern,
+		)
+	}
+	return query
+}
+
+// ApplyStudentBillStatus filter status sederhana berdasarkan paid_amount (tanpa menghitung diskon)
+func ApplyStudentBillStatus(query *gorm.DB, status string) *gorm.DB {
+	switch status {
+	case "paid":
+		// Approximate: jika paid_amount >= amount (tanpa discount), kemungkinan sudah lunas
+		return query.Where("student_bills.paid_amount >= student_bills.quantity * student_bills.amount")
+	case "unpaid":
+		return query.Where("student_bills.paid_amount = 0 AND (student_bills.quantity * student_bills.amount) > 0")
+	case "partial":
+		return query.Where("student_bills.paid_amount > 0 AND student_bills.paid_amount < student_bills.quantity * student_bills.amount")
+	}
+	// "all" - tanpa filter tambahan
+	return query
+}
+
+// PaymentStatusBaseQuery query dasar GET /payment-status (tanpa filter status)
+func PaymentStatusBaseQuery(db *gorm.DB, filter models.PaymentStatusFilter) *gorm.DB {
+	query := db.Model(&models.StudentBill{})
+	if filter.StudentID != "" {
+		query = query.Where("student_id = ?", filter.StudentID)
+	}
+	if filter.AcademicYear != "" {
+		query = query.Where("academic_year = ?", filter.AcademicYear)
+	}
+	return query
+}
+
+// ApplyPaymentStatus filter status untuk GET /payment-status
+func ApplyPaymentStatus(query *gorm.DB, status string) *gorm.DB {
+	switch status {
+	case "paid":
+		return query.Where("(quantity * amount) - paid_amount <= 0")
+	case "unpaid":
+		return query.Where("(quantity * amount) - paid_amount > 0")
+	}
+	return query
+}
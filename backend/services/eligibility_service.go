@@ -0,0 +1,172 @@
+package services
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/dedegunawan/backend-ujian-telp-v5/models"
+	"github.com/dedegunawan/backend-ujian-telp-v5/utils"
+	"gorm.io/gorm"
+)
+
+// EligibilityService menentukan apakah mahasiswa boleh ditagih berdasarkan kode status_akademiks
+type EligibilityService interface {
+	EvaluateKode(kode string, nama string) *models.EligibilityResult
+	EvaluateMaster(mhswMaster *models.MahasiswaMaster) *models.EligibilityResult
+	Rules() map[string]models.EligibilityRule
+}
+
+type eligibilityService struct {
+	db    *gorm.DB
+	rules map[string]models.EligibilityRule
+}
+
+// defaultEligibilityRules aturan bawaan jika ELIGIBILITY_RULES tidak di-set
+var defaultEligibilityRules = []models.EligibilityRule{
+	{Kode: "A", Label: "aktif", Outcome: models.EligibilityBillFull},
+	{Kode: "C", Label: "cuti", Outcome: models.EligibilityNoBill},
+	{Kode: "N", Label: "non_aktif", Outcome: models.EligibilityBlock},
+	{Kode: "L", Label: "lulus", Outcome: models.EligibilityNoBill},
+	{Kode: "D", Label: "do", Outcome: models.EligibilityBlock},
+}
+
+// legacyActiveStatusAkademikID status_akademik_id yang dulu dianggap aktif oleh GetIsMahasiswaAktifFromFullData
+const legacyActiveStatusAkademikID = 1
+
+// eligibilityRules aturan aktif; bawaan sampai LoadEligibilityRules dipanggil saat start
+var eligibilityRules = buildEligibilityRules(nil)
+
+// LoadEligibilityRules membaca ELIGIBILITY_RULES sekali saat aplikasi start. Konfigurasi yang
+// tidak valid dikembalikan sebagai error (start dihentikan) alih-alih diam-diam memakai aturan bawaan.
+// Format: "A:bill_full,C:bill_reduced:50,N:block,L:no_bill,D:block"
+func LoadEligibilityRules() error {
+	raw := os.Getenv("ELIGIBILITY_RULES")
+	if raw == "" {
+		return nil
+	}
+	parsed, err := parseEligibilityRules(raw)
+	if err != nil {
+		return fmt.Errorf("ELIGIBILITY_RULES tidak valid: %w", err)
+	}
+	eligibilityRules = buildEligibilityRules(parsed)
+	return nil
+}
+
+// buildEligibilityRules menggabungkan aturan bawaan dengan override
+func buildEligibilityRules(overrides map[string]models.EligibilityRule) map[string]models.EligibilityRule {
+	rules := make(map[string]models.EligibilityRule)
+	for _, rule := range defaultEligibilityRules {
+		rules[rule.Kode] = rule
+	}
+	for kode, rule := range overrides {
+		if existing, ok := rules[kode]; ok && rule.Label == "" {
+			rule.Label = existing.Label
+		}
+		rules[kode] = rule
+	}
+	for kode, rule := range rules {
+		if rule.Label == "" {
+			rule.Label = strings.ToLower(kode)
+			rules[kode] = rule
+		}
+	}
+	return rules
+}
+
+func NewEligibilityService(db *gorm.DB) EligibilityService {
+	return &eligibilityService{db: db, rules: eligibilityRules}
+}
+
+func parseEligibilityRules(raw string) (map[string]models.EligibilityRule, error) {
+	rules := make(map[string]models.EligibilityRule)
+	for _, entry := range strings.Split(raw, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		parts := strings.Split(entry, ":")
+		if len(parts) < 2 {
+			return nil, fmt.Errorf("aturan %q harus berformat KODE:outcome", entry)
+		}
+
+		rule := models.EligibilityRule{
+			Kode:    strings.ToUpper(strings.TrimSpace(parts[0])),
+			Outcome: strings.TrimSpace(parts[1]),
+		}
+
+		switch rule.Outcome {
+		case models.EligibilityBillFull, models.EligibilityNoBill, models.EligibilityBlock:
+		case models.EligibilityBillReduced:
+			if len(parts) < 3 {
+				return nil, fmt.Errorf("aturan %q membutuhkan persentase", entry)
+			}
+			pct, err := strconv.Atoi(strings.TrimSpace(parts[2]))
+			if err != nil || pct <= 0 || pct >= 100 {
+				return nil, fmt.Errorf("persentase pada aturan %q harus 1-99", entry)
+			}
+			rule.Percentage = pct
+		default:
+			return nil, fmt.Errorf("outcome %q tidak dikenal", rule.Outcome)
+		}
+
+		rules[rule.Kode] = rule
+	}
+	return rules, nil
+}
+
+func (s *eligibilityService) Rules() map[string]models.EligibilityRule {
+	return s.rules
+}
+
+// EvaluateKode mengevaluasi kelayakan dari kode status akademik
+// Kode yang tidak dikenal selalu diblokir
+func (s *eligibilityService) EvaluateKode(kode string, nama string) *models.EligibilityResult {
+	kode = strings.ToUpper(strings.TrimSpace(kode))
+
+	rule, ok := s.rules[kode]
+	if !ok {
+		return &models.EligibilityResult{
+			StatusKode: kode,
+			StatusNama: nama,
+			Outcome:    models.EligibilityBlock,
+			Reason:     "status_unknown",
+			CanBill:    false,
+		}
+	}
+
+	return &models.EligibilityResult{
+		StatusKode:    kode,
+		StatusNama:    nama,
+		Outcome:       rule.Outcome,
+		Reason:        "status_" + rule.Label,
+		Percentage:    rule.Percentage,
+		CanBill:       rule.Outcome == models.EligibilityBillFull || rule.Outcome == models.EligibilityBillReduced,
+		CanGenerateVA: rule.Outcome == models.EligibilityBillFull,
+	}
+}
+
+// EvaluateMaster mengevaluasi kelayakan dari mahasiswa_masters.status_akademik_id
+func (s *eligibilityService) EvaluateMaster(mhswMaster *models.MahasiswaMaster) *models.EligibilityResult {
+	if mhswMaster == nil || mhswMaster.StatusAkademikID == 0 {
+		return s.EvaluateKode("", "")
+	}
+
+	var statusAkademik models.StatusAkademik
+	err := s.db.Where("id = ?", mhswMaster.StatusAkademikID).First(&statusAkademik).Error
+	if err != nil || statusAkademik.Kode == "" {
+		utils.Log.Warnw("Eligibility: status akademik tidak ditemukan",
+			"mhswID", mhswMaster.StudentID,
+			"StatusAkademikID", mhswMaster.StatusAkademikID,
+			"error", err,
+		)
+		if mhswMaster.StatusAkademikID == legacyActiveStatusAkademikID {
+			return s.EvaluateKode("A", "")
+		}
+		return s.EvaluateKode("", "")
+	}
+
+	return s.EvaluateKode(statusAkademik.Kode, statusAkademik.Nama)
+}
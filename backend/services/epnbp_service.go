@@ -7,6 +7,7 @@ import (
 	"os"
 	"time"
 
+	"github.com/dedegunawan/backend-ujian-telp-v5/metrics"
 	"github.com/dedegunawan/backend-ujian-telp-v5/models"
 	"github.com/dedegunawan/backend-ujian-telp-v5/repositories"
 )
@@ -26,6 +27,12 @@ func NewEpnbpService(repo repositories.EpnbpRepository) EpnbpService {
 	return &epnbpService{repo: repo}
 }
 
+// client EPNBP yang ikut membawa ctx koneksi DB repo (diisi database.WithContext di controller),
+// sehingga panggilan ke EPNBP menjadi child span dari request
+func (es epnbpService) client() utils.Epnbp {
+	return utils.NewEpnbp().WithContext(es.repo.GetDB().Statement.Context)
+}
+
 func (es *epnbpService) GenerateNewPayUrl(user models.User, mahasiswa models.Mahasiswa, studentBill models.StudentBill, financeYear models.FinanceYear) (*models.PayUrl, error) {
 
 	// Siapkan payload untuk API
@@ -65,7 +72,12 @@ func (es *epnbpService) GenerateNewPayUrl(user models.User, mahasiswa models.Mah
 		"exp": now.Unix() + 2*3600,
 	}
 
-	utils.Log.Infof("GenerateNewPayUrl payload: %v", payload)
+	// Payload berisi nama, email dan nomor HP mahasiswa: cukup catat identitas tagihannya
+	utils.LogCtx(es.repo.GetDB().Statement.Context).Infow("GenerateNewPayUrl",
+		"invoice_number", payload["invoice_number"],
+		"npm", mahasiswa.MhswID,
+		"total_amount", studentBill.Amount,
+	)
 
 	var result struct {
 		PayUrl    string `json:"pay_url"`
@@ -74,8 +86,9 @@ func (es *epnbpService) GenerateNewPayUrl(user models.User, mahasiswa models.Mah
 		ExpiredAt string `json:"expired_at"`
 	}
 
-	resultInvoice, err := utils.NewEpnbp().CreateInvoice(payload)
+	resultInvoice, err := es.client().CreateInvoice(payload)
 	if err != nil {
+		metrics.VAGenerations.WithLabelValues(metrics.OutcomeError).Inc()
 		return nil, err
 	}
 
@@ -103,15 +116,17 @@ func (es *epnbpService) GenerateNewPayUrl(user models.User, mahasiswa models.Mah
 	}
 
 	if err := es.repo.GetDB().Save(&payUrl).Error; err != nil {
+		metrics.VAGenerations.WithLabelValues(metrics.OutcomeError).Inc()
 		return nil, err
 	}
 
+	metrics.VAGenerations.WithLabelValues(metrics.OutcomeSuccess).Inc()
 	return &payUrl, nil
 }
 
 func (es epnbpService) CheckStatusPaidByInvoiceID(invoiceId string) (bool, *time.Time) {
 	utils.Log.Infof("CheckStatusPaidByInvoiceID invoiceId: %s", invoiceId)
-	result, err := utils.NewEpnbp().SearchByInvoiceID(invoiceId)
+	result, err := es.client().SearchByInvoiceID(invoiceId)
 	if err != nil {
 		return false, nil
 	}
@@ -138,7 +153,7 @@ func (es *epnbpService) isPaidInvoiceResult(result map[string]interface{}) (bool
 }
 
 func (es epnbpService) CheckStatusPaidByVirtualAccount(virtualAccount string, invoiceIDs []string) (bool, *time.Time) {
-	result, err := utils.NewEpnbp().SearchByVirtualAccount(virtualAccount)
+	result, err := es.client().SearchByVirtualAccount(virtualAccount)
 	if err != nil {
 		return false, nil
 	}
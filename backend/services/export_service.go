@@ -0,0 +1,540 @@
+package services
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/dedegunawan/backend-ujian-telp-v5/config"
+	"github.com/dedegunawan/backend-ujian-telp-v5/models"
+	"github.com/dedegunawan/backend-ujian-telp-v5/utils"
+	"github.com/xuri/excelize/v2"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+const (
+	exportBatchSize  = 1000
+	jobTypeRunExport = "run_export"
+)
+
+// exportSlots membatasi jumlah export yang berjalan bersamaan per proses
+var exportSlots = make(chan struct{}, 2)
+
+type ExportService interface {
+	Enqueue(userID, email, exportType, format string, filters interface{}) (*models.ExportJob, error)
+	History(userID string, limit int) ([]models.ExportJob, error)
+	Get(userID string, id uint) (*models.ExportJob, error)
+	Run(exportID uint) error
+	FailStale(olderThan time.Duration) (int64, error)
+}
+
+type exportService struct {
+	db     *gorm.DB
+	worker WorkerService
+}
+
+func NewExportService(db *gorm.DB) ExportService {
+	return &exportService{db: db, worker: NewWorkerService(db)}
+}
+
+// exportURLTTL masa berlaku signed URL hasil export (EXPORT_URL_TTL, default 1 jam)
+func exportURLTTL() time.Duration {
+	if raw := config.GetEnv("EXPORT_URL_TTL"); raw != "" {
+		if ttl, err := time.ParseDuration(raw); err == nil && ttl > 0 {
+			return ttl
+		}
+	}
+	return time.Hour
+}
+
+// Enqueue mencatat ExportJob baru lalu mengantrekannya di JobQueue; job-worker yang menjalankannya
+// sehingga export ikut ditunggu saat shutdown
+func (s *exportService) Enqueue(userID, email, exportType, format string, filters interface{}) (*models.ExportJob, error) {
+	if exportType != models.ExportTypeStudentBills && exportType != models.ExportTypePaymentStatus {
+		return nil, fmt.Errorf("jenis export tidak dikenal: %s", exportType)
+	}
+	if format != "xlsx" && format != "csv" {
+		return nil, fmt.Errorf("format export harus xlsx atau csv")
+	}
+
+	payload, err := json.Marshal(filters)
+	if err != nil {
+		return nil, err
+	}
+
+	job := models.ExportJob{
+		UserID:    userID,
+		UserEmail: email,
+		Type:      exportType,
+		Format:    format,
+		Filters:   datatypes.JSON(payload),
+		Status:    models.ExportStatusQueued,
+	}
+	if err := s.db.Create(&job).Error; err != nil {
+		return nil, fmt.Errorf("gagal menyimpan export job: %w", err)
+	}
+
+	if err := s.worker.EnqueueJob(jobTypeRunExport, map[string]interface{}{"export_id": job.ID}, 0); err != nil {
+		message := err.Error()
+		s.db.Model(&job).Updates(map[string]interface{}{"status": models.ExportStatusFailed, "error": message})
+		return nil, fmt.Errorf("gagal mengantrekan export job: %w", err)
+	}
+
+	job.ComputeProgress()
+	return &job, nil
+}
+
+// History riwayat export milik user, terbaru di atas
+func (s *exportService) History(userID string, limit int) ([]models.ExportJob, error) {
+	var jobs []models.ExportJob
+	err := s.db.Where("user_id = ?", userID).
+		Order("created_at DESC").
+		Limit(limit).
+		Find(&jobs).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range jobs {
+		s.decorate(&jobs[i])
+	}
+	return jobs, nil
+}
+
+// Get detail satu export milik user, termasuk signed URL baru jika sudah selesai
+func (s *exportService) Get(userID string, id uint) (*models.ExportJob, error) {
+	var job models.ExportJob
+	if err := s.db.Where("id = ? AND user_id = ?", id, userID).First(&job).Error; err != nil {
+		return nil, err
+	}
+	s.decorate(&job)
+	return &job, nil
+}
+
+func (s *exportService) decorate(job *models.ExportJob) {
+	job.ComputeProgress()
+	if job.Status != models.ExportStatusDone || job.ObjectName == "" {
+		return
+	}
+
+	url, err := utils.MinioSignedURL(minioBucket(), job.ObjectName, exportURLTTL())
+	if err != nil {
+		utils.Log.Errorw("ExportService: gagal membuat signed URL",
+			"export_id", job.ID,
+			"error", err,
+		)
+		return
+	}
+	job.URL = url
+}
+
+func minioBucket() string {
+	bucketName := os.Getenv("MINIO_BUCKET")
+	if bucketName == "" {
+		bucketName = "default"
+	}
+	return bucketName
+}
+
+// exportSheetWriter abstraksi tulis baris untuk xlsx (StreamWriter) maupun csv
+type exportSheetWriter interface {
+	WriteRow(values []interface{}) error
+	Close() error
+}
+
+type csvSheetWriter struct {
+	file   *os.File
+	writer *csv.Writer
+}
+
+func (w *csvSheetWriter) WriteRow(values []interface{}) error {
+	record := make([]string, len(values))
+	for i, v := range values {
+		record[i] = fmt.Sprint(v)
+	}
+	return w.writer.Write(record)
+}
+
+func (w *csvSheetWriter) Close() error {
+	w.writer.Flush()
+	if err := w.writer.Error(); err != nil {
+		w.file.Close()
+		return err
+	}
+	return w.file.Close()
+}
+
+type xlsxSheetWriter struct {
+	path   string
+	file   *excelize.File
+	stream *excelize.StreamWriter
+	row    int
+}
+
+func (w *xlsxSheetWriter) WriteRow(values []interface{}) error {
+	w.row++
+	cell, _ := excelize.CoordinatesToCellName(1, w.row)
+	return w.stream.SetRow(cell, values)
+}
+
+func (w *xlsxSheetWriter) Close() error {
+	defer w.file.Close()
+	if err := w.stream.Flush(); err != nil {
+		return err
+	}
+	return w.file.SaveAs(w.path)
+}
+
+func newExportSheetWriter(format, path string) (exportSheetWriter, error) {
+	if format == "csv" {
+		file, err := os.Create(path)
+		if err != nil {
+			return nil, err
+		}
+		return &csvSheetWriter{file: file, writer: csv.NewWriter(file)}, nil
+	}
+
+	file := excelize.NewFile()
+	stream, err := file.NewStreamWriter("Sheet1")
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	return &xlsxSheetWriter{path: path, file: file, stream: stream}, nil
+}
+
+// Run dipanggil oleh WorkerService. Kegagalan export dicatat di ExportJob dan tidak diulang
+// otomatis (user bisa meminta export baru), jadi hanya error database yang dikembalikan.
+func (s *exportService) Run(exportID uint) error {
+	exportSlots <- struct{}{}
+	defer func() { <-exportSlots }()
+
+	var job models.ExportJob
+	if err := s.db.First(&job, exportID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+	// Sudah selesai, atau sudah digagalkan FailStale
+	if job.Status != models.ExportStatusQueued {
+		return nil
+	}
+
+	now := time.Now()
+	err := s.db.Model(&job).Updates(map[string]interface{}{
+		"status":     models.ExportStatusProcessing,
+		"started_at": now,
+	}).Error
+	if err != nil {
+		return err
+	}
+
+	objectName, err := s.generateSafely(&job)
+
+	finished := time.Now()
+	updates := map[string]interface{}{"finished_at": finished}
+	if err != nil {
+		utils.Log.Errorw("ExportService: export gagal",
+			"export_id", job.ID,
+			"type", job.Type,
+			"error", err,
+		)
+		updates["status"] = models.ExportStatusFailed
+		updates["error"] = err.Error()
+	} else {
+		updates["status"] = models.ExportStatusDone
+		updates["object_name"] = objectName
+	}
+	return s.db.Model(&job).Updates(updates).Error
+}
+
+// generateSafely menjalankan generate dan mengubah panic (excelize, MinIO) menjadi error export
+func (s *exportService) generateSafely(job *models.ExportJob) (objectName string, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic saat export: %v", r)
+		}
+	}()
+	return s.generate(job)
+}
+
+// FailStale menandai gagal export queued/processing yang tidak bergerak lebih dari olderThan,
+// yaitu export yang terputus karena proses berhenti (restart, crash, SHUTDOWN_TIMEOUT habis).
+// Dipanggil saat start; progress per batch memperbarui updated_at export yang masih berjalan.
+func (s *exportService) FailStale(olderThan time.Duration) (int64, error) {
+	now := time.Now()
+	result := s.db.Model(&models.ExportJob{}).
+		Where("status IN ? AND updated_at < ?",
+			[]string{models.ExportStatusQueued, models.ExportStatusProcessing}, now.Add(-olderThan)).
+		Updates(map[string]interface{}{
+			"status":      models.ExportStatusFailed,
+			"error":       "export terhenti karena aplikasi berhenti, silakan ulangi export",
+			"finished_at": now,
+		})
+	return result.RowsAffected, result.Error
+}
+
+func exportIDFromJob(job *models.JobQueue) (uint, error) {
+	var payload struct {
+		ExportID uint `json:"export_id"`
+	}
+	if err := json.Unmarshal(job.Payload, &payload); err != nil {
+		return 0, err
+	}
+	return payload.ExportID, nil
+}
+
+// generate menulis semua baris ke file sementara per batch lalu meng-upload ke MinIO
+func (s *exportService) generate(job *models.ExportJob) (string, error) {
+	tmp, err := os.CreateTemp("", fmt.Sprintf("export-%d-*.%s", job.ID, job.Format))
+	if err != nil {
+		return "", err
+	}
+	path := tmp.Name()
+	tmp.Close()
+	defer os.Remove(path)
+
+	writer, err := newExportSheetWriter(job.Format, path)
+	if err != nil {
+		return "", err
+	}
+
+	switch job.Type {
+	case models.ExportTypeStudentBills:
+		err = s.writeStudentBills(job, writer)
+	case models.ExportTypePaymentStatus:
+		err = s.writePaymentStatus(job, writer)
+	}
+	if closeErr := writer.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return "", err
+	}
+
+	objectName := fmt.Sprintf("exports/%s/%s_%d.%s", job.Type, time.Now().Format("20060102"), job.ID, job.Format)
+	if _, err := utils.UploadFileToMinio(objectName, path); err != nil {
+		return "", fmt.Errorf("gagal upload export ke MinIO: %w", err)
+	}
+	return objectName, nil
+}
+
+func (s *exportService) progress(job *models.ExportJob, processed int64) {
+	job.ProcessedRows = processed
+	s.db.Model(job).Update("processed_rows", processed)
+}
+
+func (s *exportService) writeStudentBills(job *models.ExportJob, writer exportSheetWriter) error {
+	var filter models.StudentBillFilter
+	if err := json.Unmarshal(job.Filters, &filter); err != nil {
+		return err
+	}
+
+	query := ApplyStudentBillStatus(StudentBillsBaseQuery(s.db, filter), filter.Status)
+
+	var total int64
+	if err := query.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		return err
+	}
+	s.db.Model(job).Update("total_rows", total)
+
+	if err := writer.WriteRow([]interface{}{
+		"ID", "NPM", "Nama", "Tahun Akademik", "Tagihan", "Jumlah", "Nominal", "Beasiswa",
+		"Nominal Bersih", "Dibayar", "Sisa", "Status", "Draft", "Invoice ID", "Virtual Account", "Catatan", "Dibuat",
+	}); err != nil {
+		return err
+	}
+
+	var processed int64
+	var bills []models.StudentBill
+	result := query.Session(&gorm.Session{}).
+		Preload("Discounts").
+		Select("student_bills.*").
+		FindInBatches(&bills, exportBatchSize, func(tx *gorm.DB, batch int) error {
+			names, payUrls, vaMap := s.loadBillRelations(bills)
+			for _, bill := range bills {
+				invoiceID := ""
+				if payUrl, ok := payUrls[bill.ID]; ok && payUrl.InvoiceID > 0 {
+					invoiceID = strconv.FormatUint(uint64(payUrl.InvoiceID), 10)
+				}
+				row := []interface{}{
+					bill.ID, bill.StudentID, names[bill.StudentID], bill.AcademicYear, bill.Name, bill.Quantity,
+					bill.Amount, bill.Beasiswa, bill.NetAmount(), bill.PaidAmount, bill.Remaining(),
+					billStatus(bill), bill.Draft, invoiceID, vaMap[bill.ID], bill.Note,
+					bill.CreatedAt.Format("2006-01-02 15:04:05"),
+				}
+				if err := writer.WriteRow(row); err != nil {
+					return err
+				}
+			}
+			processed += int64(len(bills))
+			s.progress(job, processed)
+			return nil
+		})
+	return result.Error
+}
+
+func (s *exportService) writePaymentStatus(job *models.ExportJob, writer exportSheetWriter) error {
+	var filter models.PaymentStatusFilter
+	if err := json.Unmarshal(job.Filters, &filter); err != nil {
+		return err
+	}
+
+	query := ApplyPaymentStatus(PaymentStatusBaseQuery(s.db, filter), filter.Status)
+
+	var total int64
+	if err := query.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		return err
+	}
+	s.db.Model(job).Update("total_rows", total)
+
+	if err := writer.WriteRow([]interface{}{
+		"ID", "NPM", "Nama", "Tahun Akademik", "Tagihan", "Nominal", "Dibayar", "Sisa", "Status",
+		"Status DBPNBP", "Invoice ID", "Virtual Account", "VA Dibuat", "Pay URL Dibuat", "Pay URL Expired", "Dibuat",
+	}); err != nil {
+		return err
+	}
+
+	var processed int64
+	var bills []models.StudentBill
+	result := query.Session(&gorm.Session{}).
+		FindInBatches(&bills, exportBatchSize, func(tx *gorm.DB, batch int) error {
+			names, payUrls, _ := s.loadBillRelations(bills)
+
+			var invoiceIDs []uint
+			for _, payUrl := range payUrls {
+				invoiceIDs = append(invoiceIDs, payUrl.InvoiceID)
+			}
+			invoices := s.loadInvoiceInfo(invoiceIDs)
+
+			for _, bill := range bills {
+				row := []interface{}{
+					bill.ID, bill.StudentID, names[bill.StudentID], bill.AcademicYear, bill.Name,
+					bill.NetAmount(), bill.PaidAmount, bill.Remaining(), billStatus(bill),
+				}
+				if payUrl, ok := payUrls[bill.ID]; ok {
+					info := invoices[payUrl.InvoiceID]
+					expiredAt := ""
+					if !payUrl.ExpiredAt.IsZero() {
+						expiredAt = payUrl.ExpiredAt.Format("2006-01-02 15:04:05")
+					}
+					row = append(row, info.Status, payUrl.InvoiceID, info.VirtualAccount, info.CreatedAt,
+						payUrl.CreatedAt.Format("2006-01-02 15:04:05"), expiredAt)
+				} else {
+					row = append(row, "", "", "", "", "", "")
+				}
+				row = append(row, bill.CreatedAt.Format("2006-01-02 15:04:05"))
+
+				if err := writer.WriteRow(row); err != nil {
+					return err
+				}
+			}
+			processed += int64(len(bills))
+			s.progress(job, processed)
+			return nil
+		})
+	return result.Error
+}
+
+// loadBillRelations nama mahasiswa, pay_url terbaru dan VA terbaru untuk satu batch tagihan
+func (s *exportService) loadBillRelations(bills []models.StudentBill) (map[string]string, map[uint]models.PayUrl, map[uint]string) {
+	names := make(map[string]string)
+	payUrlMap := make(map[uint]models.PayUrl)
+	vaMap := make(map[uint]string)
+
+	var studentIDs []string
+	var billIDs []uint
+	for _, bill := range bills {
+		if bill.StudentID != "" {
+			studentIDs = append(studentIDs, bill.StudentID)
+		}
+		billIDs = append(billIDs, bill.ID)
+	}
+	if len(billIDs) == 0 {
+		return names, payUrlMap, vaMap
+	}
+
+	if len(studentIDs) > 0 {
+		var mahasiswas []models.Mahasiswa
+		if err := s.db.Where("mhsw_id IN ?", studentIDs).Find(&mahasiswas).Error; err == nil {
+			for _, m := range mahasiswas {
+				names[m.MhswID] = m.Nama
+			}
+		}
+	}
+
+	var payUrls []models.PayUrl
+	if err := s.db.Where("student_bill_id IN ?", billIDs).Order("created_at DESC").Find(&payUrls).Error; err == nil {
+		for _, payUrl := range payUrls {
+			if _, exists := payUrlMap[payUrl.StudentBillID]; !exists {
+				payUrlMap[payUrl.StudentBillID] = payUrl
+			}
+		}
+	}
+
+	var confirmations []models.PaymentConfirmation
+	if err := s.db.Where("student_bill_id IN ?", billIDs).Order("created_at DESC").Find(&confirmations).Error; err == nil {
+		for _, pc := range confirmations {
+			if _, exists := vaMap[pc.StudentBillID]; !exists && pc.VaNumber != "" {
+				vaMap[pc.StudentBillID] = pc.VaNumber
+			}
+		}
+	}
+
+	return names, payUrlMap, vaMap
+}
+
+type exportInvoiceInfo struct {
+	InvoiceID      uint       `gorm:"column:id"`
+	Status         string     `gorm:"column:status"`
+	VirtualAccount string     `gorm:"column:virtual_account"`
+	VaCreatedAt    *time.Time `gorm:"column:va_created_at"`
+	CreatedAt      string     `gorm:"-"`
+}
+
+// loadInvoiceInfo status invoice & VA terbaru dari MySQL untuk banyak invoice sekaligus
+func (s *exportService) loadInvoiceInfo(invoiceIDs []uint) map[uint]exportInvoiceInfo {
+	result := make(map[uint]exportInvoiceInfo)
+	if len(invoiceIDs) == 0 {
+		return result
+	}
+
+	var rows []exportInvoiceInfo
+	err := s.db.
+		Table("invoices").
+		Select("invoices.id, invoices.status, virtual_accounts.virtual_account, virtual_accounts.created_at AS va_created_at").
+		Joins("LEFT JOIN virtual_accounts ON virtual_accounts.invoice_id = invoices.id").
+		Where("invoices.id IN ?", invoiceIDs).
+		Order("virtual_accounts.created_at DESC").
+		Scan(&rows).Error
+	if err != nil {
+		return result
+	}
+
+	for _, row := range rows {
+		if _, exists := result[row.InvoiceID]; exists {
+			continue
+		}
+		if row.VaCreatedAt != nil {
+			row.CreatedAt = row.VaCreatedAt.Format("2006-01-02 15:04:05")
+		}
+		result[row.InvoiceID] = row
+	}
+	return result
+}
+
+func billStatus(bill models.StudentBill) string {
+	remaining := bill.Remaining()
+	if remaining <= 0 {
+		return "paid"
+	} else if bill.PaidAmount > 0 {
+		return "partial"
+	}
+	return "unpaid"
+}
@@ -0,0 +1,234 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/dedegunawan/backend-ujian-telp-v5/database"
+	"github.com/dedegunawan/backend-ujian-telp-v5/utils"
+)
+
+const (
+	HealthStatusOK          = "ok"
+	HealthStatusDegraded    = "degraded"    // dependency opsional bermasalah, tetap menerima traffic
+	HealthStatusUnavailable = "unavailable" // dependency wajib bermasalah atau sedang shutdown
+	HealthStatusSkipped     = "skipped"     // dependency tidak dikonfigurasi
+
+	defaultHealthCheckTimeout = 2 * time.Second
+	defaultHealthCacheTTL     = 10 * time.Second
+)
+
+// HealthCheck hasil satu dependency
+type HealthCheck struct {
+	Status    string    `json:"status"`
+	Critical  bool      `json:"critical"`
+	LatencyMs int64     `json:"latency_ms"`
+	Error     string    `json:"error,omitempty"`
+	CheckedAt time.Time `json:"checked_at"`
+}
+
+// HealthReport hasil /readyz
+type HealthReport struct {
+	Status    string                 `json:"status"`
+	Checks    map[string]HealthCheck `json:"checks"`
+	CheckedAt time.Time              `json:"checked_at"`
+}
+
+// Ready false jika ada dependency wajib yang gagal atau proses sedang shutdown
+func (r HealthReport) Ready() bool {
+	return r.Status != HealthStatusUnavailable
+}
+
+type HealthService interface {
+	Readiness(ctx context.Context) HealthReport
+}
+
+type dependencyCheck struct {
+	name     string
+	critical bool
+	// enabled false jika env dependency kosong
+	enabled bool
+	check   func(ctx context.Context) error
+}
+
+type healthService struct {
+	checks  []dependencyCheck
+	timeout time.Duration
+	ttl     time.Duration
+
+	mu     sync.Mutex
+	cached *HealthReport
+}
+
+var (
+	shuttingDown  atomic.Bool
+	defaultHealth HealthService
+	healthOnce    sync.Once
+)
+
+// MarkShuttingDown membuat /readyz mengembalikan 503 supaya load balancer berhenti mengirim request baru
+func MarkShuttingDown() {
+	shuttingDown.Store(true)
+}
+
+// DefaultHealthService dependency PNBP MySQL (wajib), OIDC (wajib), MinIO dan EPNBP (opsional).
+// HEALTH_CHECK_TIMEOUT dan HEALTH_CACHE_TTL mengatur timeout per check dan lama hasil di-cache.
+func DefaultHealthService() HealthService {
+	healthOnce.Do(func() {
+		defaultHealth = newHealthService(
+			durationEnv("HEALTH_CHECK_TIMEOUT", defaultHealthCheckTimeout),
+			durationEnv("HEALTH_CACHE_TTL", defaultHealthCacheTTL),
+			[]dependencyCheck{
+				{name: "database", critical: true, enabled: true, check: checkDatabase},
+				{name: "oidc", critical: true, enabled: os.Getenv("OIDC_ISSUER") != "", check: checkOIDC},
+				{name: "minio", enabled: os.Getenv("MINIO_ENDPOINT") != "", check: checkMinio},
+				{name: "epnbp", enabled: os.Getenv("EPNBP_URL") != "", check: checkEpnbp},
+			},
+		)
+	})
+	return defaultHealth
+}
+
+func newHealthService(timeout, ttl time.Duration, checks []dependencyCheck) *healthService {
+	return &healthService{checks: checks, timeout: timeout, ttl: ttl}
+}
+
+// Readiness menjalankan semua check secara paralel; hasil di-cache selama ttl agar probe tidak membebani dependency
+func (s *healthService) Readiness(ctx context.Context) HealthReport {
+	if shuttingDown.Load() {
+		return HealthReport{Status: HealthStatusUnavailable, Checks: map[string]HealthCheck{}, CheckedAt: time.Now()}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.cached != nil && time.Since(s.cached.CheckedAt) < s.ttl {
+		return *s.cached
+	}
+
+	results := make([]HealthCheck, len(s.checks))
+	var wg sync.WaitGroup
+	for i, dep := range s.checks {
+		if !dep.enabled {
+			results[i] = HealthCheck{Status: HealthStatusSkipped, Critical: dep.critical, CheckedAt: time.Now()}
+			continue
+		}
+		wg.Add(1)
+		go func(i int, dep dependencyCheck) {
+			defer wg.Done()
+			results[i] = s.run(ctx, dep)
+		}(i, dep)
+	}
+	wg.Wait()
+
+	report := HealthReport{Status: HealthStatusOK, Checks: make(map[string]HealthCheck, len(s.checks)), CheckedAt: time.Now()}
+	for i, dep := range s.checks {
+		result := results[i]
+		report.Checks[dep.name] = result
+		if result.Status != HealthStatusUnavailable {
+			continue
+		}
+		if dep.critical {
+			report.Status = HealthStatusUnavailable
+		} else if report.Status == HealthStatusOK {
+			report.Status = HealthStatusDegraded
+		}
+	}
+
+	s.cached = &report
+	return report
+}
+
+func (s *healthService) run(ctx context.Context, dep dependencyCheck) (result HealthCheck) {
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	started := time.Now()
+	defer func() {
+		if r := recover(); r != nil {
+			result = HealthCheck{Status: HealthStatusUnavailable, Critical: dep.critical, Error: fmt.Sprint(r), CheckedAt: time.Now()}
+		}
+	}()
+
+	err := dep.check(ctx)
+	result = HealthCheck{
+		Status:    HealthStatusOK,
+		Critical:  dep.critical,
+		LatencyMs: time.Since(started).Milliseconds(),
+		CheckedAt: time.Now(),
+	}
+	if err != nil {
+		result.Status = HealthStatusUnavailable
+		result.Error = err.Error()
+		utils.Log.Warnf("Health check %s gagal: %v", dep.name, err)
+	}
+	return result
+}
+
+func checkDatabase(ctx context.Context) error {
+	if database.DBPNBP == nil {
+		return fmt.Errorf("database belum terhubung")
+	}
+	sqlDB, err := database.DBPNBP.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.PingContext(ctx)
+}
+
+func checkOIDC(ctx context.Context) error {
+	issuer := strings.TrimSuffix(os.Getenv("OIDC_ISSUER"), "/")
+	return checkHTTP(ctx, issuer+"/.well-known/openid-configuration", true)
+}
+
+func checkMinio(ctx context.Context) error {
+	if utils.MinioClient == nil {
+		return fmt.Errorf("MinIO client belum diinisialisasi")
+	}
+	bucket := os.Getenv("MINIO_BUCKET")
+	exists, err := utils.MinioClient.BucketExists(ctx, bucket)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return fmt.Errorf("bucket %s tidak ditemukan", bucket)
+	}
+	return nil
+}
+
+// checkEpnbp cukup memastikan EPNBP bisa dijangkau; status < 500 dianggap hidup
+func checkEpnbp(ctx context.Context) error {
+	return checkHTTP(ctx, os.Getenv("EPNBP_URL"), false)
+}
+
+func checkHTTP(ctx context.Context, url string, requireOK bool) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 500 || (requireOK && resp.StatusCode != http.StatusOK) {
+		return fmt.Errorf("%s: status %d", url, resp.StatusCode)
+	}
+	return nil
+}
+
+func durationEnv(key string, def time.Duration) time.Duration {
+	if raw := os.Getenv(key); raw != "" {
+		if parsed, err := time.ParseDuration(raw); err == nil && parsed > 0 {
+			return parsed
+		}
+		utils.Log.Warnf("⚠️ %s tidak valid: %s, memakai %s", key, raw, def)
+	}
+	return def
+}
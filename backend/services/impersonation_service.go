@@ -0,0 +1,48 @@
+package services
+
+import (
+	"github.com/dedegunawan/backend-ujian-telp-v5/models"
+	"github.com/dedegunawan/backend-ujian-telp-v5/repositories"
+	"gorm.io/gorm"
+)
+
+// ImpersonationService izin dan audit mode "lihat sebagai mahasiswa" untuk staf helpdesk
+type ImpersonationService interface {
+	HasPermission(email, permission string) (bool, error)
+	Record(entry *models.ImpersonationLog) error
+	List(staffEmail, studentID string, limit int) ([]models.ImpersonationLog, error)
+}
+
+type impersonationService struct {
+	db *gorm.DB
+}
+
+func NewImpersonationService(db *gorm.DB) ImpersonationService {
+	return &impersonationService{db: db}
+}
+
+func (s *impersonationService) HasPermission(email, permission string) (bool, error) {
+	if email == "" {
+		return false, nil
+	}
+	userRepo := repositories.UserRepository{DB: s.db}
+	return userRepo.HasPermission(email, permission)
+}
+
+func (s *impersonationService) Record(entry *models.ImpersonationLog) error {
+	return s.db.Create(entry).Error
+}
+
+func (s *impersonationService) List(staffEmail, studentID string, limit int) ([]models.ImpersonationLog, error) {
+	query := s.db.Model(&models.ImpersonationLog{})
+	if staffEmail != "" {
+		query = query.Where("staff_email = ?", staffEmail)
+	}
+	if studentID != "" {
+		query = query.Where("student_id = ?", studentID)
+	}
+
+	var rows []models.ImpersonationLog
+	err := query.Order("id DESC").Limit(limit).Find(&rows).Error
+	return rows, err
+}
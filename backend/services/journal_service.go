@@ -0,0 +1,323 @@
+package services
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dedegunawan/backend-ujian-telp-v5/config"
+	"github.com/dedegunawan/backend-ujian-telp-v5/models"
+	"github.com/xuri/excelize/v2"
+	"gorm.io/gorm"
+)
+
+type JournalService interface {
+	Build(from, to time.Time) (*models.JournalBatch, error)
+	WriteCSV(batch *models.JournalBatch) (*bytes.Buffer, error)
+	WriteXLSX(batch *models.JournalBatch) (*bytes.Buffer, error)
+}
+
+type journalService struct {
+	db       *gorm.DB
+	accounts models.JournalAccountMap
+}
+
+func NewJournalService(db *gorm.DB) JournalService {
+	return &journalService{db: db, accounts: LoadJournalAccountMap()}
+}
+
+func journalAccountFromEnv(prefix string, defaultCode, defaultName string) models.JournalAccount {
+	account := models.JournalAccount{
+		Code: config.GetEnv(prefix + "_CODE"),
+		Name: config.GetEnv(prefix + "_NAME"),
+	}
+	if account.Code == "" {
+		account.Code = defaultCode
+	}
+	if account.Name == "" {
+		account.Name = defaultName
+	}
+	return account
+}
+
+// LoadJournalAccountMap membaca pemetaan akun dari env:
+//   - JOURNAL_ACCOUNT_BANK_CODE / _NAME
+//   - JOURNAL_ACCOUNT_REVENUE_CODE / _NAME (default semua revenue source)
+//   - JOURNAL_ACCOUNT_REVENUE_<REVENUE_SOURCE_ID>_CODE / _NAME (override per revenue source)
+//   - JOURNAL_ACCOUNT_SCHOLARSHIP_CODE / _NAME
+//   - JOURNAL_ACCOUNT_DEPOSIT_CODE / _NAME
+func LoadJournalAccountMap() models.JournalAccountMap {
+	accounts := models.JournalAccountMap{
+		Bank:                  journalAccountFromEnv("JOURNAL_ACCOUNT_BANK", "KAS_BANK", "Kas di Bank Penerimaan"),
+		Revenue:               journalAccountFromEnv("JOURNAL_ACCOUNT_REVENUE", "PENDAPATAN_PNBP", "Pendapatan PNBP Pendidikan"),
+		RevenueBySource:       make(map[string]models.JournalAccount),
+		ScholarshipReceivable: journalAccountFromEnv("JOURNAL_ACCOUNT_SCHOLARSHIP", "PIUTANG_BEASISWA", "Piutang Beasiswa"),
+		DepositLiability:      journalAccountFromEnv("JOURNAL_ACCOUNT_DEPOSIT", "TITIPAN_DEPOSIT", "Titipan Deposit Mahasiswa"),
+	}
+
+	if sourceID := os.Getenv("REVENUE_SOURCE_ID"); sourceID != "" {
+		prefix := "JOURNAL_ACCOUNT_REVENUE_" + strings.ToUpper(sourceID)
+		if code := config.GetEnv(prefix + "_CODE"); code != "" {
+			accounts.RevenueBySource[sourceID] = journalAccountFromEnv(prefix, code, accounts.Revenue.Name)
+		}
+	}
+	return accounts
+}
+
+type journalPaymentRow struct {
+	PaymentID      uint      `gorm:"column:payment_id"`
+	InvoiceID      uint      `gorm:"column:invoice_id"`
+	Amount         int64     `gorm:"column:amount"`
+	PaidAt         time.Time `gorm:"column:paid_at"`
+	NPM            string    `gorm:"column:npm"`
+	TahunID        string    `gorm:"column:tahun_id"`
+	VirtualAccount string    `gorm:"column:virtual_account"`
+}
+
+type journalScholarshipRow struct {
+	DetailID  uint      `gorm:"column:detail_id"`
+	NoSK      string    `gorm:"column:no_sk"`
+	TanggalSK time.Time `gorm:"column:tanggal_sk"`
+	NPM       string    `gorm:"column:npm"`
+	TahunID   string    `gorm:"column:tahun_id"`
+	Amount    int64     `gorm:"column:amount"`
+}
+
+// latestVirtualAccountJoin VA terbaru dari invoice pembayaran; VA terhubung ke invoice lewat
+// virtual_accounts.invoice_id dan satu invoice bisa punya beberapa VA, jadi hanya satu yang diambil
+// supaya baris pembayaran tidak terduplikasi
+const latestVirtualAccountJoin = `LEFT JOIN virtual_accounts ON virtual_accounts.id = (
+	SELECT va.id FROM virtual_accounts va WHERE va.invoice_id = payments.invoice_id
+	ORDER BY va.created_at DESC, va.id DESC LIMIT 1)`
+
+// Build menyusun jurnal untuk transaksi pada rentang [from, to)
+//   - Pembayaran invoice Paid: Debit Kas/Bank, Kredit Pendapatan PNBP
+//   - Beasiswa aktif (berdasarkan tanggal SK): Debit Piutang Beasiswa, Kredit Pendapatan PNBP
+//   - Deposit masuk (credit): Debit Kas/Bank, Kredit Titipan Deposit
+//   - Deposit dipakai untuk UKT (debit): Debit Titipan Deposit, Kredit Pendapatan PNBP
+func (s *journalService) Build(from, to time.Time) (*models.JournalBatch, error) {
+	revenue := s.accounts.RevenueAccount(os.Getenv("REVENUE_SOURCE_ID"))
+	var entries []models.JournalEntry
+
+	var payments []journalPaymentRow
+	err := s.db.
+		Table("payments").
+		Select(`payments.id AS payment_id, invoices.id AS invoice_id,
+			CAST(payments.amount AS SIGNED) AS amount, payments.created_at AS paid_at,
+			customers.identifier AS npm, COALESCE(budget_periods.kode, '') AS tahun_id,
+			COALESCE(virtual_accounts.virtual_account, '') AS virtual_account`).
+		Joins("INNER JOIN invoices ON invoices.id = payments.invoice_id").
+		Joins("INNER JOIN customers ON customers.id = invoices.customer_id").
+		Joins("LEFT JOIN budget_periods ON budget_periods.id = invoices.budget_period_id").
+		Joins(latestVirtualAccountJoin).
+		Where("invoices.status = ? AND payments.created_at >= ? AND payments.created_at < ?", "Paid", from, to).
+		Order("payments.created_at ASC").
+		Scan(&payments).Error
+	if err != nil {
+		return nil, fmt.Errorf("gagal mengambil pembayaran: %w", err)
+	}
+
+	for _, p := range payments {
+		reference := fmt.Sprintf("INV-%d", p.InvoiceID)
+		if p.VirtualAccount != "" {
+			reference += "/VA-" + p.VirtualAccount
+		}
+		description := fmt.Sprintf("Penerimaan UKT %s %s", p.NPM, p.TahunID)
+		entries = append(entries, s.entry(models.JournalSourcePayment, p.PaidAt, p.NPM, p.TahunID, reference, description,
+			s.accounts.Bank, revenue, p.Amount))
+	}
+
+	var scholarships []journalScholarshipRow
+	err = s.db.
+		Table("detail_beasiswa").
+		Select(`detail_beasiswa.id AS detail_id, beasiswa.no_sk, beasiswa.tanggal_sk,
+			detail_beasiswa.npm, detail_beasiswa.tahun_id,
+			CAST(detail_beasiswa.nominal_beasiswa AS SIGNED) AS amount`).
+		Joins("INNER JOIN beasiswa ON beasiswa.id = detail_beasiswa.beasiswa_id").
+		Where("beasiswa.status = ? AND beasiswa.tanggal_sk >= ? AND beasiswa.tanggal_sk < ?", "active", from, to).
+		Order("beasiswa.tanggal_sk ASC, detail_beasiswa.id ASC").
+		Scan(&scholarships).Error
+	if err != nil {
+		return nil, fmt.Errorf("gagal mengambil beasiswa: %w", err)
+	}
+
+	for _, b := range scholarships {
+		description := fmt.Sprintf("Beasiswa %s %s (SK %s)", b.NPM, b.TahunID, b.NoSK)
+		entries = append(entries, s.entry(models.JournalSourceScholarship, b.TanggalSK, b.NPM, b.TahunID, "SK-"+b.NoSK, description,
+			s.accounts.ScholarshipReceivable, revenue, b.Amount))
+	}
+
+	var deposits []models.DepositLedgerEntry
+	err = models.DepositLedgerEntry{}.ScopePosted(s.db).
+		Where("posted_at >= ? AND posted_at < ?", from, to).
+		Order("posted_at ASC, id ASC").
+		Find(&deposits).Error
+	if err != nil {
+		return nil, fmt.Errorf("gagal mengambil deposit: %w", err)
+	}
+
+	for _, d := range deposits {
+		reference := d.ReferenceNo
+		if reference == "" {
+			reference = fmt.Sprintf("DEP-%d", d.ID)
+		}
+		if d.Direction == models.DirCredit {
+			description := fmt.Sprintf("Deposit masuk %s %s", d.NPM, d.TahunID)
+			entries = append(entries, s.entry(models.JournalSourceDepositIn, *d.PostedAt, d.NPM, d.TahunID, reference, description,
+				s.accounts.Bank, s.accounts.DepositLiability, d.Amount))
+		} else {
+			description := fmt.Sprintf("Deposit dipakai UKT %s %s", d.NPM, d.TahunID)
+			entries = append(entries, s.entry(models.JournalSourceDepositApplied, *d.PostedAt, d.NPM, d.TahunID, reference, description,
+				s.accounts.DepositLiability, revenue, d.Amount))
+		}
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].Date.Before(entries[j].Date)
+	})
+
+	batch := &models.JournalBatch{
+		BatchNo: fmt.Sprintf("JB-%s-%s", from.Format("20060102"), to.AddDate(0, 0, -1).Format("20060102")),
+		From:    from.Format("2006-01-02"),
+		To:      to.AddDate(0, 0, -1).Format("2006-01-02"),
+		Entries: []models.JournalEntry{},
+	}
+
+	daily := make(map[string]int)
+	for _, entry := range entries {
+		day := entry.Date.Format("20060102")
+		daily[day]++
+		entry.EntryNo = fmt.Sprintf("JU-%s-%04d", day, daily[day])
+		for i := range entry.Lines {
+			entry.Lines[i].EntryNo = entry.EntryNo
+			batch.TotalDebit += entry.Lines[i].Debit
+			batch.TotalCredit += entry.Lines[i].Credit
+		}
+		if !entry.IsBalanced() {
+			batch.Errors = append(batch.Errors, fmt.Sprintf("%s tidak seimbang atau bernilai nol", entry.EntryNo))
+		}
+		for _, line := range entry.Lines {
+			if line.AccountCode == "" {
+				batch.Errors = append(batch.Errors, fmt.Sprintf("%s memakai akun tanpa kode", entry.EntryNo))
+				break
+			}
+		}
+		batch.Entries = append(batch.Entries, entry)
+	}
+
+	if batch.TotalDebit != batch.TotalCredit {
+		batch.Errors = append(batch.Errors, fmt.Sprintf("Total debit %d tidak sama dengan total kredit %d", batch.TotalDebit, batch.TotalCredit))
+	}
+	batch.Balanced = len(batch.Errors) == 0
+
+	return batch, nil
+}
+
+func (s *journalService) entry(source string, date time.Time, npm, tahunID, reference, description string, debit, credit models.JournalAccount, amount int64) models.JournalEntry {
+	line := models.JournalLine{
+		Date:        date,
+		Description: description,
+		Reference:   reference,
+		NPM:         npm,
+		TahunID:     tahunID,
+		Source:      source,
+	}
+
+	debitLine := line
+	debitLine.AccountCode = debit.Code
+	debitLine.AccountName = debit.Name
+	debitLine.Debit = amount
+
+	creditLine := line
+	creditLine.AccountCode = credit.Code
+	creditLine.AccountName = credit.Name
+	creditLine.Credit = amount
+
+	return models.JournalEntry{
+		Date:   date,
+		Source: source,
+		Lines:  []models.JournalLine{debitLine, creditLine},
+	}
+}
+
+var journalHeaders = []string{"No Batch", "No Jurnal", "Tanggal", "Kode Akun", "Nama Akun", "Debit", "Kredit", "Uraian", "Referensi", "NPM", "Tahun ID", "Sumber"}
+
+func journalRow(batch *models.JournalBatch, line models.JournalLine) []string {
+	return []string{
+		batch.BatchNo,
+		line.EntryNo,
+		line.Date.Format("2006-01-02"),
+		line.AccountCode,
+		line.AccountName,
+		strconv.FormatInt(line.Debit, 10),
+		strconv.FormatInt(line.Credit, 10),
+		line.Description,
+		line.Reference,
+		line.NPM,
+		line.TahunID,
+		line.Source,
+	}
+}
+
+// WriteCSV format impor: satu baris per debit/kredit
+func (s *journalService) WriteCSV(batch *models.JournalBatch) (*bytes.Buffer, error) {
+	var buffer bytes.Buffer
+	writer := csv.NewWriter(&buffer)
+	if err := writer.Write(journalHeaders); err != nil {
+		return nil, err
+	}
+	for _, entry := range batch.Entries {
+		for _, line := range entry.Lines {
+			if err := writer.Write(journalRow(batch, line)); err != nil {
+				return nil, err
+			}
+		}
+	}
+	writer.Flush()
+	return &buffer, writer.Error()
+}
+
+// WriteXLSX kolom sama dengan CSV, nominal sebagai angka
+func (s *journalService) WriteXLSX(batch *models.JournalBatch) (*bytes.Buffer, error) {
+	excel := excelize.NewFile()
+	defer excel.Close()
+
+	sheet := "Jurnal"
+	excel.SetSheetName("Sheet1", sheet)
+	for i, h := range journalHeaders {
+		cell, _ := excelize.CoordinatesToCellName(i+1, 1)
+		excel.SetCellValue(sheet, cell, h)
+	}
+
+	row := 2
+	for _, entry := range batch.Entries {
+		for _, line := range entry.Lines {
+			values := []interface{}{
+				batch.BatchNo, line.EntryNo, line.Date.Format("2006-01-02"), line.AccountCode, line.AccountName,
+				line.Debit, line.Credit, line.Description, line.Reference, line.NPM, line.TahunID, line.Source,
+			}
+			for i, v := range values {
+				cell, _ := excelize.CoordinatesToCellName(i+1, row)
+				excel.SetCellValue(sheet, cell, v)
+			}
+			row++
+		}
+	}
+
+	totalValues := []interface{}{"TOTAL", "", "", "", "", batch.TotalDebit, batch.TotalCredit}
+	for i, v := range totalValues {
+		cell, _ := excelize.CoordinatesToCellName(i+1, row)
+		excel.SetCellValue(sheet, cell, v)
+	}
+
+	var buffer bytes.Buffer
+	if err := excel.Write(&buffer); err != nil {
+		return nil, err
+	}
+	return &buffer, nil
+}
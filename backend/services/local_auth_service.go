@@ -0,0 +1,610 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dedegunawan/backend-ujian-telp-v5/models"
+	"github.com/dedegunawan/backend-ujian-telp-v5/notification"
+	"github.com/dedegunawan/backend-ujian-telp-v5/repositories"
+	"github.com/dedegunawan/backend-ujian-telp-v5/utils"
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	defaultLockoutThreshold = 5
+	defaultLockoutBase      = time.Minute
+	maxLockoutDuration      = time.Hour
+	mfaChallengeTTL         = 5 * time.Minute
+	defaultPasswordResetTTL = 30 * time.Minute
+	minPasswordLength       = 10
+	recoveryCodeCount       = 10
+
+	signedPurposeMFA           = "login-mfa"
+	signedPurposePasswordReset = "password-reset"
+)
+
+var (
+	ErrInvalidCredentials   = errors.New("email atau password salah")
+	ErrAccountInactive      = errors.New("akun tidak aktif")
+	ErrMFAInvalid           = errors.New("kode verifikasi tidak valid")
+	ErrMFAChallengeInvalid  = errors.New("sesi verifikasi 2FA tidak valid atau kedaluwarsa, silakan login ulang")
+	ErrTOTPAlreadyEnabled   = errors.New("2FA sudah aktif")
+	ErrTOTPNotEnabled       = errors.New("2FA belum aktif")
+	ErrTOTPSetupRequired    = errors.New("mulai pendaftaran 2FA terlebih dahulu")
+	ErrLocalAccountRequired = errors.New("akun tidak memakai login password (gunakan SSO)")
+	ErrPasswordResetInvalid = errors.New("link reset password tidak valid atau sudah kedaluwarsa")
+	ErrPasswordTooWeak      = fmt.Errorf("password minimal %d karakter dan tidak boleh sama dengan email", minPasswordLength)
+)
+
+// AccountLockedError akun dikunci sementara karena terlalu banyak gagal login
+type AccountLockedError struct {
+	Until time.Time
+}
+
+func (e *AccountLockedError) Error() string {
+	return "akun dikunci sementara karena terlalu banyak percobaan login gagal"
+}
+
+// RetryAfter detik sampai kunci dibuka (untuk header Retry-After)
+func (e *AccountLockedError) RetryAfter() int {
+	seconds := int(time.Until(e.Until).Seconds()) + 1
+	if seconds < 1 {
+		return 1
+	}
+	return seconds
+}
+
+// LocalLoginResult hasil langkah password. Jika MFARequired, token baru diterbitkan
+// setelah VerifySecondFactor dengan MFAToken ini.
+type LocalLoginResult struct {
+	User        *models.User
+	MFARequired bool
+	MFAToken    string
+}
+
+// TOTPEnrollment secret baru yang harus dikonfirmasi dengan satu kode dari aplikasi authenticator
+type TOTPEnrollment struct {
+	Secret     string `json:"secret"`
+	OTPAuthURL string `json:"otpauth_url"`
+}
+
+// LocalAuthService login email/password untuk akun staf non-SSO (bagian keuangan, auditor eksternal)
+type LocalAuthService interface {
+	Login(email, password string) (*LocalLoginResult, error)
+	VerifySecondFactor(mfaToken, code, recoveryCode string) (*models.User, error)
+	BeginTOTPEnrollment(user *models.User) (*TOTPEnrollment, error)
+	EnableTOTP(user *models.User, code string) ([]string, error)
+	DisableTOTP(user *models.User, password, code string) error
+	RegenerateRecoveryCodes(user *models.User, code string) ([]string, error)
+	TOTPStatus(user *models.User) (bool, int64, error)
+	RequestPasswordReset(email string) error
+	ResetPassword(token, newPassword string) error
+}
+
+type localAuthService struct {
+	db       *gorm.DB
+	userRepo *repositories.UserRepository
+}
+
+func NewLocalAuthService(db *gorm.DB) LocalAuthService {
+	return &localAuthService{db: db, userRepo: &repositories.UserRepository{DB: db}}
+}
+
+// Hash pembanding untuk email yang tidak terdaftar agar waktu respon sama (tidak bocor email mana yang ada)
+var dummyPasswordHash, _ = bcrypt.GenerateFromPassword([]byte("dummy-password-for-timing"), bcrypt.DefaultCost)
+
+func lockoutThreshold() int {
+	if raw := os.Getenv("LOGIN_LOCKOUT_THRESHOLD"); raw != "" {
+		if parsed, err := strconv.Atoi(raw); err == nil && parsed > 0 {
+			return parsed
+		}
+	}
+	return defaultLockoutThreshold
+}
+
+func lockoutBase() time.Duration {
+	if raw := os.Getenv("LOGIN_LOCKOUT_BASE"); raw != "" {
+		if parsed, err := time.ParseDuration(raw); err == nil && parsed > 0 {
+			return parsed
+		}
+	}
+	return defaultLockoutBase
+}
+
+// lockoutDuration bertingkat: gagal ke-threshold dikunci base, setiap gagal berikutnya dua kali lipat (maks 1 jam)
+func lockoutDuration(failedAttempts int) time.Duration {
+	over := failedAttempts - lockoutThreshold()
+	if over < 0 {
+		return 0
+	}
+	duration := lockoutBase()
+	for i := 0; i < over && duration < maxLockoutDuration; i++ {
+		duration *= 2
+	}
+	if duration > maxLockoutDuration {
+		duration = maxLockoutDuration
+	}
+	return duration
+}
+
+func (s *localAuthService) Login(email, password string) (*LocalLoginResult, error) {
+	email = strings.ToLower(strings.TrimSpace(email))
+	user, err := s.userRepo.FindByEmail(email)
+	if err != nil || user.ID == uuid.Nil || user.Password == nil || *user.Password == "" {
+		bcrypt.CompareHashAndPassword(dummyPasswordHash, []byte(password))
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, err
+		}
+		return nil, ErrInvalidCredentials
+	}
+
+	security, err := s.loadSecurity(user.ID)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	if security.Locked(now) {
+		return nil, &AccountLockedError{Until: *security.LockedUntil}
+	}
+
+	if bcrypt.CompareHashAndPassword([]byte(*user.Password), []byte(password)) != nil {
+		return nil, s.registerFailure(user, security)
+	}
+	// Akun nonaktif baru diberi tahu setelah password benar
+	if !user.IsActive {
+		return nil, ErrAccountInactive
+	}
+
+	if security.TOTPEnabled() {
+		mfaToken, err := utils.SignToken(signedPurposeMFA, mfaChallenge{UserID: user.ID.String()}, now.Add(mfaChallengeTTL))
+		if err != nil {
+			return nil, err
+		}
+		return &LocalLoginResult{User: user, MFARequired: true, MFAToken: mfaToken}, nil
+	}
+
+	s.registerSuccess(security)
+	return &LocalLoginResult{User: user}, nil
+}
+
+type mfaChallenge struct {
+	UserID string `json:"uid"`
+}
+
+// VerifySecondFactor langkah kedua login: kode TOTP atau salah satu recovery code.
+// Kode salah dihitung ke penguncian yang sama dengan password salah.
+func (s *localAuthService) VerifySecondFactor(mfaToken, code, recoveryCode string) (*models.User, error) {
+	var challenge mfaChallenge
+	if err := utils.VerifySignedToken(signedPurposeMFA, mfaToken, &challenge); err != nil {
+		return nil, ErrMFAChallengeInvalid
+	}
+	user, err := s.userRepo.FindByID(challenge.UserID)
+	if err != nil || !user.IsActive {
+		return nil, ErrMFAChallengeInvalid
+	}
+
+	security, err := s.loadSecurity(user.ID)
+	if err != nil {
+		return nil, err
+	}
+	if security.Locked(time.Now()) {
+		return nil, &AccountLockedError{Until: *security.LockedUntil}
+	}
+	if !security.TOTPEnabled() {
+		return nil, ErrMFAChallengeInvalid
+	}
+
+	var ok bool
+	if recoveryCode != "" {
+		ok, err = s.consumeRecoveryCode(user.ID, recoveryCode)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			utils.Log.Warnw("Login memakai recovery code 2FA", "user_id", user.ID.String())
+		}
+	} else {
+		ok, err = s.checkTOTP(security, security.TOTPSecret, code)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if !ok {
+		if lockErr := s.registerFailure(user, security); !errors.Is(lockErr, ErrInvalidCredentials) {
+			return nil, lockErr
+		}
+		return nil, ErrMFAInvalid
+	}
+
+	s.registerSuccess(security)
+	return user, nil
+}
+
+func (s *localAuthService) BeginTOTPEnrollment(user *models.User) (*TOTPEnrollment, error) {
+	if user.Password == nil || *user.Password == "" {
+		return nil, ErrLocalAccountRequired
+	}
+	security, err := s.loadSecurity(user.ID)
+	if err != nil {
+		return nil, err
+	}
+	if security.TOTPEnabled() {
+		return nil, ErrTOTPAlreadyEnabled
+	}
+
+	secret, err := utils.GenerateTOTPSecret()
+	if err != nil {
+		return nil, err
+	}
+	encrypted, err := utils.EncryptString(secret)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.db.Model(security).Update("totp_pending_secret", encrypted).Error; err != nil {
+		return nil, err
+	}
+
+	issuer := os.Getenv("TOTP_ISSUER")
+	if issuer == "" {
+		issuer = "ePNBP"
+	}
+	return &TOTPEnrollment{Secret: secret, OTPAuthURL: utils.TOTPURL(issuer, user.Email, secret)}, nil
+}
+
+// EnableTOTP mengaktifkan 2FA setelah kode pertama benar dan mengembalikan recovery code (hanya ditampilkan sekali)
+func (s *localAuthService) EnableTOTP(user *models.User, code string) ([]string, error) {
+	security, err := s.loadSecurity(user.ID)
+	if err != nil {
+		return nil, err
+	}
+	if security.TOTPEnabled() {
+		return nil, ErrTOTPAlreadyEnabled
+	}
+	if security.TOTPPendingSecret == "" {
+		return nil, ErrTOTPSetupRequired
+	}
+	ok, err := s.checkTOTP(security, security.TOTPPendingSecret, code)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrMFAInvalid
+	}
+
+	now := time.Now()
+	var codes []string
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(security).Updates(map[string]interface{}{
+			"totp_secret":         security.TOTPPendingSecret,
+			"totp_pending_secret": "",
+			"totp_enabled_at":     now,
+		}).Error; err != nil {
+			return err
+		}
+		codes, err = replaceRecoveryCodes(tx, user.ID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	utils.Log.Infow("2FA diaktifkan", "user_id", user.ID.String())
+	return codes, nil
+}
+
+// DisableTOTP butuh password dan kode TOTP saat ini (token curian saja tidak cukup)
+func (s *localAuthService) DisableTOTP(user *models.User, password, code string) error {
+	if user.Password == nil || bcrypt.CompareHashAndPassword([]byte(*user.Password), []byte(password)) != nil {
+		return ErrInvalidCredentials
+	}
+	security, err := s.loadSecurity(user.ID)
+	if err != nil {
+		return err
+	}
+	if !security.TOTPEnabled() {
+		return ErrTOTPNotEnabled
+	}
+	ok, err := s.checkTOTP(security, security.TOTPSecret, code)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrMFAInvalid
+	}
+
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(security).Updates(map[string]interface{}{
+			"totp_secret":         "",
+			"totp_pending_secret": "",
+			"totp_enabled_at":     nil,
+		}).Error; err != nil {
+			return err
+		}
+		return tx.Where("user_id = ?", user.ID).Delete(&models.UserRecoveryCode{}).Error
+	})
+	if err == nil {
+		utils.Log.Warnw("2FA dinonaktifkan", "user_id", user.ID.String())
+	}
+	return err
+}
+
+func (s *localAuthService) RegenerateRecoveryCodes(user *models.User, code string) ([]string, error) {
+	security, err := s.loadSecurity(user.ID)
+	if err != nil {
+		return nil, err
+	}
+	if !security.TOTPEnabled() {
+		return nil, ErrTOTPNotEnabled
+	}
+	ok, err := s.checkTOTP(security, security.TOTPSecret, code)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrMFAInvalid
+	}
+
+	var codes []string
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		codes, err = replaceRecoveryCodes(tx, user.ID)
+		return err
+	})
+	return codes, err
+}
+
+// TOTPStatus apakah 2FA aktif dan sisa recovery code yang belum dipakai
+func (s *localAuthService) TOTPStatus(user *models.User) (bool, int64, error) {
+	security, err := s.loadSecurity(user.ID)
+	if err != nil {
+		return false, 0, err
+	}
+	var remaining int64
+	err = s.db.Model(&models.UserRecoveryCode{}).Where("user_id = ? AND used_at IS NULL", user.ID).Count(&remaining).Error
+	return security.TOTPEnabled(), remaining, err
+}
+
+type passwordResetClaims struct {
+	UserID string `json:"uid"`
+	// Sidik password saat link dibuat: setelah password diganti, link otomatis tidak berlaku (sekali pakai)
+	PasswordFingerprint string `json:"pf"`
+}
+
+// RequestPasswordReset mengirim link reset bertanda tangan ke email. Selalu sukses untuk email tak dikenal
+// agar endpoint tidak bisa dipakai menebak akun.
+func (s *localAuthService) RequestPasswordReset(email string) error {
+	email = strings.ToLower(strings.TrimSpace(email))
+	user, err := s.userRepo.FindByEmail(email)
+	if err != nil || user.ID == uuid.Nil || !user.IsActive || user.Password == nil || *user.Password == "" {
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+		return nil
+	}
+
+	ttl := defaultPasswordResetTTL
+	if raw := os.Getenv("PASSWORD_RESET_TTL"); raw != "" {
+		if parsed, err := time.ParseDuration(raw); err == nil && parsed > 0 {
+			ttl = parsed
+		}
+	}
+	token, err := utils.SignToken(signedPurposePasswordReset, passwordResetClaims{
+		UserID:              user.ID.String(),
+		PasswordFingerprint: passwordFingerprint(*user.Password),
+	}, time.Now().Add(ttl))
+	if err != nil {
+		return err
+	}
+
+	resetURL, err := passwordResetURL(token)
+	if err != nil {
+		return err
+	}
+
+	channel, ok := notification.Default().Get(notification.ChannelEmail)
+	if !ok {
+		return errors.New("channel email belum dikonfigurasi (SMTP_HOST)")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	_, err = channel.Send(ctx, notification.Message{
+		To:      user.Email,
+		Subject: "Reset password akun ePNBP",
+		Body: "Yth. " + user.Name + ",\n\n" +
+			"Kami menerima permintaan reset password untuk akun Anda. Buka link berikut untuk membuat password baru " +
+			"(berlaku " + ttl.String() + " dan hanya bisa dipakai sekali):\n\n" + resetURL + "\n\n" +
+			"Abaikan email ini jika Anda tidak meminta reset password.",
+	})
+	return err
+}
+
+// ResetPassword mengganti password, membuka kunci dan mencabut semua sesi yang masih berjalan
+func (s *localAuthService) ResetPassword(token, newPassword string) error {
+	var claims passwordResetClaims
+	if err := utils.VerifySignedToken(signedPurposePasswordReset, token, &claims); err != nil {
+		return ErrPasswordResetInvalid
+	}
+	user, err := s.userRepo.FindByID(claims.UserID)
+	if err != nil || !user.IsActive || user.Password == nil ||
+		passwordFingerprint(*user.Password) != claims.PasswordFingerprint {
+		return ErrPasswordResetInvalid
+	}
+	if len(newPassword) < minPasswordLength || strings.EqualFold(newPassword, user.Email) {
+		return ErrPasswordTooWeak
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(newPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+	security, err := s.loadSecurity(user.ID)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.User{}).Where("id = ?", user.ID).Update("password", string(hash)).Error; err != nil {
+			return err
+		}
+		return tx.Model(security).Updates(map[string]interface{}{
+			"failed_attempts":     0,
+			"locked_until":        nil,
+			"password_changed_at": now,
+		}).Error
+	})
+	if err != nil {
+		return err
+	}
+
+	if _, err := NewUserTokenService(s.db, nil).RevokeUser(user.ID, user.Email, models.RevokeReasonAdmin); err != nil {
+		utils.Log.Errorw("Gagal mencabut sesi setelah reset password",
+			"user_id", user.ID.String(),
+			"error", err,
+		)
+	}
+	utils.Log.Infow("Password direset", "user_id", user.ID.String())
+	return nil
+}
+
+func passwordFingerprint(passwordHash string) string {
+	sum := sha256.Sum256([]byte(passwordHash))
+	return hex.EncodeToString(sum[:8])
+}
+
+// passwordResetURL PASSWORD_RESET_URL (halaman frontend) + ?token=, default FRONTEND_URL/reset-password
+func passwordResetURL(token string) (string, error) {
+	base := os.Getenv("PASSWORD_RESET_URL")
+	if base == "" {
+		base = strings.TrimRight(os.Getenv("FRONTEND_URL"), "/") + "/reset-password"
+	}
+	parsed, err := url.Parse(base)
+	if err != nil {
+		return "", err
+	}
+	query := parsed.Query()
+	query.Set("token", token)
+	parsed.RawQuery = query.Encode()
+	return parsed.String(), nil
+}
+
+// loadSecurity baris user_login_securities, dibuat jika belum ada
+func (s *localAuthService) loadSecurity(userID uuid.UUID) (*models.UserLoginSecurity, error) {
+	security := models.UserLoginSecurity{UserID: userID}
+	if err := s.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&security).Error; err != nil {
+		return nil, err
+	}
+	if err := s.db.First(&security, "user_id = ?", userID).Error; err != nil {
+		return nil, err
+	}
+	return &security, nil
+}
+
+// registerFailure menambah hitungan gagal secara atomik lalu mengunci jika melewati ambang
+func (s *localAuthService) registerFailure(user *models.User, security *models.UserLoginSecurity) error {
+	now := time.Now()
+	err := s.db.Model(security).Updates(map[string]interface{}{
+		"failed_attempts": gorm.Expr("failed_attempts + 1"),
+		"last_failed_at":  now,
+	}).Error
+	if err != nil {
+		return err
+	}
+	if err := s.db.First(security, "user_id = ?", user.ID).Error; err != nil {
+		return err
+	}
+
+	if duration := lockoutDuration(security.FailedAttempts); duration > 0 {
+		lockedUntil := now.Add(duration)
+		s.db.Model(security).Update("locked_until", lockedUntil)
+		utils.Log.Warnw("Akun dikunci karena gagal login berulang",
+			"user_id", user.ID.String(),
+			"failed_attempts", security.FailedAttempts,
+			"locked_until", lockedUntil,
+		)
+		return &AccountLockedError{Until: lockedUntil}
+	}
+	return ErrInvalidCredentials
+}
+
+func (s *localAuthService) registerSuccess(security *models.UserLoginSecurity) {
+	s.db.Model(security).Updates(map[string]interface{}{
+		"failed_attempts": 0,
+		"locked_until":    nil,
+		"last_login_at":   time.Now(),
+	})
+}
+
+// checkTOTP validasi kode terhadap secret terenkripsi dan menolak step yang sudah pernah dipakai
+func (s *localAuthService) checkTOTP(security *models.UserLoginSecurity, encryptedSecret, code string) (bool, error) {
+	secret, err := utils.DecryptString(encryptedSecret)
+	if err != nil {
+		return false, err
+	}
+	step, ok := utils.ValidateTOTP(secret, code, time.Now(), 1)
+	if !ok || step <= security.TOTPLastStep {
+		return false, nil
+	}
+	// Update bersyarat: dua request paralel dengan kode yang sama hanya satu yang lolos
+	result := s.db.Model(&models.UserLoginSecurity{}).
+		Where("user_id = ? AND totp_last_step < ?", security.UserID, step).
+		Update("totp_last_step", step)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	security.TOTPLastStep = step
+	return result.RowsAffected == 1, nil
+}
+
+func (s *localAuthService) consumeRecoveryCode(userID uuid.UUID, code string) (bool, error) {
+	normalized := normalizeRecoveryCode(code)
+	if normalized == "" {
+		return false, nil
+	}
+	result := s.db.Model(&models.UserRecoveryCode{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userID, utils.HashToken(normalized)).
+		Update("used_at", time.Now())
+	return result.RowsAffected == 1, result.Error
+}
+
+// replaceRecoveryCodes menghapus kode lama dan membuat set baru; format xxxxx-xxxxx
+func replaceRecoveryCodes(tx *gorm.DB, userID uuid.UUID) ([]string, error) {
+	if err := tx.Where("user_id = ?", userID).Delete(&models.UserRecoveryCode{}).Error; err != nil {
+		return nil, err
+	}
+	codes := make([]string, 0, recoveryCodeCount)
+	rows := make([]models.UserRecoveryCode, 0, recoveryCodeCount)
+	now := time.Now()
+	for i := 0; i < recoveryCodeCount; i++ {
+		raw, err := utils.RandomToken(5)
+		if err != nil {
+			return nil, err
+		}
+		code := raw[:5] + "-" + raw[5:]
+		codes = append(codes, code)
+		rows = append(rows, models.UserRecoveryCode{
+			UserID:    userID,
+			CodeHash:  utils.HashToken(normalizeRecoveryCode(code)),
+			CreatedAt: now,
+		})
+	}
+	if err := tx.Create(&rows).Error; err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+func normalizeRecoveryCode(code string) string {
+	return strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(strings.TrimSpace(code)))
+}
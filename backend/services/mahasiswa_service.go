@@ -58,42 +58,40 @@ func (s *mahasiswaService) Delete(id uuid.UUID) error {
 func (s *mahasiswaService) CreateFromMasterMahasiswa(mhswID string) error {
 	// Selalu sinkronkan dari mahasiswa_masters, tidak peduli apakah sudah ada atau belum
 	// Ini memastikan data selalu up-to-date
-	utils.Log.Info("Memulai CreateFromMasterMahasiswa", map[string]interface{}{
-		"mhswID": mhswID,
-	})
+	utils.Log.Infow("Memulai CreateFromMasterMahasiswa", "mhswID", mhswID)
 
 	// 1. Ambil data mahasiswa dari Master Mahasiswa
 	var mhswMaster models.MahasiswaMaster
 	err := database.DBPNBP.Preload("MasterTagihan").Where("student_id=?", mhswID).First(&mhswMaster).Error
 	if err != nil {
 		// Jika record tidak ditemukan, return error dengan detail
-		utils.Log.Error("Mahasiswa tidak ditemukan di mahasiswa_masters", map[string]interface{}{
-			"mhswID": mhswID,
-			"error":  err.Error(),
-		})
+		utils.Log.Errorw("Mahasiswa tidak ditemukan di mahasiswa_masters",
+			"mhswID", mhswID,
+			"error", err,
+		)
 		return fmt.Errorf("mahasiswa tidak ditemukan di mahasiswa_masters: %w", err)
 	}
 
 	// Pastikan data valid
 	if mhswMaster.ID == 0 {
-		utils.Log.Error("Mahasiswa master ID = 0", "mhswID", mhswID)
+		utils.Log.Errorw("Mahasiswa master ID = 0", "mhswID", mhswID)
 		return fmt.Errorf("mahasiswa tidak ditemukan di mahasiswa_masters (ID=0)")
 	}
 
-	utils.Log.Info("Mahasiswa master ditemukan", map[string]interface{}{
-		"mhswID":          mhswID,
-		"masterID":        mhswMaster.ID,
-		"namaLengkap":     mhswMaster.NamaLengkap,
-		"prodiID":         mhswMaster.ProdiID,
-		"masterTagihanID": mhswMaster.MasterTagihanID,
-	})
+	utils.Log.Infow("Mahasiswa master ditemukan",
+		"mhswID", mhswID,
+		"masterID", mhswMaster.ID,
+		"namaLengkap", mhswMaster.NamaLengkap,
+		"prodiID", mhswMaster.ProdiID,
+		"masterTagihanID", mhswMaster.MasterTagihanID,
+	)
 
 	prodi_id := mhswMaster.ProdiID
 	if prodi_id == 0 {
-		utils.Log.Error("ProdiID = 0 di mahasiswa_masters", map[string]interface{}{
-			"mhswID":   mhswID,
-			"masterID": mhswMaster.ID,
-		})
+		utils.Log.Errorw("ProdiID = 0 di mahasiswa_masters",
+			"mhswID", mhswID,
+			"masterID", mhswMaster.ID,
+		)
 		return fmt.Errorf("gagal ambil data mahasiswa master: prodi_id tidak ditemukan")
 	}
 
@@ -102,37 +100,37 @@ func (s *mahasiswaService) CreateFromMasterMahasiswa(mhswID string) error {
 	var prodiData models.ProdiPnbp
 	err = database.DBPNBP.Where("id=?", prodi_id).First(&prodiData).Error
 	if err != nil {
-		utils.Log.Error("Gagal query prodi dari database PNBP", map[string]interface{}{
-			"mhswID":  mhswID,
-			"prodiID": prodi_id,
-			"error":   err.Error(),
-		})
+		utils.Log.Errorw("Gagal query prodi dari database PNBP",
+			"mhswID", mhswID,
+			"prodiID", prodi_id,
+			"error", err,
+		)
 		return fmt.Errorf("gagal ambil data prodi master (id=%d): %w", prodi_id, err)
 	}
 
 	// Validasi prodi ditemukan
 	if prodiData.ID == 0 {
-		utils.Log.Error("Prodi ID = 0 setelah query", map[string]interface{}{
-			"mhswID":  mhswID,
-			"prodiID": prodi_id,
-		})
+		utils.Log.Errorw("Prodi ID = 0 setelah query",
+			"mhswID", mhswID,
+			"prodiID", prodi_id,
+		)
 		return fmt.Errorf("prodi dengan id %d tidak ditemukan di database PNBP", prodi_id)
 	}
 
-	utils.Log.Info("Prodi ditemukan di database PNBP", map[string]interface{}{
-		"mhswID":     mhswID,
-		"prodiID":    prodiData.ID,
-		"kodeProdi":  prodiData.KodeProdi,
-		"namaProdi":  prodiData.NamaProdi,
-		"fakultasID": prodiData.FakultasID,
-	})
+	utils.Log.Infow("Prodi ditemukan di database PNBP",
+		"mhswID", mhswID,
+		"prodiID", prodiData.ID,
+		"kodeProdi", prodiData.KodeProdi,
+		"namaProdi", prodiData.NamaProdi,
+		"fakultasID", prodiData.FakultasID,
+	)
 
 	// Validasi fakultasID tidak kosong
 	if prodiData.FakultasID == 0 {
-		utils.Log.Error("FakultasID = 0 di prodi", map[string]interface{}{
-			"mhswID":  mhswID,
-			"prodiID": prodiData.ID,
-		})
+		utils.Log.Errorw("FakultasID = 0 di prodi",
+			"mhswID", mhswID,
+			"prodiID", prodiData.ID,
+		)
 		return fmt.Errorf("fakultas_id tidak ditemukan di prodi (id=%d)", prodiData.ID)
 	}
 
@@ -142,21 +140,21 @@ func (s *mahasiswaService) CreateFromMasterMahasiswa(mhswID string) error {
 	var fakultasData models.FakultasPnbp
 	err = database.DBPNBP.Where("id=?", prodiData.FakultasID).First(&fakultasData).Error
 	if err != nil {
-		utils.Log.Error("Gagal query fakultas dari database PNBP", map[string]interface{}{
-			"mhswID":     mhswID,
-			"fakultasID": prodiData.FakultasID,
-			"prodiID":    prodiData.ID,
-			"error":      err.Error(),
-		})
+		utils.Log.Errorw("Gagal query fakultas dari database PNBP",
+			"mhswID", mhswID,
+			"fakultasID", prodiData.FakultasID,
+			"prodiID", prodiData.ID,
+			"error", err,
+		)
 		return fmt.Errorf("gagal ambil data fakultas master (id=%d): %w", prodiData.FakultasID, err)
 	}
 
-	utils.Log.Info("Fakultas ditemukan di database PNBP", map[string]interface{}{
-		"mhswID":       mhswID,
-		"fakultasID":   fakultasData.ID,
-		"kodeFakultas": fakultasData.KodeFakultas,
-		"namaFakultas": fakultasData.NamaFakultas,
-	})
+	utils.Log.Infow("Fakultas ditemukan di database PNBP",
+		"mhswID", mhswID,
+		"fakultasID", fakultasData.ID,
+		"kodeFakultas", fakultasData.KodeFakultas,
+		"namaFakultas", fakultasData.NamaFakultas,
+	)
 
 	// Validasi fakultas ditemukan
 	if fakultasData.ID == 0 {
@@ -177,7 +175,7 @@ func (s *mahasiswaService) CreateFromMasterMahasiswa(mhswID string) error {
 
 	// 3. Sinkron Fakultas
 	var fakultas models.Fakultas
-	utils.Log.Info(prodiData.ID)
+	utils.Log.Debugw("Sinkron fakultas", "prodi_id", prodiData.ID)
 	// 3.1. FirstOrCreate
 	err = db.FirstOrCreate(&fakultas, models.Fakultas{
 		KodeFakultas: fakultasData.KodeFakultas,
@@ -230,23 +228,23 @@ func (s *mahasiswaService) CreateFromMasterMahasiswa(mhswID string) error {
 		errStatus := database.DBPNBP.Where("id = ?", mhswMaster.StatusAkademikID).First(&statusAkademik).Error
 		if errStatus == nil && statusAkademik.Kode != "" {
 			statusMhswID = statusAkademik.Kode
-			utils.Log.Info("Status akademik diambil dari tabel status_akademiks", map[string]interface{}{
-				"mhswID":           mhswMaster.StudentID,
-				"StatusAkademikID": mhswMaster.StatusAkademikID,
-				"Kode":             statusAkademik.Kode,
-				"Nama":             statusAkademik.Nama,
-			})
+			utils.Log.Infow("Status akademik diambil dari tabel status_akademiks",
+				"mhswID", mhswMaster.StudentID,
+				"StatusAkademikID", mhswMaster.StatusAkademikID,
+				"Kode", statusAkademik.Kode,
+				"Nama", statusAkademik.Nama,
+			)
 		} else {
-			utils.Log.Warn("Status akademik tidak ditemukan di tabel status_akademiks", map[string]interface{}{
-				"mhswID":           mhswMaster.StudentID,
-				"StatusAkademikID": mhswMaster.StatusAkademikID,
-				"error":            errStatus,
-			})
+			utils.Log.Warnw("Status akademik tidak ditemukan di tabel status_akademiks",
+				"mhswID", mhswMaster.StudentID,
+				"StatusAkademikID", mhswMaster.StatusAkademikID,
+				"error", errStatus,
+			)
 			// Fallback: jika tidak ditemukan, gunakan default "N"
 			statusMhswID = "N"
 		}
 	} else {
-		utils.Log.Warn("StatusAkademikID = 0, menggunakan default non-aktif", "mhswID", mhswMaster.StudentID)
+		utils.Log.Warnw("StatusAkademikID = 0, menggunakan default non-aktif", "mhswID", mhswMaster.StudentID)
 	}
 
 	fullDataMap := map[string]interface{}{
@@ -282,11 +280,11 @@ func (s *mahasiswaService) CreateFromMasterMahasiswa(mhswID string) error {
 		return fmt.Errorf("gagal firstOrCreate mahasiswa: %w", err)
 	}
 
-	utils.Log.Info("Mahasiswa FirstOrCreate berhasil", map[string]interface{}{
-		"mhswID":  mahasiswa.MhswID,
-		"prodiID": mahasiswa.ProdiID,
-		"id":      mahasiswa.ID,
-	})
+	utils.Log.Infow("Mahasiswa FirstOrCreate berhasil",
+		"mhswID", mahasiswa.MhswID,
+		"prodiID", mahasiswa.ProdiID,
+		"id", mahasiswa.ID,
+	)
 
 	// mahasiswa_masters.UKT adalah kelompok UKT (decimal seperti 2.00), bukan nominal
 	// Nominal UKT diambil dari detail_tagihan berdasarkan master_tagihan_id dan kelompok UKT
@@ -333,43 +331,43 @@ func (s *mahasiswaService) CreateFromMasterMahasiswa(mhswID string) error {
 
 		if errDetail == nil {
 			nominalUKT = detailTagihan.Nominal
-			utils.Log.Info("Nominal UKT ditemukan dari detail_tagihan", map[string]interface{}{
-				"mhswID":            mhswMaster.StudentID,
-				"masterTagihanID":   mhswMaster.MasterTagihanID,
-				"uktValue":          mhswMaster.UKT,
-				"kelompokUKT":       detailTagihan.KelUKT,
-				"nominalUKT":        nominalUKT,
-				"detailTagihanID":   detailTagihan.ID,
-				"detailTagihanNama": detailTagihan.Nama,
-				"detailTagihanFull": map[string]interface{}{
+			utils.Log.Infow("Nominal UKT ditemukan dari detail_tagihan",
+				"mhswID", mhswMaster.StudentID,
+				"masterTagihanID", mhswMaster.MasterTagihanID,
+				"uktValue", mhswMaster.UKT,
+				"kelompokUKT", detailTagihan.KelUKT,
+				"nominalUKT", nominalUKT,
+				"detailTagihanID", detailTagihan.ID,
+				"detailTagihanNama", detailTagihan.Nama,
+				"detailTagihanFull", map[string]interface{}{
 					"ID":              detailTagihan.ID,
 					"MasterTagihanID": detailTagihan.MasterTagihanID,
 					"KelUKT":          detailTagihan.KelUKT,
 					"Nama":            detailTagihan.Nama,
 					"Nominal":         detailTagihan.Nominal,
 				},
-			})
+			)
 		} else {
 			// Fallback: cari berdasarkan master_tagihan_id saja (ambil yang pertama)
 			errFallback := database.DBPNBP.Where("master_tagihan_id = ?", mhswMaster.MasterTagihanID).
 				First(&detailTagihan).Error
 			if errFallback == nil {
 				nominalUKT = detailTagihan.Nominal
-				utils.Log.Warn("Nominal UKT diambil dari detail_tagihan fallback (tidak match kelompok)", map[string]interface{}{
-					"mhswID":          mhswMaster.StudentID,
-					"masterTagihanID": mhswMaster.MasterTagihanID,
-					"uktValue":        mhswMaster.UKT,
-					"kelompokUKT":     detailTagihan.KelUKT,
-					"nominalUKT":      nominalUKT,
-					"error":           errDetail,
-				})
+				utils.Log.Warnw("Nominal UKT diambil dari detail_tagihan fallback (tidak match kelompok)",
+					"mhswID", mhswMaster.StudentID,
+					"masterTagihanID", mhswMaster.MasterTagihanID,
+					"uktValue", mhswMaster.UKT,
+					"kelompokUKT", detailTagihan.KelUKT,
+					"nominalUKT", nominalUKT,
+					"error", errDetail,
+				)
 			} else {
-				utils.Log.Warn("Nominal UKT tidak ditemukan di detail_tagihan", map[string]interface{}{
-					"mhswID":          mhswMaster.StudentID,
-					"masterTagihanID": mhswMaster.MasterTagihanID,
-					"uktValue":        mhswMaster.UKT,
-					"error":           errDetail,
-				})
+				utils.Log.Warnw("Nominal UKT tidak ditemukan di detail_tagihan",
+					"mhswID", mhswMaster.StudentID,
+					"masterTagihanID", mhswMaster.MasterTagihanID,
+					"uktValue", mhswMaster.UKT,
+					"error", errDetail,
+				)
 			}
 		}
 	}
@@ -377,51 +375,17 @@ func (s *mahasiswaService) CreateFromMasterMahasiswa(mhswID string) error {
 	// Konversi UKT untuk disimpan di field UKT mahasiswa (format int sebagai string)
 	kelompokUKT := strconv.Itoa(int(mhswMaster.UKT))
 
-	utils.Log.Info("Data dari mahasiswa_masters", map[string]interface{}{
-		"mhswID":          mhswMaster.StudentID,
-		"masterTagihanID": mhswMaster.MasterTagihanID,
-		"kelompokUKT":     kelompokUKT,
-		"nominalUKT":      nominalUKT,
-	})
+	utils.Log.Infow("Data dari mahasiswa_masters",
+		"mhswID", mhswMaster.StudentID,
+		"masterTagihanID", mhswMaster.MasterTagihanID,
+		"kelompokUKT", kelompokUKT,
+		"nominalUKT", nominalUKT,
+	)
 
 	// Update FullData dengan nominal UKT
 	fullDataMap["UKT"] = int(mhswMaster.UKT) // Simpan kelompok UKT sebagai int (bukan float)
 	fullDataMap["UKTNominal"] = nominalUKT   // Simpan nominal UKT dari detail_tagihan
 
-	// Log fullDataMap untuk NPM 227007054 untuk debugging
-	if mhswMaster.StudentID == "227007054" {
-		utils.Log.Info("=== ANALISIS fullDataMap untuk NPM 227007054 ===", map[string]interface{}{
-			"fullDataMap": fullDataMap,
-			"mhswMaster": map[string]interface{}{
-				"ID":               mhswMaster.ID,
-				"StudentID":        mhswMaster.StudentID,
-				"NamaLengkap":      mhswMaster.NamaLengkap,
-				"ProdiID":          mhswMaster.ProdiID,
-				"ProgramID":        mhswMaster.ProgramID,
-				"TahunMasuk":       mhswMaster.TahunMasuk,
-				"SemesterMasukID":  mhswMaster.SemesterMasukID,
-				"StatusAkademikID": mhswMaster.StatusAkademikID,
-				"UKT":              mhswMaster.UKT,
-				"MasterTagihanID":  mhswMaster.MasterTagihanID,
-			},
-			"prodiData": map[string]interface{}{
-				"ID":         prodiData.ID,
-				"KodeProdi":  prodiData.KodeProdi,
-				"NamaProdi":  prodiData.NamaProdi,
-				"FakultasID": prodiData.FakultasID,
-			},
-			"fakultasData": map[string]interface{}{
-				"ID":           fakultasData.ID,
-				"KodeFakultas": fakultasData.KodeFakultas,
-				"NamaFakultas": fakultasData.NamaFakultas,
-			},
-			"statusMhswID":  statusMhswID,
-			"semesterMasuk": semesterMasuk,
-			"nominalUKT":    nominalUKT,
-			"kelompokUKT":   kelompokUKT,
-		})
-	}
-
 	jsonBytes, err := json.Marshal(fullDataMap)
 	if err != nil {
 		return fmt.Errorf("Gagal encode JSON mahasiswa: %w", err)
@@ -442,14 +406,14 @@ func (s *mahasiswaService) CreateFromMasterMahasiswa(mhswID string) error {
 		return fmt.Errorf("gagal update mahasiswa: %w", err)
 	}
 
-	utils.Log.Info("Mahasiswa berhasil di-update dari mahasiswa_masters", map[string]interface{}{
-		"mhswID":    mahasiswa.MhswID,
-		"nama":      mhswMaster.NamaLengkap,
-		"email":     mhswMaster.Email,
-		"prodiID":   prodi.ID,
-		"kodeProdi": prodi.KodeProdi,
-		"namaProdi": prodi.NamaProdi,
-	})
+	utils.Log.Infow("Mahasiswa berhasil di-update dari mahasiswa_masters",
+		"mhswID", mahasiswa.MhswID,
+		"nama", mhswMaster.NamaLengkap,
+		"email", mhswMaster.Email,
+		"prodiID", prodi.ID,
+		"kodeProdi", prodi.KodeProdi,
+		"namaProdi", prodi.NamaProdi,
+	)
 
 	return nil
 }
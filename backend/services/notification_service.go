@@ -0,0 +1,256 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/dedegunawan/backend-ujian-telp-v5/database"
+	"github.com/dedegunawan/backend-ujian-telp-v5/models"
+	"github.com/dedegunawan/backend-ujian-telp-v5/notification"
+	"github.com/dedegunawan/backend-ujian-telp-v5/utils"
+	"gorm.io/gorm"
+)
+
+const (
+	jobTypeSendNotification = "send_notification"
+	notificationMaxAttempts = 3
+)
+
+// NotificationRecipient alamat tujuan seorang mahasiswa
+type NotificationRecipient struct {
+	NPM    string
+	Nama   string
+	Email  string
+	Phone  string
+	Locale string
+}
+
+type NotificationService interface {
+	ResolveRecipient(npm string) (*NotificationRecipient, error)
+	Publish(event string, recipient NotificationRecipient, data notification.TemplateData, dedupeKey string) ([]models.Notification, error)
+	Deliver(notificationID uint) error
+	Retry(notificationID uint) (*models.Notification, error)
+	List(status, event, npm string, limit int) ([]models.Notification, error)
+}
+
+type notificationService struct {
+	db       *gorm.DB
+	registry *notification.Registry
+	worker   WorkerService
+}
+
+func NewNotificationService(db *gorm.DB) NotificationService {
+	return &notificationService{
+		db:       db,
+		registry: notification.Default(),
+		worker:   NewWorkerService(db),
+	}
+}
+
+func defaultNotificationLocale() string {
+	if locale := os.Getenv("NOTIFICATION_DEFAULT_LOCALE"); locale == notification.LocaleEN {
+		return locale
+	}
+	return notification.LocaleID
+}
+
+// ResolveRecipient mengambil nama, email dan nomor HP dari mahasiswa_masters,
+// fallback ke mahasiswas (FullData["Handphone"]) seperti payload invoice EPNBP
+func (s *notificationService) ResolveRecipient(npm string) (*NotificationRecipient, error) {
+	recipient := &NotificationRecipient{NPM: npm, Locale: defaultNotificationLocale()}
+
+	var master models.MahasiswaMaster
+	if err := s.db.Where("student_id = ?", npm).First(&master).Error; err == nil {
+		recipient.Nama = master.NamaLengkap
+		recipient.Email = master.Email
+		recipient.Phone = master.NoHP
+	}
+
+	if recipient.Email == "" || recipient.Phone == "" || recipient.Nama == "" {
+		var mahasiswa models.Mahasiswa
+		if err := s.db.Where("mhsw_id = ?", npm).First(&mahasiswa).Error; err == nil {
+			if recipient.Nama == "" {
+				recipient.Nama = mahasiswa.Nama
+			}
+			if recipient.Email == "" {
+				recipient.Email = mahasiswa.Email
+			}
+			if recipient.Phone == "" {
+				if handphone, ok := mahasiswa.ParseFullData()["Handphone"].(string); ok {
+					recipient.Phone = handphone
+				}
+			}
+		}
+	}
+
+	if recipient.Nama == "" && recipient.Email == "" && recipient.Phone == "" {
+		return nil, fmt.Errorf("mahasiswa %s tidak ditemukan", npm)
+	}
+	return recipient, nil
+}
+
+// Publish membuat satu Notification per channel aktif lalu mengantrekannya di JobQueue.
+// dedupeKey mencegah pesan yang sama terkirim dua kali (per channel).
+func (s *notificationService) Publish(event string, recipient NotificationRecipient, data notification.TemplateData, dedupeKey string) ([]models.Notification, error) {
+	if data.NPM == "" {
+		data.NPM = recipient.NPM
+	}
+	if data.Nama == "" {
+		data.Nama = recipient.Nama
+	}
+	locale := recipient.Locale
+	if locale == "" {
+		locale = defaultNotificationLocale()
+	}
+
+	subject, body, err := notification.Render(event, locale, data)
+	if err != nil {
+		return nil, err
+	}
+
+	var created []models.Notification
+	for _, channel := range s.registry.Names() {
+		address := recipient.Email
+		if channel == notification.ChannelWhatsApp {
+			address = recipient.Phone
+		}
+		if address == "" {
+			continue
+		}
+
+		key := fmt.Sprintf("%s:%s", dedupeKey, channel)
+		row := models.Notification{
+			Event:       event,
+			Channel:     channel,
+			Locale:      locale,
+			NPM:         recipient.NPM,
+			Recipient:   address,
+			Subject:     subject,
+			Body:        body,
+			DedupeKey:   key,
+			Status:      models.NotificationQueued,
+			MaxAttempts: notificationMaxAttempts,
+		}
+		if err := s.db.Create(&row).Error; err != nil {
+			// Unique index dedupe_key: sudah dibuat oleh watcher / run reminder lain
+			if database.IsDuplicateKey(err) {
+				continue
+			}
+			return created, fmt.Errorf("gagal menyimpan notifikasi: %w", err)
+		}
+		if err := s.worker.EnqueueJob(jobTypeSendNotification, map[string]interface{}{"notification_id": row.ID}, 0); err != nil {
+			return created, fmt.Errorf("gagal mengantrekan notifikasi: %w", err)
+		}
+		created = append(created, row)
+	}
+
+	return created, nil
+}
+
+// Deliver dipanggil oleh WorkerService. Error dikembalikan agar JobQueue menjadwalkan ulang.
+func (s *notificationService) Deliver(notificationID uint) error {
+	var row models.Notification
+	if err := s.db.First(&row, notificationID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+	if row.Status == models.NotificationSent || row.Status == models.NotificationSkipped {
+		return nil
+	}
+
+	channel, ok := s.registry.Get(row.Channel)
+	if !ok {
+		reason := fmt.Sprintf("channel %s tidak aktif", row.Channel)
+		row.Status = models.NotificationSkipped
+		row.LastError = &reason
+		return s.db.Save(&row).Error
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	row.Attempts++
+	providerID, err := channel.Send(ctx, notification.Message{
+		To:      row.Recipient,
+		Subject: row.Subject,
+		Body:    row.Body,
+	})
+	if err != nil {
+		message := err.Error()
+		row.LastError = &message
+		row.Status = models.NotificationRetrying
+		if row.Attempts >= row.MaxAttempts {
+			row.Status = models.NotificationFailed
+		}
+		s.db.Save(&row)
+
+		utils.Log.Warnw("Notifikasi gagal dikirim",
+			"notification_id", row.ID,
+			"channel", row.Channel,
+			"attempts", row.Attempts,
+			"error", message,
+		)
+		return err
+	}
+
+	now := time.Now()
+	row.Status = models.NotificationSent
+	row.ProviderMessageID = providerID
+	row.SentAt = &now
+	row.LastError = nil
+	return s.db.Save(&row).Error
+}
+
+// Retry mengantrekan ulang notifikasi yang gagal
+func (s *notificationService) Retry(notificationID uint) (*models.Notification, error) {
+	var row models.Notification
+	if err := s.db.First(&row, notificationID).Error; err != nil {
+		return nil, err
+	}
+	if row.Status == models.NotificationSent {
+		return nil, fmt.Errorf("notifikasi sudah terkirim")
+	}
+
+	row.Status = models.NotificationQueued
+	row.Attempts = 0
+	if err := s.db.Save(&row).Error; err != nil {
+		return nil, err
+	}
+	if err := s.worker.EnqueueJob(jobTypeSendNotification, map[string]interface{}{"notification_id": row.ID}, 0); err != nil {
+		return nil, err
+	}
+	return &row, nil
+}
+
+func (s *notificationService) List(status, event, npm string, limit int) ([]models.Notification, error) {
+	query := s.db.Model(&models.Notification{})
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if event != "" {
+		query = query.Where("event = ?", event)
+	}
+	if npm != "" {
+		query = query.Where("npm = ?", npm)
+	}
+
+	var rows []models.Notification
+	err := query.Order("created_at DESC").Limit(limit).Find(&rows).Error
+	return rows, err
+}
+
+func notificationIDFromJob(job *models.JobQueue) (uint, error) {
+	var payload struct {
+		NotificationID uint `json:"notification_id"`
+	}
+	if err := json.Unmarshal(job.Payload, &payload); err != nil {
+		return 0, err
+	}
+	return payload.NotificationID, nil
+}
@@ -0,0 +1,194 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/dedegunawan/backend-ujian-telp-v5/metrics"
+	"github.com/dedegunawan/backend-ujian-telp-v5/models"
+	"github.com/dedegunawan/backend-ujian-telp-v5/notification"
+	"github.com/dedegunawan/backend-ujian-telp-v5/utils"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const notificationWatcherBatch = 200
+
+// NotificationWatcher memantau tabel EPNBP (invoices, virtual_accounts, payments) dan
+// menerbitkan event notifikasi untuk baris baru. Posisi terakhir disimpan di notification_cursors.
+type NotificationWatcher interface {
+	StartWorker(ctx context.Context, workerName string, interval time.Duration)
+	Poll() error
+}
+
+type notificationWatcher struct {
+	db       *gorm.DB
+	notifSvc NotificationService
+}
+
+func NewNotificationWatcher(db *gorm.DB) NotificationWatcher {
+	return &notificationWatcher{db: db, notifSvc: NewNotificationService(db)}
+}
+
+func (w *notificationWatcher) StartWorker(ctx context.Context, workerName string, interval time.Duration) {
+	utils.Log.Infof("[%s] Notification watcher started, interval %s", workerName, interval)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			utils.Log.Infof("[%s] Notification watcher stopped", workerName)
+			return
+		case <-ticker.C:
+			start := time.Now()
+			err := w.Poll()
+			metrics.ObserveWorkerRun(workerName, start, err)
+			if err != nil {
+				utils.Log.Errorf("[%s] Error polling notification events: %v", workerName, err)
+			}
+		}
+	}
+}
+
+type watchedRow struct {
+	ID             uint       `gorm:"column:id"`
+	InvoiceID      uint       `gorm:"column:invoice_id"`
+	NPM            string     `gorm:"column:npm"`
+	TahunID        string     `gorm:"column:tahun_id"`
+	Amount         int64      `gorm:"column:amount"`
+	VirtualAccount string     `gorm:"column:virtual_account"`
+	DueDate        *time.Time `gorm:"column:due_date"`
+	CreatedAt      time.Time  `gorm:"column:created_at"`
+}
+
+func (w *notificationWatcher) Poll() error {
+	sources := []struct {
+		cursor string
+		event  string
+		query  func(lastID uint) *gorm.DB
+	}{
+		{"invoices", notification.EventBillIssued, func(lastID uint) *gorm.DB {
+			return w.db.Table("invoices").
+				Select(`invoices.id, invoices.id AS invoice_id, customers.identifier AS npm,
+					COALESCE(budget_periods.kode, '') AS tahun_id, CAST(invoices.total_amount AS SIGNED) AS amount,
+					budget_periods.payment_end_date AS due_date, invoices.created_at`).
+				Joins("INNER JOIN customers ON customers.id = invoices.customer_id").
+				Joins("LEFT JOIN budget_periods ON budget_periods.id = invoices.budget_period_id").
+				Where("invoices.id > ?", lastID).
+				Order("invoices.id ASC")
+		}},
+		{"virtual_accounts", notification.EventVACreated, func(lastID uint) *gorm.DB {
+			return w.db.Table("virtual_accounts").
+				Select(`virtual_accounts.id, invoices.id AS invoice_id, customers.identifier AS npm,
+					COALESCE(budget_periods.kode, '') AS tahun_id, CAST(invoices.total_amount AS SIGNED) AS amount,
+					virtual_accounts.virtual_account, budget_periods.payment_end_date AS due_date, virtual_accounts.created_at`).
+				Joins("INNER JOIN invoices ON invoices.id = virtual_accounts.invoice_id").
+				Joins("INNER JOIN customers ON customers.id = invoices.customer_id").
+				Joins("LEFT JOIN budget_periods ON budget_periods.id = invoices.budget_period_id").
+				Where("virtual_accounts.id > ?", lastID).
+				Order("virtual_accounts.id ASC")
+		}},
+		{"payments", notification.EventPaymentReceived, func(lastID uint) *gorm.DB {
+			return w.db.Table("payments").
+				Select(`payments.id, invoices.id AS invoice_id, customers.identifier AS npm,
+					COALESCE(budget_periods.kode, '') AS tahun_id, CAST(payments.amount AS SIGNED) AS amount,
+					COALESCE(virtual_accounts.virtual_account, '') AS virtual_account, payments.created_at`).
+				Joins("INNER JOIN invoices ON invoices.id = payments.invoice_id").
+				Joins("INNER JOIN customers ON customers.id = invoices.customer_id").
+				Joins("LEFT JOIN budget_periods ON budget_periods.id = invoices.budget_period_id").
+				Joins(latestVirtualAccountJoin).
+				Where("payments.id > ?", lastID).
+				Order("payments.id ASC")
+		}},
+	}
+
+	for _, source := range sources {
+		cursor, fresh, err := w.cursor(source.cursor)
+		if err != nil {
+			return err
+		}
+		if fresh {
+			// Run pertama: mulai dari posisi sekarang, jangan kirim notifikasi untuk data lama
+			continue
+		}
+
+		var rows []watchedRow
+		if err := source.query(cursor.LastID).Limit(notificationWatcherBatch).Scan(&rows).Error; err != nil {
+			return fmt.Errorf("gagal membaca %s: %w", source.cursor, err)
+		}
+
+		for _, row := range rows {
+			w.publish(source.event, source.cursor, row)
+			cursor.LastID = row.ID
+		}
+		if len(rows) > 0 {
+			if err := w.db.Save(cursor).Error; err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (w *notificationWatcher) publish(event, source string, row watchedRow) {
+	recipient, err := w.notifSvc.ResolveRecipient(row.NPM)
+	if err != nil {
+		utils.Log.Warnw("NotificationWatcher: penerima tidak ditemukan",
+			"event", event,
+			"npm", row.NPM,
+		)
+		return
+	}
+
+	data := notification.TemplateData{
+		TahunID:        row.TahunID,
+		Amount:         row.Amount,
+		VirtualAccount: row.VirtualAccount,
+		InvoiceID:      row.InvoiceID,
+	}
+	if row.DueDate != nil {
+		data.DueDate = row.DueDate.Format("02-01-2006")
+	}
+	if event == notification.EventPaymentReceived {
+		data.PaymentDate = row.CreatedAt.Format("02-01-2006 15:04")
+	}
+
+	dedupeKey := fmt.Sprintf("%s:%s:%d", event, source, row.ID)
+	if _, err := w.notifSvc.Publish(event, *recipient, data, dedupeKey); err != nil {
+		utils.Log.Errorw("NotificationWatcher: gagal publish",
+			"event", event,
+			"npm", row.NPM,
+			"error", err,
+		)
+	}
+}
+
+// cursor mengambil cursor; jika belum ada, dibuat dengan MAX(id) tabel sumber (fresh = true)
+func (w *notificationWatcher) cursor(name string) (*models.NotificationCursor, bool, error) {
+	return loadEventCursor(w.db, name, name)
+}
+
+// loadEventCursor cursor bernama name atas tabel sumber table, dipakai bersama oleh watcher notifikasi dan webhook
+func loadEventCursor(db *gorm.DB, name, table string) (*models.NotificationCursor, bool, error) {
+	var cursor models.NotificationCursor
+	err := db.Where("name = ?", name).First(&cursor).Error
+	if err == nil {
+		return &cursor, false, nil
+	}
+	if err != gorm.ErrRecordNotFound {
+		return nil, false, err
+	}
+
+	var maxID uint
+	if err := db.Table(table).Select("COALESCE(MAX(id), 0)").Scan(&maxID).Error; err != nil {
+		return nil, false, err
+	}
+	cursor = models.NotificationCursor{Name: name, LastID: maxID}
+	if err := db.Clauses(clause.OnConflict{DoNothing: true}).Create(&cursor).Error; err != nil {
+		return nil, false, err
+	}
+	return &cursor, true, nil
+}
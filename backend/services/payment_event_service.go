@@ -0,0 +1,163 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/dedegunawan/backend-ujian-telp-v5/metrics"
+	"github.com/dedegunawan/backend-ujian-telp-v5/realtime"
+	"github.com/dedegunawan/backend-ujian-telp-v5/utils"
+	"gorm.io/gorm"
+)
+
+const paymentEventBatch = 200
+
+// PaymentEventService menerbitkan perubahan status pembayaran ke hub realtime (SSE).
+// Sumbernya callback pembayaran dan poller tabel payments (rekonsiliasi dari EPNBP).
+type PaymentEventService interface {
+	PublishInvoice(invoiceID uint, source, key string) error
+	StartWorker(ctx context.Context, workerName string, interval time.Duration)
+	Poll() error
+}
+
+type paymentEventService struct {
+	db     *gorm.DB
+	hub    *realtime.Hub
+	lastID uint
+	primed bool
+}
+
+func NewPaymentEventService(db *gorm.DB) PaymentEventService {
+	return &paymentEventService{db: db, hub: realtime.Default()}
+}
+
+// invoiceStatusRow status invoice terkini beserta total yang sudah dibayar
+type invoiceStatusRow struct {
+	InvoiceID      uint       `gorm:"column:invoice_id"`
+	NPM            string     `gorm:"column:npm"`
+	TahunID        string     `gorm:"column:tahun_id"`
+	Status         string     `gorm:"column:status"`
+	TotalAmount    int64      `gorm:"column:total_amount"`
+	PaidAmount     int64      `gorm:"column:paid_amount"`
+	VirtualAccount string     `gorm:"column:virtual_account"`
+	PaidAt         *time.Time `gorm:"column:paid_at"`
+}
+
+// IsPaid invoice berstatus Paid atau total pembayaran sudah menutup tagihan
+func (r invoiceStatusRow) IsPaid() bool {
+	return r.Status == "Paid" || (r.TotalAmount > 0 && r.PaidAmount >= r.TotalAmount)
+}
+
+func loadInvoiceStatus(db *gorm.DB, invoiceID uint) (*invoiceStatusRow, error) {
+	var row invoiceStatusRow
+	err := db.Table("invoices").
+		Select(`invoices.id AS invoice_id, customers.identifier AS npm,
+			COALESCE(budget_periods.kode, '') AS tahun_id, COALESCE(invoices.status, '') AS status,
+			CAST(invoices.total_amount AS SIGNED) AS total_amount,
+			(SELECT CAST(COALESCE(SUM(p.amount), 0) AS SIGNED) FROM payments p WHERE p.invoice_id = invoices.id) AS paid_amount,
+			(SELECT MAX(p.created_at) FROM payments p WHERE p.invoice_id = invoices.id) AS paid_at,
+			COALESCE((SELECT va.virtual_account FROM virtual_accounts va WHERE va.invoice_id = invoices.id
+				ORDER BY va.created_at DESC LIMIT 1), '') AS virtual_account`).
+		Joins("INNER JOIN customers ON customers.id = invoices.customer_id").
+		Joins("LEFT JOIN budget_periods ON budget_periods.id = invoices.budget_period_id").
+		Where("invoices.id = ?", invoiceID).
+		Scan(&row).Error
+	if err != nil {
+		return nil, err
+	}
+	if row.InvoiceID == 0 {
+		return nil, fmt.Errorf("invoice %d tidak ditemukan", invoiceID)
+	}
+	return &row, nil
+}
+
+// PublishInvoice membaca status invoice terkini lalu mengirimnya ke mahasiswa pemilik invoice.
+// key dipakai untuk dedupe; event dengan key sama tidak dikirim dua kali.
+func (s *paymentEventService) PublishInvoice(invoiceID uint, source, key string) error {
+	row, err := loadInvoiceStatus(s.db, invoiceID)
+	if err != nil {
+		return err
+	}
+
+	data := map[string]interface{}{
+		"invoice_id":      row.InvoiceID,
+		"tahun_id":        row.TahunID,
+		"status":          row.Status,
+		"is_paid":         row.IsPaid(),
+		"total_amount":    row.TotalAmount,
+		"paid_amount":     row.PaidAmount,
+		"virtual_account": row.VirtualAccount,
+		"source":          source,
+	}
+	if row.PaidAt != nil {
+		data["paid_at"] = row.PaidAt.Format("2006-01-02 15:04:05")
+	}
+
+	return s.hub.Publish(context.Background(), realtime.Event{
+		Key:  key,
+		NPM:  row.NPM,
+		Type: realtime.EventPaymentStatus,
+		Data: data,
+	})
+}
+
+func (s *paymentEventService) StartWorker(ctx context.Context, workerName string, interval time.Duration) {
+	utils.Log.Infof("[%s] Payment event poller started, interval %s, broker %s", workerName, interval, s.hub.BrokerName())
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			utils.Log.Infof("[%s] Payment event poller stopped", workerName)
+			return
+		case <-ticker.C:
+			start := time.Now()
+			err := s.Poll()
+			metrics.ObserveWorkerRun(workerName, start, err)
+			if err != nil {
+				utils.Log.Errorf("[%s] Error polling payments: %v", workerName, err)
+			}
+		}
+	}
+}
+
+// Poll mengirim event untuk baris payments baru. Posisi hanya disimpan di memori:
+// event bersifat sementara, saat restart cukup mulai dari MAX(id) sekarang.
+func (s *paymentEventService) Poll() error {
+	if !s.primed {
+		if err := s.db.Table("payments").Select("COALESCE(MAX(id), 0)").Scan(&s.lastID).Error; err != nil {
+			return err
+		}
+		s.primed = true
+		return nil
+	}
+
+	var rows []struct {
+		ID        uint `gorm:"column:id"`
+		InvoiceID uint `gorm:"column:invoice_id"`
+	}
+	if err := s.db.Table("payments").
+		Select("id, invoice_id").
+		Where("id > ?", s.lastID).
+		Order("id ASC").
+		Limit(paymentEventBatch).
+		Scan(&rows).Error; err != nil {
+		return fmt.Errorf("gagal membaca payments: %w", err)
+	}
+
+	for _, row := range rows {
+		if err := s.PublishInvoice(row.InvoiceID, "payment", fmt.Sprintf("payment:%d", row.ID)); err != nil {
+			utils.Log.Warnw("PaymentEvent: gagal publish status pembayaran",
+				"payment_id", row.ID,
+				"invoice_id", row.InvoiceID,
+				"error", err,
+			)
+		}
+		metrics.PaymentsProcessed.WithLabelValues("payment").Inc()
+		s.lastID = row.ID
+	}
+	return nil
+}
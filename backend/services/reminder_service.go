@@ -0,0 +1,372 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dedegunawan/backend-ujian-telp-v5/config"
+	"github.com/dedegunawan/backend-ujian-telp-v5/metrics"
+	"github.com/dedegunawan/backend-ujian-telp-v5/models"
+	"github.com/dedegunawan/backend-ujian-telp-v5/notification"
+	"github.com/dedegunawan/backend-ujian-telp-v5/repositories"
+	"github.com/dedegunawan/backend-ujian-telp-v5/utils"
+	"gorm.io/gorm"
+)
+
+// reminderOverdue offset khusus: semua item yang sudah lewat jatuh tempo (dikirim sekali)
+const reminderOverdue = -1
+
+type ReminderService interface {
+	Run(now time.Time) (*models.ReminderRun, error)
+	Runs(limit int) ([]models.ReminderRun, error)
+	StartScheduler(ctx context.Context, workerName string, interval time.Duration)
+}
+
+type reminderService struct {
+	db       *gorm.DB
+	notifSvc NotificationService
+	offsets  []int
+	quiet    [2]int // jam mulai & selesai quiet hours, -1 jika tidak aktif
+	lookback int    // hari ke belakang untuk item overdue
+}
+
+func NewReminderService(db *gorm.DB) ReminderService {
+	lookback, err := strconv.Atoi(config.GetEnv("REMINDER_OVERDUE_LOOKBACK_DAYS"))
+	if err != nil || lookback <= 0 {
+		lookback = 30
+	}
+	return &reminderService{
+		db:       db,
+		notifSvc: NewNotificationService(db),
+		offsets:  ParseReminderOffsets(config.GetEnv("REMINDER_OFFSETS")),
+		quiet:    parseQuietHours(config.GetEnv("REMINDER_QUIET_HOURS")),
+		lookback: lookback,
+	}
+}
+
+// ParseReminderOffsets "H-7,H-3,H-1,overdue" -> [7 3 1 -1]. Angka biasa juga diterima ("7,3,1").
+func ParseReminderOffsets(raw string) []int {
+	if strings.TrimSpace(raw) == "" {
+		raw = "H-7,H-3,H-1,overdue"
+	}
+
+	seen := make(map[int]bool)
+	var offsets []int
+	for _, token := range strings.Split(raw, ",") {
+		token = strings.ToUpper(strings.TrimSpace(token))
+		var days int
+		switch {
+		case token == "OVERDUE":
+			days = reminderOverdue
+		case token == "H" || token == "H-0":
+			days = 0
+		case strings.HasPrefix(token, "H-"):
+			n, err := strconv.Atoi(token[2:])
+			if err != nil || n < 0 {
+				continue
+			}
+			days = n
+		default:
+			n, err := strconv.Atoi(token)
+			if err != nil || n < 0 {
+				continue
+			}
+			days = n
+		}
+		if !seen[days] {
+			seen[days] = true
+			offsets = append(offsets, days)
+		}
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(offsets)))
+	return offsets
+}
+
+func reminderOffsetLabel(offset int) string {
+	if offset == reminderOverdue {
+		return "overdue"
+	}
+	return fmt.Sprintf("H-%d", offset)
+}
+
+// parseQuietHours "21-07" -> [21 7]; format tidak valid -> tidak aktif
+func parseQuietHours(raw string) [2]int {
+	parts := strings.Split(strings.TrimSpace(raw), "-")
+	if len(parts) != 2 {
+		return [2]int{-1, -1}
+	}
+	start, err1 := strconv.Atoi(strings.TrimSpace(parts[0]))
+	end, err2 := strconv.Atoi(strings.TrimSpace(parts[1]))
+	if err1 != nil || err2 != nil || start < 0 || start > 23 || end < 0 || end > 23 || start == end {
+		return [2]int{-1, -1}
+	}
+	return [2]int{start, end}
+}
+
+func (s *reminderService) inQuietHours(now time.Time) bool {
+	start, end := s.quiet[0], s.quiet[1]
+	if start < 0 {
+		return false
+	}
+	hour := now.Hour()
+	if start < end {
+		return hour >= start && hour < end
+	}
+	// Melewati tengah malam, mis. 21-07
+	return hour >= start || hour < end
+}
+
+// reminderGroup item-item milik satu mahasiswa dengan jatuh tempo yang sama
+type reminderGroup struct {
+	npm     string
+	tahunID string
+	dueDate time.Time
+	offset  int
+	amount  int64
+}
+
+func (s *reminderService) Run(now time.Time) (*models.ReminderRun, error) {
+	run := &models.ReminderRun{StartedAt: now}
+	perOffset := make(map[string]int)
+
+	finish := func(err error) (*models.ReminderRun, error) {
+		finished := time.Now()
+		run.FinishedAt = &finished
+		if err != nil {
+			message := err.Error()
+			run.Error = &message
+		}
+		encoded, _ := json.Marshal(perOffset)
+		run.PerOffset = string(encoded)
+		if saveErr := s.db.Create(run).Error; saveErr != nil {
+			utils.Log.Errorw("ReminderService: gagal menyimpan ringkasan run", "error", saveErr)
+		}
+		utils.Log.Infow("ReminderService: run selesai",
+			"candidates", run.Candidates,
+			"sent", run.Sent,
+			"deduplicated", run.Deduplicated,
+			"no_contact", run.NoContact,
+			"failed", run.Failed,
+			"quiet_hours", run.SkippedQuietHours,
+			"per_offset", run.PerOffset,
+		)
+		return run, err
+	}
+
+	if s.inQuietHours(now) {
+		run.SkippedQuietHours = true
+		return finish(nil)
+	}
+	if len(s.offsets) == 0 {
+		return finish(fmt.Errorf("REMINDER_OFFSETS tidak berisi offset yang valid"))
+	}
+
+	groups, err := s.collect(now)
+	if err != nil {
+		return finish(err)
+	}
+
+	for _, group := range groups {
+		run.Candidates++
+
+		recipient, err := s.notifSvc.ResolveRecipient(group.npm)
+		if err != nil || (recipient.Email == "" && recipient.Phone == "") {
+			run.NoContact++
+			continue
+		}
+
+		label := reminderOffsetLabel(group.offset)
+		daysLeft := group.offset
+		if group.offset == reminderOverdue {
+			daysLeft = -int(dateOnly(now).Sub(group.dueDate).Hours() / 24)
+		}
+		data := notification.TemplateData{
+			TahunID:  group.tahunID,
+			Amount:   group.amount,
+			DueDate:  group.dueDate.Format("02-01-2006"),
+			DaysLeft: daysLeft,
+		}
+
+		// Satu pesan per mahasiswa per jatuh tempo per offset
+		dedupeKey := fmt.Sprintf("reminder:%s:%s:%s", group.npm, group.dueDate.Format("20060102"), label)
+		created, err := s.notifSvc.Publish(notification.EventDeadlineApproaching, *recipient, data, dedupeKey)
+		if err != nil {
+			run.Failed++
+			utils.Log.Errorw("ReminderService: gagal publish",
+				"npm", group.npm,
+				"error", err,
+			)
+			continue
+		}
+		if len(created) == 0 {
+			run.Deduplicated++
+			continue
+		}
+		run.Sent++
+		perOffset[label]++
+	}
+
+	return finish(nil)
+}
+
+// reminderItemsSQL item belum lunas yang jatuh temponya berada di jendela reminder, baik yang
+// akan datang (H-n) maupun yang sudah lewat (overdue, dibatasi lookback). Berbeda dengan
+// arrearsItemsSQL yang hanya berisi item lewat jatuh tempo. Aturan sumber & pembayaran cicilan
+// sama: tahun yang punya cicilan hanya diambil dari detail_cicilans, registrasi hanya tahun aktif
+// (jatuh temponya dari FinanceYear.EndDate per mahasiswa, disaring di Go).
+// Parameter: tahun aktif, awal jendela due_date, akhir jendela due_date (eksklusif), tahun aktif.
+const reminderItemsSQL = `
+	SELECT 'cicilan' AS source, dc.id AS item_id, c.npm, c.tahun_id, dc.sequence_no, dc.due_date,
+		CAST(dc.amount AS SIGNED) AS amount,
+		CAST(COALESCE(pa.paid_amount, 0) AS SIGNED) AS paid_amount,
+		0 AS beasiswa,
+		CAST(GREATEST(dc.amount - COALESCE(pa.paid_amount, 0), 0) AS SIGNED) AS remaining_amount
+	FROM detail_cicilans dc
+	INNER JOIN cicilans c ON c.id = dc.cicilan_id
+	LEFT JOIN (
+		SELECT ir.detail_cicilan_id, SUM(p.amount) AS paid_amount
+		FROM invoice_relations ir
+		INNER JOIN invoices inv ON inv.id = ir.invoice_id AND inv.status = 'Paid'
+		INNER JOIN payments p ON p.invoice_id = inv.id
+		WHERE ir.detail_cicilan_id IS NOT NULL
+		GROUP BY ir.detail_cicilan_id
+	) pa ON pa.detail_cicilan_id = dc.id
+	WHERE (dc.status IS NULL OR dc.status <> 'paid') AND dc.amount > 0 AND c.tahun_id <= ?
+		AND dc.due_date >= ? AND dc.due_date < ?
+		AND dc.amount - COALESCE(pa.paid_amount, 0) > 0
+	UNION ALL
+	SELECT 'registrasi' AS source, rm.id AS item_id, rm.npm, rm.tahun_id, NULL AS sequence_no, NULL AS due_date,
+		CAST(COALESCE(rm.nominal_ukt, 0) AS SIGNED) AS amount,
+		CAST(COALESCE(rm.nominal_bayar, 0) AS SIGNED) AS paid_amount,
+		CAST(COALESCE(b.nominal, 0) AS SIGNED) AS beasiswa,
+		CAST(GREATEST(COALESCE(rm.nominal_ukt, 0) - COALESCE(b.nominal, 0) - COALESCE(rm.nominal_bayar, 0), 0) AS SIGNED) AS remaining_amount
+	FROM registrasi_mahasiswa rm
+	LEFT JOIN (` + scholarshipRowsSQL + `) b ON b.npm = rm.npm AND b.tahun_id = rm.tahun_id
+	WHERE rm.tahun_id = ?
+		AND NOT EXISTS (SELECT 1 FROM cicilans c2 WHERE c2.npm = rm.npm AND c2.tahun_id = rm.tahun_id)
+		AND COALESCE(rm.nominal_ukt, 0) - COALESCE(b.nominal, 0) - COALESCE(rm.nominal_bayar, 0) > 0`
+
+// collect item belum lunas: detail_cicilans dengan due_date, dan registrasi tahun aktif
+// dengan jatuh tempo dari FinanceYear.EndDate yang sudah di-override per mahasiswa
+func (s *reminderService) collect(now time.Time) ([]reminderGroup, error) {
+	tagihanRepo := repositories.NewTagihanRepository(s.db, s.db)
+	activeYear, err := tagihanRepo.GetActiveFinanceYear()
+	if err != nil {
+		return nil, fmt.Errorf("tahun aktif tidak ditemukan: %w", err)
+	}
+
+	today := dateOnly(now)
+	maxOffset := 0
+	for _, offset := range s.offsets {
+		if offset > maxOffset {
+			maxOffset = offset
+		}
+	}
+
+	var items []models.ArrearsItem
+	err = s.db.Raw(reminderItemsSQL,
+		activeYear.AcademicYear, today.AddDate(0, 0, -s.lookback), today.AddDate(0, 0, maxOffset+1),
+		activeYear.AcademicYear,
+	).Scan(&items).Error
+	if err != nil {
+		return nil, fmt.Errorf("gagal mengambil tagihan belum lunas: %w", err)
+	}
+
+	resolvedDue := make(map[string]time.Time)
+	grouped := make(map[string]*reminderGroup)
+	var order []string
+
+	for _, item := range items {
+		var due time.Time
+		if item.Source == "cicilan" {
+			if item.DueDate == nil {
+				continue
+			}
+			due = dateOnly(*item.DueDate)
+		} else {
+			cached, ok := resolvedDue[item.NPM]
+			if !ok {
+				financeYear, err := tagihanRepo.GetActiveFinanceYearWithOverride(models.Mahasiswa{MhswID: item.NPM})
+				if err != nil || financeYear.EndDate.IsZero() {
+					continue
+				}
+				cached = dateOnly(financeYear.EndDate)
+				resolvedDue[item.NPM] = cached
+			}
+			due = cached
+		}
+
+		daysLeft := int(due.Sub(today).Hours() / 24)
+		offset, ok := s.matchOffset(daysLeft)
+		if !ok {
+			continue
+		}
+
+		key := fmt.Sprintf("%s|%s|%d", item.NPM, due.Format("20060102"), offset)
+		group, exists := grouped[key]
+		if !exists {
+			group = &reminderGroup{npm: item.NPM, tahunID: item.TahunID, dueDate: due, offset: offset}
+			grouped[key] = group
+			order = append(order, key)
+		}
+		group.amount += item.RemainingAmount
+	}
+
+	groups := make([]reminderGroup, 0, len(order))
+	for _, key := range order {
+		groups = append(groups, *grouped[key])
+	}
+	return groups, nil
+}
+
+func (s *reminderService) matchOffset(daysLeft int) (int, bool) {
+	for _, offset := range s.offsets {
+		if offset == reminderOverdue {
+			if daysLeft < 0 && -daysLeft <= s.lookback {
+				return offset, true
+			}
+			continue
+		}
+		if daysLeft == offset {
+			return offset, true
+		}
+	}
+	return 0, false
+}
+
+func (s *reminderService) Runs(limit int) ([]models.ReminderRun, error) {
+	var runs []models.ReminderRun
+	err := s.db.Order("started_at DESC").Limit(limit).Find(&runs).Error
+	return runs, err
+}
+
+func (s *reminderService) StartScheduler(ctx context.Context, workerName string, interval time.Duration) {
+	utils.Log.Infof("[%s] Payment reminder scheduler started, interval %s, offsets %v", workerName, interval, s.offsets)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			utils.Log.Infof("[%s] Payment reminder scheduler stopped", workerName)
+			return
+		case <-ticker.C:
+			start := time.Now()
+			_, err := s.Run(start)
+			metrics.ObserveWorkerRun(workerName, start, err)
+			if err != nil {
+				utils.Log.Errorf("[%s] Error running payment reminders: %v", workerName, err)
+			}
+		}
+	}
+}
+
+func dateOnly(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.Local)
+}
@@ -0,0 +1,235 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/dedegunawan/backend-ujian-telp-v5/models"
+	"gorm.io/gorm"
+)
+
+type RevenueService interface {
+	Summary(filter models.RevenueFilter) (*models.RevenueSummaryResponse, error)
+	TimeSeries(filter models.RevenueFilter, interval string, from, to time.Time) ([]models.RevenuePoint, error)
+}
+
+type revenueService struct {
+	db *gorm.DB
+}
+
+func NewRevenueService(db *gorm.DB) RevenueService {
+	return &revenueService{db: db}
+}
+
+// billRowsSQL menghasilkan satu baris per (npm, tahun_id) dengan aturan yang sama seperti tagihanNewService:
+// jika mahasiswa punya cicilan untuk tahun_id tersebut, sumber tagihan hanya dari cicilan,
+// jika tidak, sumber tagihan dari registrasi_mahasiswa.
+const billRowsSQL = `
+	SELECT c.npm, c.tahun_id, MAX(c.kel_ukt) AS kel_ukt,
+		CAST(SUM(dc.amount) AS SIGNED) AS expected,
+		CAST(SUM(CASE WHEN dc.status = 'paid' THEN dc.amount ELSE 0 END) AS SIGNED) AS collected
+	FROM cicilans c
+	INNER JOIN detail_cicilans dc ON dc.cicilan_id = c.id
+	GROUP BY c.npm, c.tahun_id
+	UNION ALL
+	SELECT rm.npm, rm.tahun_id, rm.kel_ukt,
+		CAST(COALESCE(rm.nominal_ukt, 0) AS SIGNED) AS expected,
+		CAST(COALESCE(rm.nominal_bayar, 0) AS SIGNED) AS collected
+	FROM registrasi_mahasiswa rm
+	WHERE NOT EXISTS (
+		SELECT 1 FROM cicilans c2 WHERE c2.npm = rm.npm AND c2.tahun_id = rm.tahun_id
+	)`
+
+// scholarshipRowsSQL total beasiswa aktif per (npm, tahun_id). Bantuan UKT belum ikut dihitung:
+// struktur tabelnya belum diketahui dan perhitungan tagihan (GetTotalBantuanUKT) juga masih 0,
+// jadi tambahkan di sini bersamaan dengan implementasi tersebut agar scholarship = potongan tagihan.
+const scholarshipRowsSQL = `
+	SELECT db.npm, db.tahun_id, CAST(SUM(db.nominal_beasiswa) AS SIGNED) AS nominal
+	FROM detail_beasiswa db
+	INNER JOIN beasiswa bs ON bs.id = db.beasiswa_id
+	WHERE bs.status = 'active'
+	GROUP BY db.npm, db.tahun_id`
+
+// revenueGroupColumns whitelist kolom untuk group_by, urutan sesuai urutan tampil
+var revenueGroupColumns = map[string][]string{
+	"fakultas": {"f.id AS fakultas_id", "f.nama_fakultas AS nama_fakultas"},
+	"prodi":    {"p.id AS prodi_id", "p.nama_prodi AS nama_prodi"},
+	"angkatan": {"mm.tahun_masuk AS angkatan"},
+	"ukt":      {"t.kel_ukt AS kel_ukt"},
+	"tahun_id": {"t.tahun_id AS tahun_id"},
+}
+
+var revenueGroupOrder = []string{"fakultas", "prodi", "angkatan", "ukt", "tahun_id"}
+
+// NormalizeRevenueGroupBy membuang group_by yang tidak dikenal & duplikat, lalu mengurutkannya
+func NormalizeRevenueGroupBy(groupBy []string) []string {
+	requested := make(map[string]bool)
+	for _, g := range groupBy {
+		requested[strings.TrimSpace(strings.ToLower(g))] = true
+	}
+
+	var result []string
+	for _, g := range revenueGroupOrder {
+		if requested[g] {
+			result = append(result, g)
+		}
+	}
+	return result
+}
+
+// studentScopeWhere filter yang berlaku pada mahasiswa_masters/prodi
+func studentScopeWhere(filter models.RevenueFilter) ([]string, []interface{}) {
+	var where []string
+	var args []interface{}
+
+	if filter.FakultasID != "" {
+		where = append(where, "p.fakultas_id = ?")
+		args = append(args, filter.FakultasID)
+	}
+	if filter.ProdiID != "" {
+		where = append(where, "mm.prodi_id = ?")
+		args = append(args, filter.ProdiID)
+	}
+	if filter.Angkatan != "" {
+		where = append(where, "mm.tahun_masuk = ?")
+		args = append(args, filter.Angkatan)
+	}
+	return where, args
+}
+
+func (s *revenueService) Summary(filter models.RevenueFilter) (*models.RevenueSummaryResponse, error) {
+	groupBy := NormalizeRevenueGroupBy(filter.GroupBy)
+
+	var selectCols, groupCols []string
+	for _, g := range groupBy {
+		for _, col := range revenueGroupColumns[g] {
+			selectCols = append(selectCols, col)
+			groupCols = append(groupCols, strings.SplitN(col, " AS ", 2)[0])
+		}
+	}
+	selectCols = append(selectCols,
+		"COUNT(DISTINCT t.npm) AS students",
+		"CAST(COALESCE(SUM(t.expected), 0) AS SIGNED) AS expected",
+		"CAST(COALESCE(SUM(LEAST(COALESCE(b.nominal, 0), t.expected)), 0) AS SIGNED) AS scholarship",
+		"CAST(COALESCE(SUM(t.collected), 0) AS SIGNED) AS collected",
+		"CAST(COALESCE(SUM(GREATEST(t.expected - COALESCE(b.nominal, 0) - t.collected, 0)), 0) AS SIGNED) AS outstanding",
+	)
+
+	where, args := studentScopeWhere(filter)
+	if filter.TahunID != "" {
+		where = append(where, "t.tahun_id = ?")
+		args = append(args, filter.TahunID)
+	}
+	if filter.KelUKT != "" {
+		where = append(where, "t.kel_ukt = ?")
+		args = append(args, filter.KelUKT)
+	}
+
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM (%s) t
+		LEFT JOIN (%s) b ON b.npm = t.npm AND b.tahun_id = t.tahun_id
+		LEFT JOIN mahasiswa_masters mm ON mm.student_id = t.npm AND mm.deleted_at IS NULL
+		LEFT JOIN prodi p ON p.id = mm.prodi_id
+		LEFT JOIN fakultas f ON f.id = p.fakultas_id`,
+		strings.Join(selectCols, ", "), billRowsSQL, scholarshipRowsSQL)
+
+	if len(where) > 0 {
+		query += " WHERE " + strings.Join(where, " AND ")
+	}
+	if len(groupCols) > 0 {
+		query += " GROUP BY " + strings.Join(groupCols, ", ") + " ORDER BY " + strings.Join(groupCols, ", ")
+	}
+
+	var rows []models.RevenueRow
+	if err := s.db.Raw(query, args...).Scan(&rows).Error; err != nil {
+		return nil, fmt.Errorf("gagal mengambil agregat pendapatan: %w", err)
+	}
+
+	total := models.RevenueRow{}
+	for i := range rows {
+		rows[i].CollectionRate = collectionRate(rows[i])
+		total.Students += rows[i].Students
+		total.Expected += rows[i].Expected
+		total.Scholarship += rows[i].Scholarship
+		total.Collected += rows[i].Collected
+		total.Outstanding += rows[i].Outstanding
+	}
+	total.CollectionRate = collectionRate(total)
+
+	if rows == nil {
+		rows = []models.RevenueRow{}
+	}
+	if groupBy == nil {
+		groupBy = []string{}
+	}
+
+	return &models.RevenueSummaryResponse{
+		GroupBy: groupBy,
+		Total:   total,
+		Rows:    rows,
+	}, nil
+}
+
+// TimeSeries pendapatan yang diterima (payments dari invoice Paid) per hari atau per minggu
+func (s *revenueService) TimeSeries(filter models.RevenueFilter, interval string, from, to time.Time) ([]models.RevenuePoint, error) {
+	period := "DATE_FORMAT(payments.created_at, '%Y-%m-%d')"
+	if interval == "week" {
+		// Awal minggu (Senin)
+		period = "DATE_FORMAT(DATE_SUB(DATE(payments.created_at), INTERVAL WEEKDAY(payments.created_at) DAY), '%Y-%m-%d')"
+	}
+
+	where, args := studentScopeWhere(filter)
+	where = append(where, "invoices.status = ?", "payments.created_at >= ?", "payments.created_at < ?")
+	args = append(args, "Paid", from, to)
+	if filter.TahunID != "" {
+		where = append(where, "budget_periods.kode = ?")
+		args = append(args, filter.TahunID)
+	}
+	if filter.KelUKT != "" {
+		// Sumber kel_ukt sama dengan billRowsSQL: cicilan jika ada, selain itu registrasi_mahasiswa
+		where = append(where, `(EXISTS (
+				SELECT 1 FROM cicilans c
+				WHERE c.npm = customers.identifier AND c.tahun_id = budget_periods.kode AND c.kel_ukt = ?
+			) OR (
+				NOT EXISTS (SELECT 1 FROM cicilans c2 WHERE c2.npm = customers.identifier AND c2.tahun_id = budget_periods.kode)
+				AND EXISTS (
+					SELECT 1 FROM registrasi_mahasiswa rm
+					WHERE rm.npm = customers.identifier AND rm.tahun_id = budget_periods.kode AND rm.kel_ukt = ?
+				)
+			))`)
+		args = append(args, filter.KelUKT, filter.KelUKT)
+	}
+
+	var points []models.RevenuePoint
+	err := s.db.
+		Table("payments").
+		Select(period+" AS period, COUNT(payments.id) AS payments, CAST(COALESCE(SUM(payments.amount), 0) AS SIGNED) AS collected").
+		Joins("INNER JOIN invoices ON invoices.id = payments.invoice_id").
+		Joins("INNER JOIN customers ON customers.id = invoices.customer_id").
+		Joins("LEFT JOIN budget_periods ON budget_periods.id = invoices.budget_period_id").
+		Joins("LEFT JOIN mahasiswa_masters mm ON mm.student_id = customers.identifier AND mm.deleted_at IS NULL").
+		Joins("LEFT JOIN prodi p ON p.id = mm.prodi_id").
+		Where(strings.Join(where, " AND "), args...).
+		Group("period").
+		Order("period ASC").
+		Scan(&points).Error
+	if err != nil {
+		return nil, fmt.Errorf("gagal mengambil time-series pendapatan: %w", err)
+	}
+
+	if points == nil {
+		points = []models.RevenuePoint{}
+	}
+	return points, nil
+}
+
+func collectionRate(row models.RevenueRow) float64 {
+	billable := row.Expected - row.Scholarship
+	if billable <= 0 {
+		return 0
+	}
+	rate := float64(row.Collected) / float64(billable) * 100
+	return float64(int64(rate*100)) / 100
+}
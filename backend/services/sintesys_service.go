@@ -1,26 +1,28 @@
 package services
 
 import (
-	"crypto/tls"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"log"
 	"net/http"
-	"net/url"
 	"os"
 	"strconv"
+	"time"
 
 	"github.com/dedegunawan/backend-ujian-telp-v5/database"
+	"github.com/dedegunawan/backend-ujian-telp-v5/metrics"
 	"github.com/dedegunawan/backend-ujian-telp-v5/models"
 	"github.com/dedegunawan/backend-ujian-telp-v5/repositories"
+	"github.com/dedegunawan/backend-ujian-telp-v5/tracing"
 	"github.com/dedegunawan/backend-ujian-telp-v5/utils"
-	"github.com/go-resty/resty/v2"
+	"github.com/dedegunawan/backend-ujian-telp-v5/webhook"
 	"gorm.io/datatypes"
 )
 
 type Sintesys interface {
-	SendCallback(npm, tahun_id string, ukt string) error
+	SendCallback(ctx context.Context, npm, tahun_id string, ukt string) error
 	ScanNewCallback()
 }
 
@@ -33,48 +35,52 @@ func NewSintesys() Sintesys {
 	return &sintesys{AppUrl: os.Getenv("SINTESYS_CALLBACK_URL"), Token: os.Getenv("SINTESYS_TOKEN")}
 }
 
-func (s *sintesys) SendCallback(npm, tahun_id string, ukt string) error {
-	// Gunakan Resty
-	client := resty.New()
-
-	client.SetTLSClientConfig(&tls.Config{InsecureSkipVerify: true})
+// SendCallback memberi tahu Sintesys (form-urlencoded + Bearer token, format lama Sintesys).
+// Pengiriman HTTP memakai client yang sama dengan webhook; consumer lain cukup didaftarkan
+// sebagai WebhookSubscription dan menerima event bertanda tangan HMAC.
+func (s *sintesys) SendCallback(ctx context.Context, npm, tahun_id string, ukt string) error {
+	start := time.Now()
+	err := s.sendCallback(ctx, npm, tahun_id, ukt)
+	metrics.ObserveUpstream(metrics.UpstreamSintesys, "send_callback", start, err)
+	return err
+}
 
-	values := url.Values{}
-	values.Set("npm", npm)
-	values.Set("tahun_id", tahun_id)
-	if max_sks, isCapped := utils.MaxSKSFromUkt(ukt); isCapped {
-		values.Set("max_sks", strconv.Itoa(max_sks))
+func (s *sintesys) sendCallback(ctx context.Context, npm, tahun_id string, ukt string) error {
+	formBody := map[string]string{
+		"npm":      npm,
+		"tahun_id": tahun_id,
 	}
-
-	formBody := make(map[string]string)
-	for key, val := range values {
-		formBody[key] = val[0]
+	if max_sks, isCapped := utils.MaxSKSFromUkt(ukt); isCapped {
+		formBody["max_sks"] = strconv.Itoa(max_sks)
 	}
 
-	resp, err := client.R().
-		SetHeader("Content-Type", "application/x-www-form-urlencoded").
-		SetHeader("Accept", "application/json").
-		SetHeader("Authorization", "Bearer "+s.Token).
-		SetFormData(formBody).
-		Post(s.AppUrl)
+	resp, err := webhook.Post(ctx, webhook.Request{
+		URL:      s.AppUrl,
+		FormData: formBody,
+		Headers: map[string]string{
+			"Accept":        "application/json",
+			"Authorization": "Bearer " + s.Token,
+		},
+		InsecureSkipVerify: true,
+		Upstream:           tracing.UpstreamSintesys,
+	})
 
-	utils.Log.Info("Sintesys SendCallback", "npm : ", npm, " : ", resp)
+	utils.LogCtx(ctx).Infow("Sintesys SendCallback", "npm", npm, "status", resp.StatusCode)
 
 	// Tidak menyimpan ke database - hanya consume data dari DBPNBP (read-only)
 
 	if err != nil {
-		utils.Log.Infof("Error on send callback %v", err.Error())
-		return fmt.Errorf("gagal mengirim request: %w", err)
+		utils.LogCtx(ctx).Errorw("Error on send callback", "npm", npm, "error", err)
+		return err
 	}
 
-	if resp.StatusCode() != http.StatusOK {
-		utils.Log.Info(string(resp.Body()), " status code:", resp.StatusCode(), " url:", s.AppUrl)
-		utils.Log.Info("Response Body:", string(resp.Body()))
+	if resp.StatusCode != http.StatusOK {
+		utils.LogCtx(ctx).Warnw("Sintesys SendCallback ditolak", "npm", npm, "status", resp.StatusCode, "body", resp.Body)
 		return errors.New("gagal membuat request ke sintesys")
 	}
 
 	var result map[string]interface{}
-	if err := json.Unmarshal(resp.Body(), &result); err != nil {
+	if err := json.Unmarshal([]byte(resp.Body), &result); err != nil {
 		return fmt.Errorf("gagal parsing respons: %w", err)
 	}
 	return nil
@@ -154,12 +160,10 @@ func (s *sintesys) ExtractInvoiceID(body map[string]interface{}) (int, error) {
 func (s *sintesys) FindDataEncoded(request datatypes.JSON) (string, error) {
 	var requestMap map[string]interface{}
 	if err := json.Unmarshal(request, &requestMap); err != nil {
-		utils.Log.Info("Gagal unmarshal callback.Request:", err)
+		utils.Log.Errorw("Gagal unmarshal callback.Request", "error", err)
 		return "", fmt.Errorf("Gagal unmarshal callback.Request: %w", err)
 	}
 
-	utils.Log.Info("requestMap->Body :", requestMap["body"])
-
 	bodyRaw, ok := requestMap["body"]
 	if !ok {
 		utils.Log.Info("Field 'body' tidak ditemukan")
@@ -175,7 +179,6 @@ func (s *sintesys) FindDataEncoded(request datatypes.JSON) (string, error) {
 	// --- Ambil field 'data' jika ada ---
 	data, ok := bodyMap["data"]
 	if ok {
-		utils.Log.Info("DATA ditemukan:", data.(string))
 		return data.(string), nil
 	}
 
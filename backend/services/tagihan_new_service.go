@@ -96,7 +96,7 @@ func (s *tagihanNewService) GetTagihanMahasiswa(mahasiswa *models.Mahasiswa, fin
 	// - Semua tahun_id cicilan/registrasi untuk mahasiswa ini dengan tahun_id <= academicYear
 	tahunList, err := getAllRelevantAcademicYears(mhswID, academicYear)
 	if err != nil {
-		utils.Log.Error("Error mengambil daftar tahun_id tagihan", "error", err.Error(), "mhswID", mhswID, "academic_year", academicYear)
+		utils.Log.Errorw("Error mengambil daftar tahun_id tagihan", "error", err, "mhswID", mhswID, "academic_year", academicYear)
 		// Fallback: hanya tahun aktif
 		tahunList = []string{academicYear}
 	}
@@ -105,7 +105,7 @@ func (s *tagihanNewService) GetTagihanMahasiswa(mahasiswa *models.Mahasiswa, fin
 		// 1. Cek cicilan untuk tahun ini
 		hasCicilan, cicilanTagihan, err := s.getTagihanFromCicilan(mhswID, tahunID, financeYear)
 		if err != nil {
-			utils.Log.Error("Error mengambil tagihan dari cicilan", "error", err.Error(), "tahun_id", tahunID)
+			utils.Log.Errorw("Error mengambil tagihan dari cicilan", "error", err, "tahun_id", tahunID)
 			return nil, fmt.Errorf("gagal mengambil tagihan dari cicilan: %w", err)
 		}
 
@@ -120,7 +120,7 @@ func (s *tagihanNewService) GetTagihanMahasiswa(mahasiswa *models.Mahasiswa, fin
 		// 2. Jika tahun ini tidak punya cicilan sama sekali, ambil dari registrasi_mahasiswa
 		registrasiTagihan, err := s.getTagihanFromRegistrasi(mhswID, tahunID, financeYear)
 		if err != nil {
-			utils.Log.Error("Error mengambil tagihan dari registrasi", "error", err.Error(), "tahun_id", tahunID)
+			utils.Log.Errorw("Error mengambil tagihan dari registrasi", "error", err, "tahun_id", tahunID)
 			return nil, fmt.Errorf("gagal mengambil tagihan dari registrasi: %w", err)
 		}
 
@@ -243,7 +243,7 @@ func (s *tagihanNewService) getPaidAmountFromCicilan(detailCicilanID uint) int64
 		Scan(&total).Error
 	
 	if err != nil {
-		utils.Log.Info("Error saat ambil paid amount cicilan dari invoice_relations:", err)
+		utils.Log.Errorw("Error saat ambil paid amount cicilan dari invoice_relations", "error", err)
 		return 0
 	}
 	
@@ -377,7 +377,7 @@ func (s *tagihanNewService) GetTotalBeasiswa(npm string, tahunID string) int64 {
 		Scan(&total).Error
 
 	if err != nil {
-		utils.Log.Info("Error saat ambil total beasiswa:", err)
+		utils.Log.Errorw("Error saat ambil total beasiswa", "error", err)
 		return 0
 	}
 
@@ -394,7 +394,7 @@ func (s *tagihanNewService) GetHistoryTagihanMahasiswa(mahasiswa *models.Mahasis
 	// 1. Ambil riwayat dari registrasi_mahasiswa yang sudah paid/lunas atau nominal_bayar > 0
 	registrasiHistory, err := s.getHistoryFromRegistrasi(mhswID, academicYear, financeYear)
 	if err != nil {
-		utils.Log.Error("Error mengambil riwayat dari registrasi", "error", err.Error())
+		utils.Log.Errorw("Error mengambil riwayat dari registrasi", "error", err)
 		return nil, fmt.Errorf("gagal mengambil riwayat dari registrasi: %w", err)
 	}
 	historyList = append(historyList, registrasiHistory...)
@@ -402,7 +402,7 @@ func (s *tagihanNewService) GetHistoryTagihanMahasiswa(mahasiswa *models.Mahasis
 	// 2. Ambil riwayat dari detail_cicilan yang sudah paid atau paid_amount > 0
 	cicilanHistory, err := s.getHistoryFromCicilan(mhswID, academicYear, financeYear)
 	if err != nil {
-		utils.Log.Error("Error mengambil riwayat dari cicilan", "error", err.Error())
+		utils.Log.Errorw("Error mengambil riwayat dari cicilan", "error", err)
 		return nil, fmt.Errorf("gagal mengambil riwayat dari cicilan: %w", err)
 	}
 	historyList = append(historyList, cicilanHistory...)
@@ -51,7 +51,7 @@ func (r *tagihanService) GetNominalBeasiswa(studentId string, academicYear strin
 		Scan(&total).Error
 
 	if err != nil {
-		utils.Log.Info("Error saat ambil total nominal_beasiswa:", err)
+		utils.Log.Errorw("Error saat ambil total nominal_beasiswa", "error", err)
 		return 0
 	}
 
@@ -159,17 +159,17 @@ func (r *tagihanService) getBIPOTIDFromMahasiswaMasters(mhswID string) (string,
 	var mhswMaster models.MahasiswaMaster
 	err := database.DBPNBP.Preload("MasterTagihan").Where("student_id = ?", mhswID).First(&mhswMaster).Error
 	if err != nil {
-		utils.Log.Warn("Mahasiswa tidak ditemukan di mahasiswa_masters", "mhswID", mhswID, "error", err)
+		utils.Log.Warnw("Mahasiswa tidak ditemukan di mahasiswa_masters", "mhswID", mhswID, "error", err)
 		return "", fmt.Errorf("mahasiswa tidak ditemukan di mahasiswa_masters: %w", err)
 	}
 
 	if mhswMaster.MasterTagihanID == 0 {
-		utils.Log.Warn("MasterTagihanID = 0 untuk mahasiswa", "mhswID", mhswID)
+		utils.Log.Warnw("MasterTagihanID = 0 untuk mahasiswa", "mhswID", mhswID)
 		return "", fmt.Errorf("master_tagihan_id tidak ditemukan untuk mahasiswa %s (MasterTagihanID=0)", mhswID)
 	}
 
 	if mhswMaster.MasterTagihan == nil {
-		utils.Log.Warn("MasterTagihan nil untuk mahasiswa, mencoba load manual", "mhswID", mhswID, "MasterTagihanID", mhswMaster.MasterTagihanID)
+		utils.Log.Warnw("MasterTagihan nil untuk mahasiswa, mencoba load manual", "mhswID", mhswID, "MasterTagihanID", mhswMaster.MasterTagihanID)
 		// Coba load manual jika Preload gagal
 		var masterTagihan models.MasterTagihan
 		errLoad := database.DBPNBP.Where("id = ?", mhswMaster.MasterTagihanID).First(&masterTagihan).Error
@@ -180,54 +180,54 @@ func (r *tagihanService) getBIPOTIDFromMahasiswaMasters(mhswID string) (string,
 	}
 
 	if mhswMaster.MasterTagihan.BipotID == 0 {
-		utils.Log.Warn("BipotID = 0 di master_tagihan", "mhswID", mhswID, "MasterTagihanID", mhswMaster.MasterTagihanID)
+		utils.Log.Warnw("BipotID = 0 di master_tagihan", "mhswID", mhswID, "MasterTagihanID", mhswMaster.MasterTagihanID)
 		return "", fmt.Errorf("bipotid tidak ditemukan di master_tagihan untuk mahasiswa %s (BipotID=0)", mhswID)
 	}
 
 	BIPOTID := strconv.Itoa(int(mhswMaster.MasterTagihan.BipotID))
-	utils.Log.Info("BIPOTID diambil dari mahasiswa_masters", "mhswID", mhswID, "BIPOTID", BIPOTID, "MasterTagihanID", mhswMaster.MasterTagihanID)
+	utils.Log.Infow("BIPOTID diambil dari mahasiswa_masters", "mhswID", mhswID, "BIPOTID", BIPOTID, "MasterTagihanID", mhswMaster.MasterTagihanID)
 	return BIPOTID, nil
 }
 
 func (r *tagihanService) CreateNewTagihan(mahasiswa *models.Mahasiswa, financeYear *models.FinanceYear) error {
-	utils.Log.Info("CreateNewTagihan dimulai", map[string]interface{}{
-		"mhswID":       mahasiswa.MhswID,
-		"nama":         mahasiswa.Nama,
-		"BIPOTID":      mahasiswa.BIPOTID,
-		"UKT":          mahasiswa.UKT,
-		"academicYear": financeYear.AcademicYear,
-	})
+	utils.Log.Infow("CreateNewTagihan dimulai",
+		"mhswID", mahasiswa.MhswID,
+		"nama", mahasiswa.Nama,
+		"BIPOTID", mahasiswa.BIPOTID,
+		"UKT", mahasiswa.UKT,
+		"academicYear", financeYear.AcademicYear,
+	)
 
 	// interception: jika mahasiswa memiliki data cicilan generate dari cicilan tersebut
 	hasCicilan := r.GenerateCicilanMahasiswa(mahasiswa, financeYear)
 	if hasCicilan {
-		utils.Log.Info("Mahasiswa memiliki cicilan, generate dari cicilan", "mhswID", mahasiswa.MhswID)
+		utils.Log.Infow("Mahasiswa memiliki cicilan, generate dari cicilan", "mhswID", mahasiswa.MhswID)
 		return nil
 	}
 
 	// Ambil data dari mahasiswa_masters -> master_tagihan -> detail_tagihan
 	// JANGAN gunakan mahasiswa.BIPOTID atau mahasiswa.UKT, ambil langsung dari mahasiswa_masters
-	utils.Log.Info("Mengambil data dari mahasiswa_masters", "mhswID", mahasiswa.MhswID)
+	utils.Log.Infow("Mengambil data dari mahasiswa_masters", "mhswID", mahasiswa.MhswID)
 
 	var mhswMaster models.MahasiswaMaster
 	err := database.DBPNBP.Preload("MasterTagihan").Where("student_id = ?", mahasiswa.MhswID).First(&mhswMaster).Error
 	if err != nil {
-		utils.Log.Error("Mahasiswa tidak ditemukan di mahasiswa_masters", "mhswID", mahasiswa.MhswID, "error", err)
+		utils.Log.Errorw("Mahasiswa tidak ditemukan di mahasiswa_masters", "mhswID", mahasiswa.MhswID, "error", err)
 		return fmt.Errorf("mahasiswa tidak ditemukan di mahasiswa_masters untuk %s: %w", mahasiswa.MhswID, err)
 	}
 
 	if mhswMaster.MasterTagihanID == 0 {
-		utils.Log.Error("MasterTagihanID = 0 untuk mahasiswa", "mhswID", mahasiswa.MhswID)
+		utils.Log.Errorw("MasterTagihanID = 0 untuk mahasiswa", "mhswID", mahasiswa.MhswID)
 		return fmt.Errorf("master_tagihan_id tidak ditemukan untuk mahasiswa %s (MasterTagihanID=0)", mahasiswa.MhswID)
 	}
 
 	// Load master_tagihan jika belum ter-load
 	if mhswMaster.MasterTagihan == nil {
-		utils.Log.Info("MasterTagihan nil, mencoba load manual", "mhswID", mahasiswa.MhswID, "MasterTagihanID", mhswMaster.MasterTagihanID)
+		utils.Log.Infow("MasterTagihan nil, mencoba load manual", "mhswID", mahasiswa.MhswID, "MasterTagihanID", mhswMaster.MasterTagihanID)
 		var masterTagihan models.MasterTagihan
 		errLoad := database.DBPNBP.Where("id = ?", mhswMaster.MasterTagihanID).First(&masterTagihan).Error
 		if errLoad != nil {
-			utils.Log.Error("Gagal load master_tagihan", "mhswID", mahasiswa.MhswID, "MasterTagihanID", mhswMaster.MasterTagihanID, "error", errLoad)
+			utils.Log.Errorw("Gagal load master_tagihan", "mhswID", mahasiswa.MhswID, "MasterTagihanID", mhswMaster.MasterTagihanID, "error", errLoad)
 			return fmt.Errorf("gagal load master_tagihan untuk mahasiswa %s: %w", mahasiswa.MhswID, errLoad)
 		}
 		mhswMaster.MasterTagihan = &masterTagihan
@@ -236,17 +236,17 @@ func (r *tagihanService) CreateNewTagihan(mahasiswa *models.Mahasiswa, financeYe
 	// Catatan: BIPOTID tidak diperlukan lagi karena kita tidak menggunakan bill_template
 	// Langsung menggunakan detail_tagihan dari master_tagihan_id
 	if mhswMaster.MasterTagihan.BipotID == 0 {
-		utils.Log.Warn("BipotID = 0 di master_tagihan, lanjut tanpa BIPOTID (tidak diperlukan)", map[string]interface{}{
-			"mhswID":          mahasiswa.MhswID,
-			"MasterTagihanID": mhswMaster.MasterTagihanID,
-		})
+		utils.Log.Warnw("BipotID = 0 di master_tagihan, lanjut tanpa BIPOTID (tidak diperlukan)",
+			"mhswID", mahasiswa.MhswID,
+			"MasterTagihanID", mhswMaster.MasterTagihanID,
+		)
 	} else {
 		BIPOTID := strconv.Itoa(int(mhswMaster.MasterTagihan.BipotID))
-		utils.Log.Info("BIPOTID diambil dari master_tagihan (untuk referensi)", map[string]interface{}{
-			"mhswID":          mahasiswa.MhswID,
-			"BIPOTID":         BIPOTID,
-			"MasterTagihanID": mhswMaster.MasterTagihanID,
-		})
+		utils.Log.Infow("BIPOTID diambil dari master_tagihan (untuk referensi)",
+			"mhswID", mahasiswa.MhswID,
+			"BIPOTID", BIPOTID,
+			"MasterTagihanID", mhswMaster.MasterTagihanID,
+		)
 	}
 
 	// Ambil kelompok UKT dari mahasiswa_masters.ukt
@@ -291,33 +291,33 @@ func (r *tagihanService) CreateNewTagihan(mahasiswa *models.Mahasiswa, financeYe
 		// JANGAN fallback ke semua master_tagihan_id karena akan mengambil yang pertama (bisa salah)
 		// Gunakan nilai dari mahasiswa_masters sebagai string
 		UKT = strconv.Itoa(int(mhswMaster.UKT))
-		utils.Log.Warn("Kelompok UKT tidak ditemukan dengan format apapun, menggunakan nilai dari mahasiswa_masters", map[string]interface{}{
-			"mhswID":          mahasiswa.MhswID,
-			"UKT":             UKT,
-			"uktValue":        mhswMaster.UKT,
-			"masterTagihanID": mhswMaster.MasterTagihanID,
-			"error":           errDetail.Error(),
-			"triedFormats":    triedFormats,
-		})
+		utils.Log.Warnw("Kelompok UKT tidak ditemukan dengan format apapun, menggunakan nilai dari mahasiswa_masters",
+			"mhswID", mahasiswa.MhswID,
+			"UKT", UKT,
+			"uktValue", mhswMaster.UKT,
+			"masterTagihanID", mhswMaster.MasterTagihanID,
+			"error", errDetail,
+			"triedFormats", triedFormats,
+		)
 	} else {
-		utils.Log.Info("Kelompok UKT ditemukan dari detail_tagihan", map[string]interface{}{
-			"mhswID":               mahasiswa.MhswID,
-			"kelompokUKT":          UKT,
-			"uktValue":             mhswMaster.UKT,
-			"masterTagihanID":      mhswMaster.MasterTagihanID,
-			"detailTagihanID":      detailTagihan.ID,
-			"detailTagihanNominal": detailTagihan.Nominal,
-		})
+		utils.Log.Infow("Kelompok UKT ditemukan dari detail_tagihan",
+			"mhswID", mahasiswa.MhswID,
+			"kelompokUKT", UKT,
+			"uktValue", mhswMaster.UKT,
+			"masterTagihanID", mhswMaster.MasterTagihanID,
+			"detailTagihanID", detailTagihan.ID,
+			"detailTagihanNominal", detailTagihan.Nominal,
+		)
 	}
 
 	// Ambil semua detail_tagihan dari master_tagihan_id yang sesuai dengan UKT nominal
 	// JANGAN gunakan bill_template atau bill_template_items, langsung dari detail_tagihan
-	utils.Log.Info("Mencari detail_tagihan dari master_tagihan", map[string]interface{}{
-		"masterTagihanID": mhswMaster.MasterTagihanID,
-		"uktValue":        mhswMaster.UKT, // Ini adalah kelompok UKT, bukan nominal
-		"kelompokUKT":     UKT,
-		"mhswID":          mahasiswa.MhswID,
-	})
+	utils.Log.Infow("Mencari detail_tagihan dari master_tagihan",
+		"masterTagihanID", mhswMaster.MasterTagihanID,
+		"uktValue", mhswMaster.UKT,
+		"kelompokUKT", UKT,
+		"mhswID", mahasiswa.MhswID,
+	)
 
 	var detailTagihans []models.DetailTagihan
 	// Ambil semua detail_tagihan yang sesuai dengan master_tagihan_id dan kel_ukt
@@ -328,33 +328,33 @@ func (r *tagihanService) CreateNewTagihan(mahasiswa *models.Mahasiswa, financeYe
 		Find(&detailTagihans).Error
 
 	// Log query yang digunakan - PASTIKAN UKT sudah benar
-	utils.Log.Info("Query detail_tagihan untuk generate bill", map[string]interface{}{
-		"masterTagihanID": mhswMaster.MasterTagihanID,
-		"kelompokUKT":     UKT,
-		"uktValue":        mhswMaster.UKT,
-		"mhswID":          mahasiswa.MhswID,
-		"query":           fmt.Sprintf("master_tagihan_id = %d AND kel_ukt = '%s'", mhswMaster.MasterTagihanID, UKT),
-		"note":            "UKT ini harus match dengan kel_ukt di database untuk mendapatkan nominal yang benar",
-	})
+	utils.Log.Infow("Query detail_tagihan untuk generate bill",
+		"masterTagihanID", mhswMaster.MasterTagihanID,
+		"kelompokUKT", UKT,
+		"uktValue", mhswMaster.UKT,
+		"mhswID", mahasiswa.MhswID,
+		"query", fmt.Sprintf("master_tagihan_id = %d AND kel_ukt = '%s'", mhswMaster.MasterTagihanID, UKT),
+		"note", "UKT ini harus match dengan kel_ukt di database untuk mendapatkan nominal yang benar",
+	)
 
 	if errDetailList != nil {
-		utils.Log.Error("Gagal query detail_tagihan", map[string]interface{}{
-			"masterTagihanID": mhswMaster.MasterTagihanID,
-			"kelompokUKT":     UKT,
-			"error":           errDetailList.Error(),
-		})
+		utils.Log.Errorw("Gagal query detail_tagihan",
+			"masterTagihanID", mhswMaster.MasterTagihanID,
+			"kelompokUKT", UKT,
+			"error", errDetailList,
+		)
 		return fmt.Errorf("gagal query detail_tagihan untuk master_tagihan_id %d dan kel_ukt %s: %w", mhswMaster.MasterTagihanID, UKT, errDetailList)
 	}
 
 	// Jika tidak ditemukan dengan kel_ukt, jangan fallback ke semua master_tagihan_id
 	// karena itu akan menghasilkan semua tagihan, bukan hanya yang sesuai kelompok UKT
 	if len(detailTagihans) == 0 {
-		utils.Log.Error("Detail tagihan tidak ditemukan dengan kel_ukt yang sesuai", map[string]interface{}{
-			"masterTagihanID": mhswMaster.MasterTagihanID,
-			"kelompokUKT":     UKT,
-			"uktValue":        mhswMaster.UKT,
-			"mhswID":          mahasiswa.MhswID,
-		})
+		utils.Log.Errorw("Detail tagihan tidak ditemukan dengan kel_ukt yang sesuai",
+			"masterTagihanID", mhswMaster.MasterTagihanID,
+			"kelompokUKT", UKT,
+			"uktValue", mhswMaster.UKT,
+			"mhswID", mahasiswa.MhswID,
+		)
 		return fmt.Errorf("tidak ada detail_tagihan yang ditemukan untuk master_tagihan_id %d dan kel_ukt %s (mahasiswa %s)", mhswMaster.MasterTagihanID, UKT, mahasiswa.MhswID)
 	}
 
@@ -371,30 +371,31 @@ func (r *tagihanService) CreateNewTagihan(mahasiswa *models.Mahasiswa, financeYe
 			"Nominal":         dt.Nominal,
 		})
 		// Log individual untuk memastikan Nominal ter-populate
-		utils.Log.Info(fmt.Sprintf("Detail tagihan [%d] setelah query", i), map[string]interface{}{
-			"ID":              dt.ID,
-			"MasterTagihanID": dt.MasterTagihanID,
-			"KelUKT":          dt.KelUKT,
-			"Nama":            dt.Nama,
-			"Nominal":         dt.Nominal,
-		})
+		utils.Log.Infow("Detail tagihan setelah query",
+			"index", i,
+			"ID", dt.ID,
+			"MasterTagihanID", dt.MasterTagihanID,
+			"KelUKT", dt.KelUKT,
+			"Nama", dt.Nama,
+			"Nominal", dt.Nominal,
+		)
 	}
-	utils.Log.Info("Detail tagihan ditemukan", map[string]interface{}{
-		"count":           len(detailTagihans),
-		"masterTagihanID": mhswMaster.MasterTagihanID,
-		"kelompokUKT":     UKT,
-		"mhswID":          mahasiswa.MhswID,
-		"detailTagihans":  detailTagihansLog,
-	})
+	utils.Log.Infow("Detail tagihan ditemukan",
+		"count", len(detailTagihans),
+		"masterTagihanID", mhswMaster.MasterTagihanID,
+		"kelompokUKT", UKT,
+		"mhswID", mahasiswa.MhswID,
+		"detailTagihans", detailTagihansLog,
+	)
 
 	// Jika ada lebih dari 1 record, ambil hanya yang pertama (atau filter berdasarkan nama tertentu)
 	// Biasanya untuk UKT hanya ada 1 record per kelompok UKT
 	if len(detailTagihans) > 1 {
-		utils.Log.Warn("Ditemukan lebih dari 1 detail_tagihan, akan menggunakan yang pertama", map[string]interface{}{
-			"count":           len(detailTagihans),
-			"masterTagihanID": mhswMaster.MasterTagihanID,
-			"kelompokUKT":     UKT,
-		})
+		utils.Log.Warnw("Ditemukan lebih dari 1 detail_tagihan, akan menggunakan yang pertama",
+			"count", len(detailTagihans),
+			"masterTagihanID", mhswMaster.MasterTagihanID,
+			"kelompokUKT", UKT,
+		)
 		// Filter untuk mengambil hanya record dengan nama "UKT" atau "Uang Kuliah"
 		// Prioritas: 1. Nama mengandung "UKT", 2. Nama mengandung "UANG KULIAH", 3. Yang pertama
 		var filteredDetailTagihans []models.DetailTagihan
@@ -414,38 +415,38 @@ func (r *tagihanService) CreateNewTagihan(mahasiswa *models.Mahasiswa, financeYe
 		// Gunakan yang mengandung "UKT" jika ada, jika tidak gunakan "UANG KULIAH", jika tidak gunakan yang pertama
 		if len(uktOnlyDetailTagihans) > 0 {
 			detailTagihans = uktOnlyDetailTagihans
-			utils.Log.Info("Detail tagihan setelah filter (prioritas UKT)", map[string]interface{}{
-				"count":    len(detailTagihans),
-				"selected": detailTagihans[0].Nama,
-				"nominal":  detailTagihans[0].Nominal,
-			})
+			utils.Log.Infow("Detail tagihan setelah filter (prioritas UKT)",
+				"count", len(detailTagihans),
+				"selected", detailTagihans[0].Nama,
+				"nominal", detailTagihans[0].Nominal,
+			)
 		} else if len(filteredDetailTagihans) > 0 {
 			detailTagihans = filteredDetailTagihans
-			utils.Log.Info("Detail tagihan setelah filter (prioritas UANG KULIAH)", map[string]interface{}{
-				"count":    len(detailTagihans),
-				"selected": detailTagihans[0].Nama,
-				"nominal":  detailTagihans[0].Nominal,
-			})
+			utils.Log.Infow("Detail tagihan setelah filter (prioritas UANG KULIAH)",
+				"count", len(detailTagihans),
+				"selected", detailTagihans[0].Nama,
+				"nominal", detailTagihans[0].Nominal,
+			)
 		} else {
 			// Ambil yang pertama, tapi log warning
-			utils.Log.Warn("Tidak ada detail_tagihan dengan nama UKT atau UANG KULIAH, menggunakan yang pertama", map[string]interface{}{
-				"count":    len(detailTagihans),
-				"selected": detailTagihans[0].Nama,
-				"nominal":  detailTagihans[0].Nominal,
-				"allNames": func() []string {
+			utils.Log.Warnw("Tidak ada detail_tagihan dengan nama UKT atau UANG KULIAH, menggunakan yang pertama",
+				"count", len(detailTagihans),
+				"selected", detailTagihans[0].Nama,
+				"nominal", detailTagihans[0].Nominal,
+				"allNames", func() []string {
 					names := make([]string, len(detailTagihans))
 					for i, dt := range detailTagihans {
 						names[i] = dt.Nama
 					}
 					return names
 				}(),
-			})
+			)
 			detailTagihans = detailTagihans[:1] // Ambil hanya yang pertama
 		}
 	}
 
 	nominalBeasiswa := r.GetNominalBeasiswa(string(mahasiswa.MhswID), financeYear.AcademicYear)
-	utils.Log.Info("nominalBeasiswa:", nominalBeasiswa)
+	utils.Log.Infow("nominalBeasiswa", "nominal_beasiswa", nominalBeasiswa)
 
 	sisaBeasiswa := nominalBeasiswa
 	// Generate StudentBill langsung dari detail_tagihan
@@ -471,17 +472,17 @@ func (r *tagihanService) CreateNewTagihan(mahasiswa *models.Mahasiswa, financeYe
 
 			// Cek apakah Amount berbeda dengan yang seharusnya
 			if existingBill.Amount != nominalTagihanSeharusnya {
-				utils.Log.Warn("StudentBill sudah ada tapi Amount tidak sesuai, akan di-update", map[string]interface{}{
-					"mhswID":               mahasiswa.MhswID,
-					"academicYear":         financeYear.AcademicYear,
-					"name":                 dt.Nama,
-					"billID":               existingBill.ID,
-					"amountLama":           existingBill.Amount,
-					"amountBaru":           nominalTagihanSeharusnya,
-					"detailTagihanNominal": dt.Nominal,
-					"beasiswa":             existingBill.Beasiswa,
-					"paidAmount":           existingBill.PaidAmount,
-				})
+				utils.Log.Warnw("StudentBill sudah ada tapi Amount tidak sesuai, akan di-update",
+					"mhswID", mahasiswa.MhswID,
+					"academicYear", financeYear.AcademicYear,
+					"name", dt.Nama,
+					"billID", existingBill.ID,
+					"amountLama", existingBill.Amount,
+					"amountBaru", nominalTagihanSeharusnya,
+					"detailTagihanNominal", dt.Nominal,
+					"beasiswa", existingBill.Beasiswa,
+					"paidAmount", existingBill.PaidAmount,
+				)
 
 				// Update Amount dengan nilai yang benar dari detail_tagihan
 				updateData := map[string]interface{}{
@@ -492,40 +493,40 @@ func (r *tagihanService) CreateNewTagihan(mahasiswa *models.Mahasiswa, financeYe
 				// Ini untuk mencegah PaidAmount > Amount
 				if existingBill.PaidAmount > nominalTagihanSeharusnya {
 					updateData["paid_amount"] = nominalTagihanSeharusnya
-					utils.Log.Warn("PaidAmount lebih besar dari Amount baru, akan disesuaikan", map[string]interface{}{
-						"mhswID":         mahasiswa.MhswID,
-						"billID":         existingBill.ID,
-						"paidAmountLama": existingBill.PaidAmount,
-						"paidAmountBaru": nominalTagihanSeharusnya,
-					})
+					utils.Log.Warnw("PaidAmount lebih besar dari Amount baru, akan disesuaikan",
+						"mhswID", mahasiswa.MhswID,
+						"billID", existingBill.ID,
+						"paidAmountLama", existingBill.PaidAmount,
+						"paidAmountBaru", nominalTagihanSeharusnya,
+					)
 				}
 
 				errUpdate := r.repo.DB.Model(&existingBill).Updates(updateData).Error
 				if errUpdate != nil {
-					utils.Log.Error("Gagal update StudentBill", map[string]interface{}{
-						"mhswID": mahasiswa.MhswID,
-						"billID": existingBill.ID,
-						"error":  errUpdate.Error(),
-					})
+					utils.Log.Errorw("Gagal update StudentBill",
+						"mhswID", mahasiswa.MhswID,
+						"billID", existingBill.ID,
+						"error", errUpdate,
+					)
 				} else {
-					utils.Log.Info("StudentBill berhasil di-update dengan nominal yang benar", map[string]interface{}{
-						"mhswID":       mahasiswa.MhswID,
-						"academicYear": financeYear.AcademicYear,
-						"name":         dt.Nama,
-						"billID":       existingBill.ID,
-						"amountLama":   existingBill.Amount,
-						"amountBaru":   nominalTagihanSeharusnya,
-					})
+					utils.Log.Infow("StudentBill berhasil di-update dengan nominal yang benar",
+						"mhswID", mahasiswa.MhswID,
+						"academicYear", financeYear.AcademicYear,
+						"name", dt.Nama,
+						"billID", existingBill.ID,
+						"amountLama", existingBill.Amount,
+						"amountBaru", nominalTagihanSeharusnya,
+					)
 				}
 			} else {
-				utils.Log.Info("StudentBill sudah ada dan nominal sudah sesuai, skip", map[string]interface{}{
-					"mhswID":       mahasiswa.MhswID,
-					"academicYear": financeYear.AcademicYear,
-					"name":         dt.Nama,
-					"billID":       existingBill.ID,
-					"amount":       existingBill.Amount,
-					"beasiswa":     existingBill.Beasiswa,
-				})
+				utils.Log.Infow("StudentBill sudah ada dan nominal sudah sesuai, skip",
+					"mhswID", mahasiswa.MhswID,
+					"academicYear", financeYear.AcademicYear,
+					"name", dt.Nama,
+					"billID", existingBill.ID,
+					"amount", existingBill.Amount,
+					"beasiswa", existingBill.Beasiswa,
+				)
 			}
 			continue
 		}
@@ -541,28 +542,28 @@ func (r *tagihanService) CreateNewTagihan(mahasiswa *models.Mahasiswa, financeYe
 		isMatch := kelUKTStr == UKT
 
 		if !isMatch {
-			utils.Log.Warn("SKIP: kelUKT tidak match dengan yang dicari, detail_tagihan ini tidak akan digunakan", map[string]interface{}{
-				"mhswID":          mahasiswa.MhswID,
-				"kelUKTSearched":  UKT,
-				"kelUKTFound":     kelUKTStr,
-				"nominal":         dt.Nominal,
-				"detailTagihanID": dt.ID,
-			})
+			utils.Log.Warnw("SKIP: kelUKT tidak match dengan yang dicari, detail_tagihan ini tidak akan digunakan",
+				"mhswID", mahasiswa.MhswID,
+				"kelUKTSearched", UKT,
+				"kelUKTFound", kelUKTStr,
+				"nominal", dt.Nominal,
+				"detailTagihanID", dt.ID,
+			)
 			continue // Skip detail_tagihan yang tidak match
 		}
 
 		// Log detail tagihan yang akan digunakan untuk generate bill
-		utils.Log.Info("Menggunakan detail_tagihan untuk generate StudentBill", map[string]interface{}{
-			"mhswID":          mahasiswa.MhswID,
-			"detailTagihanID": dt.ID,
-			"nama":            dt.Nama,
-			"kelUKT":          kelUKTStr,
-			"kelUKTSearched":  UKT,
-			"isMatch":         true,
-			"nominal":         dt.Nominal,
-			"masterTagihanID": dt.MasterTagihanID,
-			"academicYear":    financeYear.AcademicYear,
-		})
+		utils.Log.Infow("Menggunakan detail_tagihan untuk generate StudentBill",
+			"mhswID", mahasiswa.MhswID,
+			"detailTagihanID", dt.ID,
+			"nama", dt.Nama,
+			"kelUKT", kelUKTStr,
+			"kelUKTSearched", UKT,
+			"isMatch", true,
+			"nominal", dt.Nominal,
+			"masterTagihanID", dt.MasterTagihanID,
+			"academicYear", financeYear.AcademicYear,
+		)
 
 		if sisaBeasiswa > 0 && sisaBeasiswa >= dt.Nominal {
 			sisaBeasiswa = sisaBeasiswa - dt.Nominal
@@ -586,24 +587,24 @@ func (r *tagihanService) CreateNewTagihan(mahasiswa *models.Mahasiswa, financeYe
 		}
 
 		if err := r.repo.DB.Create(&bill).Error; err != nil {
-			utils.Log.Error("Gagal membuat StudentBill", map[string]interface{}{
-				"mhswID":          mahasiswa.MhswID,
-				"detailTagihanID": dt.ID,
-				"nama":            dt.Nama,
-				"nominal":         dt.Nominal,
-				"error":           err.Error(),
-			})
+			utils.Log.Errorw("Gagal membuat StudentBill",
+				"mhswID", mahasiswa.MhswID,
+				"detailTagihanID", dt.ID,
+				"nama", dt.Nama,
+				"nominal", dt.Nominal,
+				"error", err,
+			)
 			return fmt.Errorf("gagal membuat tagihan mahasiswa dari detail_tagihan ID %d: %w", dt.ID, err)
 		}
 
-		utils.Log.Info("StudentBill berhasil dibuat dari detail_tagihan", map[string]interface{}{
-			"mhswID":          mahasiswa.MhswID,
-			"detailTagihanID": dt.ID,
-			"nama":            dt.Nama,
-			"nominal":         dt.Nominal,
-			"amount":          nominalTagihan,
-			"beasiswa":        nominalBeasiswaSaatIni,
-		})
+		utils.Log.Infow("StudentBill berhasil dibuat dari detail_tagihan",
+			"mhswID", mahasiswa.MhswID,
+			"detailTagihanID", dt.ID,
+			"nama", dt.Nama,
+			"nominal", dt.Nominal,
+			"amount", nominalTagihan,
+			"beasiswa", nominalBeasiswaSaatIni,
+		)
 	}
 
 	return nil
@@ -612,10 +613,10 @@ func (r *tagihanService) CreateNewTagihanPasca(mahasiswa *models.Mahasiswa, fina
 	mhswID := mahasiswa.MhswID
 	activeYear := financeYear.AcademicYear // Menggunakan AcademicYear sebagai tahun_id
 
-	utils.Log.Info("CreateNewTagihanPasca: Mengambil data tagihan dari registrasi_mahasiswa", map[string]interface{}{
-		"mhswID":     mhswID,
-		"activeYear": activeYear,
-	})
+	utils.Log.Infow("CreateNewTagihanPasca: Mengambil data tagihan dari registrasi_mahasiswa",
+		"mhswID", mhswID,
+		"activeYear", activeYear,
+	)
 
 	// Ambil data tagihan dari tabel registrasi_mahasiswa di database PNBP
 	// Filter: tahun_id = activeYear dan npm = MhswID
@@ -628,37 +629,37 @@ func (r *tagihanService) CreateNewTagihanPasca(mahasiswa *models.Mahasiswa, fina
 	}
 
 	if len(registrasiTagihan) == 0 {
-		utils.Log.Info("CreateNewTagihanPasca: Tidak ada data tagihan ditemukan", map[string]interface{}{
-			"mhswID":     mhswID,
-			"activeYear": activeYear,
-		})
+		utils.Log.Infow("CreateNewTagihanPasca: Tidak ada data tagihan ditemukan",
+			"mhswID", mhswID,
+			"activeYear", activeYear,
+		)
 		return fmt.Errorf("tidak ada data tagihan ditemukan untuk npm %s dengan tahun_id %s", mhswID, activeYear)
 	}
 
-	utils.Log.Info("CreateNewTagihanPasca: Data tagihan ditemukan", map[string]interface{}{
-		"mhswID":        mhswID,
-		"activeYear":    activeYear,
-		"jumlahTagihan": len(registrasiTagihan),
-	})
+	utils.Log.Infow("CreateNewTagihanPasca: Data tagihan ditemukan",
+		"mhswID", mhswID,
+		"activeYear", activeYear,
+		"jumlahTagihan", len(registrasiTagihan),
+	)
 
 	// Generate StudentBill berdasarkan data dari registrasi_mahasiswa
 	for _, reg := range registrasiTagihan {
 		// Skip jika sudah bayar atau nominal_ukt tidak ada
 		if reg.SudahBayar {
-			utils.Log.Info("CreateNewTagihanPasca: Skip tagihan yang sudah dibayar", map[string]interface{}{
-				"mhswID":     mhswID,
-				"activeYear": activeYear,
-				"id":         reg.ID,
-			})
+			utils.Log.Infow("CreateNewTagihanPasca: Skip tagihan yang sudah dibayar",
+				"mhswID", mhswID,
+				"activeYear", activeYear,
+				"id", reg.ID,
+			)
 			continue
 		}
 
 		if reg.NominalUKT == nil || *reg.NominalUKT == 0 {
-			utils.Log.Warn("CreateNewTagihanPasca: Skip tagihan dengan nominal_ukt kosong atau 0", map[string]interface{}{
-				"mhswID":     mhswID,
-				"activeYear": activeYear,
-				"id":         reg.ID,
-			})
+			utils.Log.Warnw("CreateNewTagihanPasca: Skip tagihan dengan nominal_ukt kosong atau 0",
+				"mhswID", mhswID,
+				"activeYear", activeYear,
+				"id", reg.ID,
+			)
 			continue
 		}
 
@@ -690,21 +691,21 @@ func (r *tagihanService) CreateNewTagihanPasca(mahasiswa *models.Mahasiswa, fina
 		}
 
 		if err := r.repo.DB.Create(&bill).Error; err != nil {
-			utils.Log.Error("CreateNewTagihanPasca: Gagal membuat tagihan", map[string]interface{}{
-				"mhswID":     mhswID,
-				"activeYear": activeYear,
-				"error":      err,
-			})
+			utils.Log.Errorw("CreateNewTagihanPasca: Gagal membuat tagihan",
+				"mhswID", mhswID,
+				"activeYear", activeYear,
+				"error", err,
+			)
 			return fmt.Errorf("gagal membuat tagihan mahasiswa: %w", err)
 		}
 
-		utils.Log.Info("CreateNewTagihanPasca: Tagihan berhasil dibuat", map[string]interface{}{
-			"mhswID":      mhswID,
-			"activeYear":  activeYear,
-			"namaTagihan": namaTagihan,
-			"nominal":     nominal,
-			"paidAmount":  paidAmount,
-		})
+		utils.Log.Infow("CreateNewTagihanPasca: Tagihan berhasil dibuat",
+			"mhswID", mhswID,
+			"activeYear", activeYear,
+			"namaTagihan", namaTagihan,
+			"nominal", nominal,
+			"paidAmount", paidAmount,
+		)
 	}
 
 	return nil
@@ -733,7 +734,7 @@ func getTahunIDFromMahasiswaMasters(mhswID string) string {
 
 	if mhswMaster.TahunMasuk > 0 {
 		TahunID := fmt.Sprintf("%d%d", mhswMaster.TahunMasuk, semesterMasuk)
-		utils.Log.Info("TahunID diambil dari mahasiswa_masters", "mhswID", mhswID, "TahunMasuk", mhswMaster.TahunMasuk, "SemesterMasukID", mhswMaster.SemesterMasukID, "TahunID", TahunID)
+		utils.Log.Infow("TahunID diambil dari mahasiswa_masters", "mhswID", mhswID, "TahunMasuk", mhswMaster.TahunMasuk, "SemesterMasukID", mhswMaster.SemesterMasukID, "TahunID", TahunID)
 		return TahunID
 	}
 
@@ -755,7 +756,7 @@ func getTahunIDFormParsed(mahasiswa *models.Mahasiswa) string {
 		case int:
 			TahunID = strconv.Itoa(v)
 		default:
-			utils.Log.Info("TahunID ditemukan tapi tipe tidak dikenali", "value", tahunRaw)
+			utils.Log.Infow("TahunID ditemukan tapi tipe tidak dikenali", "value", tahunRaw)
 			return ""
 		}
 		if TahunID != "" {
@@ -766,11 +767,11 @@ func getTahunIDFormParsed(mahasiswa *models.Mahasiswa) string {
 	// Fallback: coba ambil dari TahunMasuk jika ada
 	if tahunMasuk, ok := data["TahunMasuk"].(float64); ok {
 		TahunID := fmt.Sprintf("%.0f1", tahunMasuk) // Default semester 1
-		utils.Log.Info("TahunID dibuat dari TahunMasuk", "TahunMasuk", tahunMasuk, "TahunID", TahunID)
+		utils.Log.Infow("TahunID dibuat dari TahunMasuk", "TahunMasuk", tahunMasuk, "TahunID", TahunID)
 		return TahunID
 	}
 
-	utils.Log.Info("Field TahunID tidak ditemukan pada data mahasiswa", "data", data)
+	utils.Log.Infow("Field TahunID tidak ditemukan pada data mahasiswa", "data", data)
 	return ""
 
 }
@@ -780,10 +781,10 @@ func getTahunIDFormParsed(mahasiswa *models.Mahasiswa) string {
 // Contoh: budget_periods.kode = 20252, angkatan = 2024, semester masuk = 1 → semester = (2025 - 2024) * 2 + 2 - 1 + 1 = 4
 // Contoh: budget_periods.kode = 20252, angkatan = 2025, semester masuk = 1 → semester = (2025 - 2025) * 2 + 2 - 1 + 1 = 2
 func (r *tagihanService) HitungSemesterSaatIni(tahunIDAwal string, tahunIDSekarang string) (int, error) {
-	utils.Log.Info("HitungSemesterSaatIni", map[string]interface{}{
-		"tahunIDAwal":     tahunIDAwal,
-		"tahunIDSekarang": tahunIDSekarang,
-	})
+	utils.Log.Infow("HitungSemesterSaatIni",
+		"tahunIDAwal", tahunIDAwal,
+		"tahunIDSekarang", tahunIDSekarang,
+	)
 
 	if len(tahunIDSekarang) != 5 {
 		return 0, fmt.Errorf("format tahunIDSekarang tidak valid, harus 5 digit seperti 20251")
@@ -819,14 +820,14 @@ func (r *tagihanService) HitungSemesterSaatIni(tahunIDAwal string, tahunIDSekara
 	selisihTahun := tahunSekarang - tahunMasuk
 	semester := (selisihTahun * 2) + semesterSekarang - semesterMasuk + 1
 
-	utils.Log.Info("Perhitungan semester", map[string]interface{}{
-		"tahunMasuk":       tahunMasuk,
-		"semesterMasuk":    semesterMasuk,
-		"tahunSekarang":    tahunSekarang,
-		"semesterSekarang": semesterSekarang,
-		"selisihTahun":     selisihTahun,
-		"semester":         semester,
-	})
+	utils.Log.Infow("Perhitungan semester",
+		"tahunMasuk", tahunMasuk,
+		"semesterMasuk", semesterMasuk,
+		"tahunSekarang", tahunSekarang,
+		"semesterSekarang", semesterSekarang,
+		"selisihTahun", selisihTahun,
+		"semester", semester,
+	)
 
 	return semester, nil
 }
@@ -924,12 +925,12 @@ func (r *tagihanService) CekPenangguhanMahasiswa(mahasiswa *models.Mahasiswa, fi
 		Model(&models.DepositLedgerEntry{}).Count(&hasDepositDebitCount).Error
 
 	if err != nil {
-		utils.Log.Error("Error checking deposit debit count:", err)
+		utils.Log.Errorw("Error checking deposit debit count", "error", err)
 		return false
 
 	}
 
-	utils.Log.Info("Has Deposit Debit Count for Mahasiswa:", mhswID, "Finance Year:", financeCode, "Count:", hasDepositDebitCount)
+	utils.Log.Infow("Has Deposit Debit Count", "mhswID", mhswID, "finance_year", financeCode, "count", hasDepositDebitCount)
 
 	if hasDepositDebitCount > 0 {
 		return true
@@ -964,7 +965,7 @@ func (r *tagihanService) IsNominalDibayarLebihKecilSeharusnya(mahasiswa *models.
 
 	// ambil nominal tagihan yang sudah dibayar oleh mahasiswa
 	totalTagihanDibayar := r.repo.GetTotalStudentBill(mahasiswa.MhswID, financeYear.AcademicYear)
-	utils.Log.Info("Tagihan seharusnya:", tagihanSeharusnya, " Total tagihan dibayar:", totalTagihanDibayar)
+	utils.Log.Infow("Perbandingan tagihan", "tagihan_seharusnya", tagihanSeharusnya, "total_dibayar", totalTagihanDibayar)
 
 	return totalTagihanDibayar < tagihanSeharusnya, tagihanSeharusnya, totalTagihanDibayar
 }
@@ -981,7 +982,7 @@ func (r *tagihanService) CreateNewTagihanSekurangnya(mahasiswa *models.Mahasiswa
 		UpdatedAt:          time.Now(),
 	}
 
-	utils.Log.Info("Membuat tagihan mahasiswa dengan nominal kurang:", tagihanKurang)
+	utils.Log.Infow("Membuat tagihan mahasiswa dengan nominal kurang", "tagihan_kurang", tagihanKurang)
 
 	if err := r.repo.DB.Create(&studentBill).Error; err != nil {
 		return fmt.Errorf("gagal membuat tagihan mahasiswa: %w", err)
@@ -997,28 +998,26 @@ func (r *tagihanService) ValidateBillAmount(studentBill *models.StudentBill, mah
 	var mhswMaster models.MahasiswaMaster
 	errMhswMaster := database.DBPNBP.Where("student_id = ?", mahasiswa.MhswID).First(&mhswMaster).Error
 	if errMhswMaster != nil {
-		utils.Log.Warn("ValidateBillAmount: mahasiswa_masters tidak ditemukan", map[string]interface{}{
-			"mhswID": mahasiswa.MhswID,
-			"error":  errMhswMaster.Error(),
-		})
+		utils.Log.Warnw("ValidateBillAmount: mahasiswa_masters tidak ditemukan",
+			"mhswID", mahasiswa.MhswID,
+			"error", errMhswMaster,
+		)
 		// Jika tidak ditemukan, skip validasi (untuk kompatibilitas)
 		return nil
 	}
 
 	if mhswMaster.MasterTagihanID == 0 {
-		utils.Log.Warn("ValidateBillAmount: MasterTagihanID adalah 0", map[string]interface{}{
-			"mhswID": mahasiswa.MhswID,
-		})
+		utils.Log.Warnw("ValidateBillAmount: MasterTagihanID adalah 0", "mhswID", mahasiswa.MhswID)
 		return nil
 	}
 
 	// Ambil UKT dari mahasiswa
 	UKT := utils.GetStringFromAny(mahasiswa.UKT)
 	if UKT == "" || UKT == "0" {
-		utils.Log.Warn("ValidateBillAmount: UKT kosong atau 0", map[string]interface{}{
-			"mhswID": mahasiswa.MhswID,
-			"UKT":    UKT,
-		})
+		utils.Log.Warnw("ValidateBillAmount: UKT kosong atau 0",
+			"mhswID", mahasiswa.MhswID,
+			"UKT", UKT,
+		)
 		return nil
 	}
 
@@ -1036,13 +1035,13 @@ func (r *tagihanService) ValidateBillAmount(studentBill *models.StudentBill, mah
 			Find(&detailTagihans).Error
 
 		if errDetailList != nil || len(detailTagihans) == 0 {
-			utils.Log.Warn("ValidateBillAmount: detail_tagihan tidak ditemukan", map[string]interface{}{
-				"mhswID":          mahasiswa.MhswID,
-				"masterTagihanID": mhswMaster.MasterTagihanID,
-				"UKT":             UKT,
-				"billName":        studentBill.Name,
-				"error":           errDetail.Error(),
-			})
+			utils.Log.Warnw("ValidateBillAmount: detail_tagihan tidak ditemukan",
+				"mhswID", mahasiswa.MhswID,
+				"masterTagihanID", mhswMaster.MasterTagihanID,
+				"UKT", UKT,
+				"billName", studentBill.Name,
+				"error", errDetail,
+			)
 			// Jika tidak ditemukan, skip validasi (untuk kompatibilitas)
 			return nil
 		}
@@ -1063,25 +1062,25 @@ func (r *tagihanService) ValidateBillAmount(studentBill *models.StudentBill, mah
 
 	// Validasi: cek apakah Amount sesuai dengan yang seharusnya
 	if studentBill.Amount != nominalTagihanSeharusnya {
-		utils.Log.Warn("ValidateBillAmount: Amount tidak sesuai dengan detail_tagihan", map[string]interface{}{
-			"mhswID":               mahasiswa.MhswID,
-			"billID":               studentBill.ID,
-			"billName":             studentBill.Name,
-			"amountSaatIni":        studentBill.Amount,
-			"amountSeharusnya":     nominalTagihanSeharusnya,
-			"detailTagihanNominal": detailTagihan.Nominal,
-			"beasiswa":             studentBill.Beasiswa,
-		})
+		utils.Log.Warnw("ValidateBillAmount: Amount tidak sesuai dengan detail_tagihan",
+			"mhswID", mahasiswa.MhswID,
+			"billID", studentBill.ID,
+			"billName", studentBill.Name,
+			"amountSaatIni", studentBill.Amount,
+			"amountSeharusnya", nominalTagihanSeharusnya,
+			"detailTagihanNominal", detailTagihan.Nominal,
+			"beasiswa", studentBill.Beasiswa,
+		)
 		return fmt.Errorf("nominal tagihan tidak sesuai. Silakan klik 'Perbaiki Tagihan' untuk memperbarui tagihan")
 	}
 
-	utils.Log.Info("ValidateBillAmount: Amount sesuai dengan detail_tagihan", map[string]interface{}{
-		"mhswID":   mahasiswa.MhswID,
-		"billID":   studentBill.ID,
-		"billName": studentBill.Name,
-		"amount":   studentBill.Amount,
-		"beasiswa": studentBill.Beasiswa,
-	})
+	utils.Log.Infow("ValidateBillAmount: Amount sesuai dengan detail_tagihan",
+		"mhswID", mahasiswa.MhswID,
+		"billID", studentBill.ID,
+		"billName", studentBill.Name,
+		"amount", studentBill.Amount,
+		"beasiswa", studentBill.Beasiswa,
+	)
 
 	return nil
 }
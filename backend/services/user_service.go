@@ -49,7 +49,7 @@ func (s *UserService) GetOrCreateByEmail(ssoID, email, name string) (*models.Use
 				user.Name = name
 			}
 			if err := s.Repo.Update(user); err != nil {
-				utils.Log.Error("Failed to update user sso_id:", err)
+				utils.Log.Errorw("Failed to update user sso_id", "error", err)
 				// Tetap return user meskipun update gagal
 			}
 		}
@@ -136,12 +136,18 @@ func (s *UserService) UpdateUser(ID string, Name string, Email string, Password
 		}
 		user.Password = ptr(string(hash))
 	}
+	wasActive := user.IsActive
 	user.IsActive = IsActive
 
 	if err := s.Repo.Update(user); err != nil {
 		return nil, err
 	}
 
+	// User dinonaktifkan: cabut semua sesi yang masih berjalan
+	if wasActive && !IsActive {
+		s.revokeSessions(user)
+	}
+
 	if len(RoleIDs) > 0 {
 		if err := s.Repo.AssignRoles(user.ID.String(), RoleIDs); err != nil {
 			return nil, err
@@ -154,11 +160,30 @@ func (s *UserService) UpdateUser(ID string, Name string, Email string, Password
 func (s *UserService) DeleteUser(ID string) error {
 	user, _ := s.Repo.FindByID(ID)
 	if user != nil && user.ID.String() != "" {
-		return s.Repo.Delete(user)
+		if err := s.Repo.Delete(user); err != nil {
+			return err
+		}
+		s.revokeSessions(user)
 	}
 	return nil
 }
 
+func (s *UserService) revokeSessions(user *models.User) {
+	tokenService := NewUserTokenService(s.Repo.DB, nil)
+	count, err := tokenService.RevokeUser(user.ID, user.Email, models.RevokeReasonDeactivated)
+	if err != nil {
+		utils.Log.Errorw("Gagal mencabut sesi user",
+			"user_id", user.ID.String(),
+			"error", err,
+		)
+		return
+	}
+	utils.Log.Infow("Sesi user dicabut",
+		"user_id", user.ID.String(),
+		"count", count,
+	)
+}
+
 func ptr[T any](v T) *T {
 	return &v
 }
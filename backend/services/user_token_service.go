@@ -2,74 +2,314 @@ package services
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/dedegunawan/backend-ujian-telp-v5/auth"
 	"github.com/dedegunawan/backend-ujian-telp-v5/config"
+	"github.com/dedegunawan/backend-ujian-telp-v5/metrics"
 	"github.com/dedegunawan/backend-ujian-telp-v5/utils"
-	"time"
+	"golang.org/x/oauth2"
+	"gorm.io/gorm"
 
 	"github.com/dedegunawan/backend-ujian-telp-v5/models"
 	"github.com/dedegunawan/backend-ujian-telp-v5/repositories"
 	"github.com/google/uuid"
 )
 
+var (
+	ErrSessionNotFound = errors.New("sesi tidak ditemukan")
+	ErrSessionRevoked  = errors.New("sesi sudah dicabut")
+	ErrRefreshReuse    = errors.New("refresh token sudah pernah dipakai, sesi dicabut")
+	ErrRefreshExpired  = errors.New("refresh token sudah kedaluwarsa")
+)
+
 type UserTokenService struct {
 	Repo    *repositories.UserTokenRepository
 	Context context.Context
 }
 
+// NewUserTokenService service sesi dengan repository di db
+func NewUserTokenService(db *gorm.DB, ctx context.Context) *UserTokenService {
+	return &UserTokenService{Repo: &repositories.UserTokenRepository{DB: db}, Context: ctx}
+}
+
 // Simpan token dari login OIDC
 func (s *UserTokenService) SaveUserToken(userID uuid.UUID, accessToken, refreshToken, tokenType string, expiresAt time.Time) error {
 	token := &models.UserToken{
-		UserID:       userID,
-		AccessToken:  accessToken,
-		RefreshToken: refreshToken,
-		TokenType:    tokenType,
-		ExpiresAt:    expiresAt,
-		CreatedAt:    time.Now(),
-	}
-	json_, err := json.Marshal(&token)
-	utils.Log.Info(userID, "json token : ", string(json_), err)
+		UserID:          userID,
+		AccessTokenHash: utils.HashToken(accessToken),
+		TokenType:       tokenType,
+		ExpiresAt:       expiresAt,
+		CreatedAt:       time.Now(),
+	}
+	if refreshToken != "" {
+		token.RefreshTokenHash = utils.Ptr(utils.HashToken(refreshToken))
+	}
 	return s.Repo.Create(token)
 }
 
-// Simpan token dari login OIDC
-func (s *UserTokenService) SaveLoginUserToken(userID uuid.UUID, accessToken string) (*models.UserToken, error) {
-	refreshToken, err := utils.GenerateJWT(userID, "refresh", "refresh", config.GetDefaultRefreshTokenExpired())
+// Simpan token dari login internal (JWT aplikasi sendiri); refresh token mentah dikembalikan ke pemanggil
+func (s *UserTokenService) SaveLoginUserToken(user *models.User, accessToken string) (*models.UserToken, string, error) {
+	refreshExpiresAt := config.GetDefaultRefreshTokenExpired()
+	refreshToken, err := utils.GenerateRefreshJWT(user.ID, refreshExpiresAt)
 	if err != nil {
-		refreshToken = accessToken + "refresh"
+		return nil, "", err
+	}
+
+	token := &models.UserToken{
+		UserID:           user.ID,
+		Subject:          user.ID.String(),
+		Email:            user.Email,
+		AccessTokenHash:  utils.HashToken(accessToken),
+		RefreshTokenHash: utils.Ptr(utils.HashToken(refreshToken)),
+		TokenType:        "Bearer",
+		ExpiresAt:        config.GetDefaultTokenExpired(),
+		RefreshExpiresAt: &refreshExpiresAt,
+		CreatedAt:        time.Now(),
+		JwtType:          models.JWTTypeInternal,
+	}
+	s.applyClientInfo(token)
+	if err := s.Repo.Create(token); err != nil {
+		return nil, "", err
 	}
+	return token, refreshToken, nil
+}
 
-	tokenType := "Bearer"
-	expiresAt := time.Now().Add(1 * time.Hour)
+// CreateLocalSession menerbitkan JWT internal (diterima checkInternalToken) untuk login email/password
+func (s *UserTokenService) CreateLocalSession(user *models.User) (*oauth2.Token, *models.UserToken, error) {
+	expiresAt := config.GetDefaultTokenExpired()
+	accessToken, err := utils.GenerateJWT(user.ID, user.Email, user.Name, expiresAt)
+	if err != nil {
+		return nil, nil, err
+	}
+	session, refreshToken, err := s.SaveLoginUserToken(user, accessToken)
+	if err != nil {
+		return nil, nil, err
+	}
+	token := &oauth2.Token{AccessToken: accessToken, RefreshToken: refreshToken, TokenType: "Bearer", Expiry: expiresAt}
+	return token, session, nil
+}
+
+// CreateSession menyimpan sesi baru dari hasil login SSO
+func (s *UserTokenService) CreateSession(subject, email string, userID uuid.UUID, token *oauth2.Token) (*models.UserToken, error) {
+	row := s.newTokenRow(token)
+	row.Subject = subject
+	row.Email = email
+	row.UserID = userID
+	row.JwtType = models.JWTTypeKeycloak
+	if err := s.Repo.Create(row); err != nil {
+		return nil, err
+	}
+	return row, nil
+}
+
+func (s *UserTokenService) newTokenRow(token *oauth2.Token) *models.UserToken {
+	row := &models.UserToken{
+		AccessTokenHash: utils.HashToken(token.AccessToken),
+		TokenType:       token.TokenType,
+		ExpiresAt:       token.Expiry,
+		CreatedAt:       time.Now(),
+	}
+	if row.ExpiresAt.IsZero() {
+		row.ExpiresAt = config.GetDefaultTokenExpired()
+	}
+	if token.RefreshToken != "" {
+		row.RefreshTokenHash = utils.Ptr(utils.HashToken(token.RefreshToken))
+		refreshExpiresAt := config.GetDefaultRefreshTokenExpired()
+		if seconds, ok := token.Extra("refresh_expires_in").(float64); ok && seconds > 0 {
+			refreshExpiresAt = time.Now().Add(time.Duration(seconds) * time.Second)
+		}
+		row.RefreshExpiresAt = &refreshExpiresAt
+	}
+	s.applyClientInfo(row)
+	return row
+}
+
+// Refresh menukar refresh token ke provider OIDC dan merotasi sesi.
+// Refresh token yang sudah dirotasi tapi dipakai lagi dianggap bocor: seluruh sesi dicabut.
+// Rotasi diklaim dengan UPDATE bersyarat, jadi dari dua refresh bersamaan dengan token yang
+// sama hanya satu yang menang; yang lain diperlakukan sebagai pemakaian ulang.
+func (s *UserTokenService) Refresh(refreshToken string) (*oauth2.Token, *models.UserToken, error) {
+	refreshHash := utils.HashToken(refreshToken)
+	current, err := s.Repo.FindByRefreshTokenHash(refreshHash)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, ErrSessionNotFound
+		}
+		return nil, nil, err
+	}
+
+	if current.Revoked() {
+		if current.RevokedReason == models.RevokeReasonRotated {
+			s.revokeReusedSession(current)
+			return nil, nil, ErrRefreshReuse
+		}
+		return nil, nil, ErrSessionRevoked
+	}
+	if current.RefreshExpiresAt != nil && current.RefreshExpiresAt.Before(time.Now()) {
+		return nil, nil, ErrRefreshExpired
+	}
 
-	// Ambil data tambahan dari internal context/service
 	fingerprint := s.getFingerprintFromContext()
-	userAgent := s.getUserAgentFromContext()
-	var ipAddress *string
-	ip := s.getIPAddressFromContext()
-	if ip != "" {
-		ipAddress = &ip
+	if current.Fingerprint != "" && fingerprint != "" && current.Fingerprint != fingerprint {
+		utils.Log.Warnw("Fingerprint refresh berbeda dengan saat login",
+			"session_id", current.SessionID.String(),
+			"email", current.Email,
+		)
+	}
+
+	var newToken *oauth2.Token
+	if current.JwtType == models.JWTTypeInternal {
+		newToken, err = s.refreshInternal(current)
 	} else {
-		ipAddress = nil
+		newToken, err = auth.OAuth2Config.TokenSource(context.Background(), &oauth2.Token{RefreshToken: refreshToken}).Token()
+		if err != nil {
+			err = fmt.Errorf("refresh ke provider gagal: %w", err)
+		}
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	if newToken.RefreshToken == "" || newToken.RefreshToken == refreshToken {
+		// Provider tidak merotasi refresh token: pakai ulang untuk baris baru
+		newToken.RefreshToken = refreshToken
 	}
 
-	token := &models.UserToken{
-		UserID:       userID,
-		AccessToken:  accessToken,
-		RefreshToken: refreshToken,
-		TokenType:    tokenType,
-		ExpiresAt:    expiresAt,
-		CreatedAt:    time.Now(),
-		JwtType:      models.JWTTypeInternal,
-		Fingerprint:  fingerprint,
-		UserAgent:    userAgent,
-		IPAddress:    ipAddress,
-	}
-	err = s.Repo.Create(token)
+	next := s.newTokenRow(newToken)
+	next.SessionID = current.SessionID
+	next.UserID = current.UserID
+	next.Subject = current.Subject
+	next.Email = current.Email
+	next.JwtType = current.JwtType
+
+	now := time.Now()
+	claim := map[string]interface{}{
+		"revoked_at":     now,
+		"revoked_reason": models.RevokeReasonRotated,
+		"last_used_at":   now,
+	}
+	if newToken.RefreshToken == refreshToken {
+		// Hash refresh token unik per baris; baris lama dilepas dari hash-nya
+		claim["refresh_token_hash"] = nil
+	}
+
+	err = s.Repo.DB.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.UserToken{}).
+			Where("id = ? AND refresh_token_hash = ? AND revoked_at IS NULL", current.ID, refreshHash).
+			Updates(claim)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrRefreshReuse
+		}
+		if err := tx.Create(next).Error; err != nil {
+			return err
+		}
+		return tx.Model(&models.UserToken{}).Where("id = ?", current.ID).Update("replaced_by_id", next.ID).Error
+	})
+	if errors.Is(err, ErrRefreshReuse) {
+		s.revokeReusedSession(current)
+		return nil, nil, err
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	return newToken, next, nil
+}
+
+// revokeReusedSession mencabut seluruh keluarga sesi (semua hasil rotasi, termasuk yang baru
+// diterbitkan) karena refresh token-nya dipakai lebih dari sekali
+func (s *UserTokenService) revokeReusedSession(current *models.UserToken) {
+	utils.Log.Warnw("Refresh token lama dipakai ulang, sesi dicabut",
+		"session_id", current.SessionID.String(),
+		"email", current.Email,
+	)
+	if err := s.Repo.RevokeSession(current.SessionID, models.RevokeReasonReuse); err != nil {
+		utils.Log.Errorw("Gagal mencabut sesi setelah refresh token dipakai ulang",
+			"session_id", current.SessionID.String(),
+			"error", err,
+		)
+	}
+}
+
+// refreshInternal menerbitkan ulang JWT internal; user harus masih aktif
+func (s *UserTokenService) refreshInternal(current *models.UserToken) (*oauth2.Token, error) {
+	var user models.User
+	if err := s.Repo.DB.First(&user, "id = ?", current.UserID).Error; err != nil {
+		return nil, ErrSessionNotFound
+	}
+	if !user.IsActive {
+		s.Repo.RevokeSession(current.SessionID, models.RevokeReasonDeactivated)
+		return nil, ErrSessionRevoked
+	}
+
+	expiresAt := config.GetDefaultTokenExpired()
+	accessToken, err := utils.GenerateJWT(user.ID, user.Email, user.Name, expiresAt)
+	if err != nil {
+		return nil, err
+	}
+	refreshToken, err := utils.GenerateRefreshJWT(user.ID, config.GetDefaultRefreshTokenExpired())
 	if err != nil {
 		return nil, err
 	}
-	return token, nil
+	return &oauth2.Token{AccessToken: accessToken, RefreshToken: refreshToken, TokenType: "Bearer", Expiry: expiresAt}, nil
+}
+
+// RevokeAccessToken mencabut sesi pemilik access token (logout). Token yang tidak tercatat tidak
+// perlu dicatat: IsRevoked sudah menolak token tanpa baris sesi.
+func (s *UserTokenService) RevokeAccessToken(accessToken string, reason string) error {
+	row, err := s.Repo.FindByAccessTokenHash(utils.HashToken(accessToken))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+	return s.Repo.RevokeSession(row.SessionID, reason)
+}
+
+// RevokeUser mencabut semua sesi user (mis. saat user dinonaktifkan)
+func (s *UserTokenService) RevokeUser(userID uuid.UUID, email, reason string) (int64, error) {
+	if userID == uuid.Nil && email == "" {
+		return 0, nil
+	}
+	return s.Repo.RevokeByUser(userID, email, reason)
+}
+
+// IsRevoked dipakai middleware: true jika access token tidak tercatat sebagai sesi, sesinya
+// sudah dicabut, atau user lokal pemilik sesi sudah dinonaktifkan / dihapus. Semua token yang
+// diterbitkan aplikasi ini tercatat di user_tokens, jadi token tanpa baris sesi ditolak.
+func (s *UserTokenService) IsRevoked(accessToken string) (bool, error) {
+	row, err := s.Repo.FindByAccessTokenHash(utils.HashToken(accessToken))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return true, nil
+		}
+		return false, err
+	}
+	if row.Revoked() {
+		return true, nil
+	}
+
+	// Mahasiswa tidak ada di tabel users (UserID kosong); staf dicek status aktifnya
+	if row.UserID == uuid.Nil {
+		return false, nil
+	}
+	var user models.User
+	err = s.Repo.DB.Select("id", "is_active").First(&user, "id = ?", row.UserID).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return false, err
+	}
+	if err != nil || !user.IsActive {
+		if err := s.Repo.RevokeSession(row.SessionID, models.RevokeReasonDeactivated); err != nil {
+			return true, err
+		}
+		return true, nil
+	}
+	return false, nil
 }
 
 // Ambil token terbaru (misalnya untuk logout)
@@ -82,11 +322,46 @@ func (s *UserTokenService) CleanupExpiredTokens() error {
 	return s.Repo.DeleteExpiredTokens()
 }
 
+// StartCleanup menghapus sesi kedaluwarsa secara berkala; setiap refresh menambah baris
+// user_tokens baru sehingga tabel terus tumbuh tanpa pembersihan
+func (s *UserTokenService) StartCleanup(ctx context.Context, workerName string, interval time.Duration) {
+	utils.Log.Infof("[%s] Session cleanup started, interval %s", workerName, interval)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			utils.Log.Infof("[%s] Session cleanup stopped", workerName)
+			return
+		case <-ticker.C:
+			start := time.Now()
+			err := s.CleanupExpiredTokens()
+			metrics.ObserveWorkerRun(workerName, start, err)
+			if err != nil {
+				utils.Log.Errorf("[%s] Error cleaning up expired sessions: %v", workerName, err)
+			}
+		}
+	}
+}
+
 func (s *UserTokenService) SetContext(ctx context.Context) {
 	s.Context = ctx
 }
 
+func (s *UserTokenService) applyClientInfo(token *models.UserToken) {
+	token.Fingerprint = s.getFingerprintFromContext()
+	token.UserAgent = s.getUserAgentFromContext()
+	if ip := s.getIPAddressFromContext(); ip != "" {
+		token.IPAddress = &ip
+	}
+}
+
 func (s *UserTokenService) getFingerprintFromContext() string {
+	if s.Context == nil {
+		return ""
+	}
 	// misal ambil dari context middleware
 	if v, ok := s.Context.Value("fingerprint").(string); ok {
 		return v
@@ -95,6 +370,9 @@ func (s *UserTokenService) getFingerprintFromContext() string {
 }
 
 func (s *UserTokenService) getUserAgentFromContext() string {
+	if s.Context == nil {
+		return ""
+	}
 	if v, ok := s.Context.Value("user_agent").(string); ok {
 		return v
 	}
@@ -102,6 +380,9 @@ func (s *UserTokenService) getUserAgentFromContext() string {
 }
 
 func (s *UserTokenService) getIPAddressFromContext() string {
+	if s.Context == nil {
+		return ""
+	}
 	if v, ok := s.Context.Value("ip_address").(string); ok {
 		return v
 	}
@@ -0,0 +1,418 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dedegunawan/backend-ujian-telp-v5/database"
+	"github.com/dedegunawan/backend-ujian-telp-v5/models"
+	"github.com/dedegunawan/backend-ujian-telp-v5/utils"
+	"github.com/dedegunawan/backend-ujian-telp-v5/webhook"
+	"gorm.io/gorm"
+)
+
+const (
+	jobTypeDeliverWebhook     = "deliver_webhook"
+	webhookDefaultMaxAttempts = 8
+)
+
+// WebhookSubscriptionInput data create/update subscription
+type WebhookSubscriptionInput struct {
+	Name        string   `json:"name"`
+	URL         string   `json:"url"`
+	Secret      string   `json:"secret"` // Kosong = dibuatkan otomatis (hanya saat create)
+	EventTypes  []string `json:"event_types"`
+	Active      *bool    `json:"active"`
+	MaxAttempts int      `json:"max_attempts"`
+}
+
+// WebhookDeliveryFilter filter log delivery
+type WebhookDeliveryFilter struct {
+	SubscriptionID uint
+	Status         string
+	EventType      string
+	EventID        string
+	Limit          int
+}
+
+// WebhookService registry subscription dan pengiriman event ke sistem kampus lain.
+// Setiap delivery ditandatangani HMAC-SHA256 dan dikirim lewat JobQueue dengan retry + backoff.
+type WebhookService interface {
+	ListSubscriptions() ([]models.WebhookSubscription, error)
+	CreateSubscription(input WebhookSubscriptionInput, createdBy string) (*models.WebhookSubscription, string, error)
+	UpdateSubscription(id uint, input WebhookSubscriptionInput) (*models.WebhookSubscription, error)
+	DeleteSubscription(id uint) error
+	RotateSecret(id uint) (string, error)
+
+	Dispatch(eventType, eventID string, data map[string]interface{}) (int, error)
+	Deliver(deliveryID uint) error
+	Replay(deliveryID uint) (*models.WebhookDelivery, error)
+	ReplaySubscription(subscriptionID uint, since, until time.Time, eventType string) (int, error)
+	ListDeliveries(filter WebhookDeliveryFilter) ([]models.WebhookDelivery, error)
+}
+
+type webhookService struct {
+	db     *gorm.DB
+	worker WorkerService
+}
+
+func NewWebhookService(db *gorm.DB) WebhookService {
+	return &webhookService{db: db, worker: NewWorkerService(db)}
+}
+
+func generateWebhookSecret() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return "whsec_" + hex.EncodeToString(buf), nil
+}
+
+func validateWebhookInput(input WebhookSubscriptionInput) error {
+	if err := webhook.ValidateDestination(context.Background(), input.URL); err != nil {
+		return err
+	}
+	if len(input.EventTypes) == 0 {
+		return errors.New("event_types wajib diisi")
+	}
+	for _, t := range input.EventTypes {
+		if !webhook.IsKnownEvent(t) {
+			return fmt.Errorf("event type tidak dikenal: %s", t)
+		}
+	}
+	return nil
+}
+
+func (s *webhookService) ListSubscriptions() ([]models.WebhookSubscription, error) {
+	var rows []models.WebhookSubscription
+	err := s.db.Order("id ASC").Find(&rows).Error
+	return rows, err
+}
+
+// CreateSubscription mengembalikan secret dalam bentuk asli; setelah ini secret tidak pernah ditampilkan lagi
+func (s *webhookService) CreateSubscription(input WebhookSubscriptionInput, createdBy string) (*models.WebhookSubscription, string, error) {
+	if err := validateWebhookInput(input); err != nil {
+		return nil, "", err
+	}
+
+	secret := input.Secret
+	if secret == "" {
+		generated, err := generateWebhookSecret()
+		if err != nil {
+			return nil, "", err
+		}
+		secret = generated
+	}
+	maxAttempts := input.MaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = webhookDefaultMaxAttempts
+	}
+
+	row := models.WebhookSubscription{
+		Name:        input.Name,
+		URL:         input.URL,
+		Secret:      secret,
+		EventTypes:  strings.Join(input.EventTypes, ","),
+		Active:      input.Active == nil || *input.Active,
+		MaxAttempts: maxAttempts,
+		CreatedBy:   createdBy,
+	}
+	if err := s.db.Create(&row).Error; err != nil {
+		return nil, "", fmt.Errorf("gagal menyimpan subscription: %w", err)
+	}
+	return &row, secret, nil
+}
+
+func (s *webhookService) UpdateSubscription(id uint, input WebhookSubscriptionInput) (*models.WebhookSubscription, error) {
+	var row models.WebhookSubscription
+	if err := s.db.First(&row, id).Error; err != nil {
+		return nil, err
+	}
+	if err := validateWebhookInput(input); err != nil {
+		return nil, err
+	}
+
+	row.Name = input.Name
+	row.URL = input.URL
+	row.EventTypes = strings.Join(input.EventTypes, ",")
+	if input.Active != nil {
+		row.Active = *input.Active
+	}
+	if input.MaxAttempts > 0 {
+		row.MaxAttempts = input.MaxAttempts
+	}
+	if input.Secret != "" {
+		row.Secret = input.Secret
+	}
+	if err := s.db.Save(&row).Error; err != nil {
+		return nil, err
+	}
+	return &row, nil
+}
+
+// DeleteSubscription menghapus subscription; log delivery tetap disimpan
+func (s *webhookService) DeleteSubscription(id uint) error {
+	result := s.db.Delete(&models.WebhookSubscription{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (s *webhookService) RotateSecret(id uint) (string, error) {
+	var row models.WebhookSubscription
+	if err := s.db.First(&row, id).Error; err != nil {
+		return "", err
+	}
+	secret, err := generateWebhookSecret()
+	if err != nil {
+		return "", err
+	}
+	row.Secret = secret
+	if err := s.db.Save(&row).Error; err != nil {
+		return "", err
+	}
+	return secret, nil
+}
+
+// Dispatch membuat satu delivery per subscription aktif yang menerima eventType.
+// eventID harus stabil untuk event yang sama (mis. "payment.paid:123") agar tidak terkirim dua kali.
+func (s *webhookService) Dispatch(eventType, eventID string, data map[string]interface{}) (int, error) {
+	var subscriptions []models.WebhookSubscription
+	if err := s.db.Where("active = ?", true).Find(&subscriptions).Error; err != nil {
+		return 0, err
+	}
+
+	payload, err := webhook.Envelope{
+		ID:        eventID,
+		Type:      eventType,
+		CreatedAt: time.Now(),
+		Data:      data,
+	}.Marshal()
+	if err != nil {
+		return 0, err
+	}
+
+	created := 0
+	for _, sub := range subscriptions {
+		if !sub.Subscribes(eventType) {
+			continue
+		}
+
+		key := fmt.Sprintf("%d:%s", sub.ID, eventID)
+		row := models.WebhookDelivery{
+			SubscriptionID: sub.ID,
+			EventID:        eventID,
+			EventType:      eventType,
+			Payload:        string(payload),
+			DedupeKey:      &key,
+			Status:         models.WebhookDeliveryQueued,
+			MaxAttempts:    sub.MaxAttempts,
+		}
+		if err := s.enqueue(&row); err != nil {
+			// Unique index dedupe_key: sudah diantrekan watcher lain / replay yang berjalan bersamaan
+			if database.IsDuplicateKey(err) {
+				continue
+			}
+			return created, err
+		}
+		created++
+	}
+	return created, nil
+}
+
+func (s *webhookService) enqueue(row *models.WebhookDelivery) error {
+	if row.MaxAttempts <= 0 {
+		row.MaxAttempts = webhookDefaultMaxAttempts
+	}
+	if err := s.db.Create(row).Error; err != nil {
+		return fmt.Errorf("gagal menyimpan delivery webhook: %w", err)
+	}
+	if err := s.worker.EnqueueJob(jobTypeDeliverWebhook, map[string]interface{}{"delivery_id": row.ID}, 0); err != nil {
+		return fmt.Errorf("gagal mengantrekan delivery webhook: %w", err)
+	}
+	return nil
+}
+
+// Deliver dipanggil oleh WorkerService. Retry dijadwalkan sendiri dengan backoff eksponensial,
+// jadi job selalu selesai tanpa error kecuali gagal membaca/menyimpan ke database.
+func (s *webhookService) Deliver(deliveryID uint) error {
+	var row models.WebhookDelivery
+	if err := s.db.First(&row, deliveryID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+	if row.Status == models.WebhookDeliveryDelivered || row.Status == models.WebhookDeliveryFailed ||
+		row.Status == models.WebhookDeliverySkipped {
+		return nil
+	}
+
+	var sub models.WebhookSubscription
+	if err := s.db.First(&sub, row.SubscriptionID).Error; err != nil || !sub.Active {
+		reason := "subscription tidak aktif atau sudah dihapus"
+		row.Status = models.WebhookDeliverySkipped
+		row.LastError = &reason
+		return s.db.Save(&row).Error
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	timestamp := time.Now().Unix()
+	body := []byte(row.Payload)
+	row.Attempts++
+	result, err := webhook.Post(ctx, webhook.Request{
+		URL:        sub.URL,
+		Body:       body,
+		PublicOnly: true,
+		Headers: map[string]string{
+			webhook.HeaderID:        row.EventID,
+			webhook.HeaderEvent:     row.EventType,
+			webhook.HeaderTimestamp: strconv.FormatInt(timestamp, 10),
+			webhook.HeaderSignature: webhook.Sign(sub.Secret, timestamp, body),
+			"User-Agent":            "epnbp-webhook/1",
+		},
+	})
+	row.ResponseStatus = result.StatusCode
+	row.ResponseBody = result.Body
+	row.DurationMs = result.Duration.Milliseconds()
+	if err == nil && !result.Success() {
+		err = webhook.StatusError(result)
+	}
+
+	if err == nil {
+		now := time.Now()
+		row.Status = models.WebhookDeliveryDelivered
+		row.DeliveredAt = &now
+		row.NextAttemptAt = nil
+		row.LastError = nil
+		return s.db.Save(&row).Error
+	}
+
+	message := err.Error()
+	row.LastError = &message
+	// Tujuan internal tidak akan berubah jadi sah dengan retry; hentikan langsung
+	if row.Attempts >= row.MaxAttempts || errors.Is(err, webhook.ErrForbiddenDestination) {
+		row.Status = models.WebhookDeliveryFailed
+		row.NextAttemptAt = nil
+	} else {
+		delay := webhook.Backoff(row.Attempts)
+		next := time.Now().Add(delay)
+		row.Status = models.WebhookDeliveryRetrying
+		row.NextAttemptAt = &next
+		if err := s.worker.EnqueueJob(jobTypeDeliverWebhook, map[string]interface{}{"delivery_id": row.ID}, delay); err != nil {
+			return err
+		}
+	}
+
+	utils.Log.Warnw("Webhook gagal dikirim",
+		"delivery_id", row.ID,
+		"subscription_id", row.SubscriptionID,
+		"event_type", row.EventType,
+		"attempts", row.Attempts,
+		"error", message,
+	)
+	return s.db.Save(&row).Error
+}
+
+// Replay mengirim ulang payload yang sama sebagai delivery baru (event ID tetap, penerima bisa dedupe)
+func (s *webhookService) Replay(deliveryID uint) (*models.WebhookDelivery, error) {
+	var original models.WebhookDelivery
+	if err := s.db.First(&original, deliveryID).Error; err != nil {
+		return nil, err
+	}
+
+	var sub models.WebhookSubscription
+	if err := s.db.First(&sub, original.SubscriptionID).Error; err != nil {
+		return nil, fmt.Errorf("subscription sudah dihapus")
+	}
+
+	row := models.WebhookDelivery{
+		SubscriptionID: original.SubscriptionID,
+		EventID:        original.EventID,
+		EventType:      original.EventType,
+		Payload:        original.Payload,
+		ReplayOf:       &original.ID,
+		Status:         models.WebhookDeliveryQueued,
+		MaxAttempts:    sub.MaxAttempts,
+	}
+	if err := s.enqueue(&row); err != nil {
+		return nil, err
+	}
+	return &row, nil
+}
+
+// ReplaySubscription mengirim ulang semua event (delivery asli, bukan replay) dalam rentang waktu
+func (s *webhookService) ReplaySubscription(subscriptionID uint, since, until time.Time, eventType string) (int, error) {
+	var sub models.WebhookSubscription
+	if err := s.db.First(&sub, subscriptionID).Error; err != nil {
+		return 0, err
+	}
+
+	query := s.db.Where("subscription_id = ? AND replay_of IS NULL AND created_at >= ? AND created_at < ?", subscriptionID, since, until)
+	if eventType != "" {
+		query = query.Where("event_type = ?", eventType)
+	}
+	var originals []models.WebhookDelivery
+	if err := query.Order("id ASC").Find(&originals).Error; err != nil {
+		return 0, err
+	}
+
+	for i, original := range originals {
+		row := models.WebhookDelivery{
+			SubscriptionID: sub.ID,
+			EventID:        original.EventID,
+			EventType:      original.EventType,
+			Payload:        original.Payload,
+			ReplayOf:       &originals[i].ID,
+			Status:         models.WebhookDeliveryQueued,
+			MaxAttempts:    sub.MaxAttempts,
+		}
+		if err := s.enqueue(&row); err != nil {
+			return i, err
+		}
+	}
+	return len(originals), nil
+}
+
+func (s *webhookService) ListDeliveries(filter WebhookDeliveryFilter) ([]models.WebhookDelivery, error) {
+	query := s.db.Model(&models.WebhookDelivery{})
+	if filter.SubscriptionID > 0 {
+		query = query.Where("subscription_id = ?", filter.SubscriptionID)
+	}
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	if filter.EventType != "" {
+		query = query.Where("event_type = ?", filter.EventType)
+	}
+	if filter.EventID != "" {
+		query = query.Where("event_id = ?", filter.EventID)
+	}
+
+	var rows []models.WebhookDelivery
+	err := query.Order("id DESC").Limit(filter.Limit).Find(&rows).Error
+	return rows, err
+}
+
+func webhookDeliveryIDFromJob(job *models.JobQueue) (uint, error) {
+	var payload struct {
+		DeliveryID uint `json:"delivery_id"`
+	}
+	if err := json.Unmarshal(job.Payload, &payload); err != nil {
+		return 0, err
+	}
+	return payload.DeliveryID, nil
+}
@@ -0,0 +1,231 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/dedegunawan/backend-ujian-telp-v5/metrics"
+	"github.com/dedegunawan/backend-ujian-telp-v5/utils"
+	"github.com/dedegunawan/backend-ujian-telp-v5/webhook"
+	"gorm.io/gorm"
+)
+
+const webhookWatcherBatch = 200
+
+// WebhookWatcher memantau tabel EPNBP dan menerbitkan event webhook:
+// invoices baru -> bill.created, payments yang melunasi invoice -> payment.paid,
+// deposit_ledger_entries debit (penangguhan) yang sudah posted -> postponement.approved.
+// Posisi terakhir disimpan di notification_cursors dengan prefix "webhook_".
+type WebhookWatcher interface {
+	StartWorker(ctx context.Context, workerName string, interval time.Duration)
+	Poll() error
+}
+
+type webhookWatcher struct {
+	db      *gorm.DB
+	service WebhookService
+}
+
+func NewWebhookWatcher(db *gorm.DB) WebhookWatcher {
+	return &webhookWatcher{db: db, service: NewWebhookService(db)}
+}
+
+func (w *webhookWatcher) StartWorker(ctx context.Context, workerName string, interval time.Duration) {
+	utils.Log.Infof("[%s] Webhook watcher started, interval %s", workerName, interval)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			utils.Log.Infof("[%s] Webhook watcher stopped", workerName)
+			return
+		case <-ticker.C:
+			start := time.Now()
+			err := w.Poll()
+			metrics.ObserveWorkerRun(workerName, start, err)
+			if err != nil {
+				utils.Log.Errorf("[%s] Error polling webhook events: %v", workerName, err)
+			}
+		}
+	}
+}
+
+func (w *webhookWatcher) Poll() error {
+	sources := []struct {
+		cursor string
+		table  string
+		poll   func(lastID uint) (uint, error)
+	}{
+		{"webhook_invoices", "invoices", w.pollInvoices},
+		{"webhook_payments", "payments", w.pollPayments},
+		{"webhook_postponements", "deposit_ledger_entries", w.pollPostponements},
+	}
+
+	for _, source := range sources {
+		cursor, fresh, err := loadEventCursor(w.db, source.cursor, source.table)
+		if err != nil {
+			return err
+		}
+		if fresh {
+			// Run pertama: mulai dari posisi sekarang, data lama tidak dikirim
+			continue
+		}
+
+		lastID, err := source.poll(cursor.LastID)
+		if err != nil {
+			return fmt.Errorf("gagal membaca %s: %w", source.table, err)
+		}
+		if lastID != cursor.LastID {
+			cursor.LastID = lastID
+			if err := w.db.Save(cursor).Error; err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (w *webhookWatcher) dispatch(eventType, eventID string, data map[string]interface{}) {
+	if _, err := w.service.Dispatch(eventType, eventID, data); err != nil {
+		utils.Log.Errorw("WebhookWatcher: gagal dispatch",
+			"event_type", eventType,
+			"event_id", eventID,
+			"error", err,
+		)
+	}
+}
+
+func (w *webhookWatcher) pollInvoices(lastID uint) (uint, error) {
+	var rows []struct {
+		ID          uint       `gorm:"column:id"`
+		NPM         string     `gorm:"column:npm"`
+		TahunID     string     `gorm:"column:tahun_id"`
+		TotalAmount int64      `gorm:"column:total_amount"`
+		DueDate     *time.Time `gorm:"column:due_date"`
+		CreatedAt   time.Time  `gorm:"column:created_at"`
+	}
+	err := w.db.Table("invoices").
+		Select(`invoices.id, customers.identifier AS npm, COALESCE(budget_periods.kode, '') AS tahun_id,
+			CAST(invoices.total_amount AS SIGNED) AS total_amount, budget_periods.payment_end_date AS due_date, invoices.created_at`).
+		Joins("INNER JOIN customers ON customers.id = invoices.customer_id").
+		Joins("LEFT JOIN budget_periods ON budget_periods.id = invoices.budget_period_id").
+		Where("invoices.id > ?", lastID).
+		Order("invoices.id ASC").
+		Limit(webhookWatcherBatch).
+		Scan(&rows).Error
+	if err != nil {
+		return lastID, err
+	}
+
+	for _, row := range rows {
+		data := map[string]interface{}{
+			"invoice_id":   row.ID,
+			"npm":          row.NPM,
+			"tahun_id":     row.TahunID,
+			"total_amount": row.TotalAmount,
+			"created_at":   row.CreatedAt.Format(time.RFC3339),
+		}
+		if row.DueDate != nil {
+			data["due_date"] = row.DueDate.Format("2006-01-02")
+		}
+		w.dispatch(webhook.EventBillCreated, fmt.Sprintf("%s:%d", webhook.EventBillCreated, row.ID), data)
+		lastID = row.ID
+	}
+	return lastID, nil
+}
+
+// pollPayments hanya mengirim payment.paid saat invoice sudah lunas; event ID per invoice
+// sehingga cicilan pembayaran berikutnya pada invoice yang sama tidak mengirim ulang
+func (w *webhookWatcher) pollPayments(lastID uint) (uint, error) {
+	var rows []struct {
+		ID        uint `gorm:"column:id"`
+		InvoiceID uint `gorm:"column:invoice_id"`
+	}
+	err := w.db.Table("payments").
+		Select("id, invoice_id").
+		Where("id > ?", lastID).
+		Order("id ASC").
+		Limit(webhookWatcherBatch).
+		Scan(&rows).Error
+	if err != nil {
+		return lastID, err
+	}
+
+	for _, row := range rows {
+		lastID = row.ID
+		status, err := loadInvoiceStatus(w.db, row.InvoiceID)
+		if err != nil {
+			utils.Log.Warnw("WebhookWatcher: invoice pembayaran tidak ditemukan",
+				"payment_id", row.ID,
+				"invoice_id", row.InvoiceID,
+			)
+			continue
+		}
+		if !status.IsPaid() {
+			continue
+		}
+
+		data := map[string]interface{}{
+			"invoice_id":      status.InvoiceID,
+			"payment_id":      row.ID,
+			"npm":             status.NPM,
+			"tahun_id":        status.TahunID,
+			"total_amount":    status.TotalAmount,
+			"paid_amount":     status.PaidAmount,
+			"virtual_account": status.VirtualAccount,
+		}
+		if status.PaidAt != nil {
+			data["paid_at"] = status.PaidAt.Format(time.RFC3339)
+		}
+		w.dispatch(webhook.EventPaymentPaid, fmt.Sprintf("%s:%d", webhook.EventPaymentPaid, status.InvoiceID), data)
+	}
+	return lastID, nil
+}
+
+// pollPostponements penangguhan dicatat EPNBP sebagai debit deposit (lihat CekPenangguhanMahasiswa)
+func (w *webhookWatcher) pollPostponements(lastID uint) (uint, error) {
+	var rows []struct {
+		ID          uint       `gorm:"column:id"`
+		NPM         string     `gorm:"column:npm"`
+		TahunID     string     `gorm:"column:tahun_id"`
+		Amount      int64      `gorm:"column:amount"`
+		Direction   string     `gorm:"column:direction"`
+		Status      string     `gorm:"column:status"`
+		PostedAt    *time.Time `gorm:"column:posted_at"`
+		ReferenceNo string     `gorm:"column:reference_no"`
+		ReasonCode  string     `gorm:"column:reason_code"`
+	}
+	err := w.db.Table("deposit_ledger_entries").
+		Select("id, npm, tahun_id, amount, direction, status, posted_at, reference_no, reason_code").
+		Where("id > ?", lastID).
+		Order("id ASC").
+		Limit(webhookWatcherBatch).
+		Scan(&rows).Error
+	if err != nil {
+		return lastID, err
+	}
+
+	for _, row := range rows {
+		lastID = row.ID
+		if row.Direction != "debit" || row.Status != "posted" {
+			continue
+		}
+
+		data := map[string]interface{}{
+			"ledger_entry_id": row.ID,
+			"npm":             row.NPM,
+			"tahun_id":        row.TahunID,
+			"amount":          row.Amount,
+			"reference_no":    row.ReferenceNo,
+			"reason_code":     row.ReasonCode,
+		}
+		if row.PostedAt != nil {
+			data["approved_at"] = row.PostedAt.Format(time.RFC3339)
+		}
+		w.dispatch(webhook.EventPostponementApproved, fmt.Sprintf("%s:%d", webhook.EventPostponementApproved, row.ID), data)
+	}
+	return lastID, nil
+}
@@ -1,19 +1,22 @@
 package services
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"github.com/dedegunawan/backend-ujian-telp-v5/metrics"
 	"github.com/dedegunawan/backend-ujian-telp-v5/models"
 	"github.com/dedegunawan/backend-ujian-telp-v5/utils"
 	"gorm.io/datatypes"
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
 	"log"
+	"sync"
 	"time"
 )
 
 type WorkerService interface {
-	StartWorker(workerName string)
+	StartWorker(ctx context.Context, workerName string)
 	ProcessJob(job *models.JobQueue) error
 	EnqueueJob(jobType string, payload interface{}, delay time.Duration) error
 }
@@ -26,9 +29,20 @@ func NewWorkerService(db *gorm.DB) WorkerService {
 	return &workerService{db: db}
 }
 
-func (ws *workerService) StartWorker(workerName string) {
+// StartWorker mengambil job sampai ctx selesai, lalu menunggu job yang sedang diproses sebelum kembali
+func (ws *workerService) StartWorker(ctx context.Context, workerName string) {
 	db := ws.db
+	var inFlight sync.WaitGroup
+	defer func() {
+		inFlight.Wait()
+		utils.Log.Infof("[%s] Job worker stopped", workerName)
+	}()
+
 	for {
+		if ctx.Err() != nil {
+			return
+		}
+
 		tx := db.Begin()
 
 		var job models.JobQueue
@@ -41,7 +55,7 @@ func (ws *workerService) StartWorker(workerName string) {
 
 		if err != nil {
 			tx.Rollback()
-			time.Sleep(1 * time.Second)
+			sleepContext(ctx, 1*time.Second)
 			continue
 		}
 
@@ -50,9 +64,13 @@ func (ws *workerService) StartWorker(workerName string) {
 		tx.Save(&job)
 		tx.Commit()
 
+		inFlight.Add(1)
 		go func(job models.JobQueue) {
+			defer inFlight.Done()
 			log.Printf("[%s] Memproses job #%d - %s\n", workerName, job.ID, job.Type)
-			err := ws.ProcessJob(&job)
+			start := time.Now()
+			err := ws.processJobSafely(&job)
+			metrics.ObserveJob(job.Type, start, err)
 			if err != nil {
 				job.Retries++
 				job.LastError = utils.Ptr(err.Error())
@@ -69,7 +87,28 @@ func (ws *workerService) StartWorker(workerName string) {
 			db.Save(&job)
 		}(job)
 
-		time.Sleep(200 * time.Millisecond) // tunggu sebentar sebelum ambil job lagi
+		sleepContext(ctx, 200*time.Millisecond) // tunggu sebentar sebelum ambil job lagi
+	}
+}
+
+// processJobSafely seperti ProcessJob, tapi panic di handler job dicatat sebagai error job
+// (dijadwalkan ulang / failed) alih-alih menghentikan seluruh proses API
+func (ws *workerService) processJobSafely(job *models.JobQueue) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic saat memproses job %s: %v", job.Type, r)
+		}
+	}()
+	return ws.ProcessJob(job)
+}
+
+// sleepContext seperti time.Sleep tapi langsung kembali saat ctx selesai
+func sleepContext(ctx context.Context, d time.Duration) {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+	case <-timer.C:
 	}
 }
 
@@ -84,6 +123,24 @@ func (ws *workerService) ProcessJob(job *models.JobQueue) error {
 		// Kirim email...
 		log.Println("Mengirim email ke:", email)
 		return nil
+	case jobTypeSendNotification:
+		notificationID, err := notificationIDFromJob(job)
+		if err != nil {
+			return err
+		}
+		return NewNotificationService(ws.db).Deliver(notificationID)
+	case jobTypeDeliverWebhook:
+		deliveryID, err := webhookDeliveryIDFromJob(job)
+		if err != nil {
+			return err
+		}
+		return NewWebhookService(ws.db).Deliver(deliveryID)
+	case jobTypeRunExport:
+		exportID, err := exportIDFromJob(job)
+		if err != nil {
+			return err
+		}
+		return NewExportService(ws.db).Run(exportID)
 	default:
 		return fmt.Errorf("unknown job type: %s", job.Type)
 	}
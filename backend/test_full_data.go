@@ -30,9 +30,7 @@ func main() {
 	utils.Log.Info("=== Memanggil CreateFromMasterMahasiswa ===")
 	err := mahasiswaService.CreateFromMasterMahasiswa(npm)
 	if err != nil {
-		utils.Log.Error("Error CreateFromMasterMahasiswa", map[string]interface{}{
-			"error": err.Error(),
-		})
+		utils.Log.Errorw("Error CreateFromMasterMahasiswa", "error", err)
 		return
 	}
 
@@ -40,10 +38,10 @@ func main() {
 	var mahasiswa models.Mahasiswa
 	err = database.DB.Where("mhsw_id = ?", npm).First(&mahasiswa).Error
 	if err != nil {
-		utils.Log.Error("Mahasiswa tidak ditemukan di database lokal", map[string]interface{}{
-			"npm":   npm,
-			"error": err.Error(),
-		})
+		utils.Log.Errorw("Mahasiswa tidak ditemukan di database lokal",
+			"npm", npm,
+			"error", err,
+		)
 		return
 	}
 
@@ -52,9 +50,7 @@ func main() {
 	if mahasiswa.FullData != "" {
 		err = json.Unmarshal([]byte(mahasiswa.FullData), &fullDataMap)
 		if err != nil {
-			utils.Log.Error("Gagal parse FullData", map[string]interface{}{
-				"error": err.Error(),
-			})
+			utils.Log.Errorw("Gagal parse FullData", "error", err)
 			return
 		}
 	}
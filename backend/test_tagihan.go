@@ -26,10 +26,10 @@ func main() {
 	var mahasiswa models.Mahasiswa
 	err := database.DB.Where("mhsw_id = ?", npm).First(&mahasiswa).Error
 	if err != nil {
-		utils.Log.Error("Mahasiswa tidak ditemukan di database lokal", map[string]interface{}{
-			"npm":   npm,
-			"error": err.Error(),
-		})
+		utils.Log.Errorw("Mahasiswa tidak ditemukan di database lokal",
+			"npm", npm,
+			"error", err,
+		)
 		return
 	}
 
@@ -38,10 +38,10 @@ func main() {
 	mahasiswaService := services.NewMahasiswaService(mahasiswaRepo)
 	err = mahasiswaService.CreateFromMasterMahasiswa(npm)
 	if err != nil {
-		utils.Log.Error("Gagal sync dari mahasiswa_masters", map[string]interface{}{
-			"npm":   npm,
-			"error": err.Error(),
-		})
+		utils.Log.Errorw("Gagal sync dari mahasiswa_masters",
+			"npm", npm,
+			"error", err,
+		)
 		return
 	}
 
@@ -55,10 +55,10 @@ func main() {
 	var mhswMaster models.MahasiswaMaster
 	err = database.DBPNBP.Preload("MasterTagihan").Where("student_id = ?", npm).First(&mhswMaster).Error
 	if err != nil {
-		utils.Log.Error("Mahasiswa tidak ditemukan di mahasiswa_masters", map[string]interface{}{
-			"npm":   npm,
-			"error": err.Error(),
-		})
+		utils.Log.Errorw("Mahasiswa tidak ditemukan di mahasiswa_masters",
+			"npm", npm,
+			"error", err,
+		)
 		return
 	}
 
@@ -0,0 +1,102 @@
+// Package tracing OpenTelemetry: span untuk handler gin, query GORM dan HTTP keluar (EPNBP, Sintesys, MinIO)
+// dengan propagasi W3C trace context. Seperti metrics, package ini tidak bergantung ke utils.
+package tracing
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	instrumentationName = "github.com/dedegunawan/backend-ujian-telp-v5"
+	defaultServiceName  = "epnbp-backend"
+)
+
+// Nama upstream untuk span HTTP keluar
+const (
+	UpstreamEpnbp    = "epnbp"
+	UpstreamSintesys = "sintesys"
+	UpstreamWebhook  = "webhook"
+	UpstreamMinio    = "minio"
+)
+
+// Init memasang TracerProvider global sesuai OTEL_TRACES_EXPORTER:
+//   - otlp: kirim ke collector (OTEL_EXPORTER_OTLP_ENDPOINT, default http://localhost:4318)
+//   - stdout/console: cetak span ke stdout untuk lokal
+//   - none/kosong: tracing mati, hanya propagasi traceparent yang tetap jalan
+//
+// Sampling mengikuti OTEL_TRACES_SAMPLER/OTEL_TRACES_SAMPLER_ARG, nama service OTEL_SERVICE_NAME.
+// Fungsi shutdown yang dikembalikan mem-flush span tersisa.
+func Init(ctx context.Context) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	exporter, err := newExporter(ctx, strings.ToLower(os.Getenv("OTEL_TRACES_EXPORTER")))
+	if err != nil || exporter == nil {
+		return func(context.Context) error { return nil }, err
+	}
+
+	res, err := resource.New(ctx,
+		resource.WithAttributes(semconv.ServiceName(defaultServiceName)),
+		resource.WithFromEnv(),
+		resource.WithTelemetrySDK(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("gagal membuat resource tracing: %w", err)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+	)
+	otel.SetTracerProvider(provider)
+	return provider.Shutdown, nil
+}
+
+func newExporter(ctx context.Context, name string) (sdktrace.SpanExporter, error) {
+	switch name {
+	case "", "none":
+		return nil, nil
+	case "otlp":
+		return otlptracehttp.New(ctx)
+	case "stdout", "console":
+		return stdouttrace.New(stdouttrace.WithPrettyPrint())
+	default:
+		return nil, fmt.Errorf("OTEL_TRACES_EXPORTER tidak dikenal: %s", name)
+	}
+}
+
+// Tracer tracer aplikasi dari provider global
+func Tracer() trace.Tracer {
+	return otel.Tracer(instrumentationName)
+}
+
+// Transport http.Transport baru yang setiap request-nya menjadi span client dan membawa header traceparent
+func Transport(upstream string, tlsConfig *tls.Config) http.RoundTripper {
+	base := http.DefaultTransport.(*http.Transport).Clone()
+	base.TLSClientConfig = tlsConfig
+	return WrapTransport(upstream, base)
+}
+
+// WrapTransport bungkus transport yang sudah ada (mis. milik MinIO)
+func WrapTransport(upstream string, base http.RoundTripper) http.RoundTripper {
+	return otelhttp.NewTransport(base,
+		otelhttp.WithSpanNameFormatter(func(_ string, r *http.Request) string {
+			// path tidak dipakai di nama span (bisa berisi nama object/ID); ada di atribut url.full
+			return upstream + " " + r.Method
+		}),
+	)
+}
@@ -1,31 +1,59 @@
 package utils
 
 import (
-	"encoding/base64"
-	"errors"
 	"net/url"
+	"os"
+	"strings"
 )
 
-func EncodeBackState(backTo string) string {
-	stateParams := url.Values{}
-	stateParams.Set("backTo", backTo)
-	state := base64.URLEncoding.EncodeToString([]byte(stateParams.Encode()))
-	return state
-}
+// SanitizeBackTo memvalidasi tujuan redirect setelah login agar tidak menjadi open redirect.
+// Yang diterima: path relatif ("/dashboard?tab=1") atau URL absolut yang origin-nya ada di
+// BACKTO_ALLOWED_ORIGINS (dipisah koma) atau sama dengan FRONTEND_URL. Selain itu dikembalikan "".
+func SanitizeBackTo(backTo string) string {
+	backTo = strings.TrimSpace(backTo)
+	if backTo == "" || len(backTo) > 2048 || strings.ContainsAny(backTo, "\\\r\n\t") {
+		return ""
+	}
 
-func DecodeBackState(encodedState string) (string, error) {
-	decodedStateBytes, err := base64.URLEncoding.DecodeString(encodedState)
+	parsed, err := url.Parse(backTo)
 	if err != nil {
-		return "", errors.New("Invalid state")
+		return ""
 	}
 
-	stateParams, err := url.ParseQuery(string(decodedStateBytes))
-	if err != nil {
-		return "", errors.New("Invalid state params")
+	// Path relatif; "//evil.com" adalah URL protocol-relative, bukan path
+	if strings.HasPrefix(backTo, "/") && !strings.HasPrefix(backTo, "//") {
+		if parsed.Scheme != "" || parsed.Host != "" || parsed.User != nil {
+			return ""
+		}
+		return parsed.RequestURI() + fragment(parsed)
 	}
 
-	backTo := stateParams.Get("backTo")
+	if (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" || parsed.User != nil {
+		return ""
+	}
+	if !allowedBackToOrigins()[strings.ToLower(parsed.Scheme+"://"+parsed.Host)] {
+		return ""
+	}
+	return parsed.String()
+}
 
-	return backTo, nil
+func fragment(u *url.URL) string {
+	if u.Fragment == "" {
+		return ""
+	}
+	return "#" + u.EscapedFragment()
+}
 
+func allowedBackToOrigins() map[string]bool {
+	origins := map[string]bool{}
+	candidates := strings.Split(os.Getenv("BACKTO_ALLOWED_ORIGINS"), ",")
+	candidates = append(candidates, os.Getenv("FRONTEND_URL"))
+	for _, candidate := range candidates {
+		parsed, err := url.Parse(strings.TrimSpace(candidate))
+		if err != nil || parsed.Scheme == "" || parsed.Host == "" {
+			continue
+		}
+		origins[strings.ToLower(parsed.Scheme+"://"+parsed.Host)] = true
+	}
+	return origins
 }
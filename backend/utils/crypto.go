@@ -0,0 +1,165 @@
+package utils
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"os"
+	"strings"
+	"time"
+)
+
+var (
+	ErrSignedTokenInvalid = errors.New("token tidak valid")
+	ErrSignedTokenExpired = errors.New("token sudah kedaluwarsa")
+	// ErrEncryptionKeyMissing tanpa kunci, AES / HMAC memakai sha256("") yang bisa dihitung siapa saja
+	ErrEncryptionKeyMissing = errors.New("AUTH_ENCRYPTION_KEY (atau JWT_SECRET) wajib diisi")
+)
+
+// CheckEncryptionKey dipanggil saat start supaya aplikasi tidak berjalan tanpa kunci enkripsi
+func CheckEncryptionKey() error {
+	_, err := encryptionKey()
+	return err
+}
+
+// encryptionKey kunci AES-256 dari AUTH_ENCRYPTION_KEY (fallback JWT_SECRET)
+func encryptionKey() ([]byte, error) {
+	secret := os.Getenv("AUTH_ENCRYPTION_KEY")
+	if secret == "" {
+		secret = os.Getenv("JWT_SECRET")
+	}
+	if strings.TrimSpace(secret) == "" {
+		return nil, ErrEncryptionKeyMissing
+	}
+	sum := sha256.Sum256([]byte(secret))
+	return sum[:], nil
+}
+
+// EncryptString AES-GCM, hasil base64 (nonce + ciphertext)
+func EncryptString(plain string) (string, error) {
+	key, err := encryptionKey()
+	if err != nil {
+		return "", err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return "", err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	sealed := gcm.Seal(nonce, nonce, []byte(plain), nil)
+	return base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+func DecryptString(encoded string) (string, error) {
+	data, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", err
+	}
+	key, err := encryptionKey()
+	if err != nil {
+		return "", err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return "", err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return "", err
+	}
+	if len(data) < gcm.NonceSize() {
+		return "", errors.New("ciphertext terlalu pendek")
+	}
+	plain, err := gcm.Open(nil, data[:gcm.NonceSize()], data[gcm.NonceSize():], nil)
+	if err != nil {
+		return "", err
+	}
+	return string(plain), nil
+}
+
+// RandomToken string acak hex sepanjang 2*n karakter
+func RandomToken(n int) (string, error) {
+	buf := make([]byte, n)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+type signedEnvelope struct {
+	Purpose   string          `json:"p"`
+	ExpiresAt int64           `json:"e"`
+	Data      json.RawMessage `json:"d"`
+}
+
+// SignToken token stateless bertanda tangan HMAC-SHA256 untuk satu keperluan (purpose), mis. link reset password.
+// Purpose ikut ditandatangani sehingga token untuk keperluan lain tidak bisa dipakai silang.
+func SignToken(purpose string, data interface{}, expiresAt time.Time) (string, error) {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return "", err
+	}
+	payload, err := json.Marshal(signedEnvelope{Purpose: purpose, ExpiresAt: expiresAt.Unix(), Data: raw})
+	if err != nil {
+		return "", err
+	}
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+	signature, err := signPart(encoded)
+	if err != nil {
+		return "", err
+	}
+	return encoded + "." + signature, nil
+}
+
+// VerifySignedToken memeriksa tanda tangan, purpose dan kedaluwarsa lalu mengisi data
+func VerifySignedToken(purpose, token string, data interface{}) error {
+	encoded, signature, ok := strings.Cut(token, ".")
+	if !ok {
+		return ErrSignedTokenInvalid
+	}
+	expected, err := signPart(encoded)
+	if err != nil {
+		return err
+	}
+	if !hmac.Equal([]byte(signature), []byte(expected)) {
+		return ErrSignedTokenInvalid
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return ErrSignedTokenInvalid
+	}
+	var envelope signedEnvelope
+	if err := json.Unmarshal(payload, &envelope); err != nil || envelope.Purpose != purpose {
+		return ErrSignedTokenInvalid
+	}
+	if time.Now().Unix() > envelope.ExpiresAt {
+		return ErrSignedTokenExpired
+	}
+	if err := json.Unmarshal(envelope.Data, data); err != nil {
+		return ErrSignedTokenInvalid
+	}
+	return nil
+}
+
+func signPart(encoded string) (string, error) {
+	key, err := encryptionKey()
+	if err != nil {
+		return "", err
+	}
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte("signed-token|" + encoded))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil)), nil
+}
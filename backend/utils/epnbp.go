@@ -1,19 +1,25 @@
 package utils
 
 import (
+	"context"
 	"crypto/md5"
 	"crypto/tls"
 	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"github.com/dedegunawan/backend-ujian-telp-v5/metrics"
+	"github.com/dedegunawan/backend-ujian-telp-v5/tracing"
 	"github.com/go-resty/resty/v2"
 	"github.com/golang-jwt/jwt/v5"
 	"net/http"
 	"os"
+	"time"
 )
 
 type Epnbp interface {
+	// WithContext salinan client yang membawa ctx (span trace, pembatalan) ke setiap request
+	WithContext(ctx context.Context) Epnbp
 	CreateInvoice(payload map[string]interface{}) (map[string]interface{}, error)
 	GenerateJWTSecret() string
 	EncodePayloadToJWT(payload map[string]interface{}) (string, error)
@@ -26,6 +32,7 @@ type epnbp struct {
 	AppUrl    string `json:"app_url"`
 	AppId     string `json:"app_id"`
 	SecretKey string `json:"secret"`
+	ctx       context.Context
 }
 
 func NewEpnbp() Epnbp {
@@ -53,6 +60,24 @@ func NewEpnbp() Epnbp {
 	}
 }
 
+func (e *epnbp) WithContext(ctx context.Context) Epnbp {
+	clone := *e
+	clone.ctx = ctx
+	return &clone
+}
+
+func (e *epnbp) context() context.Context {
+	if e.ctx == nil {
+		return context.Background()
+	}
+	return e.ctx
+}
+
+// newClient resty dengan transport ter-trace (span client + header traceparent ke EPNBP)
+func (e *epnbp) newClient() *resty.Client {
+	return resty.New().SetTransport(tracing.Transport(tracing.UpstreamEpnbp, &tls.Config{InsecureSkipVerify: true}))
+}
+
 func min(a, b int) int {
 	if a < b {
 		return a
@@ -60,6 +85,35 @@ func min(a, b int) int {
 	return b
 }
 
+// Setiap panggilan ke EPNBP dicatat ke metrik upstream (latensi dan error per operasi)
+func (e *epnbp) CreateInvoice(payload map[string]interface{}) (map[string]interface{}, error) {
+	start := time.Now()
+	result, err := e.createInvoice(payload)
+	metrics.ObserveUpstream(metrics.UpstreamEpnbp, "create_invoice", start, err)
+	return result, err
+}
+
+func (e *epnbp) SearchByInvoiceID(invoiceId string) (map[string]interface{}, error) {
+	start := time.Now()
+	result, err := e.searchByInvoiceID(invoiceId)
+	metrics.ObserveUpstream(metrics.UpstreamEpnbp, "search_invoice", start, err)
+	return result, err
+}
+
+func (e *epnbp) SearchByVirtualAccount(virtualAccount string) (map[string]interface{}, error) {
+	start := time.Now()
+	result, err := e.searchByVirtualAccount(virtualAccount)
+	metrics.ObserveUpstream(metrics.UpstreamEpnbp, "search_va", start, err)
+	return result, err
+}
+
+func (e *epnbp) SearchByIdentifier(identifier string) (map[string]interface{}, error) {
+	start := time.Now()
+	result, err := e.searchByIdentifier(identifier)
+	metrics.ObserveUpstream(metrics.UpstreamEpnbp, "search_identifier", start, err)
+	return result, err
+}
+
 // Fungsi untuk generate MD5 dari app_id.secret_key
 func (e *epnbp) GenerateJWTSecret() string {
 	hash := md5.Sum([]byte(fmt.Sprintf("%s.%s", e.AppId, e.SecretKey)))
@@ -77,7 +131,7 @@ func (e *epnbp) EncodePayloadToJWT(payload map[string]interface{}) (string, erro
 }
 
 // Fungsi utama menggunakan Resty
-func (e *epnbp) CreateInvoice(payload map[string]interface{}) (map[string]interface{}, error) {
+func (e *epnbp) createInvoice(payload map[string]interface{}) (map[string]interface{}, error) {
 	// Encode payload ke JWT string
 	jwtString, err := e.EncodePayloadToJWT(payload)
 	if err != nil {
@@ -89,11 +143,10 @@ func (e *epnbp) CreateInvoice(payload map[string]interface{}) (map[string]interf
 	formBody := "data=" + jwtString
 
 	// Gunakan Resty
-	client := resty.New()
-
-	client.SetTLSClientConfig(&tls.Config{InsecureSkipVerify: true})
+	client := e.newClient()
 
 	resp, err := client.R().
+		SetContext(e.context()).
 		SetHeader("Content-Type", "application/x-www-form-urlencoded").
 		SetHeader("Accept", "application/json").
 		SetHeader("x-app-id", e.AppId).
@@ -101,13 +154,16 @@ func (e *epnbp) CreateInvoice(payload map[string]interface{}) (map[string]interf
 		SetBody(formBody).
 		Post(e.AppUrl + "/api/invoices/create")
 	if err != nil {
-		Log.Infof("Error creating invoice %v", err.Error())
+		Log.Errorw("Error creating invoice", "error", err)
 		return nil, fmt.Errorf("gagal mengirim request: %w", err)
 	}
 
 	if resp.StatusCode() != http.StatusOK {
-		Log.Info(string(resp.Body()), " status code:", resp.StatusCode(), " url:", e.AppUrl+"api/invoices/create")
-		Log.Info("Response Body:", string(resp.Body()))
+		Log.Errorw("EPNBP create invoice ditolak",
+			"status", resp.StatusCode(),
+			"url", e.AppUrl+"/api/invoices/create",
+			"body", string(resp.Body()),
+		)
 		return nil, errors.New("gagal membuat invoice EPNBP")
 	}
 
@@ -120,14 +176,13 @@ func (e *epnbp) CreateInvoice(payload map[string]interface{}) (map[string]interf
 }
 
 // Fungsi utama menggunakan Resty
-func (e *epnbp) SearchByInvoiceID(invoiceId string) (map[string]interface{}, error) {
+func (e *epnbp) searchByInvoiceID(invoiceId string) (map[string]interface{}, error) {
 
 	// Gunakan Resty
-	client := resty.New()
-
-	client.SetTLSClientConfig(&tls.Config{InsecureSkipVerify: true})
+	client := e.newClient()
 
 	resp, err := client.R().
+		SetContext(e.context()).
 		SetHeader("Content-Type", "application/x-www-form-urlencoded").
 		SetHeader("Accept", "application/json").
 		SetHeader("x-app-id", e.AppId).
@@ -135,13 +190,17 @@ func (e *epnbp) SearchByInvoiceID(invoiceId string) (map[string]interface{}, err
 		SetQueryParam("invoice_id", invoiceId).
 		Get(e.AppUrl + "/api/virtual-accounts/search-invoice")
 	if err != nil {
-		Log.Infof("Error creating search invoice %v", err.Error())
+		Log.Errorw("Error creating search invoice", "error", err)
 		return nil, fmt.Errorf("gagal mengirim request: %w", err)
 	}
 
 	if resp.StatusCode() != http.StatusOK {
-		Log.Info(string(resp.Body()), " status code:", resp.StatusCode(), " url:", e.AppUrl+"/api/virtual-accounts/search-invoice", "invoice_id", invoiceId)
-		Log.Info("Response Body:", string(resp.Body()))
+		Log.Errorw("EPNBP search invoice ditolak",
+			"status", resp.StatusCode(),
+			"url", e.AppUrl+"/api/virtual-accounts/search-invoice",
+			"invoice_id", invoiceId,
+			"body", string(resp.Body()),
+		)
 		return nil, errors.New("gagal membuat seach invoice EPNBP")
 	}
 
@@ -154,14 +213,13 @@ func (e *epnbp) SearchByInvoiceID(invoiceId string) (map[string]interface{}, err
 }
 
 // Fungsi utama menggunakan Resty
-func (e *epnbp) SearchByVirtualAccount(virtualAccount string) (map[string]interface{}, error) {
+func (e *epnbp) searchByVirtualAccount(virtualAccount string) (map[string]interface{}, error) {
 
 	// Gunakan Resty
-	client := resty.New()
-
-	client.SetTLSClientConfig(&tls.Config{InsecureSkipVerify: true})
+	client := e.newClient()
 
 	resp, err := client.R().
+		SetContext(e.context()).
 		SetHeader("Content-Type", "application/x-www-form-urlencoded").
 		SetHeader("Accept", "application/json").
 		SetHeader("x-app-id", e.AppId).
@@ -169,13 +227,16 @@ func (e *epnbp) SearchByVirtualAccount(virtualAccount string) (map[string]interf
 		SetQueryParam("va", virtualAccount).
 		Get(e.AppUrl + "/api/virtual-accounts/search-va")
 	if err != nil {
-		Log.Infof("Error creating search virtual account %v", err.Error())
+		Log.Errorw("Error creating search virtual account", "error", err)
 		return nil, fmt.Errorf("gagal mengirim request: %w", err)
 	}
 
 	if resp.StatusCode() != http.StatusOK {
-		Log.Info(string(resp.Body()), " status code:", resp.StatusCode(), " url:", e.AppUrl+"/api/virtual-accounts/search-va")
-		Log.Info("Response Body:", string(resp.Body()))
+		Log.Errorw("EPNBP search virtual account ditolak",
+			"status", resp.StatusCode(),
+			"url", e.AppUrl+"/api/virtual-accounts/search-va",
+			"body", string(resp.Body()),
+		)
 		return nil, errors.New("gagal membuat seach va EPNBP")
 	}
 
@@ -187,14 +248,13 @@ func (e *epnbp) SearchByVirtualAccount(virtualAccount string) (map[string]interf
 	return result, nil
 }
 
-// SearchByIdentifier mencari data pembayaran berdasarkan identifier (NPM/Student ID)
-func (e *epnbp) SearchByIdentifier(identifier string) (map[string]interface{}, error) {
+// searchByIdentifier mencari data pembayaran berdasarkan identifier (NPM/Student ID)
+func (e *epnbp) searchByIdentifier(identifier string) (map[string]interface{}, error) {
 	// Gunakan Resty
-	client := resty.New()
-
-	client.SetTLSClientConfig(&tls.Config{InsecureSkipVerify: true})
+	client := e.newClient()
 
 	resp, err := client.R().
+		SetContext(e.context()).
 		SetHeader("Content-Type", "application/x-www-form-urlencoded").
 		SetHeader("Accept", "application/json").
 		SetHeader("x-app-id", e.AppId).
@@ -202,13 +262,17 @@ func (e *epnbp) SearchByIdentifier(identifier string) (map[string]interface{}, e
 		SetQueryParam("identifier", identifier).
 		Get(e.AppUrl + "/api/virtual-accounts/search-identifier")
 	if err != nil {
-		Log.Infof("Error searching by identifier %v", err.Error())
+		Log.Errorw("Error searching by identifier", "error", err)
 		return nil, fmt.Errorf("gagal mengirim request: %w", err)
 	}
 
 	if resp.StatusCode() != http.StatusOK {
-		Log.Info(string(resp.Body()), " status code:", resp.StatusCode(), " url:", e.AppUrl+"/api/virtual-accounts/search-identifier", "identifier", identifier)
-		Log.Info("Response Body:", string(resp.Body()))
+		Log.Errorw("EPNBP search identifier ditolak",
+			"status", resp.StatusCode(),
+			"url", e.AppUrl+"/api/virtual-accounts/search-identifier",
+			"identifier", identifier,
+			"body", string(resp.Body()),
+		)
 		return nil, errors.New("gagal search by identifier EPNBP")
 	}
 
@@ -1,6 +1,8 @@
 package utils
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"github.com/golang-jwt/jwt/v5"
@@ -9,30 +11,49 @@ import (
 	"time"
 )
 
+// Jenis JWT internal (klaim token_type); hanya access token yang diterima middleware auth
+const (
+	TokenTypeAccess  = "access"
+	TokenTypeRefresh = "refresh"
+)
+
 type Claims struct {
-	UserID uuid.UUID `json:"user_id"`
-	Email  string    `json:"email"`
-	Name   string    `json:"name"`
+	UserID    uuid.UUID `json:"user_id"`
+	Email     string    `json:"email"`
+	Name      string    `json:"name"`
+	TokenType string    `json:"token_type"`
 	jwt.RegisteredClaims
 }
 
 var jwtSecret = []byte(os.Getenv("JWT_SECRET"))
 
+// GenerateJWT access token internal
 func GenerateJWT(userID uuid.UUID, email string, name string, t time.Time) (string, error) {
-	claims := Claims{
-		UserID: userID,
-		Email:  email,
-		Name:   name,
-		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(t),
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
-		},
+	return signJWT(Claims{UserID: userID, Email: email, Name: name, TokenType: TokenTypeAccess}, t)
+}
+
+// GenerateRefreshJWT refresh token internal; tidak membawa email/nama
+func GenerateRefreshJWT(userID uuid.UUID, t time.Time) (string, error) {
+	return signJWT(Claims{UserID: userID, TokenType: TokenTypeRefresh}, t)
+}
+
+func signJWT(claims Claims, t time.Time) (string, error) {
+	claims.RegisteredClaims = jwt.RegisteredClaims{
+		ID:        uuid.NewString(), // jti: token selalu unik walau dibuat di detik yang sama
+		ExpiresAt: jwt.NewNumericDate(t),
+		IssuedAt:  jwt.NewNumericDate(time.Now()),
 	}
 
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 	return token.SignedString(jwtSecret)
 }
 
+// HashToken SHA-256 hex dari token; yang disimpan di database hanya hash ini
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
 func CheckJwt(tokenStr string) (*Claims, error) {
 	token, err := jwt.ParseWithClaims(tokenStr, &Claims{}, func(token *jwt.Token) (interface{}, error) {
 		// Pastikan token menggunakan algoritma HS256
@@ -0,0 +1,49 @@
+package utils
+
+import (
+	"context"
+
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type logFieldsKey struct{}
+
+// ContextWithLogFields menambahkan field log (mis. request_id, user_id, npm) ke ctx. Dipanggil
+// middleware; handler dan service cukup memakai LogCtx(ctx) untuk mendapatkannya kembali.
+func ContextWithLogFields(ctx context.Context, keyValues ...interface{}) context.Context {
+	merged := logrus.Fields{}
+	for key, value := range logFieldsFromContext(ctx) {
+		merged[key] = value
+	}
+	for key, value := range keyValueFields(keyValues) {
+		merged[key] = value
+	}
+	return context.WithValue(ctx, logFieldsKey{}, merged)
+}
+
+// LogCtx mengembalikan Entry yang membawa field dari ContextWithLogFields dan trace_id dari
+// span aktif. Untuk handler gin gunakan c.Request.Context(), bukan c.
+func LogCtx(ctx context.Context) *Entry {
+	entry := logrus.NewEntry(Log.base)
+	if ctx == nil {
+		return &Entry{entry: entry}
+	}
+
+	fields := logrus.Fields{}
+	for key, value := range logFieldsFromContext(ctx) {
+		fields[key] = value
+	}
+	if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+		fields["trace_id"] = sc.TraceID().String()
+	}
+	return &Entry{entry: entry.WithContext(ctx).WithFields(fields)}
+}
+
+func logFieldsFromContext(ctx context.Context) logrus.Fields {
+	if ctx == nil {
+		return nil
+	}
+	fields, _ := ctx.Value(logFieldsKey{}).(logrus.Fields)
+	return fields
+}
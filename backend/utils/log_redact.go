@@ -0,0 +1,142 @@
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	redactedValue = "[REDACTED]"
+	omittedValue  = "[OMITTED]"
+)
+
+var (
+	bearerPattern = regexp.MustCompile(`(?i)\b(bearer)\s+[A-Za-z0-9\-._~+/]+=*`)
+	jwtPattern    = regexp.MustCompile(`\beyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+\.[A-Za-z0-9_-]*`)
+	// Parameter rahasia di URL / form (kode login SSO, token reset password, dst)
+	secretParamPattern = regexp.MustCompile(`(?i)\b(access_token|refresh_token|id_token|token|code|password|client_secret|secret)=([^&\s"']+)`)
+	emailPattern       = regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`)
+	// NIK: kode wilayah + tanggal lahir (DD bisa +40 untuk perempuan) + bulan valid + nomor urut,
+	// supaya nomor VA 16 digit tidak ikut tersamarkan
+	nikPattern   = regexp.MustCompile(`\b(?:1[1-9]|[2-9][0-9])[0-9]{4}(?:[0-6][0-9]|7[01])(?:0[1-9]|1[0-2])[0-9]{6}\b`)
+	phonePattern = regexp.MustCompile(`(?:\+62|\b62|\b0)8[0-9]{7,11}\b`)
+)
+
+// Redact menyamarkan NIK, nomor HP, email, bearer token / JWT dan parameter rahasia di URL.
+// Dipanggil otomatis untuk setiap entry log; bisa juga dipakai sebelum menulis teks bebas ke
+// tabel audit.
+func Redact(s string) string {
+	if s == "" {
+		return s
+	}
+	s = bearerPattern.ReplaceAllString(s, "$1 "+redactedValue)
+	s = jwtPattern.ReplaceAllString(s, redactedValue)
+	s = secretParamPattern.ReplaceAllString(s, "$1="+redactedValue)
+	s = emailPattern.ReplaceAllStringFunc(s, maskEmail)
+	s = nikPattern.ReplaceAllStringFunc(s, func(nik string) string {
+		return strings.Repeat("*", len(nik)-4) + nik[len(nik)-4:]
+	})
+	s = phonePattern.ReplaceAllStringFunc(s, func(phone string) string {
+		return phone[:4] + strings.Repeat("*", len(phone)-7) + phone[len(phone)-3:]
+	})
+	return s
+}
+
+// maskEmail menyisakan dua huruf pertama dan domain: "227007054@student.unsil.ac.id" -> "22***@student.unsil.ac.id"
+func maskEmail(email string) string {
+	at := strings.LastIndex(email, "@")
+	local := email[:at]
+	if len(local) > 2 {
+		local = local[:2]
+	}
+	return local + "***" + email[at:]
+}
+
+// redactHook dipasang di logger sehingga redaksi berlaku untuk semua pemanggil, termasuk
+// yang masih mengirim map atau teks bebas.
+type redactHook struct{}
+
+func (redactHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+// Fire mengganti isi entry yang sudah di-Dup oleh logrus; map bersarang tidak diubah di tempat
+// karena masih milik pemanggil.
+func (redactHook) Fire(entry *logrus.Entry) error {
+	entry.Message = Redact(entry.Message)
+	for key, value := range entry.Data {
+		entry.Data[key] = redactField(key, value)
+	}
+	return nil
+}
+
+func redactField(key string, value interface{}) interface{} {
+	normalized := strings.ToLower(strings.ReplaceAll(key, "_", ""))
+	switch {
+	case key == "file" || key == "request_id" || key == "trace_id":
+		return value
+	case strings.HasPrefix(normalized, "fulldata"):
+		// Blob data mahasiswa dari connector berisi alamat, kontak dan data orang tua
+		return omittedValue
+	case strings.Contains(normalized, "password"), strings.Contains(normalized, "secret"),
+		strings.HasSuffix(normalized, "token"), normalized == "authorization", normalized == "cookie":
+		return redactedValue
+	}
+	return redactValue(value)
+}
+
+func redactValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case nil, bool, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+		return v
+	case string:
+		return Redact(v)
+	case []byte:
+		return Redact(string(v))
+	case error:
+		return Redact(v.Error())
+	case fmt.Stringer:
+		return Redact(v.String())
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			out[key] = redactField(key, item)
+		}
+		return out
+	case logrus.Fields:
+		return redactValue(map[string]interface{}(v))
+	case map[string]string:
+		out := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			out[key] = redactField(key, item)
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, len(v))
+		for i, item := range v {
+			out[i] = redactValue(item)
+		}
+		return out
+	case []string:
+		out := make([]interface{}, len(v))
+		for i, item := range v {
+			out[i] = Redact(item)
+		}
+		return out
+	}
+
+	// Struct, pointer dan slice lain: lewat JSON supaya field bersarang ikut diperiksa
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return Redact(fmt.Sprintf("%+v", value))
+	}
+	var decoded interface{}
+	if err := json.Unmarshal(raw, &decoded); err != nil {
+		return Redact(string(raw))
+	}
+	return redactValue(decoded)
+}
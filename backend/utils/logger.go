@@ -3,46 +3,199 @@ package utils
 import (
 	"fmt"
 	"os"
+	"path/filepath"
 	"runtime"
+	"strconv"
 	"strings"
 
 	"github.com/sirupsen/logrus"
 )
 
-var Log *logrus.Logger
+// Logger membungkus logrus supaya semua pemanggil memakai API key/value yang sama, lokasi
+// pemanggil tercatat dengan benar dan setiap entry melewati redaksi PII sebelum ditulis.
+//
+// Field selalu dikirim sebagai pasangan key/value lewat method *w:
+//
+//	Log.Infow("pesan", "npm", npm, "error", err)   // pasangan key/value
+//	Log.Infof("pesan %s", npm)                     // format, tanpa field
+//	Log.Info("pesan")                              // pesan saja
+//	LogCtx(ctx).Errorw("pesan", "error", err)      // + request_id, user_id, npm, trace_id
+//
+// Info/Warn/Error dengan beberapa argumen hanya menggabungkannya menjadi pesan (seperti
+// fmt.Sprintln); argumen tersebut tidak dijadikan field.
+type Logger struct {
+	base *logrus.Logger
+}
 
-func InitLogger() {
-	Log = logrus.New()
+// Entry adalah Logger dengan field tambahan (hasil With / LogCtx).
+type Entry struct {
+	entry *logrus.Entry
+}
 
-	// Aktifkan informasi pemanggil
-	Log.SetReportCaller(true)
+var Log *Logger
+
+func InitLogger() {
+	base := logrus.New()
 
 	// Coba buka file logs/app.log
+	fallback := false
 	file, err := os.OpenFile("logs/app.log", os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0666)
 	if err == nil {
-		Log.Out = file
+		base.Out = file
 	} else {
-		Log.Out = os.Stdout
+		base.Out = os.Stdout
+		fallback = true
+	}
+
+	// Lokasi pemanggil diisi sendiri di field "file" (lihat write), karena caller bawaan logrus
+	// akan selalu menunjuk ke file ini
+	base.SetFormatter(&logrus.JSONFormatter{PrettyPrint: true})
+	base.AddHook(redactHook{})
+
+	level, err := logrus.ParseLevel(os.Getenv("LOG_LEVEL"))
+	if err != nil {
+		level = logrus.InfoLevel
+	}
+	base.SetLevel(level)
+
+	Log = &Logger{base: base}
+	if fallback {
 		Log.Warn("❗ Failed to log to file, using default stdout")
 	}
+}
+
+// With mengembalikan Entry dengan field tambahan dari pasangan key/value.
+func (l *Logger) With(keyValues ...interface{}) *Entry {
+	return l.root().With(keyValues...)
+}
 
-	// Custom formatter untuk tampilkan lokasi file & baris
-	Log.SetFormatter(&logrus.JSONFormatter{
-		PrettyPrint: true,
-		CallerPrettyfier: func(f *runtime.Frame) (function string, file string) {
-			// Ambil nama file dan baris, potong path jika terlalu panjang
-			filename := f.File
-			if idx := strings.LastIndex(filename, "/"); idx != -1 {
-				filename = filename[idx+1:]
-			}
-			return "", filename + ":" + funcLine(f)
-		},
-	})
+func (l *Logger) root() *Entry {
+	return &Entry{entry: logrus.NewEntry(l.base)}
+}
+
+func (l *Logger) Debug(args ...interface{}) { l.root().log(logrus.DebugLevel, args) }
+func (l *Logger) Info(args ...interface{})  { l.root().log(logrus.InfoLevel, args) }
+func (l *Logger) Warn(args ...interface{})  { l.root().log(logrus.WarnLevel, args) }
+func (l *Logger) Error(args ...interface{}) { l.root().log(logrus.ErrorLevel, args) }
+func (l *Logger) Fatal(args ...interface{}) { l.root().log(logrus.FatalLevel, args) }
 
-	Log.SetLevel(logrus.InfoLevel)
+func (l *Logger) Debugf(format string, args ...interface{}) {
+	l.root().logf(logrus.DebugLevel, format, args)
+}
+func (l *Logger) Infof(format string, args ...interface{}) {
+	l.root().logf(logrus.InfoLevel, format, args)
+}
+func (l *Logger) Warnf(format string, args ...interface{}) {
+	l.root().logf(logrus.WarnLevel, format, args)
+}
+func (l *Logger) Errorf(format string, args ...interface{}) {
+	l.root().logf(logrus.ErrorLevel, format, args)
+}
+func (l *Logger) Fatalf(format string, args ...interface{}) {
+	l.root().logf(logrus.FatalLevel, format, args)
 }
 
-// funcLine membuat string gabungan function + line number
-func funcLine(f *runtime.Frame) string {
-	return fmt.Sprintf("%d", f.Line)
+// Printf dan Println dipertahankan untuk pemanggil lama; keduanya setara Infof / Info.
+func (l *Logger) Printf(format string, args ...interface{}) {
+	l.root().logf(logrus.InfoLevel, format, args)
+}
+func (l *Logger) Println(args ...interface{}) { l.root().log(logrus.InfoLevel, args) }
+
+func (l *Logger) Debugw(msg string, keyValues ...interface{}) {
+	l.root().logw(logrus.DebugLevel, msg, keyValues)
+}
+func (l *Logger) Infow(msg string, keyValues ...interface{}) {
+	l.root().logw(logrus.InfoLevel, msg, keyValues)
+}
+func (l *Logger) Warnw(msg string, keyValues ...interface{}) {
+	l.root().logw(logrus.WarnLevel, msg, keyValues)
+}
+func (l *Logger) Errorw(msg string, keyValues ...interface{}) {
+	l.root().logw(logrus.ErrorLevel, msg, keyValues)
+}
+func (l *Logger) Fatalw(msg string, keyValues ...interface{}) {
+	l.root().logw(logrus.FatalLevel, msg, keyValues)
+}
+
+// With mengembalikan Entry baru dengan field tambahan; Entry asal tidak berubah.
+func (e *Entry) With(keyValues ...interface{}) *Entry {
+	return &Entry{entry: e.entry.WithFields(keyValueFields(keyValues))}
+}
+
+func (e *Entry) Debug(args ...interface{}) { e.log(logrus.DebugLevel, args) }
+func (e *Entry) Info(args ...interface{})  { e.log(logrus.InfoLevel, args) }
+func (e *Entry) Warn(args ...interface{})  { e.log(logrus.WarnLevel, args) }
+func (e *Entry) Error(args ...interface{}) { e.log(logrus.ErrorLevel, args) }
+
+func (e *Entry) Debugf(format string, args ...interface{}) { e.logf(logrus.DebugLevel, format, args) }
+func (e *Entry) Infof(format string, args ...interface{})  { e.logf(logrus.InfoLevel, format, args) }
+func (e *Entry) Warnf(format string, args ...interface{})  { e.logf(logrus.WarnLevel, format, args) }
+func (e *Entry) Errorf(format string, args ...interface{}) { e.logf(logrus.ErrorLevel, format, args) }
+
+func (e *Entry) Debugw(msg string, keyValues ...interface{}) {
+	e.logw(logrus.DebugLevel, msg, keyValues)
+}
+func (e *Entry) Infow(msg string, keyValues ...interface{}) {
+	e.logw(logrus.InfoLevel, msg, keyValues)
+}
+func (e *Entry) Warnw(msg string, keyValues ...interface{}) {
+	e.logw(logrus.WarnLevel, msg, keyValues)
+}
+func (e *Entry) Errorw(msg string, keyValues ...interface{}) {
+	e.logw(logrus.ErrorLevel, msg, keyValues)
+}
+
+// log, logf dan logw harus dipanggil langsung dari method publik supaya kedalaman stack di
+// write tetap sama (method publik -> log* -> write).
+func (e *Entry) log(level logrus.Level, args []interface{}) {
+	if !e.entry.Logger.IsLevelEnabled(level) {
+		return
+	}
+	e.write(level, strings.TrimSuffix(fmt.Sprintln(args...), "\n"), nil)
+}
+
+func (e *Entry) logf(level logrus.Level, format string, args []interface{}) {
+	if !e.entry.Logger.IsLevelEnabled(level) {
+		return
+	}
+	e.write(level, fmt.Sprintf(format, args...), nil)
+}
+
+func (e *Entry) logw(level logrus.Level, msg string, keyValues []interface{}) {
+	if !e.entry.Logger.IsLevelEnabled(level) {
+		return
+	}
+	e.write(level, msg, keyValueFields(keyValues))
+}
+
+func (e *Entry) write(level logrus.Level, msg string, fields logrus.Fields) {
+	entry := e.entry
+	if len(fields) > 0 {
+		entry = entry.WithFields(fields)
+	}
+	if _, file, line, ok := runtime.Caller(3); ok {
+		entry = entry.WithField("file", filepath.Base(file)+":"+strconv.Itoa(line))
+	}
+	entry.Log(level, msg)
+	if level == logrus.FatalLevel {
+		entry.Logger.Exit(1)
+	}
+}
+
+// keyValueFields mengubah pasangan key/value menjadi field; nilai error disimpan sebagai
+// teksnya dan key tanpa pasangan disimpan di "extra".
+func keyValueFields(keyValues []interface{}) logrus.Fields {
+	fields := make(logrus.Fields, len(keyValues)/2)
+	for i := 0; i < len(keyValues); i += 2 {
+		if i+1 >= len(keyValues) {
+			fields["extra"] = keyValues[i]
+			break
+		}
+		value := keyValues[i+1]
+		if err, ok := value.(error); ok && err != nil {
+			value = err.Error()
+		}
+		fields[fmt.Sprint(keyValues[i])] = value
+	}
+	return fields
 }
@@ -20,7 +20,7 @@ func ErrorHandler(c *gin.Context, code int, message string) {
 		ErrorOption = errorOption
 	}
 
-	Log.Info("ERROR_OPTION : ", ErrorOption)
+	Log.Infow("ERROR_OPTION", "error", ErrorOption)
 
 	if opt == ERROR_TO_FRONTEND {
 		url := os.Getenv("FRONTEND_ERROR_URL")
@@ -0,0 +1,38 @@
+package utils
+
+import (
+	"fmt"
+	"os"
+	"time"
+)
+
+// RetryStartup mengulang fn dengan backoff (1s, 2s, 4s, maks 10s) sampai berhasil atau timeout habis.
+// Dipakai saat start supaya dependency yang baru restart (MySQL, Keycloak) tidak langsung membuat proses mati.
+func RetryStartup(name string, timeout time.Duration, fn func() error) error {
+	deadline := time.Now().Add(timeout)
+	backoff := time.Second
+	for attempt := 1; ; attempt++ {
+		err := fn()
+		if err == nil {
+			return nil
+		}
+		if time.Now().Add(backoff).After(deadline) {
+			return fmt.Errorf("%s gagal setelah %d percobaan: %w", name, attempt, err)
+		}
+		Log.Warnf("⚠️ %s gagal (percobaan %d), coba lagi dalam %s: %v", name, attempt, backoff, err)
+		time.Sleep(backoff)
+		if backoff *= 2; backoff > 10*time.Second {
+			backoff = 10 * time.Second
+		}
+	}
+}
+
+// StartupTimeout dari env STARTUP_RETRY_TIMEOUT (default 60s)
+func StartupTimeout() time.Duration {
+	if raw := os.Getenv("STARTUP_RETRY_TIMEOUT"); raw != "" {
+		if parsed, err := time.ParseDuration(raw); err == nil && parsed >= 0 {
+			return parsed
+		}
+	}
+	return time.Minute
+}
@@ -4,6 +4,8 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"github.com/dedegunawan/backend-ujian-telp-v5/metrics"
+	"github.com/dedegunawan/backend-ujian-telp-v5/tracing"
 	"github.com/minio/minio-go/v7"
 	"github.com/minio/minio-go/v7/pkg/credentials"
 	"io"
@@ -18,6 +20,19 @@ import (
 
 var MinioClient *minio.Client
 
+// observeMinio mencatat panggilan ke MinIO pada metrik upstream
+func observeMinio(operation string, start time.Time, err error) {
+	metrics.ObserveUpstream(metrics.UpstreamMinio, operation, start, err)
+}
+
+// ignoreMinioNotFound object tidak ada adalah jawaban normal, bukan MinIO yang bermasalah
+func ignoreMinioNotFound(err error) error {
+	if minio.ToErrorResponse(err).Code == "NoSuchKey" {
+		return nil
+	}
+	return err
+}
+
 func InitStorage() {
 	endpoint := os.Getenv("MINIO_ENDPOINT")
 	accessKey := os.Getenv("MINIO_ACCESS_KEY")
@@ -31,9 +46,16 @@ func InitStorage() {
 		useSSL = val
 	}
 
+	// Transport bawaan MinIO dibungkus span client supaya operasi MinIO muncul di trace
+	transport, err := minio.DefaultTransport(useSSL)
+	if err != nil {
+		Log.Fatalf("❌ Failed to create MinIO transport: %v", err)
+	}
+
 	client, err := minio.New(endpoint, &minio.Options{
-		Creds:  credentials.NewStaticV4(accessKey, secretKey, ""),
-		Secure: useSSL,
+		Creds:     credentials.NewStaticV4(accessKey, secretKey, ""),
+		Secure:    useSSL,
+		Transport: tracing.WrapTransport(tracing.UpstreamMinio, transport),
 	})
 	if err != nil {
 		Log.Fatalf("❌ Failed to connect to MinIO: %v", err)
@@ -45,7 +67,9 @@ func InitStorage() {
 
 func EnsureBucket(bucketName string) error {
 	ctx := context.Background()
+	start := time.Now()
 	exists, err := MinioClient.BucketExists(ctx, bucketName)
+	observeMinio("bucket_exists", start, err)
 	if err != nil {
 		return err
 	}
@@ -88,7 +112,9 @@ func MinioUrlExistsOrError(objectName string, expiry ...time.Duration) (string,
 	}
 
 	// ✅ Cek apakah file ada di MinIO
+	start := time.Now()
 	_, err := MinioClient.StatObject(ctx, bucketName, objectName, minio.StatObjectOptions{})
+	observeMinio("stat_object", start, ignoreMinioNotFound(err))
 	if err == nil {
 		// ✅ Jika ada → generate signed URL
 		return MinioUrl(objectName, finalExpiry)
@@ -131,9 +157,11 @@ func UploadFileToMinio(objectName, filePath string) (string, error) {
 	}
 
 	// Upload
+	start := time.Now()
 	_, err := MinioClient.FPutObject(ctx, bucketName, objectName, filePath, minio.PutObjectOptions{
 		ContentType: contentType,
 	})
+	observeMinio("put_object", start, err)
 	if err != nil {
 		return "", err
 	}
@@ -165,9 +193,11 @@ func UploadObjectToMinio(objectName string, data []byte, contentType string) (st
 		contentType = http.DetectContentType(data)
 	}
 
+	start := time.Now()
 	_, err := MinioClient.PutObject(ctx, bucketName, objectName, reader, int64(len(data)), minio.PutObjectOptions{
 		ContentType: contentType,
 	})
+	observeMinio("put_object", start, err)
 	if err != nil {
 		return "", err
 	}
@@ -183,8 +213,10 @@ func DownloadFileFromMinio(objectName, localDir string) (string, error) {
 		bucketName = "default"
 	}
 
+	start := time.Now()
 	object, err := MinioClient.GetObject(ctx, bucketName, objectName, minio.GetObjectOptions{})
 	if err != nil {
+		observeMinio("get_object", start, err)
 		return "", err
 	}
 
@@ -198,6 +230,7 @@ func DownloadFileFromMinio(objectName, localDir string) (string, error) {
 
 	// ✅ Gunakan io.Copy untuk membaca isi object dari MinIO ke file lokal
 	_, err = io.Copy(outFile, object)
+	observeMinio("get_object", start, err)
 	if err != nil {
 		return "", err
 	}
@@ -219,12 +252,16 @@ func GetFileStreamFromMinio(objectName string) (*FileStreamResult, error) {
 		bucketName = "default"
 	}
 
+	// GetObject bersifat lazy; request pertama ke MinIO terjadi saat Stat
+	start := time.Now()
 	object, err := MinioClient.GetObject(ctx, bucketName, objectName, minio.GetObjectOptions{})
 	if err != nil {
+		observeMinio("stat_object", start, err)
 		return nil, err
 	}
 
 	info, err := object.Stat()
+	observeMinio("stat_object", start, ignoreMinioNotFound(err))
 	if err != nil {
 		return nil, err
 	}
@@ -246,7 +283,9 @@ func DeleteFileFromMinio(objectName string) error {
 		bucketName = "default"
 	}
 
+	start := time.Now()
 	err := MinioClient.RemoveObject(ctx, bucketName, objectName, minio.RemoveObjectOptions{})
+	observeMinio("remove_object", start, err)
 	if err != nil {
 		return fmt.Errorf("failed to delete object: %w", err)
 	}
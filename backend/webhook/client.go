@@ -0,0 +1,117 @@
+package webhook
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/dedegunawan/backend-ujian-telp-v5/tracing"
+	"github.com/go-resty/resty/v2"
+)
+
+const (
+	defaultTimeout  = 10 * time.Second
+	maxResponseBody = 2048
+	backoffBase     = 30 * time.Second
+	backoffMax      = time.Hour
+)
+
+// Request satu HTTP POST ke consumer. Body dikirim apa adanya, atau FormData jika diisi.
+type Request struct {
+	URL                string
+	Body               []byte
+	FormData           map[string]string
+	Headers            map[string]string
+	InsecureSkipVerify bool
+	// PublicOnly menolak koneksi ke alamat internal (URL diisi pihak lain, mis. WebhookSubscription)
+	PublicOnly bool
+	// Upstream nama span trace; kosong = "webhook"
+	Upstream string
+}
+
+// Result ringkasan respons consumer untuk log delivery
+type Result struct {
+	StatusCode int
+	Body       string
+	Duration   time.Duration
+}
+
+// Success respons 2xx
+func (r Result) Success() bool {
+	return r.StatusCode >= 200 && r.StatusCode < 300
+}
+
+// Post mengirim request; error hanya untuk kegagalan jaringan, status non-2xx dicek lewat Result.Success
+func Post(ctx context.Context, req Request) (Result, error) {
+	timeout := defaultTimeout
+	if raw := os.Getenv("WEBHOOK_TIMEOUT"); raw != "" {
+		if parsed, err := time.ParseDuration(raw); err == nil && parsed > 0 {
+			timeout = parsed
+		}
+	}
+
+	var tlsConfig *tls.Config
+	if req.InsecureSkipVerify {
+		tlsConfig = &tls.Config{InsecureSkipVerify: true}
+	}
+	upstream := req.Upstream
+	if upstream == "" {
+		upstream = tracing.UpstreamWebhook
+	}
+	transport := tracing.Transport(upstream, tlsConfig)
+	if req.PublicOnly {
+		base := http.DefaultTransport.(*http.Transport).Clone()
+		base.TLSClientConfig = tlsConfig
+		// Tanpa proxy: IP yang dicek dialer harus IP consumer, bukan IP proxy
+		base.Proxy = nil
+		base.DialContext = (&net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second, Control: dialControl}).DialContext
+		transport = tracing.WrapTransport(upstream, base)
+	}
+	client := resty.New().SetTimeout(timeout).SetTransport(transport)
+
+	r := client.R().SetContext(ctx).SetHeaders(req.Headers)
+	if req.FormData != nil {
+		r.SetFormData(req.FormData)
+	} else {
+		r.SetHeader("Content-Type", "application/json").SetBody(req.Body)
+	}
+
+	started := time.Now()
+	resp, err := r.Post(req.URL)
+	result := Result{Duration: time.Since(started)}
+	if err != nil {
+		return result, fmt.Errorf("gagal mengirim request: %w", err)
+	}
+
+	result.StatusCode = resp.StatusCode()
+	body := resp.Body()
+	if len(body) > maxResponseBody {
+		body = body[:maxResponseBody]
+	}
+	result.Body = string(body)
+	return result, nil
+}
+
+// StatusError error standar untuk respons non-2xx
+func StatusError(result Result) error {
+	return fmt.Errorf("consumer membalas HTTP %d (%s)", result.StatusCode, http.StatusText(result.StatusCode))
+}
+
+// Backoff jeda sebelum percobaan berikutnya: 30s, 1m, 2m, 4m, ... maksimal 1 jam
+func Backoff(attempt int) time.Duration {
+	if attempt < 1 {
+		attempt = 1
+	}
+	delay := backoffBase
+	for i := 1; i < attempt; i++ {
+		delay *= 2
+		if delay >= backoffMax {
+			return backoffMax
+		}
+	}
+	return delay
+}
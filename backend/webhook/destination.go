@@ -0,0 +1,133 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// ErrForbiddenDestination URL subscription mengarah ke loopback / jaringan internal / metadata cloud
+var ErrForbiddenDestination = errors.New("tujuan webhook mengarah ke alamat internal yang tidak diizinkan")
+
+// Rentang yang selalu ditolak, tidak bisa dibuka lewat WEBHOOK_ALLOWED_NETWORKS
+var blockedNetworks = parseNetworks([]string{
+	"0.0.0.0/8",      // "this network"
+	"100.64.0.0/10",  // CGNAT
+	"192.0.0.0/24",   // IETF protocol assignments
+	"198.18.0.0/15",  // benchmarking
+	"240.0.0.0/4",    // reserved + broadcast
+	"64:ff9b::/96",   // NAT64 (bisa membungkus IPv4 internal)
+	"64:ff9b:1::/48", // NAT64 lokal
+})
+
+var (
+	allowedNetworksOnce sync.Once
+	allowedNetworks     []*net.IPNet
+)
+
+// privateAllowList jaringan privat yang sengaja dibuka untuk sistem kampus di intranet,
+// diisi lewat WEBHOOK_ALLOWED_NETWORKS (CIDR / IP, dipisah koma). Loopback, link-local
+// (termasuk metadata 169.254.169.254) dan rentang di blockedNetworks tetap ditolak.
+func privateAllowList() []*net.IPNet {
+	allowedNetworksOnce.Do(func() {
+		allowedNetworks = parseNetworks(strings.Split(os.Getenv("WEBHOOK_ALLOWED_NETWORKS"), ","))
+	})
+	return allowedNetworks
+}
+
+func parseNetworks(entries []string) []*net.IPNet {
+	var networks []*net.IPNet
+	for _, entry := range entries {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		if !strings.Contains(entry, "/") {
+			if ip := net.ParseIP(entry); ip != nil && ip.To4() != nil {
+				entry += "/32"
+			} else {
+				entry += "/128"
+			}
+		}
+		if _, ipNet, err := net.ParseCIDR(entry); err == nil {
+			networks = append(networks, ipNet)
+		}
+	}
+	return networks
+}
+
+func containsIP(networks []*net.IPNet, ip net.IP) bool {
+	for _, ipNet := range networks {
+		if ipNet.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckIP nil jika ip boleh dijadikan tujuan webhook
+func CheckIP(ip net.IP) error {
+	if ip4 := ip.To4(); ip4 != nil {
+		ip = ip4
+	}
+	switch {
+	case ip.IsLoopback(), ip.IsUnspecified(), ip.IsLinkLocalUnicast(), ip.IsLinkLocalMulticast(),
+		ip.IsInterfaceLocalMulticast(), ip.IsMulticast(), containsIP(blockedNetworks, ip):
+		return fmt.Errorf("%w: %s", ErrForbiddenDestination, ip)
+	case ip.IsPrivate() && !containsIP(privateAllowList(), ip):
+		return fmt.Errorf("%w: %s (tambahkan ke WEBHOOK_ALLOWED_NETWORKS jika memang sistem intranet)", ErrForbiddenDestination, ip)
+	}
+	return nil
+}
+
+// ValidateDestination memeriksa URL subscription saat dibuat / diubah: harus http/https dan
+// semua alamat hasil resolve host-nya lolos CheckIP. Saat pengiriman alamat diperiksa lagi
+// di dialer (lihat Request.PublicOnly) karena DNS bisa berubah setelah subscription disimpan.
+func ValidateDestination(ctx context.Context, rawURL string) error {
+	parsed, err := url.Parse(rawURL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Hostname() == "" {
+		return errors.New("URL webhook harus http/https yang valid")
+	}
+	if parsed.User != nil {
+		return errors.New("URL webhook tidak boleh berisi username/password")
+	}
+
+	host := parsed.Hostname()
+	if ip := net.ParseIP(host); ip != nil {
+		return CheckIP(ip)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	addrs, err := net.DefaultResolver.LookupIPAddr(ctx, host)
+	if err != nil {
+		return fmt.Errorf("host webhook %s tidak bisa di-resolve: %w", host, err)
+	}
+	for _, addr := range addrs {
+		if err := CheckIP(addr.IP); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// dialControl dipasang di net.Dialer sehingga setiap koneksi (termasuk setelah redirect) dicek
+// terhadap IP yang benar-benar dituju, bukan hasil resolve sebelumnya
+func dialControl(_, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return fmt.Errorf("%w: %s", ErrForbiddenDestination, host)
+	}
+	return CheckIP(ip)
+}
@@ -0,0 +1,41 @@
+package webhook
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Tipe event yang bisa di-subscribe
+const (
+	EventPaymentPaid          = "payment.paid"
+	EventBillCreated          = "bill.created"
+	EventPostponementApproved = "postponement.approved"
+)
+
+// EventTypes semua tipe event yang dikenal
+var EventTypes = []string{EventPaymentPaid, EventBillCreated, EventPostponementApproved}
+
+// IsKnownEvent true untuk tipe event yang dikenal atau "*"
+func IsKnownEvent(eventType string) bool {
+	if eventType == "*" {
+		return true
+	}
+	for _, t := range EventTypes {
+		if t == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// Envelope body JSON yang dikirim ke consumer
+type Envelope struct {
+	ID        string                 `json:"id"`
+	Type      string                 `json:"type"`
+	CreatedAt time.Time              `json:"created_at"`
+	Data      map[string]interface{} `json:"data"`
+}
+
+func (e Envelope) Marshal() ([]byte, error) {
+	return json.Marshal(e)
+}
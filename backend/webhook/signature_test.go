@@ -0,0 +1,82 @@
+package webhook
+
+import (
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestSign(t *testing.T) {
+	tests := []struct {
+		name      string
+		secret    string
+		timestamp int64
+		body      string
+		want      string
+	}{
+		{
+			name:      "body JSON",
+			secret:    "rahasia",
+			timestamp: 1700000000,
+			body:      `{"event":"payment.paid"}`,
+			want:      "v1=a862fee4d8791e49ece3fbb36af912372f2d9b9db149a3ffb75fa78b46c38953",
+		},
+		{
+			name:      "body kosong",
+			secret:    "rahasia",
+			timestamp: 1700000000,
+			body:      "",
+			want:      "v1=4c7bb0bd26aca6ae7c2c3e1b44b34795813cbaa61d10cb820b1bcd40e6a75c5b",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Sign(tt.secret, tt.timestamp, []byte(tt.body)); got != tt.want {
+				t.Errorf("Sign() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestVerify(t *testing.T) {
+	const secret = "rahasia"
+	body := []byte(`{"event":"payment.paid","npm":"123"}`)
+	now := time.Now().Unix()
+	valid := Sign(secret, now, body)
+	old := now - int64((10 * time.Minute).Seconds())
+
+	tests := []struct {
+		name      string
+		timestamp string
+		signature string
+		body      []byte
+		tolerance time.Duration
+		wantErr   string
+	}{
+		{name: "valid", timestamp: strconv.FormatInt(now, 10), signature: valid, body: body, tolerance: 5 * time.Minute},
+		{name: "beberapa signature saat rotasi secret", timestamp: strconv.FormatInt(now, 10), signature: Sign("lama", now, body) + ", " + valid, body: body, tolerance: 5 * time.Minute},
+		{name: "tanpa toleransi menerima timestamp lama", timestamp: strconv.FormatInt(old, 10), signature: Sign(secret, old, body), body: body},
+		{name: "timestamp lama", timestamp: strconv.FormatInt(old, 10), signature: Sign(secret, old, body), body: body, tolerance: 5 * time.Minute, wantErr: "timestamp di luar toleransi"},
+		{name: "timestamp masa depan", timestamp: strconv.FormatInt(now+600, 10), signature: Sign(secret, now+600, body), body: body, tolerance: 5 * time.Minute, wantErr: "timestamp di luar toleransi"},
+		{name: "timestamp bukan angka", timestamp: "kemarin", signature: valid, body: body, tolerance: 5 * time.Minute, wantErr: "timestamp tidak valid"},
+		{name: "timestamp header diganti", timestamp: strconv.FormatInt(now-1, 10), signature: valid, body: body, tolerance: 5 * time.Minute, wantErr: "signature tidak cocok"},
+		{name: "body diubah", timestamp: strconv.FormatInt(now, 10), signature: valid, body: []byte(`{"event":"payment.paid","npm":"456"}`), tolerance: 5 * time.Minute, wantErr: "signature tidak cocok"},
+		{name: "secret lain", timestamp: strconv.FormatInt(now, 10), signature: Sign("lain", now, body), body: body, tolerance: 5 * time.Minute, wantErr: "signature tidak cocok"},
+		{name: "tanpa prefix versi", timestamp: strconv.FormatInt(now, 10), signature: valid[len("v1="):], body: body, tolerance: 5 * time.Minute, wantErr: "signature tidak cocok"},
+		{name: "signature kosong", timestamp: strconv.FormatInt(now, 10), signature: "", body: body, tolerance: 5 * time.Minute, wantErr: "signature tidak cocok"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Verify(secret, tt.timestamp, tt.signature, tt.body, tt.tolerance)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("Verify() error = %v", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != tt.wantErr {
+				t.Fatalf("Verify() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"github.com/dedegunawan/epnbp.unsil.ac.id-students-backend2/config"
 	"github.com/dedegunawan/epnbp.unsil.ac.id-students-backend2/internal/app"
 	"github.com/dedegunawan/epnbp.unsil.ac.id-students-backend2/pkg/logger"
@@ -25,6 +26,7 @@ func main() {
 	if err != nil {
 		lg.Fatalw("bootstrap failed", "error", err)
 	}
+	defer a.ShutdownTracing(context.Background())
 
 	// start server the Gin way
 	lg.Infow("server starting (gin)", "addr", cfg.HTTPAddr)
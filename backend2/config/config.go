@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"strconv"
+	"strings"
 
 	"github.com/joho/godotenv"
 )
@@ -24,6 +25,36 @@ type RedisConfig struct {
 	RedisDB       int
 }
 
+type CacheConfig struct {
+	// CacheDriver redis | memory | none; kosong = redis jika REDIS_ADDRESS diisi, selain itu memory
+	CacheDriver     string
+	CacheTTLSeconds int
+	CachePrefix     string
+}
+
+type RateLimitConfig struct {
+	RateLimitEnabled bool
+	// RateLimitStore redis | memory; kosong = redis jika REDIS_ADDRESS diisi, selain itu memory
+	RateLimitStore string
+	// RateLimits budget per policy dari env RATE_LIMIT_<POLICY>, mis. RATE_LIMIT_USER=60/m
+	RateLimits map[string]string
+	// RateLimitAllowList IP/CIDR caller internal yang tidak dibatasi
+	RateLimitAllowList []string
+	// TrustedProxies proxy yang boleh mengisi X-Forwarded-For; tanpa ini IP client bisa dipalsukan
+	TrustedProxies []string
+}
+
+type TracingConfig struct {
+	// TracesExporter otlp | stdout | none (OTEL_TRACES_EXPORTER); endpoint OTLP lewat OTEL_EXPORTER_OTLP_ENDPOINT
+	TracesExporter string
+	ServiceName    string
+}
+
+type MetricsConfig struct {
+	// MetricsToken bearer token untuk /metrics; kosong = terbuka (batasi lewat jaringan)
+	MetricsToken string
+}
+
 type MysqlConfig struct {
 	DBHost   string
 	DBPort   string
@@ -49,6 +80,14 @@ type Config struct {
 
 	RedisConfig *RedisConfig
 
+	CacheConfig CacheConfig
+
+	RateLimitConfig RateLimitConfig
+
+	MetricsConfig MetricsConfig
+
+	TracingConfig TracingConfig
+
 	LogLevel string
 
 	// jwt config
@@ -57,6 +96,10 @@ type Config struct {
 	JWTExpiresMinutes int
 
 	OIDCConfig OIDCConfig
+
+	// EligibilityRules override aturan kelayakan per status akademik (ELIGIBILITY_RULES), format sama
+	// dengan backend lama; nilai tidak valid menghentikan start
+	EligibilityRules string
 }
 
 func LoadDotEnv() error {
@@ -89,6 +132,8 @@ func FromEnv() Config {
 
 		LogLevel: get("LOG_LEVEL", "info"),
 
+		EligibilityRules: get("ELIGIBILITY_RULES", ""),
+
 		JWTSecret:         get("JWT_SECRET", "please-change-me-32chars-min"),
 		JWTIssuer:         get("JWT_ISSUER", "golang-clean-architecture"),
 		JWTExpiresMinutes: atoi(get("JWT_EXPIRES_MINUTES", "60")),
@@ -108,6 +153,32 @@ func FromEnv() Config {
 			RedisPassword: get("REDIS_PASSWORD", ""),
 			RedisDB:       atoi(get("REDIS_DB", "0")),
 		},
+
+		CacheConfig: CacheConfig{
+			CacheDriver:     get("CACHE_DRIVER", ""),
+			CacheTTLSeconds: atoi(get("CACHE_TTL_SECONDS", "300")),
+			CachePrefix:     get("CACHE_PREFIX", "epnbp2:"),
+		},
+
+		RateLimitConfig: RateLimitConfig{
+			RateLimitEnabled: get("RATE_LIMIT_ENABLED", "true") != "false",
+			RateLimitStore:   get("RATE_LIMIT_STORE", ""),
+			RateLimits: map[string]string{
+				"default": get("RATE_LIMIT_DEFAULT", ""),
+				"user":    get("RATE_LIMIT_USER", ""),
+			},
+			RateLimitAllowList: splitList(get("RATE_LIMIT_ALLOWLIST", "")),
+			TrustedProxies:     splitList(get("TRUSTED_PROXIES", "")),
+		},
+
+		MetricsConfig: MetricsConfig{
+			MetricsToken: get("METRICS_TOKEN", ""),
+		},
+
+		TracingConfig: TracingConfig{
+			TracesExporter: get("OTEL_TRACES_EXPORTER", "none"),
+			ServiceName:    get("OTEL_SERVICE_NAME", "epnbp-backend2"),
+		},
 	}
 }
 func get(k, def string) string {
@@ -117,6 +188,16 @@ func get(k, def string) string {
 	return def
 }
 
+func splitList(s string) []string {
+	var out []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			out = append(out, item)
+		}
+	}
+	return out
+}
+
 func atoi(s string) int {
 	i, _ := strconv.Atoi(s)
 	return i
@@ -0,0 +1,3 @@
+-- role_permissions & user_roles ikut terhapus lewat ON DELETE CASCADE
+DELETE FROM roles WHERE name = 'admin';
+DELETE FROM permissions WHERE name IN ('user.manage', 'role.manage');
@@ -0,0 +1,14 @@
+-- Permission bawaan endpoint administrasi user / role / permission
+INSERT IGNORE INTO permissions (name, description, created_at, updated_at) VALUES
+    ('user.manage', 'Kelola user dan role user', NOW(), NOW()),
+    ('role.manage', 'Kelola role, permission dan permission role', NOW(), NOW());
+
+-- Role admin memiliki semua permission administrasi
+INSERT IGNORE INTO roles (name, description, created_at, updated_at) VALUES
+    ('admin', 'Administrator aplikasi', NOW(), NOW());
+
+INSERT IGNORE INTO role_permissions (role_id, permission_id, created_at)
+SELECT r.id, p.id, NOW()
+FROM roles r
+JOIN permissions p ON p.name IN ('user.manage', 'role.manage')
+WHERE r.name = 'admin';
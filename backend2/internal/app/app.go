@@ -3,19 +3,27 @@ package app
 
 import (
 	"context"
+	"fmt"
 	"github.com/dedegunawan/epnbp.unsil.ac.id-students-backend2/internal/domain/repository"
 	"github.com/dedegunawan/epnbp.unsil.ac.id-students-backend2/internal/domain/usecase"
 	"github.com/dedegunawan/epnbp.unsil.ac.id-students-backend2/internal/server/middleware"
 	"github.com/dedegunawan/epnbp.unsil.ac.id-students-backend2/internal/transport/http/auth"
 	"github.com/dedegunawan/epnbp.unsil.ac.id-students-backend2/internal/transport/http/mahasiswa"
+	"github.com/dedegunawan/epnbp.unsil.ac.id-students-backend2/internal/transport/http/rbac"
+	"github.com/dedegunawan/epnbp.unsil.ac.id-students-backend2/internal/transport/http/user"
 	"github.com/dedegunawan/epnbp.unsil.ac.id-students-backend2/pkg/authoidc"
 	"github.com/dedegunawan/epnbp.unsil.ac.id-students-backend2/pkg/jwtmanager"
+	"github.com/dedegunawan/epnbp.unsil.ac.id-students-backend2/pkg/metrics"
+	"github.com/dedegunawan/epnbp.unsil.ac.id-students-backend2/pkg/ratelimit"
 	"github.com/dedegunawan/epnbp.unsil.ac.id-students-backend2/pkg/redis"
+	"github.com/dedegunawan/epnbp.unsil.ac.id-students-backend2/pkg/tracing"
+	"github.com/dedegunawan/epnbp.unsil.ac.id-students-backend2/pkg/validator"
 	"log"
 	"os"
 	"time"
 
 	"github.com/dedegunawan/epnbp.unsil.ac.id-students-backend2/config"
+	"github.com/dedegunawan/epnbp.unsil.ac.id-students-backend2/internal/repository_implementation/cached"
 	repositoryImpelementation "github.com/dedegunawan/epnbp.unsil.ac.id-students-backend2/internal/repository_implementation/mysql"
 	"github.com/dedegunawan/epnbp.unsil.ac.id-students-backend2/internal/server"
 	"github.com/dedegunawan/epnbp.unsil.ac.id-students-backend2/pkg/logger"
@@ -30,9 +38,18 @@ type App struct {
 	DB     map[string]*gorm.DB
 	Redis  *redis.RedisClient
 	logger *logger.Logger
+
+	// ShutdownTracing flush span tersisa saat aplikasi berhenti
+	ShutdownTracing tracing.ShutdownFunc
 }
 
 func New(cfg config.Config, lg *logger.Logger) (*App, error) {
+	// tracing dipasang paling awal supaya client yang dibuat setelahnya ikut ter-trace
+	shutdownTracing, err := tracing.Init(context.Background(), cfg.TracingConfig.TracesExporter, cfg.TracingConfig.ServiceName)
+	if err != nil {
+		return nil, err
+	}
+
 	// ✅ Pakai stdout (atau ganti os.Stdout -> io.Discard kalau mau senyap total)
 	stdlog := log.New(os.Stdout, "[gorm] ", log.LstdFlags)
 
@@ -47,6 +64,9 @@ func New(cfg config.Config, lg *logger.Logger) (*App, error) {
 	if err != nil {
 		return nil, err
 	}
+	if err := db.Use(metrics.NewGormPlugin("db1")); err != nil {
+		return nil, err
+	}
 	dbs["db1"] = db
 
 	// db pnbp
@@ -54,6 +74,9 @@ func New(cfg config.Config, lg *logger.Logger) (*App, error) {
 	if err != nil {
 		return nil, err
 	}
+	if err := dbPnbp.Use(metrics.NewGormPlugin("pnbp")); err != nil {
+		return nil, err
+	}
 	dbs["pnbp"] = dbPnbp
 
 	// load jwt library
@@ -72,51 +95,107 @@ func New(cfg config.Config, lg *logger.Logger) (*App, error) {
 		return nil, err
 	}
 
+	// cache token / profil / periode aktif
+	appCache, redisClient, err := newCache(cfg, lg)
+	if err != nil {
+		return nil, err
+	}
+	cacheTTL := time.Duration(cfg.CacheConfig.CacheTTLSeconds) * time.Second
+
+	rateLimiter, err := newRateLimiter(cfg, redisClient, lg)
+	if err != nil {
+		return nil, err
+	}
+
 	// repository / database impelementation
-	userRepository := repositoryImpelementation.NewUserRepository(db)
+	userRepository := cached.NewUserRepository(repositoryImpelementation.NewUserRepository(db), appCache, cacheTTL, lg)
 	roleRepository := repositoryImpelementation.NewRoleRepository(db)
 	permissionRepository := repositoryImpelementation.NewPermissionRepository(db)
 	rolePermissionRepository := repositoryImpelementation.NewRolePermissionRepository(db)
-	userTokenRepository := repositoryImpelementation.NewUserTokenRepository(db)
-	mahasiswaRepository := repositoryImpelementation.NewMahasiswaRepository(dbPnbp)
-	budgetPeriodRepository := repositoryImpelementation.NewBudgetPeriodRepository(dbPnbp, lg)
+	userRoleRepository := repositoryImpelementation.NewUserRoleRepository(db)
+	userTokenRepository := cached.NewUserTokenRepository(repositoryImpelementation.NewUserTokenRepository(db), appCache, cacheTTL, lg)
+	mahasiswaRepository := cached.NewMahasiswaRepository(repositoryImpelementation.NewMahasiswaRepository(dbPnbp), appCache, cacheTTL, lg)
+	budgetPeriodRepository := cached.NewBudgetPeriodRepository(repositoryImpelementation.NewBudgetPeriodRepository(dbPnbp, lg), appCache, cacheTTL, lg)
+	cicilanRepository := repositoryImpelementation.NewCicilanRepository(dbPnbp, lg)
+	registrasiMahasiswaRepository := repositoryImpelementation.NewRegistrasiMahasiswaRepository(dbPnbp, lg)
+	beasiswaRepository := repositoryImpelementation.NewBeasiswaRepository(dbPnbp)
+	bantuanUKTRepository := repositoryImpelementation.NewBantuanUKTRepository(dbPnbp)
+	statusAkademikRepository := repositoryImpelementation.NewStatusAkademikRepository(dbPnbp)
 
 	repositories := repository.NewRepository(
 		userRepository,
 		roleRepository,
 		permissionRepository,
 		rolePermissionRepository,
+		userRoleRepository,
 		userTokenRepository,
 		mahasiswaRepository,
 		budgetPeriodRepository,
+		cicilanRepository,
+		registrasiMahasiswaRepository,
+		beasiswaRepository,
+		bantuanUKTRepository,
+		statusAkademikRepository,
 	)
 
 	// service / usecase
 	userUsecase := usecase.NewUserUsecase(repositories.UserRepository)
 	roleUsecase := usecase.NewRoleUsecase(repositories.RoleRepository)
 	permissionUsecase := usecase.NewPermissionUsecase(repositories.PermissionRepository)
-	rolePermissionUsecase := usecase.NewRolePermissionUsecase(repositories.RolePermissionRepository)
+	rolePermissionUsecase := usecase.NewRolePermissionUsecase(
+		repositories.RolePermissionRepository,
+		repositories.RoleRepository,
+		repositories.PermissionRepository,
+	)
+	userRoleUsecase := usecase.NewUserRoleUsecase(
+		repositories.UserRoleRepository,
+		repositories.UserRepository,
+		repositories.RoleRepository,
+	)
 	userTokenUsecase := usecase.NewUserTokenUsecase(repositories.UserTokenRepository, context.Background(), lg, jwt)
 	mahasiswaUsecase := usecase.NewMahasiswaUsecase(repositories.MahasiswaRepository, lg)
 	budgetPeriodUsecase := usecase.NewBudgetPeriodUsecase(repositories.BudgetPeriodRepository, lg)
+	billingUsecase := usecase.NewBillingUsecase(
+		repositories.CicilanRepository,
+		repositories.RegistrasiMahasiswaRepository,
+		repositories.BeasiswaRepository,
+		repositories.BantuanUKTRepository,
+		lg,
+	)
+
+	eligibilityRules, err := usecase.ParseEligibilityRules(cfg.EligibilityRules)
+	if err != nil {
+		return nil, fmt.Errorf("ELIGIBILITY_RULES tidak valid: %w", err)
+	}
+	eligibilityUsecase := usecase.NewEligibilityUsecase(repositories.StatusAkademikRepository, eligibilityRules, lg)
 
 	usecases := usecase.NewUsecase(
 		userUsecase,
 		roleUsecase,
 		permissionUsecase,
 		rolePermissionUsecase,
+		userRoleUsecase,
 		userTokenUsecase,
 		mahasiswaUsecase,
 		budgetPeriodUsecase,
+		billingUsecase,
+		eligibilityUsecase,
 	)
 
+	// aturan validasi tambahan untuk binding request
+	validator.Register()
+
 	// handler
 	authSsoHandler := auth.NewAuthSsoHandler(*usecases, *authOidc, lg, *jwt)
 	mahasiswaHandler := mahasiswa.NewMahasiswaHandler(lg, usecases)
-	//userHandler := userHttp.NewUserHandler(userSvc)
+	userHandler := user.NewUserHandler(*usecases)
+	roleHandler := rbac.NewRoleHandler(usecases)
+	permissionHandler := rbac.NewPermissionHandler(usecases)
 
 	// auth middleware
 	authMiddleware := middleware.NewJwtMiddleware(jwt, lg, usecases, authOidc)
+	permissionMiddleware := middleware.NewPermissionMiddleware(lg, usecases)
+	rateLimitMiddleware := middleware.NewRateLimitMiddleware(lg, rateLimiter, cfg.RateLimitConfig.RateLimitEnabled)
 
 	// Container: middleware (jadikan gin.HandlerFunc di sini)
 	m := &server.Middleware{
@@ -125,19 +204,33 @@ func New(cfg config.Config, lg *logger.Logger) (*App, error) {
 		Logger:    middleware.ZapLogger(lg),
 		Recovery:  middleware.Recovery(lg),
 		// unimplemented cors
-		CORS: middleware.DefaultMiddleware(),
-		Rate: middleware.DefaultMiddleware(300),
+		CORS:    middleware.DefaultMiddleware(),
+		Rate:    rateLimitMiddleware.Limit(ratelimit.PolicyDefault),
+		Metrics: middleware.Metrics(),
+		Tracing: middleware.Tracing(),
+
+		RateLimit:         rateLimitMiddleware.Limit,
+		RequirePermission: permissionMiddleware.RequirePermission,
 	}
 
 	// Daftarkan semua route terpusat
 	handlers := &server.Handlers{
-		AuthSSO:   authSsoHandler,
-		Mahasiswa: mahasiswaHandler,
+		AuthSSO:    authSsoHandler,
+		User:       userHandler,
+		Mahasiswa:  mahasiswaHandler,
+		Role:       roleHandler,
+		Permission: permissionHandler,
+		Metrics:    metrics.Handler(cfg.MetricsConfig.MetricsToken),
 	}
 
 	r := server.New(lg)
+	if proxies := cfg.RateLimitConfig.TrustedProxies; len(proxies) > 0 {
+		if err := r.SetTrustedProxies(proxies); err != nil {
+			return nil, err
+		}
+	}
 
 	server.RegisterRoutes(r.Engine, handlers, m)
 
-	return &App{Router: r, DB: dbs, logger: lg, Redis: nil}, nil
+	return &App{Router: r, DB: dbs, logger: lg, Redis: redisClient, ShutdownTracing: shutdownTracing}, nil
 }
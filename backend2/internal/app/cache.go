@@ -0,0 +1,48 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/dedegunawan/epnbp.unsil.ac.id-students-backend2/config"
+	"github.com/dedegunawan/epnbp.unsil.ac.id-students-backend2/pkg/cache"
+	"github.com/dedegunawan/epnbp.unsil.ac.id-students-backend2/pkg/logger"
+	"github.com/dedegunawan/epnbp.unsil.ac.id-students-backend2/pkg/redis"
+)
+
+// newCache memilih driver cache dari CACHE_DRIVER; redis client ikut dikembalikan untuk App.Redis
+func newCache(cfg config.Config, lg *logger.Logger) (cache.Cache, *redis.RedisClient, error) {
+	driver := cfg.CacheConfig.CacheDriver
+	if driver == "" {
+		driver = cache.DriverMemory
+		if cfg.RedisConfig != nil && cfg.RedisConfig.RedisAddress != "" {
+			driver = cache.DriverRedis
+		}
+	}
+
+	switch driver {
+	case cache.DriverRedis:
+		if cfg.RedisConfig == nil || cfg.RedisConfig.RedisAddress == "" {
+			return nil, nil, fmt.Errorf("CACHE_DRIVER=redis requires REDIS_ADDRESS")
+		}
+		client := redis.NewRedisClient(*cfg.RedisConfig)
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := client.Ping(ctx); err != nil {
+			return nil, nil, fmt.Errorf("connect redis: %w", err)
+		}
+		lg.Infow("cache driver", "driver", driver, "addr", cfg.RedisConfig.RedisAddress)
+		return cache.NewRedis(client.Client(), cfg.CacheConfig.CachePrefix), client, nil
+	case cache.DriverMemory:
+		memory := cache.NewMemory()
+		memory.StartPurge(context.Background(), time.Minute)
+		lg.Infow("cache driver", "driver", driver)
+		return memory, nil, nil
+	case cache.DriverNone:
+		lg.Infow("cache driver", "driver", driver)
+		return cache.Noop{}, nil, nil
+	default:
+		return nil, nil, fmt.Errorf("unknown CACHE_DRIVER %q", driver)
+	}
+}
@@ -0,0 +1,55 @@
+package app
+
+import (
+	"fmt"
+
+	"github.com/dedegunawan/epnbp.unsil.ac.id-students-backend2/config"
+	"github.com/dedegunawan/epnbp.unsil.ac.id-students-backend2/pkg/logger"
+	"github.com/dedegunawan/epnbp.unsil.ac.id-students-backend2/pkg/ratelimit"
+	"github.com/dedegunawan/epnbp.unsil.ac.id-students-backend2/pkg/redis"
+)
+
+// newRateLimiter memakai Redis yang sama dengan cache jika tersedia supaya budget berlaku untuk semua instance
+func newRateLimiter(cfg config.Config, redisClient *redis.RedisClient, lg *logger.Logger) (*ratelimit.Limiter, error) {
+	rlCfg := cfg.RateLimitConfig
+
+	limits := make(map[string]ratelimit.Limit, len(ratelimit.DefaultLimits))
+	for policy, fallback := range ratelimit.DefaultLimits {
+		raw := rlCfg.RateLimits[policy]
+		if raw == "" {
+			raw = fallback
+		}
+		limit, err := ratelimit.ParseLimit(raw)
+		if err != nil {
+			return nil, fmt.Errorf("RATE_LIMIT_%s: %w", policy, err)
+		}
+		limits[policy] = limit
+	}
+
+	driver := rlCfg.RateLimitStore
+	if driver == "" {
+		driver = "memory"
+		if redisClient != nil {
+			driver = "redis"
+		}
+	}
+
+	var store ratelimit.Store
+	switch driver {
+	case "redis":
+		if redisClient == nil {
+			if cfg.RedisConfig == nil || cfg.RedisConfig.RedisAddress == "" {
+				return nil, fmt.Errorf("RATE_LIMIT_STORE=redis requires REDIS_ADDRESS")
+			}
+			redisClient = redis.NewRedisClient(*cfg.RedisConfig)
+		}
+		store = ratelimit.NewRedisStore(redisClient.Client(), cfg.CacheConfig.CachePrefix+"ratelimit:")
+	case "memory":
+		store = ratelimit.NewMemoryStore()
+	default:
+		return nil, fmt.Errorf("unknown RATE_LIMIT_STORE %q", driver)
+	}
+
+	lg.Infow("rate limiter", "store", store.Name(), "enabled", rlCfg.RateLimitEnabled, "limits", limits)
+	return ratelimit.NewLimiter(store, limits, rlCfg.RateLimitAllowList)
+}
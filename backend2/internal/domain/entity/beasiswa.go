@@ -0,0 +1,38 @@
+package entity
+
+import "time"
+
+type Beasiswa struct {
+	ID           uint64     `gorm:"primaryKey;autoIncrement" json:"id"`
+	NoSK         string     `gorm:"column:no_sk;size:191;unique;not null" json:"no_sk"`
+	Deskripsi    string     `gorm:"type:text;not null" json:"deskripsi"`
+	TanggalSK    time.Time  `gorm:"column:tanggal_sk;type:date;not null" json:"tanggal_sk"`
+	FileBeasiswa *string    `gorm:"column:file_beasiswa;size:191" json:"file_beasiswa"`
+	Status       string     `gorm:"type:enum('draft','active','inactive');default:'draft';not null" json:"status"`
+	CreatedAt    *time.Time `json:"created_at"`
+	UpdatedAt    *time.Time `json:"updated_at"`
+
+	Details []DetailBeasiswa `gorm:"foreignKey:BeasiswaID" json:"details,omitempty"`
+}
+
+func (Beasiswa) TableName() string {
+	return "beasiswa"
+}
+
+type DetailBeasiswa struct {
+	ID                 uint64     `gorm:"primaryKey;autoIncrement" json:"id"`
+	BeasiswaID         uint64     `gorm:"column:beasiswa_id;not null" json:"beasiswa_id"`
+	NPM                string     `gorm:"size:191;not null" json:"npm"`
+	TahunID            string     `gorm:"column:tahun_id;size:191;not null" json:"tahun_id"`
+	KelompokUKTSaatIni string     `gorm:"column:kel_ukt_saat_ini;size:191;not null" json:"kel_ukt_saat_ini"`
+	NominalUKTSaatIni  float64    `gorm:"column:nominal_ukt_saat_ini;type:decimal(15,2);not null" json:"nominal_ukt_saat_ini"`
+	JenisBeasiswa      string     `gorm:"size:191;not null" json:"jenis_beasiswa"`
+	NominalBeasiswa    float64    `gorm:"type:decimal(15,2);not null" json:"nominal_beasiswa"`
+	NominalYangDibayar float64    `gorm:"column:nominal_yang_dibayar;type:decimal(15,2);not null;default:0.00" json:"nominal_yang_dibayar"`
+	CreatedAt          *time.Time `json:"created_at"`
+	UpdatedAt          *time.Time `json:"updated_at"`
+}
+
+func (DetailBeasiswa) TableName() string {
+	return "detail_beasiswa"
+}
@@ -0,0 +1,37 @@
+package entity
+
+import "time"
+
+// Cicilan pengajuan cicilan UKT mahasiswa per tahun_id (tabel cicilans di database PNBP)
+type Cicilan struct {
+	ID            uint64    `gorm:"primaryKey" json:"id"`
+	NPM           string    `gorm:"column:npm;size:20;not null" json:"npm"`
+	TahunID       string    `gorm:"column:tahun_id;size:10;not null" json:"tahun_id"`
+	KelUkt        string    `gorm:"column:kel_ukt;size:10" json:"kel_ukt"`
+	NominalUkt    int64     `gorm:"column:nominal_ukt" json:"nominal_ukt"`
+	JumlahCicilan int       `gorm:"column:jumlah_cicilan" json:"jumlah_cicilan"`
+	Catatan       string    `gorm:"column:catatan;type:text" json:"catatan"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+
+	DetailCicilan []DetailCicilan `gorm:"foreignKey:CicilanID" json:"detail_cicilan,omitempty"`
+}
+
+func (Cicilan) TableName() string {
+	return "cicilans"
+}
+
+// DetailCicilan angsuran dari sebuah cicilan
+type DetailCicilan struct {
+	ID         uint64    `gorm:"primaryKey" json:"id"`
+	CicilanID  uint64    `gorm:"column:cicilan_id" json:"cicilan_id"`
+	SequenceNo int       `gorm:"column:sequence_no" json:"sequence_no"`
+	DueDate    time.Time `gorm:"column:due_date" json:"due_date"`
+	Amount     int64     `gorm:"column:amount" json:"amount"`
+	Status     string    `gorm:"column:status;size:50" json:"status"`
+	Catatan    string    `gorm:"column:catatan;type:text" json:"catatan"`
+}
+
+func (DetailCicilan) TableName() string {
+	return "detail_cicilans"
+}
@@ -0,0 +1,53 @@
+package entity
+
+// Outcome kelayakan penagihan berdasarkan status akademik mahasiswa
+const (
+	EligibilityBillFull    = "bill_full"    // Tagihan penuh
+	EligibilityBillReduced = "bill_reduced" // Tagihan dengan potongan persentase
+	EligibilityNoBill      = "no_bill"      // Tidak ditagih (mis. lulus)
+	EligibilityBlock       = "block"        // Diblokir, tidak boleh membuat tagihan/VA
+)
+
+// EligibilityRule aturan untuk satu kode status akademik (status_akademiks.kode)
+type EligibilityRule struct {
+	Kode       string `json:"kode"`                 // Kode status, mis. "A", "C", "N", "L", "D"
+	Label      string `json:"label"`                // Label pendek untuk reason, mis. "aktif", "cuti"
+	Outcome    string `json:"outcome"`              // Salah satu konstanta Eligibility*
+	Percentage int    `json:"percentage,omitempty"` // Persentase yang ditagih untuk bill_reduced (1-99)
+}
+
+// EligibilityResult hasil evaluasi kelayakan yang dikirim ke API.
+// Bentuk JSON sama dengan EligibilityResult di backend lama.
+type EligibilityResult struct {
+	StatusKode string `json:"status_kode"`
+	StatusNama string `json:"status_nama"`
+	Outcome    string `json:"outcome"`
+	Reason     string `json:"reason"` // Machine-readable, mis. "status_cuti", "status_unknown"
+	Percentage int    `json:"percentage,omitempty"`
+	CanBill    bool   `json:"can_bill"` // true jika boleh menampilkan tagihan
+	// CanGenerateVA true hanya untuk bill_full: VA EPNBP selalu dibuat sebesar nominal penuh,
+	// jadi tagihan bill_reduced dibuatkan VA oleh bagian keuangan, bukan dari aplikasi ini
+	CanGenerateVA bool `json:"can_generate_va"`
+}
+
+// IsBlocked true jika mahasiswa tidak boleh mendapatkan tagihan sama sekali
+func (r *EligibilityResult) IsBlocked() bool {
+	return r.Outcome == EligibilityBlock
+}
+
+// IsNotBilled true jika mahasiswa tidak ditagih (bukan berarti lunas)
+func (r *EligibilityResult) IsNotBilled() bool {
+	return r.Outcome == EligibilityNoBill
+}
+
+// ApplyToAmount menghitung nominal yang ditagih sesuai outcome
+func (r *EligibilityResult) ApplyToAmount(amount int64) int64 {
+	switch r.Outcome {
+	case EligibilityBillFull:
+		return amount
+	case EligibilityBillReduced:
+		return amount * int64(r.Percentage) / 100
+	default:
+		return 0
+	}
+}
@@ -14,3 +14,9 @@ type Permission struct {
 	UpdatedAt   time.Time      `json:"updated_at"`
 	DeletedAt   gorm.DeletedAt `gorm:"index" json:"-"`
 }
+
+// Permission bawaan untuk endpoint administrasi (di-seed oleh migrasi 000003)
+const (
+	PermissionManageUsers = "user.manage"
+	PermissionManageRoles = "role.manage"
+)
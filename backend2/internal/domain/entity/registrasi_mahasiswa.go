@@ -0,0 +1,36 @@
+package entity
+
+import "time"
+
+// RegistrasiMahasiswa tagihan UKT hasil registrasi per tahun_id (tabel registrasi_mahasiswa di database PNBP)
+type RegistrasiMahasiswa struct {
+	ID                 uint64     `gorm:"primaryKey;column:id" json:"id"`
+	NPM                string     `gorm:"column:npm;size:191;not null" json:"npm"`
+	TahunID            string     `gorm:"column:tahun_id;size:191;not null" json:"tahun_id"`
+	KelUKT             *string    `gorm:"column:kel_ukt;size:191" json:"kel_ukt"`
+	IDUKT              *string    `gorm:"column:id_ukt;size:191" json:"id_ukt"`
+	NominalUKT         *float64   `gorm:"column:nominal_ukt;type:decimal(15,2)" json:"nominal_ukt"`
+	SudahBayar         bool       `gorm:"column:sudah_bayar;default:0" json:"sudah_bayar"`
+	NominalBayar       *float64   `gorm:"column:nominal_bayar;type:decimal(15,2)" json:"nominal_bayar"`
+	StatusStudentEPNBP *string    `gorm:"column:status_student_epnbp;size:191" json:"status_student_epnbp"`
+	CreatedAt          *time.Time `gorm:"column:created_at" json:"created_at"`
+	UpdatedAt          *time.Time `gorm:"column:updated_at" json:"updated_at"`
+}
+
+func (RegistrasiMahasiswa) TableName() string {
+	return "registrasi_mahasiswa"
+}
+
+func (r RegistrasiMahasiswa) NominalUKTInt() int64 {
+	if r.NominalUKT == nil {
+		return 0
+	}
+	return int64(*r.NominalUKT)
+}
+
+func (r RegistrasiMahasiswa) NominalBayarInt() int64 {
+	if r.NominalBayar == nil || *r.NominalBayar < 0 {
+		return 0
+	}
+	return int64(*r.NominalBayar)
+}
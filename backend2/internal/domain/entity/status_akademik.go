@@ -0,0 +1,18 @@
+package entity
+
+import "time"
+
+// StatusAkademik referensi status akademik mahasiswa di database PNBP (mis. "A" aktif, "C" cuti)
+type StatusAkademik struct {
+	ID        uint64    `gorm:"primaryKey" json:"id"`
+	Kode      string    `gorm:"size:10" json:"kode"`
+	Nama      string    `gorm:"size:191" json:"nama"`
+	Deskripsi string    `gorm:"type:text" json:"deskripsi"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TableName overrides the default table name
+func (StatusAkademik) TableName() string {
+	return "status_akademiks"
+}
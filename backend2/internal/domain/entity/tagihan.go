@@ -0,0 +1,43 @@
+package entity
+
+import "time"
+
+const (
+	TagihanSourceCicilan    = "cicilan"
+	TagihanSourceRegistrasi = "registrasi"
+
+	TagihanStatusPaid    = "paid"
+	TagihanStatusPartial = "partial"
+	TagihanStatusUnpaid  = "unpaid"
+)
+
+// Tagihan satu baris tagihan mahasiswa, bersumber dari cicilan atau registrasi_mahasiswa.
+// Bentuk JSON sama dengan TagihanResponse di backend lama.
+type Tagihan struct {
+	ID               uint64     `json:"id"`
+	Source           string     `json:"source"` // "cicilan" atau "registrasi"
+	NPM              string     `json:"npm"`
+	TahunID          string     `json:"tahun_id"`
+	AcademicYear     string     `json:"academic_year"`
+	BillName         string     `json:"bill_name"`
+	Amount           int64      `json:"amount"`
+	PaidAmount       int64      `json:"paid_amount"`
+	RemainingAmount  int64      `json:"remaining_amount"`
+	Beasiswa         int64      `json:"beasiswa"`
+	BantuanUKT       int64      `json:"bantuan_ukt"`
+	PotonganStatus   int64      `json:"potongan_status,omitempty"` // potongan dari aturan kelayakan status akademik
+	Status           string     `json:"status"`                    // "paid", "unpaid", "partial"
+	PaymentStartDate time.Time  `json:"payment_start_date"`
+	PaymentEndDate   *time.Time `json:"payment_end_date,omitempty"` // cicilan tidak punya batas akhir
+	CreatedAt        time.Time  `json:"created_at"`
+	UpdatedAt        time.Time  `json:"updated_at"`
+
+	// Untuk cicilan
+	CicilanID       *uint64 `json:"cicilan_id,omitempty"`
+	DetailCicilanID *uint64 `json:"detail_cicilan_id,omitempty"`
+	SequenceNo      *int    `json:"sequence_no,omitempty"`
+
+	// Untuk registrasi
+	RegistrasiID *uint64 `json:"registrasi_id,omitempty"`
+	KelUKT       *string `json:"kel_ukt,omitempty"`
+}
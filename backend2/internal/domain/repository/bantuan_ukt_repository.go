@@ -0,0 +1,6 @@
+package repository
+
+type BantuanUKTRepository interface {
+	// TotalByNPMAndTahun total bantuan UKT mahasiswa pada tahun_id
+	TotalByNPMAndTahun(npm string, tahunID string) (int64, error)
+}
@@ -0,0 +1,6 @@
+package repository
+
+type BeasiswaRepository interface {
+	// TotalActiveByNPMAndTahun total nominal beasiswa aktif mahasiswa pada tahun_id
+	TotalActiveByNPMAndTahun(npm string, tahunID string) (int64, error)
+}
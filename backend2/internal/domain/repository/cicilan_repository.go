@@ -0,0 +1,12 @@
+package repository
+
+import "github.com/dedegunawan/epnbp.unsil.ac.id-students-backend2/internal/domain/entity"
+
+type CicilanRepository interface {
+	// GetTahunIDs tahun_id cicilan milik mahasiswa sampai maxTahunID, urut ascending
+	GetTahunIDs(npm string, maxTahunID string) ([]string, error)
+	// FindByNPMAndTahun cicilan beserta detail angsurannya
+	FindByNPMAndTahun(npm string, tahunID string) ([]entity.Cicilan, error)
+	// GetPaidAmount total pembayaran invoice berstatus Paid untuk satu angsuran
+	GetPaidAmount(detailCicilanID uint64) (int64, error)
+}
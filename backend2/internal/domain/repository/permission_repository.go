@@ -3,6 +3,14 @@ package repository
 import "github.com/dedegunawan/epnbp.unsil.ac.id-students-backend2/internal/domain/entity"
 
 type PermissionRepository interface {
+	Create(permission *entity.Permission) error
+	Update(permission *entity.Permission) error
+	Delete(permissionID uint64) error
+	FindByID(id uint64) (*entity.Permission, error)
+	FindByName(name string) (*entity.Permission, error)
 	GetAll() ([]entity.Permission, error)
+	List(page, size int) ([]entity.Permission, int64, error)
 	GetByRoleID(roleID uint64) ([]entity.Permission, error)
+	// UserHasPermission true jika salah satu role user memiliki permission tersebut
+	UserHasPermission(userID uint64, name string) (bool, error)
 }
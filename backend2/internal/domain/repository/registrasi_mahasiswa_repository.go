@@ -0,0 +1,11 @@
+package repository
+
+import "github.com/dedegunawan/epnbp.unsil.ac.id-students-backend2/internal/domain/entity"
+
+type RegistrasiMahasiswaRepository interface {
+	// GetTahunIDs tahun_id registrasi milik mahasiswa sampai maxTahunID, urut ascending
+	GetTahunIDs(npm string, maxTahunID string) ([]string, error)
+	FindByNPMAndTahun(npm string, tahunID string) ([]entity.RegistrasiMahasiswa, error)
+	// FindPaidByNPMAndTahun registrasi berstatus paid/lunas atau sudah ada nominal_bayar
+	FindPaidByNPMAndTahun(npm string, tahunID string) ([]entity.RegistrasiMahasiswa, error)
+}
@@ -5,22 +5,39 @@ type Repository struct {
 	RoleRepository           RoleRepository
 	PermissionRepository     PermissionRepository
 	RolePermissionRepository RolePermissionRepository
+	UserRoleRepository       UserRoleRepository
 	UserTokenRepository      UserTokenRepository
 	MahasiswaRepository      MahasiswaRepository
 	BudgetPeriodRepository   BudgetPeriodRepository
+
+	CicilanRepository             CicilanRepository
+	RegistrasiMahasiswaRepository RegistrasiMahasiswaRepository
+	BeasiswaRepository            BeasiswaRepository
+	BantuanUKTRepository          BantuanUKTRepository
+	StatusAkademikRepository      StatusAkademikRepository
 }
 
 func NewRepository(user UserRepository, role RoleRepository, permission PermissionRepository, rolePemission RolePermissionRepository,
-	userToken UserTokenRepository, mahasiswa MahasiswaRepository,
+	userRole UserRoleRepository, userToken UserTokenRepository, mahasiswa MahasiswaRepository,
 	budgetPeriodRepository BudgetPeriodRepository,
+	cicilan CicilanRepository, registrasiMahasiswa RegistrasiMahasiswaRepository,
+	beasiswa BeasiswaRepository, bantuanUKT BantuanUKTRepository,
+	statusAkademik StatusAkademikRepository,
 ) *Repository {
 	return &Repository{
 		UserRepository:           user,
 		RoleRepository:           role,
 		PermissionRepository:     permission,
 		RolePermissionRepository: rolePemission,
+		UserRoleRepository:       userRole,
 		UserTokenRepository:      userToken,
 		MahasiswaRepository:      mahasiswa,
 		BudgetPeriodRepository:   budgetPeriodRepository,
+
+		CicilanRepository:             cicilan,
+		RegistrasiMahasiswaRepository: registrasiMahasiswa,
+		BeasiswaRepository:            beasiswa,
+		BantuanUKTRepository:          bantuanUKT,
+		StatusAkademikRepository:      statusAkademik,
 	}
 }
@@ -1,6 +1,7 @@
 package repository
 
 type RolePermissionRepository interface {
+	// AssignPermission idempotent: tidak error jika permission sudah dimiliki role
 	AssignPermission(roleID, permissionID uint64) error
 	RemovePermission(roleID, permissionID uint64) error
 }
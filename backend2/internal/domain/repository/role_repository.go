@@ -7,5 +7,7 @@ type RoleRepository interface {
 	Update(role *entity.Role) error
 	Delete(roleID uint64) error
 	FindByID(id uint64) (*entity.Role, error)
+	FindByName(name string) (*entity.Role, error)
 	GetAll() ([]entity.Role, error)
+	List(page, size int) ([]entity.Role, int64, error)
 }
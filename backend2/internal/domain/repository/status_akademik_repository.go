@@ -0,0 +1,7 @@
+package repository
+
+import "github.com/dedegunawan/epnbp.unsil.ac.id-students-backend2/internal/domain/entity"
+
+type StatusAkademikRepository interface {
+	FindByID(id uint64) (*entity.StatusAkademik, error)
+}
@@ -4,6 +4,7 @@ import "github.com/dedegunawan/epnbp.unsil.ac.id-students-backend2/internal/doma
 
 type UserRepository interface {
 	Create(user *entity.User) error
+	Update(user *entity.User) error
 	FindByID(id uint64) (*entity.User, error)
 	FindByEmail(email string) (*entity.User, error)
 	List(page, size int) ([]entity.User, int64, error)
@@ -8,6 +8,7 @@ type UserTokenRepository interface {
 	Create(token *entity.UserToken) error
 	GetLatestByUserID(userID uint64) (*entity.UserToken, error)
 	GetByAccessToken(accessToken string) (*entity.UserToken, error)
+	DeleteByAccessToken(accessToken string) error
 	DeleteByUserID(userID uint64) error
 	DeleteExpiredTokens() error
 }
@@ -0,0 +1,342 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/dedegunawan/epnbp.unsil.ac.id-students-backend2/internal/domain/entity"
+	"github.com/dedegunawan/epnbp.unsil.ac.id-students-backend2/internal/domain/repository"
+	"github.com/dedegunawan/epnbp.unsil.ac.id-students-backend2/pkg/logger"
+)
+
+// BillingUsecase tagihan mahasiswa dari cicilan dan registrasi_mahasiswa.
+// Aturannya mengikuti tagihanNewService di backend lama:
+//   - per tahun_id, jika ada detail cicilan maka tagihan hanya dari cicilan (registrasi diabaikan)
+//   - sisa registrasi = nominal_ukt - max(beasiswa, bantuan UKT) - nominal_bayar
+//   - yang dikembalikan sebagai tagihan hanya yang masih punya sisa
+type BillingUsecase interface {
+	// GetTagihan tagihan yang harus dibayar untuk semua tahun_id sampai periode aktif
+	GetTagihan(npm string, period *entity.BudgetPeriod) ([]entity.Tagihan, error)
+	// GetHistory riwayat pembayaran pada periode aktif
+	GetHistory(npm string, period *entity.BudgetPeriod) ([]entity.Tagihan, error)
+	GetTotalBeasiswa(npm string, tahunID string) int64
+	GetTotalBantuanUKT(npm string, tahunID string) int64
+}
+
+type billingUsecase struct {
+	cicilanRepo    repository.CicilanRepository
+	registrasiRepo repository.RegistrasiMahasiswaRepository
+	beasiswaRepo   repository.BeasiswaRepository
+	bantuanUKTRepo repository.BantuanUKTRepository
+	logger         *logger.Logger
+}
+
+func NewBillingUsecase(
+	cicilanRepo repository.CicilanRepository,
+	registrasiRepo repository.RegistrasiMahasiswaRepository,
+	beasiswaRepo repository.BeasiswaRepository,
+	bantuanUKTRepo repository.BantuanUKTRepository,
+	lg *logger.Logger,
+) BillingUsecase {
+	return &billingUsecase{
+		cicilanRepo:    cicilanRepo,
+		registrasiRepo: registrasiRepo,
+		beasiswaRepo:   beasiswaRepo,
+		bantuanUKTRepo: bantuanUKTRepo,
+		logger:         lg,
+	}
+}
+
+func (u *billingUsecase) GetTagihan(npm string, period *entity.BudgetPeriod) ([]entity.Tagihan, error) {
+	if npm == "" || period == nil {
+		return nil, errors.New("npm and active budget period are required")
+	}
+
+	tahunIDs, err := u.relevantTahunIDs(npm, period.Kode)
+	if err != nil {
+		u.logger.Error("Failed to retrieve tahun_id tagihan, fallback to active period", "error", err, "npm", npm)
+		tahunIDs = []string{period.Kode}
+	}
+
+	var tagihan []entity.Tagihan
+	for _, tahunID := range tahunIDs {
+		hasCicilan, cicilanTagihan, err := u.tagihanFromCicilan(npm, tahunID)
+		if err != nil {
+			return nil, fmt.Errorf("gagal mengambil tagihan dari cicilan: %w", err)
+		}
+		// Tahun yang punya cicilan tidak jatuh ke registrasi walaupun semua angsurannya lunas
+		if hasCicilan {
+			tagihan = append(tagihan, cicilanTagihan...)
+			continue
+		}
+
+		registrasiTagihan, err := u.tagihanFromRegistrasi(npm, tahunID, period)
+		if err != nil {
+			return nil, fmt.Errorf("gagal mengambil tagihan dari registrasi: %w", err)
+		}
+		tagihan = append(tagihan, registrasiTagihan...)
+	}
+
+	return tagihan, nil
+}
+
+func (u *billingUsecase) GetHistory(npm string, period *entity.BudgetPeriod) ([]entity.Tagihan, error) {
+	if npm == "" || period == nil {
+		return nil, errors.New("npm and active budget period are required")
+	}
+
+	history, err := u.historyFromRegistrasi(npm, period)
+	if err != nil {
+		return nil, fmt.Errorf("gagal mengambil riwayat dari registrasi: %w", err)
+	}
+
+	cicilanHistory, err := u.historyFromCicilan(npm, period.Kode)
+	if err != nil {
+		return nil, fmt.Errorf("gagal mengambil riwayat dari cicilan: %w", err)
+	}
+
+	return append(history, cicilanHistory...), nil
+}
+
+func (u *billingUsecase) GetTotalBeasiswa(npm string, tahunID string) int64 {
+	total, err := u.beasiswaRepo.TotalActiveByNPMAndTahun(npm, tahunID)
+	if err != nil {
+		u.logger.Error("Failed to retrieve total beasiswa", "error", err, "npm", npm, "tahun_id", tahunID)
+		return 0
+	}
+	return total
+}
+
+func (u *billingUsecase) GetTotalBantuanUKT(npm string, tahunID string) int64 {
+	total, err := u.bantuanUKTRepo.TotalByNPMAndTahun(npm, tahunID)
+	if err != nil {
+		u.logger.Error("Failed to retrieve total bantuan UKT", "error", err, "npm", npm, "tahun_id", tahunID)
+		return 0
+	}
+	return total
+}
+
+// relevantTahunIDs tahun_id cicilan lalu registrasi (tanpa duplikat) sampai periode aktif
+func (u *billingUsecase) relevantTahunIDs(npm string, currentTahunID string) ([]string, error) {
+	cicilanYears, err := u.cicilanRepo.GetTahunIDs(npm, currentTahunID)
+	if err != nil {
+		return nil, err
+	}
+	registrasiYears, err := u.registrasiRepo.GetTahunIDs(npm, currentTahunID)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]struct{})
+	var result []string
+	for _, tahunID := range append(cicilanYears, registrasiYears...) {
+		if tahunID == "" {
+			continue
+		}
+		if _, ok := seen[tahunID]; ok {
+			continue
+		}
+		seen[tahunID] = struct{}{}
+		result = append(result, tahunID)
+	}
+
+	if len(result) == 0 && currentTahunID != "" {
+		return []string{currentTahunID}, nil
+	}
+	return result, nil
+}
+
+func (u *billingUsecase) paidAmount(detailCicilanID uint64) int64 {
+	total, err := u.cicilanRepo.GetPaidAmount(detailCicilanID)
+	if err != nil {
+		u.logger.Error("Failed to retrieve paid amount cicilan", "error", err, "detail_cicilan_id", detailCicilanID)
+		return 0
+	}
+	return total
+}
+
+// tagihanFromCicilan bool pertama true jika tahun ini punya detail cicilan (apapun statusnya)
+func (u *billingUsecase) tagihanFromCicilan(npm string, tahunID string) (bool, []entity.Tagihan, error) {
+	cicilans, err := u.cicilanRepo.FindByNPMAndTahun(npm, tahunID)
+	if err != nil {
+		return false, nil, err
+	}
+
+	var tagihan []entity.Tagihan
+	hasAnyDetail := false
+	for _, cicilan := range cicilans {
+		for _, detail := range cicilan.DetailCicilan {
+			hasAnyDetail = true
+			if detail.Status == entity.TagihanStatusPaid {
+				continue
+			}
+
+			paid := u.paidAmount(detail.ID)
+			remaining := nonNegative(detail.Amount - paid)
+			if remaining == 0 {
+				continue
+			}
+
+			tagihan = append(tagihan, cicilanTagihan(npm, tahunID, cicilan, detail, paid, remaining, cicilanStatus(detail.Status, paid, remaining)))
+		}
+	}
+
+	return hasAnyDetail, tagihan, nil
+}
+
+func (u *billingUsecase) historyFromCicilan(npm string, tahunID string) ([]entity.Tagihan, error) {
+	cicilans, err := u.cicilanRepo.FindByNPMAndTahun(npm, tahunID)
+	if err != nil {
+		return nil, err
+	}
+
+	var history []entity.Tagihan
+	for _, cicilan := range cicilans {
+		for _, detail := range cicilan.DetailCicilan {
+			paid := u.paidAmount(detail.ID)
+			// Status paid di tabel dianggap lunas penuh
+			if detail.Status == entity.TagihanStatusPaid {
+				paid = detail.Amount
+			}
+			if detail.Status != entity.TagihanStatusPaid && paid <= 0 {
+				continue
+			}
+
+			remaining := nonNegative(detail.Amount - paid)
+			history = append(history, cicilanTagihan(npm, tahunID, cicilan, detail, paid, remaining, cicilanStatus(detail.Status, paid, remaining)))
+		}
+	}
+
+	return history, nil
+}
+
+func (u *billingUsecase) tagihanFromRegistrasi(npm string, tahunID string, period *entity.BudgetPeriod) ([]entity.Tagihan, error) {
+	registrasi, err := u.registrasiRepo.FindByNPMAndTahun(npm, tahunID)
+	if err != nil {
+		return nil, err
+	}
+
+	var tagihan []entity.Tagihan
+	for _, reg := range registrasi {
+		item := u.registrasiTagihan(npm, tahunID, reg, period)
+		if item.RemainingAmount > 0 {
+			tagihan = append(tagihan, item)
+		}
+	}
+	return tagihan, nil
+}
+
+func (u *billingUsecase) historyFromRegistrasi(npm string, period *entity.BudgetPeriod) ([]entity.Tagihan, error) {
+	registrasi, err := u.registrasiRepo.FindPaidByNPMAndTahun(npm, period.Kode)
+	if err != nil {
+		return nil, err
+	}
+
+	var history []entity.Tagihan
+	for _, reg := range registrasi {
+		// Status paid/lunas tanpa nominal_bayar tidak masuk riwayat
+		if reg.NominalBayarInt() == 0 {
+			continue
+		}
+		history = append(history, u.registrasiTagihan(npm, period.Kode, reg, period))
+	}
+	return history, nil
+}
+
+func (u *billingUsecase) registrasiTagihan(npm string, tahunID string, reg entity.RegistrasiMahasiswa, period *entity.BudgetPeriod) entity.Tagihan {
+	nominalUKT := reg.NominalUKTInt()
+	nominalBayar := reg.NominalBayarInt()
+
+	bantuanUKT := u.GetTotalBantuanUKT(npm, tahunID)
+	beasiswa := u.GetTotalBeasiswa(npm, tahunID)
+	potongan := bantuanUKT
+	if beasiswa > potongan {
+		potongan = beasiswa
+	}
+
+	remaining := nonNegative(nominalUKT - potongan - nominalBayar)
+	status := entity.TagihanStatusUnpaid
+	if remaining == 0 {
+		status = entity.TagihanStatusPaid
+	} else if nominalBayar > 0 {
+		status = entity.TagihanStatusPartial
+	}
+
+	billName := "Tagihan Registrasi"
+	if reg.KelUKT != nil && *reg.KelUKT != "" {
+		billName = fmt.Sprintf("UKT Kelompok %s", *reg.KelUKT)
+	}
+
+	registrasiID := reg.ID
+	paymentEndDate := period.PaymentEndDate
+	item := entity.Tagihan{
+		ID:               reg.ID,
+		Source:           entity.TagihanSourceRegistrasi,
+		NPM:              npm,
+		TahunID:          reg.TahunID,
+		AcademicYear:     tahunID,
+		BillName:         billName,
+		Amount:           nominalUKT,
+		PaidAmount:       nominalBayar,
+		RemainingAmount:  remaining,
+		Beasiswa:         beasiswa,
+		BantuanUKT:       bantuanUKT,
+		Status:           status,
+		PaymentStartDate: period.PaymentStartDate,
+		PaymentEndDate:   &paymentEndDate,
+		RegistrasiID:     &registrasiID,
+		KelUKT:           reg.KelUKT,
+	}
+	if reg.CreatedAt != nil {
+		item.CreatedAt = *reg.CreatedAt
+	}
+	if reg.UpdatedAt != nil {
+		item.UpdatedAt = *reg.UpdatedAt
+	}
+	return item
+}
+
+func cicilanTagihan(npm string, tahunID string, cicilan entity.Cicilan, detail entity.DetailCicilan, paid, remaining int64, status string) entity.Tagihan {
+	cicilanID := cicilan.ID
+	detailID := detail.ID
+	sequenceNo := detail.SequenceNo
+	return entity.Tagihan{
+		ID:               detail.ID,
+		Source:           entity.TagihanSourceCicilan,
+		NPM:              npm,
+		TahunID:          cicilan.TahunID,
+		AcademicYear:     tahunID,
+		BillName:         fmt.Sprintf("Cicilan UKT - Angsuran %d", detail.SequenceNo),
+		Amount:           detail.Amount,
+		PaidAmount:       paid,
+		RemainingAmount:  remaining,
+		Status:           status,
+		PaymentStartDate: detail.DueDate, // due_date = mulai wajib bayar, cicilan tidak punya batas akhir
+		CicilanID:        &cicilanID,
+		DetailCicilanID:  &detailID,
+		SequenceNo:       &sequenceNo,
+		CreatedAt:        cicilan.CreatedAt,
+		UpdatedAt:        cicilan.UpdatedAt,
+	}
+}
+
+// cicilanStatus status tersimpan disesuaikan dengan sisa tagihan sebenarnya
+func cicilanStatus(stored string, paid, remaining int64) string {
+	switch {
+	case remaining == 0:
+		return entity.TagihanStatusPaid
+	case stored == "" && paid > 0, stored == entity.TagihanStatusPaid:
+		return entity.TagihanStatusPartial
+	case stored == "":
+		return entity.TagihanStatusUnpaid
+	default:
+		return stored
+	}
+}
+
+func nonNegative(amount int64) int64 {
+	if amount < 0 {
+		return 0
+	}
+	return amount
+}
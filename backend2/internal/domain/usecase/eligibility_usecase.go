@@ -0,0 +1,147 @@
+package usecase
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/dedegunawan/epnbp.unsil.ac.id-students-backend2/internal/domain/entity"
+	"github.com/dedegunawan/epnbp.unsil.ac.id-students-backend2/internal/domain/repository"
+	"github.com/dedegunawan/epnbp.unsil.ac.id-students-backend2/pkg/logger"
+)
+
+// EligibilityUsecase kelayakan penagihan berdasarkan kode status_akademiks.
+// Aturan, format ELIGIBILITY_RULES dan fallback-nya sama dengan EligibilityService di backend lama,
+// sehingga kedua backend menghitung remaining_amount yang sama untuk status selain aktif.
+type EligibilityUsecase interface {
+	EvaluateKode(kode string, nama string) *entity.EligibilityResult
+	EvaluateMahasiswa(mahasiswa *entity.Mahasiswa) *entity.EligibilityResult
+}
+
+type eligibilityUsecase struct {
+	statusRepo repository.StatusAkademikRepository
+	rules      map[string]entity.EligibilityRule
+	logger     *logger.Logger
+}
+
+// defaultEligibilityRules aturan bawaan jika ELIGIBILITY_RULES tidak di-set
+var defaultEligibilityRules = []entity.EligibilityRule{
+	{Kode: "A", Label: "aktif", Outcome: entity.EligibilityBillFull},
+	{Kode: "C", Label: "cuti", Outcome: entity.EligibilityNoBill},
+	{Kode: "N", Label: "non_aktif", Outcome: entity.EligibilityBlock},
+	{Kode: "L", Label: "lulus", Outcome: entity.EligibilityNoBill},
+	{Kode: "D", Label: "do", Outcome: entity.EligibilityBlock},
+}
+
+// legacyActiveStatusAkademikID status_akademik_id yang dianggap aktif jika referensinya tidak ditemukan
+const legacyActiveStatusAkademikID = 1
+
+func NewEligibilityUsecase(statusRepo repository.StatusAkademikRepository, rules map[string]entity.EligibilityRule, lg *logger.Logger) EligibilityUsecase {
+	if rules == nil {
+		rules = buildEligibilityRules(nil)
+	}
+	return &eligibilityUsecase{statusRepo: statusRepo, rules: rules, logger: lg}
+}
+
+// ParseEligibilityRules aturan bawaan digabung override dari ELIGIBILITY_RULES.
+// Format: "A:bill_full,C:bill_reduced:50,N:block,L:no_bill,D:block"
+func ParseEligibilityRules(raw string) (map[string]entity.EligibilityRule, error) {
+	overrides := make(map[string]entity.EligibilityRule)
+	for _, item := range strings.Split(raw, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+
+		parts := strings.Split(item, ":")
+		if len(parts) < 2 {
+			return nil, fmt.Errorf("aturan %q harus berformat KODE:outcome", item)
+		}
+
+		rule := entity.EligibilityRule{
+			Kode:    strings.ToUpper(strings.TrimSpace(parts[0])),
+			Outcome: strings.TrimSpace(parts[1]),
+		}
+
+		switch rule.Outcome {
+		case entity.EligibilityBillFull, entity.EligibilityNoBill, entity.EligibilityBlock:
+		case entity.EligibilityBillReduced:
+			if len(parts) < 3 {
+				return nil, fmt.Errorf("aturan %q membutuhkan persentase", item)
+			}
+			pct, err := strconv.Atoi(strings.TrimSpace(parts[2]))
+			if err != nil || pct <= 0 || pct >= 100 {
+				return nil, fmt.Errorf("persentase pada aturan %q harus 1-99", item)
+			}
+			rule.Percentage = pct
+		default:
+			return nil, fmt.Errorf("outcome %q tidak dikenal", rule.Outcome)
+		}
+
+		overrides[rule.Kode] = rule
+	}
+	return buildEligibilityRules(overrides), nil
+}
+
+func buildEligibilityRules(overrides map[string]entity.EligibilityRule) map[string]entity.EligibilityRule {
+	rules := make(map[string]entity.EligibilityRule)
+	for _, rule := range defaultEligibilityRules {
+		rules[rule.Kode] = rule
+	}
+	for kode, rule := range overrides {
+		if existing, ok := rules[kode]; ok && rule.Label == "" {
+			rule.Label = existing.Label
+		}
+		rules[kode] = rule
+	}
+	for kode, rule := range rules {
+		if rule.Label == "" {
+			rule.Label = strings.ToLower(kode)
+			rules[kode] = rule
+		}
+	}
+	return rules
+}
+
+// EvaluateKode kode yang tidak dikenal selalu diblokir
+func (u *eligibilityUsecase) EvaluateKode(kode string, nama string) *entity.EligibilityResult {
+	kode = strings.ToUpper(strings.TrimSpace(kode))
+
+	rule, ok := u.rules[kode]
+	if !ok {
+		return &entity.EligibilityResult{
+			StatusKode: kode,
+			StatusNama: nama,
+			Outcome:    entity.EligibilityBlock,
+			Reason:     "status_unknown",
+		}
+	}
+
+	return &entity.EligibilityResult{
+		StatusKode:    kode,
+		StatusNama:    nama,
+		Outcome:       rule.Outcome,
+		Reason:        "status_" + rule.Label,
+		Percentage:    rule.Percentage,
+		CanBill:       rule.Outcome == entity.EligibilityBillFull || rule.Outcome == entity.EligibilityBillReduced,
+		CanGenerateVA: rule.Outcome == entity.EligibilityBillFull,
+	}
+}
+
+// EvaluateMahasiswa dari mahasiswa_masters.status_akademik_id
+func (u *eligibilityUsecase) EvaluateMahasiswa(mahasiswa *entity.Mahasiswa) *entity.EligibilityResult {
+	if mahasiswa == nil || mahasiswa.StatusAkademikID == 0 {
+		return u.EvaluateKode("", "")
+	}
+
+	status, err := u.statusRepo.FindByID(mahasiswa.StatusAkademikID)
+	if err != nil || status.Kode == "" {
+		u.logger.Warn("Status akademik not found for eligibility", "error", err, "npm", mahasiswa.StudentID, "status_akademik_id", mahasiswa.StatusAkademikID)
+		if mahasiswa.StatusAkademikID == legacyActiveStatusAkademikID {
+			return u.EvaluateKode("A", "")
+		}
+		return u.EvaluateKode("", "")
+	}
+
+	return u.EvaluateKode(status.Kode, status.Nama)
+}
@@ -1,13 +1,22 @@
 package usecase
 
 import (
+	"errors"
+	"fmt"
+
 	"github.com/dedegunawan/epnbp.unsil.ac.id-students-backend2/internal/domain/entity"
 	"github.com/dedegunawan/epnbp.unsil.ac.id-students-backend2/internal/domain/repository"
 )
 
 type PermissionUsecase interface {
+	Create(permission *entity.Permission) error
+	Update(permission *entity.Permission) error
+	Delete(permissionID uint64) error
+	FindByID(permissionID uint64) (*entity.Permission, error)
 	GetAll() ([]entity.Permission, error)
+	List(page, size int) ([]entity.Permission, int64, error)
 	GetByRoleID(roleID uint64) ([]entity.Permission, error)
+	UserHasPermission(userID uint64, name string) (bool, error)
 }
 
 type permissionUsecase struct {
@@ -18,10 +27,64 @@ func NewPermissionUsecase(permissionService repository.PermissionRepository) Per
 	return &permissionUsecase{permissionService}
 }
 
+func (p *permissionUsecase) Create(permission *entity.Permission) error {
+	if err := p.ensureUniqueName(permission.Name, 0); err != nil {
+		return err
+	}
+	return p.permissionService.Create(permission)
+}
+
+func (p *permissionUsecase) Update(permission *entity.Permission) error {
+	if _, err := p.FindByID(permission.ID); err != nil {
+		return err
+	}
+	if err := p.ensureUniqueName(permission.Name, permission.ID); err != nil {
+		return err
+	}
+	return p.permissionService.Update(permission)
+}
+
+func (p *permissionUsecase) Delete(permissionID uint64) error {
+	if _, err := p.FindByID(permissionID); err != nil {
+		return err
+	}
+	return p.permissionService.Delete(permissionID)
+}
+
+func (p *permissionUsecase) FindByID(permissionID uint64) (*entity.Permission, error) {
+	permission, err := p.permissionService.FindByID(permissionID)
+	if err != nil {
+		return nil, notFound(err)
+	}
+	return permission, nil
+}
+
 func (p *permissionUsecase) GetAll() ([]entity.Permission, error) {
 	return p.permissionService.GetAll()
 }
 
+func (p *permissionUsecase) List(page, size int) ([]entity.Permission, int64, error) {
+	return p.permissionService.List(page, size)
+}
+
 func (p *permissionUsecase) GetByRoleID(roleID uint64) ([]entity.Permission, error) {
 	return p.permissionService.GetByRoleID(roleID)
 }
+
+func (p *permissionUsecase) UserHasPermission(userID uint64, name string) (bool, error) {
+	if userID == 0 || name == "" {
+		return false, nil
+	}
+	return p.permissionService.UserHasPermission(userID, name)
+}
+
+func (p *permissionUsecase) ensureUniqueName(name string, exceptID uint64) error {
+	existing, err := p.permissionService.FindByName(name)
+	if err == nil && existing.ID != exceptID {
+		return fmt.Errorf("permission %q %w", name, ErrAlreadyExists)
+	}
+	if err != nil && !errors.Is(notFound(err), ErrNotFound) {
+		return err
+	}
+	return nil
+}
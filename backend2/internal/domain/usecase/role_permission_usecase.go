@@ -11,13 +11,29 @@ type RolePermissionUsecase interface {
 
 type rolePermissionUsecase struct {
 	rolePermissionService repository.RolePermissionRepository
+	roleService           repository.RoleRepository
+	permissionService     repository.PermissionRepository
 }
 
-func NewRolePermissionUsecase(rolePermissionService repository.RolePermissionRepository) RolePermissionUsecase {
-	return &rolePermissionUsecase{rolePermissionService}
+func NewRolePermissionUsecase(
+	rolePermissionService repository.RolePermissionRepository,
+	roleService repository.RoleRepository,
+	permissionService repository.PermissionRepository,
+) RolePermissionUsecase {
+	return &rolePermissionUsecase{
+		rolePermissionService: rolePermissionService,
+		roleService:           roleService,
+		permissionService:     permissionService,
+	}
 }
 
 func (u *rolePermissionUsecase) AssignPermission(roleID, permissionID uint64) error {
+	if _, err := u.roleService.FindByID(roleID); err != nil {
+		return notFound(err)
+	}
+	if _, err := u.permissionService.FindByID(permissionID); err != nil {
+		return notFound(err)
+	}
 	return u.rolePermissionService.AssignPermission(roleID, permissionID)
 }
 
@@ -1,6 +1,9 @@
 package usecase
 
 import (
+	"errors"
+	"fmt"
+
 	"github.com/dedegunawan/epnbp.unsil.ac.id-students-backend2/internal/domain/entity"
 	"github.com/dedegunawan/epnbp.unsil.ac.id-students-backend2/internal/domain/repository"
 )
@@ -11,6 +14,7 @@ type RoleUsecase interface {
 	Delete(roleID uint64) error
 	FindByID(roleID uint64) (*entity.Role, error)
 	GetAll() ([]entity.Role, error)
+	List(page, size int) ([]entity.Role, int64, error)
 }
 
 type roleUsecase struct {
@@ -22,21 +26,52 @@ func NewRoleUsecase(roleService repository.RoleRepository) RoleUsecase {
 }
 
 func (r *roleUsecase) Create(role *entity.Role) error {
+	if err := r.ensureUniqueName(role.Name, 0); err != nil {
+		return err
+	}
 	return r.roleService.Create(role)
 }
 
 func (r *roleUsecase) Update(role *entity.Role) error {
+	if _, err := r.FindByID(role.ID); err != nil {
+		return err
+	}
+	if err := r.ensureUniqueName(role.Name, role.ID); err != nil {
+		return err
+	}
 	return r.roleService.Update(role)
 }
 
 func (r *roleUsecase) Delete(roleID uint64) error {
+	if _, err := r.FindByID(roleID); err != nil {
+		return err
+	}
 	return r.roleService.Delete(roleID)
 }
 
 func (r *roleUsecase) FindByID(id uint64) (*entity.Role, error) {
-	return r.roleService.FindByID(id)
+	role, err := r.roleService.FindByID(id)
+	if err != nil {
+		return nil, notFound(err)
+	}
+	return role, nil
 }
 
 func (r *roleUsecase) GetAll() ([]entity.Role, error) {
 	return r.roleService.GetAll()
 }
+
+func (r *roleUsecase) List(page, size int) ([]entity.Role, int64, error) {
+	return r.roleService.List(page, size)
+}
+
+func (r *roleUsecase) ensureUniqueName(name string, exceptID uint64) error {
+	existing, err := r.roleService.FindByName(name)
+	if err == nil && existing.ID != exceptID {
+		return fmt.Errorf("role %q %w", name, ErrAlreadyExists)
+	}
+	if err != nil && !errors.Is(notFound(err), ErrNotFound) {
+		return err
+	}
+	return nil
+}
@@ -5,9 +5,12 @@ type Usecase struct {
 	RoleUsecase           RoleUsecase
 	PermissionUsecase     PermissionUsecase
 	RolePermissionUsecase RolePermissionUsecase
+	UserRoleUsecase       UserRoleUsecase
 	UserTokenUsecase      UserTokenUsecase
 	MahasiswaUsecase      MahasiswaUsecase
 	BudgetPeriodUsecase   BudgetPeriodUsecase
+	BillingUsecase        BillingUsecase
+	EligibilityUsecase    EligibilityUsecase
 }
 
 func NewUsecase(
@@ -15,17 +18,23 @@ func NewUsecase(
 	role RoleUsecase,
 	permission PermissionUsecase,
 	rolePemission RolePermissionUsecase,
+	userRole UserRoleUsecase,
 	userToken UserTokenUsecase,
 	mahasiswaUsecase MahasiswaUsecase,
 	budgetPeriodUsecase BudgetPeriodUsecase,
+	billingUsecase BillingUsecase,
+	eligibilityUsecase EligibilityUsecase,
 ) *Usecase {
 	return &Usecase{
 		UserUsecase:           user,
 		RoleUsecase:           role,
 		PermissionUsecase:     permission,
 		RolePermissionUsecase: rolePemission,
+		UserRoleUsecase:       userRole,
 		UserTokenUsecase:      userToken,
 		MahasiswaUsecase:      mahasiswaUsecase,
 		BudgetPeriodUsecase:   budgetPeriodUsecase,
+		BillingUsecase:        billingUsecase,
+		EligibilityUsecase:    eligibilityUsecase,
 	}
 }
@@ -13,13 +13,29 @@ type UserRoleUsecase interface {
 
 type userRoleUsecase struct {
 	userRoleService repository.UserRoleRepository
+	userService     repository.UserRepository
+	roleService     repository.RoleRepository
 }
 
-func NewUserRoleUsecase(userRoleService repository.UserRoleRepository) UserRoleUsecase {
-	return &userRoleUsecase{userRoleService}
+func NewUserRoleUsecase(
+	userRoleService repository.UserRoleRepository,
+	userService repository.UserRepository,
+	roleService repository.RoleRepository,
+) UserRoleUsecase {
+	return &userRoleUsecase{
+		userRoleService: userRoleService,
+		userService:     userService,
+		roleService:     roleService,
+	}
 }
 
 func (u *userRoleUsecase) AssignRole(userID, roleID uint64) error {
+	if _, err := u.userService.FindByID(userID); err != nil {
+		return notFound(err)
+	}
+	if _, err := u.roleService.FindByID(roleID); err != nil {
+		return notFound(err)
+	}
 	return u.userRoleService.AssignRole(userID, roleID)
 }
 
@@ -28,5 +44,8 @@ func (u *userRoleUsecase) RemoveRole(userID, roleID uint64) error {
 }
 
 func (u *userRoleUsecase) GetRoles(userID uint64) ([]entity.Role, error) {
+	if _, err := u.userService.FindByID(userID); err != nil {
+		return nil, notFound(err)
+	}
 	return u.userRoleService.GetRoles(userID)
 }
@@ -18,6 +18,8 @@ type UserTokenUsecase interface {
 	GetContext() context.Context
 	SetContext(ctx context.Context)
 	GetByAccessToken(token string) (*entity.UserToken, error)
+	RevokeToken(accessToken string) error
+	RevokeAllTokens(userID uint64) error
 }
 
 type userTokenUsecase struct {
@@ -102,6 +104,16 @@ func (s *userTokenUsecase) GetByAccessToken(token string) (*entity.UserToken, er
 	return s.userTokenRepository.GetByAccessToken(token)
 }
 
+// Cabut token saat logout; berlaku juga untuk token yang sudah di-cache
+func (s *userTokenUsecase) RevokeToken(accessToken string) error {
+	return s.userTokenRepository.DeleteByAccessToken(accessToken)
+}
+
+// Cabut semua sesi user (mis. saat user dinonaktifkan)
+func (s *userTokenUsecase) RevokeAllTokens(userID uint64) error {
+	return s.userTokenRepository.DeleteByUserID(userID)
+}
+
 // Bersihkan token expired
 func (s *userTokenUsecase) CleanupExpiredTokens() error {
 	return s.userTokenRepository.DeleteExpiredTokens()
@@ -11,6 +11,9 @@ import (
 
 type UserUsecase interface {
 	Register(name, email, password string) (*entity.User, error)
+	// Create user oleh admin; password boleh kosong untuk akun yang hanya login lewat SSO
+	Create(name, email, password string) (*entity.User, error)
+	Update(id uint64, name, email string) (*entity.User, error)
 	GetByID(id uint64) (*entity.User, error)
 	GetByEmail(email string) (*entity.User, error)
 	List(page, size int) ([]entity.User, int64, error)
@@ -57,8 +60,52 @@ func (u *userUsecase) Register(name, email, password string) (*entity.User, erro
 
 }
 
+func (u *userUsecase) Create(name, email, password string) (*entity.User, error) {
+	if name == "" || email == "" {
+		return nil, errors.New("name and email must not be empty")
+	}
+	if existingUser, _ := u.userService.FindByEmail(email); existingUser != nil {
+		return nil, fmt.Errorf("email %w", ErrAlreadyExists)
+	}
+
+	user := &entity.User{Name: name, Email: email, IsActive: true}
+	if password != "" {
+		passwordHashed, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+		if err != nil {
+			return nil, fmt.Errorf("failed to hash password: %w", err)
+		}
+		user.PasswordHash = pointer.Of(string(passwordHashed))
+	}
+
+	if err := u.userService.Create(user); err != nil {
+		return nil, fmt.Errorf("failed to create user: %w", err)
+	}
+	return user, nil
+}
+
+func (u *userUsecase) Update(id uint64, name, email string) (*entity.User, error) {
+	user, err := u.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if existingUser, _ := u.userService.FindByEmail(email); existingUser != nil && existingUser.ID != id {
+		return nil, fmt.Errorf("email %w", ErrAlreadyExists)
+	}
+
+	user.Name = name
+	user.Email = email
+	if err := u.userService.Update(user); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
 func (u *userUsecase) GetByID(id uint64) (*entity.User, error) {
-	return u.userService.FindByID(id)
+	user, err := u.userService.FindByID(id)
+	if err != nil {
+		return nil, notFound(err)
+	}
+	return user, nil
 }
 
 func (u *userUsecase) GetByEmail(email string) (*entity.User, error) {
@@ -70,10 +117,16 @@ func (u *userUsecase) List(page, size int) ([]entity.User, int64, error) {
 }
 
 func (u *userUsecase) UpdateAvatar(id uint64, url string) error {
+	if _, err := u.GetByID(id); err != nil {
+		return err
+	}
 	return u.userService.UpdateAvatar(id, url)
 }
 
 func (u *userUsecase) SetActive(id uint64, active bool) error {
+	if _, err := u.GetByID(id); err != nil {
+		return err
+	}
 	return u.userService.SetActive(id, active)
 }
 
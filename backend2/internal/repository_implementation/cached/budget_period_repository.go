@@ -0,0 +1,58 @@
+package cached
+
+import (
+	"time"
+
+	"github.com/dedegunawan/epnbp.unsil.ac.id-students-backend2/internal/domain/entity"
+	"github.com/dedegunawan/epnbp.unsil.ac.id-students-backend2/internal/domain/repository"
+	"github.com/dedegunawan/epnbp.unsil.ac.id-students-backend2/pkg/cache"
+	"github.com/dedegunawan/epnbp.unsil.ac.id-students-backend2/pkg/logger"
+)
+
+const activeBudgetPeriodKey = "budget-period:active"
+
+// BudgetPeriodRepository cache periode aktif; setiap perubahan periode menghapus cache
+type BudgetPeriodRepository struct {
+	repository.BudgetPeriodRepository
+	store store
+}
+
+func NewBudgetPeriodRepository(inner repository.BudgetPeriodRepository, c cache.Cache, ttl time.Duration, lg *logger.Logger) repository.BudgetPeriodRepository {
+	return &BudgetPeriodRepository{BudgetPeriodRepository: inner, store: store{cache: c, ttl: ttl, logger: lg}}
+}
+
+func (r *BudgetPeriodRepository) GetActive() (*entity.BudgetPeriod, error) {
+	var period entity.BudgetPeriod
+	if r.store.get(activeBudgetPeriodKey, &period) {
+		return &period, nil
+	}
+
+	found, err := r.BudgetPeriodRepository.GetActive()
+	if err != nil || found == nil {
+		return found, err
+	}
+	r.store.set(activeBudgetPeriodKey, found, r.store.ttl)
+	return found, nil
+}
+
+func (r *BudgetPeriodRepository) Create(period *entity.BudgetPeriod) error {
+	return r.invalidate(r.BudgetPeriodRepository.Create(period))
+}
+
+func (r *BudgetPeriodRepository) Update(period *entity.BudgetPeriod) error {
+	return r.invalidate(r.BudgetPeriodRepository.Update(period))
+}
+
+func (r *BudgetPeriodRepository) Delete(id uint64) error {
+	return r.invalidate(r.BudgetPeriodRepository.Delete(id))
+}
+
+func (r *BudgetPeriodRepository) SetActive(period *entity.BudgetPeriod) error {
+	return r.invalidate(r.BudgetPeriodRepository.SetActive(period))
+}
+
+// invalidate juga dijalankan saat err != nil karena perubahan bisa saja sudah sebagian tersimpan
+func (r *BudgetPeriodRepository) invalidate(err error) error {
+	r.store.delete(activeBudgetPeriodKey)
+	return err
+}
@@ -0,0 +1,48 @@
+// Package cached membungkus repository mysql dengan cache (pkg/cache).
+// Hanya lookup yang dipakai tiap request yang di-cache; method yang mengubah data
+// meneruskan ke repository asli lalu menghapus key terkait.
+package cached
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"github.com/dedegunawan/epnbp.unsil.ac.id-students-backend2/pkg/cache"
+	"github.com/dedegunawan/epnbp.unsil.ac.id-students-backend2/pkg/logger"
+)
+
+// store helper bersama: error cache hanya dicatat, request tetap jalan ke database
+type store struct {
+	cache  cache.Cache
+	ttl    time.Duration
+	logger *logger.Logger
+}
+
+func (s store) get(key string, dest any) bool {
+	err := s.cache.Get(context.Background(), key, dest)
+	if err != nil && !errors.Is(err, cache.ErrMiss) {
+		s.logger.Error("Failed to read cache", "error", err, "key", key)
+	}
+	return err == nil
+}
+
+func (s store) set(key string, value any, ttl time.Duration) {
+	if err := s.cache.Set(context.Background(), key, value, ttl); err != nil {
+		s.logger.Error("Failed to write cache", "error", err, "key", key)
+	}
+}
+
+func (s store) delete(keys ...string) {
+	if err := s.cache.Delete(context.Background(), keys...); err != nil {
+		s.logger.Error("Failed to invalidate cache", "error", err, "keys", keys)
+	}
+}
+
+// hashKey supaya access token tidak tersimpan utuh sebagai nama key
+func hashKey(value string) string {
+	sum := sha256.Sum256([]byte(value))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,58 @@
+package cached
+
+import (
+	"time"
+
+	"github.com/dedegunawan/epnbp.unsil.ac.id-students-backend2/internal/domain/entity"
+	"github.com/dedegunawan/epnbp.unsil.ac.id-students-backend2/internal/domain/repository"
+	"github.com/dedegunawan/epnbp.unsil.ac.id-students-backend2/pkg/cache"
+	"github.com/dedegunawan/epnbp.unsil.ac.id-students-backend2/pkg/logger"
+)
+
+// MahasiswaRepository cache profil mahasiswa per NPM (FindByStudentID).
+// Data master juga diubah aplikasi lain, jadi TTL membatasi umur data basi.
+type MahasiswaRepository struct {
+	repository.MahasiswaRepository
+	store store
+}
+
+func NewMahasiswaRepository(inner repository.MahasiswaRepository, c cache.Cache, ttl time.Duration, lg *logger.Logger) repository.MahasiswaRepository {
+	return &MahasiswaRepository{MahasiswaRepository: inner, store: store{cache: c, ttl: ttl, logger: lg}}
+}
+
+func mahasiswaKey(studentID string) string {
+	return "mahasiswa:" + studentID
+}
+
+func (r *MahasiswaRepository) FindByStudentID(studentID string) (*entity.Mahasiswa, error) {
+	var mahasiswa entity.Mahasiswa
+	if r.store.get(mahasiswaKey(studentID), &mahasiswa) {
+		return &mahasiswa, nil
+	}
+
+	found, err := r.MahasiswaRepository.FindByStudentID(studentID)
+	if err != nil || found == nil {
+		return found, err
+	}
+	r.store.set(mahasiswaKey(studentID), found, r.store.ttl)
+	return found, nil
+}
+
+func (r *MahasiswaRepository) Update(mahasiswa *entity.Mahasiswa) error {
+	if err := r.MahasiswaRepository.Update(mahasiswa); err != nil {
+		return err
+	}
+	r.store.delete(mahasiswaKey(mahasiswa.StudentID))
+	return nil
+}
+
+func (r *MahasiswaRepository) Delete(mahasiswaID uint64) error {
+	existing, _ := r.MahasiswaRepository.FindByID(mahasiswaID)
+	if err := r.MahasiswaRepository.Delete(mahasiswaID); err != nil {
+		return err
+	}
+	if existing != nil {
+		r.store.delete(mahasiswaKey(existing.StudentID))
+	}
+	return nil
+}
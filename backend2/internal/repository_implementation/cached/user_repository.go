@@ -0,0 +1,64 @@
+package cached
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/dedegunawan/epnbp.unsil.ac.id-students-backend2/internal/domain/entity"
+	"github.com/dedegunawan/epnbp.unsil.ac.id-students-backend2/internal/domain/repository"
+	"github.com/dedegunawan/epnbp.unsil.ac.id-students-backend2/pkg/cache"
+	"github.com/dedegunawan/epnbp.unsil.ac.id-students-backend2/pkg/logger"
+)
+
+// UserRepository cache FindByID (cek user aktif di AuthJWT dan mahasiswa_manager)
+type UserRepository struct {
+	repository.UserRepository
+	store store
+}
+
+func NewUserRepository(inner repository.UserRepository, c cache.Cache, ttl time.Duration, lg *logger.Logger) repository.UserRepository {
+	return &UserRepository{UserRepository: inner, store: store{cache: c, ttl: ttl, logger: lg}}
+}
+
+func userKey(id uint64) string {
+	return "user:" + strconv.FormatUint(id, 10)
+}
+
+func (r *UserRepository) FindByID(id uint64) (*entity.User, error) {
+	var user entity.User
+	if r.store.get(userKey(id), &user) {
+		return &user, nil
+	}
+
+	found, err := r.UserRepository.FindByID(id)
+	if err != nil {
+		return found, err
+	}
+	r.store.set(userKey(id), found, r.store.ttl)
+	return found, nil
+}
+
+func (r *UserRepository) Update(user *entity.User) error {
+	if err := r.UserRepository.Update(user); err != nil {
+		return err
+	}
+	r.store.delete(userKey(user.ID))
+	return nil
+}
+
+func (r *UserRepository) UpdateAvatar(id uint64, url string) error {
+	if err := r.UserRepository.UpdateAvatar(id, url); err != nil {
+		return err
+	}
+	r.store.delete(userKey(id))
+	return nil
+}
+
+// SetActive langsung berlaku: user yang dinonaktifkan ditolak AuthJWT pada request berikutnya
+func (r *UserRepository) SetActive(id uint64, active bool) error {
+	if err := r.UserRepository.SetActive(id, active); err != nil {
+		return err
+	}
+	r.store.delete(userKey(id))
+	return nil
+}
@@ -0,0 +1,81 @@
+package cached
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/dedegunawan/epnbp.unsil.ac.id-students-backend2/internal/domain/entity"
+	"github.com/dedegunawan/epnbp.unsil.ac.id-students-backend2/internal/domain/repository"
+	"github.com/dedegunawan/epnbp.unsil.ac.id-students-backend2/pkg/cache"
+	"github.com/dedegunawan/epnbp.unsil.ac.id-students-backend2/pkg/logger"
+)
+
+type cachedUserToken struct {
+	Token    entity.UserToken
+	CachedAt time.Time
+}
+
+// UserTokenRepository cache lookup token di AuthJWT (GetByAccessToken)
+type UserTokenRepository struct {
+	repository.UserTokenRepository
+	store store
+}
+
+func NewUserTokenRepository(inner repository.UserTokenRepository, c cache.Cache, ttl time.Duration, lg *logger.Logger) repository.UserTokenRepository {
+	return &UserTokenRepository{UserTokenRepository: inner, store: store{cache: c, ttl: ttl, logger: lg}}
+}
+
+func tokenKey(accessToken string) string {
+	return "token:" + hashKey(accessToken)
+}
+
+// revokedKey menandai waktu semua token user dicabut; token yang di-cache sebelum itu dianggap miss
+func revokedKey(userID uint64) string {
+	return "token-revoked:" + strconv.FormatUint(userID, 10)
+}
+
+func (r *UserTokenRepository) GetByAccessToken(accessToken string) (*entity.UserToken, error) {
+	key := tokenKey(accessToken)
+
+	var hit cachedUserToken
+	if r.store.get(key, &hit) && !r.revokedSince(hit.Token.UserID, hit.CachedAt) {
+		return &hit.Token, nil
+	}
+
+	token, err := r.UserTokenRepository.GetByAccessToken(accessToken)
+	if err != nil {
+		return token, err
+	}
+
+	// jangan simpan lebih lama dari umur token
+	ttl := r.store.ttl
+	if remaining := time.Until(token.ExpiresAt); remaining < ttl {
+		ttl = remaining
+	}
+	r.store.set(key, cachedUserToken{Token: *token, CachedAt: time.Now()}, ttl)
+	return token, nil
+}
+
+func (r *UserTokenRepository) DeleteByAccessToken(accessToken string) error {
+	if err := r.UserTokenRepository.DeleteByAccessToken(accessToken); err != nil {
+		return err
+	}
+	r.store.delete(tokenKey(accessToken))
+	return nil
+}
+
+func (r *UserTokenRepository) DeleteByUserID(userID uint64) error {
+	if err := r.UserTokenRepository.DeleteByUserID(userID); err != nil {
+		return err
+	}
+	r.store.set(revokedKey(userID), time.Now(), r.store.ttl)
+	return nil
+}
+
+func (r *UserTokenRepository) revokedSince(userID uint64, cachedAt time.Time) bool {
+	var revokedAt time.Time
+	if !r.store.get(revokedKey(userID), &revokedAt) {
+		return false
+	}
+	return !cachedAt.After(revokedAt)
+}
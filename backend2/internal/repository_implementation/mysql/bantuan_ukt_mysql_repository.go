@@ -0,0 +1,21 @@
+package mysql
+
+import (
+	"github.com/dedegunawan/epnbp.unsil.ac.id-students-backend2/internal/domain/repository"
+	"gorm.io/gorm"
+)
+
+// BantuanUKTRepository bantuan UKT mahasiswa. Struktur tabel bantuan UKT (tabel connector/Laravel)
+// belum diketahui, jadi sama seperti GetTotalBantuanUKT di backend lama totalnya selalu 0.
+// Implementasikan query di kedua backend sekaligus setelah strukturnya dipastikan.
+type BantuanUKTRepository struct {
+	db *gorm.DB
+}
+
+func NewBantuanUKTRepository(db *gorm.DB) repository.BantuanUKTRepository {
+	return &BantuanUKTRepository{db: db}
+}
+
+func (r *BantuanUKTRepository) TotalByNPMAndTahun(npm string, tahunID string) (int64, error) {
+	return 0, nil
+}
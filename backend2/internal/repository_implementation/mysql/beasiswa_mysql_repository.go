@@ -0,0 +1,24 @@
+package mysql
+
+import (
+	"github.com/dedegunawan/epnbp.unsil.ac.id-students-backend2/internal/domain/repository"
+	"gorm.io/gorm"
+)
+
+type BeasiswaRepository struct{ db *gorm.DB }
+
+func NewBeasiswaRepository(db *gorm.DB) repository.BeasiswaRepository {
+	return &BeasiswaRepository{db: db}
+}
+
+func (r BeasiswaRepository) TotalActiveByNPMAndTahun(npm string, tahunID string) (int64, error) {
+	var total int64
+	err := r.db.Table("detail_beasiswa").
+		Joins("JOIN beasiswa ON beasiswa.id = detail_beasiswa.beasiswa_id").
+		Select("COALESCE(CAST(SUM(detail_beasiswa.nominal_beasiswa) AS SIGNED), 0)").
+		Where("beasiswa.status = ?", "active").
+		Where("detail_beasiswa.tahun_id = ?", tahunID).
+		Where("detail_beasiswa.npm = ?", npm).
+		Scan(&total).Error
+	return total, err
+}
@@ -0,0 +1,53 @@
+package mysql
+
+import (
+	"github.com/dedegunawan/epnbp.unsil.ac.id-students-backend2/internal/domain/entity"
+	"github.com/dedegunawan/epnbp.unsil.ac.id-students-backend2/internal/domain/repository"
+	"github.com/dedegunawan/epnbp.unsil.ac.id-students-backend2/pkg/logger"
+	"gorm.io/gorm"
+)
+
+type CicilanRepository struct {
+	db     *gorm.DB
+	logger *logger.Logger
+}
+
+func NewCicilanRepository(db *gorm.DB, logger *logger.Logger) repository.CicilanRepository {
+	return &CicilanRepository{db: db, logger: logger}
+}
+
+func (r CicilanRepository) GetTahunIDs(npm string, maxTahunID string) ([]string, error) {
+	var tahunIDs []string
+	err := r.db.Model(&entity.Cicilan{}).
+		Distinct("tahun_id").
+		Where("npm = ? AND tahun_id <= ?", npm, maxTahunID).
+		Order("tahun_id ASC").
+		Pluck("tahun_id", &tahunIDs).Error
+	if err != nil {
+		r.logger.Error("Failed to retrieve cicilan tahun_id", "error", err, "npm", npm)
+		return nil, err
+	}
+	return tahunIDs, nil
+}
+
+func (r CicilanRepository) FindByNPMAndTahun(npm string, tahunID string) ([]entity.Cicilan, error) {
+	var cicilans []entity.Cicilan
+	err := r.db.
+		Where("npm = ? AND tahun_id = ?", npm, tahunID).
+		Preload("DetailCicilan").
+		Find(&cicilans).Error
+	return cicilans, err
+}
+
+// GetPaidAmount dihitung dari invoice_relations -> invoices (Paid) -> payments
+func (r CicilanRepository) GetPaidAmount(detailCicilanID uint64) (int64, error) {
+	var total int64
+	err := r.db.Table("invoice_relations").
+		Select("COALESCE(CAST(SUM(payments.amount) AS SIGNED), 0)").
+		Joins("INNER JOIN invoices ON invoices.id = invoice_relations.invoice_id").
+		Joins("INNER JOIN payments ON payments.invoice_id = invoices.id").
+		Where("invoice_relations.detail_cicilan_id = ?", detailCicilanID).
+		Where("invoices.status = ?", "Paid").
+		Scan(&total).Error
+	return total, err
+}
@@ -14,15 +14,61 @@ func NewPermissionRepository(db *gorm.DB) repository.PermissionRepository {
 	return &PermissionRepository{db}
 }
 
+func (r *PermissionRepository) Create(permission *entity.Permission) error {
+	return r.db.Create(permission).Error
+}
+
+func (r *PermissionRepository) Update(permission *entity.Permission) error {
+	return r.db.Save(permission).Error
+}
+
+func (r *PermissionRepository) Delete(permissionID uint64) error {
+	return r.db.Delete(&entity.Permission{}, permissionID).Error
+}
+
+func (r *PermissionRepository) FindByID(id uint64) (*entity.Permission, error) {
+	var permission entity.Permission
+	err := r.db.First(&permission, id).Error
+	return &permission, err
+}
+
+func (r *PermissionRepository) FindByName(name string) (*entity.Permission, error) {
+	var permission entity.Permission
+	err := r.db.Where("name = ?", name).First(&permission).Error
+	return &permission, err
+}
+
 func (r *PermissionRepository) GetAll() ([]entity.Permission, error) {
 	var permissions []entity.Permission
 	err := r.db.Find(&permissions).Error
 	return permissions, err
 }
 
+func (r *PermissionRepository) List(page, size int) ([]entity.Permission, int64, error) {
+	var permissions []entity.Permission
+	var total int64
+
+	r.db.Model(&entity.Permission{}).Count(&total)
+
+	err := r.db.Order("name ASC").Offset((page - 1) * size).Limit(size).Find(&permissions).Error
+	return permissions, total, err
+}
+
 func (r *PermissionRepository) GetByRoleID(roleID uint64) ([]entity.Permission, error) {
 	var permissions []entity.Permission
 	err := r.db.Joins("JOIN role_permissions ON role_permissions.permission_id = permissions.id").
 		Where("role_permissions.role_id = ?", roleID).Find(&permissions).Error
 	return permissions, err
 }
+
+// UserHasPermission role yang sudah dihapus (soft delete) tidak ikut dihitung
+func (r *PermissionRepository) UserHasPermission(userID uint64, name string) (bool, error) {
+	var count int64
+	err := r.db.Model(&entity.Permission{}).
+		Joins("JOIN role_permissions ON role_permissions.permission_id = permissions.id").
+		Joins("JOIN roles ON roles.id = role_permissions.role_id AND roles.deleted_at IS NULL").
+		Joins("JOIN user_roles ON user_roles.role_id = roles.id").
+		Where("user_roles.user_id = ? AND permissions.name = ?", userID, name).
+		Count(&count).Error
+	return count > 0, err
+}
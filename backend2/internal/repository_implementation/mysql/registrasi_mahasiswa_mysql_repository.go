@@ -0,0 +1,46 @@
+package mysql
+
+import (
+	"github.com/dedegunawan/epnbp.unsil.ac.id-students-backend2/internal/domain/entity"
+	"github.com/dedegunawan/epnbp.unsil.ac.id-students-backend2/internal/domain/repository"
+	"github.com/dedegunawan/epnbp.unsil.ac.id-students-backend2/pkg/logger"
+	"gorm.io/gorm"
+)
+
+type RegistrasiMahasiswaRepository struct {
+	db     *gorm.DB
+	logger *logger.Logger
+}
+
+func NewRegistrasiMahasiswaRepository(db *gorm.DB, logger *logger.Logger) repository.RegistrasiMahasiswaRepository {
+	return &RegistrasiMahasiswaRepository{db: db, logger: logger}
+}
+
+func (r RegistrasiMahasiswaRepository) GetTahunIDs(npm string, maxTahunID string) ([]string, error) {
+	var tahunIDs []string
+	err := r.db.Model(&entity.RegistrasiMahasiswa{}).
+		Distinct("tahun_id").
+		Where("npm = ? AND tahun_id <= ?", npm, maxTahunID).
+		Order("tahun_id ASC").
+		Pluck("tahun_id", &tahunIDs).Error
+	if err != nil {
+		r.logger.Error("Failed to retrieve registrasi tahun_id", "error", err, "npm", npm)
+		return nil, err
+	}
+	return tahunIDs, nil
+}
+
+func (r RegistrasiMahasiswaRepository) FindByNPMAndTahun(npm string, tahunID string) ([]entity.RegistrasiMahasiswa, error) {
+	var registrasi []entity.RegistrasiMahasiswa
+	err := r.db.Where("npm = ? AND tahun_id = ?", npm, tahunID).Find(&registrasi).Error
+	return registrasi, err
+}
+
+func (r RegistrasiMahasiswaRepository) FindPaidByNPMAndTahun(npm string, tahunID string) ([]entity.RegistrasiMahasiswa, error) {
+	var registrasi []entity.RegistrasiMahasiswa
+	err := r.db.
+		Where("npm = ? AND tahun_id = ?", npm, tahunID).
+		Where("(status_student_epnbp IN (?, ?) OR (nominal_bayar IS NOT NULL AND nominal_bayar > 0))", "paid", "lunas").
+		Find(&registrasi).Error
+	return registrasi, err
+}
@@ -32,8 +32,24 @@ func (r *RoleRepository) FindByID(id uint64) (*entity.Role, error) {
 	return &role, err
 }
 
+func (r *RoleRepository) FindByName(name string) (*entity.Role, error) {
+	var role entity.Role
+	err := r.db.Where("name = ?", name).First(&role).Error
+	return &role, err
+}
+
 func (r *RoleRepository) GetAll() ([]entity.Role, error) {
 	var roles []entity.Role
 	err := r.db.Find(&roles).Error
 	return roles, err
 }
+
+func (r *RoleRepository) List(page, size int) ([]entity.Role, int64, error) {
+	var roles []entity.Role
+	var total int64
+
+	r.db.Model(&entity.Role{}).Count(&total)
+
+	err := r.db.Order("name ASC").Offset((page - 1) * size).Limit(size).Find(&roles).Error
+	return roles, total, err
+}
@@ -15,10 +15,9 @@ func NewRolePermissionRepository(db *gorm.DB) repository.RolePermissionRepositor
 }
 
 func (r *RolePermissionRepository) AssignPermission(roleID, permissionID uint64) error {
-	return r.db.Create(&entity.RolePermission{
-		RoleID:       roleID,
-		PermissionID: permissionID,
-	}).Error
+	rolePermission := entity.RolePermission{RoleID: roleID, PermissionID: permissionID}
+	return r.db.Where("role_id = ? AND permission_id = ?", roleID, permissionID).
+		FirstOrCreate(&rolePermission).Error
 }
 
 func (r *RolePermissionRepository) RemovePermission(roleID, permissionID uint64) error {
@@ -0,0 +1,21 @@
+package mysql
+
+import (
+	"github.com/dedegunawan/epnbp.unsil.ac.id-students-backend2/internal/domain/entity"
+	"github.com/dedegunawan/epnbp.unsil.ac.id-students-backend2/internal/domain/repository"
+	"gorm.io/gorm"
+)
+
+type StatusAkademikRepository struct{ db *gorm.DB }
+
+func NewStatusAkademikRepository(db *gorm.DB) repository.StatusAkademikRepository {
+	return &StatusAkademikRepository{db: db}
+}
+
+func (r StatusAkademikRepository) FindByID(id uint64) (*entity.StatusAkademik, error) {
+	var status entity.StatusAkademik
+	if err := r.db.First(&status, id).Error; err != nil {
+		return nil, err
+	}
+	return &status, nil
+}
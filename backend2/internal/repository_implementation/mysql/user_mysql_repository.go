@@ -18,6 +18,10 @@ func (r *UserRepository) Create(user *entity.User) error {
 	return r.db.Create(user).Error
 }
 
+func (r *UserRepository) Update(user *entity.User) error {
+	return r.db.Model(user).Select("name", "email", "updated_at").Updates(user).Error
+}
+
 func (r *UserRepository) FindByID(id uint64) (*entity.User, error) {
 	var user entity.User
 	if err := r.db.First(&user, id).Error; err != nil {
@@ -48,7 +52,7 @@ func (r *UserRepository) List(page, size int) ([]entity.User, int64, error) {
 
 	r.db.Model(&entity.User{}).Count(&total)
 
-	err := r.db.Offset((page - 1) * size).Limit(size).Find(&users).Error
+	err := r.db.Order("id ASC").Offset((page - 1) * size).Limit(size).Find(&users).Error
 	return users, total, err
 }
 
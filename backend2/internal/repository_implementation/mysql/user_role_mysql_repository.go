@@ -19,7 +19,7 @@ func (r *UserRoleRepository) AssignRole(userID, roleID uint64) error {
 		UserID: userID,
 		RoleID: roleID,
 	}
-	return r.db.Create(userRole).Error
+	return r.db.Where("user_id = ? AND role_id = ?", userID, roleID).FirstOrCreate(userRole).Error
 }
 
 func (r *UserRoleRepository) RemoveRole(userID, roleID uint64) error {
@@ -29,6 +29,7 @@ func (r *UserRoleRepository) RemoveRole(userID, roleID uint64) error {
 func (r *UserRoleRepository) GetRoles(userID uint64) ([]entity.Role, error) {
 	var roles []entity.Role
 	err := r.db.Joins("JOIN user_roles ON user_roles.role_id = roles.id").
+		Order("roles.name ASC").
 		Where("user_roles.user_id = ?", userID).Find(&roles).Error
 	return roles, err
 }
@@ -36,6 +36,11 @@ func (r *UserTokenRepository) GetByAccessToken(accessToken string) (*entity.User
 	return &token, err
 }
 
+// Hapus satu token (logout sesi ini)
+func (r *UserTokenRepository) DeleteByAccessToken(accessToken string) error {
+	return r.db.Where("access_token = ?", accessToken).Delete(&entity.UserToken{}).Error
+}
+
 // Hapus semua token user (untuk logout semua sesi)
 func (r *UserTokenRepository) DeleteByUserID(userID uint64) error {
 	return r.db.Where("user_id = ?", userID).Delete(&entity.UserToken{}).Error
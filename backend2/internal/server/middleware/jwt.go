@@ -45,7 +45,7 @@ func (jt JwtMiddleware) AuthJWT() gin.HandlerFunc {
 
 		// 0. Cek apakah ada header Authorization
 		jt.Logger.Info("AuthJWT")
-		tokenStr := extractAccessToken(c)
+		tokenStr := ExtractAccessToken(c)
 		if tokenStr == "" {
 			response.Error(c, http.StatusUnauthorized, "Missing or invalid Authorization header")
 			c.Abort()
@@ -136,7 +136,8 @@ func (jt JwtMiddleware) CheckInternalToken(tokenStr string) (*jwtmanager.Claims,
 
 }
 
-func extractAccessToken(c *gin.Context) string {
+// ExtractAccessToken ambil token dari header Authorization atau cookie access_token
+func ExtractAccessToken(c *gin.Context) string {
 	authHeader := c.GetHeader("Authorization")
 	if strings.HasPrefix(authHeader, "Bearer ") {
 		return strings.TrimPrefix(authHeader, "Bearer ")
@@ -0,0 +1,49 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/dedegunawan/epnbp.unsil.ac.id-students-backend2/internal/domain/usecase"
+	"github.com/dedegunawan/epnbp.unsil.ac.id-students-backend2/pkg/logger"
+	"github.com/dedegunawan/epnbp.unsil.ac.id-students-backend2/pkg/response"
+	"github.com/dedegunawan/epnbp.unsil.ac.id-students-backend2/pkg/strings"
+	"github.com/gin-gonic/gin"
+)
+
+type PermissionMiddleware struct {
+	Logger   *logger.Logger
+	usecases *usecase.Usecase
+}
+
+func NewPermissionMiddleware(logger *logger.Logger, usecases *usecase.Usecase) *PermissionMiddleware {
+	return &PermissionMiddleware{Logger: logger, usecases: usecases}
+}
+
+// RequirePermission dipasang setelah AuthJWT; 403 jika tidak ada role user yang memiliki permission
+func (pm PermissionMiddleware) RequirePermission(permission string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userIDAny, _ := c.Get(ContextUserID)
+		userID, err := strings.GetUint64FromAny(userIDAny)
+		if err != nil || userID == 0 {
+			response.Error(c, http.StatusUnauthorized, "Unauthorized")
+			c.Abort()
+			return
+		}
+
+		allowed, err := pm.usecases.PermissionUsecase.UserHasPermission(userID, permission)
+		if err != nil {
+			pm.Logger.Error("Failed to check permission", "error", err, "user_id", userID, "permission", permission)
+			response.Error(c, http.StatusInternalServerError, "Failed to check permission")
+			c.Abort()
+			return
+		}
+		if !allowed {
+			pm.Logger.Info("Permission denied", "user_id", userID, "permission", permission, "path", c.FullPath())
+			response.Error(c, http.StatusForbidden, "Forbidden")
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
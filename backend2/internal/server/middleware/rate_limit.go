@@ -0,0 +1,69 @@
+package middleware
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/dedegunawan/epnbp.unsil.ac.id-students-backend2/pkg/logger"
+	"github.com/dedegunawan/epnbp.unsil.ac.id-students-backend2/pkg/ratelimit"
+	"github.com/dedegunawan/epnbp.unsil.ac.id-students-backend2/pkg/response"
+	"github.com/dedegunawan/epnbp.unsil.ac.id-students-backend2/pkg/strings"
+	"github.com/gin-gonic/gin"
+)
+
+type RateLimitMiddleware struct {
+	Logger  *logger.Logger
+	limiter *ratelimit.Limiter
+	enabled bool
+}
+
+func NewRateLimitMiddleware(logger *logger.Logger, limiter *ratelimit.Limiter, enabled bool) *RateLimitMiddleware {
+	return &RateLimitMiddleware{Logger: logger, limiter: limiter, enabled: enabled}
+}
+
+// Limit token bucket per user (jika sudah lewat AuthJWT) atau per IP dengan budget policy
+func (rl RateLimitMiddleware) Limit(policy string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !rl.enabled || rl.limiter.Allowed(c.ClientIP()) {
+			c.Next()
+			return
+		}
+
+		limit := rl.limiter.Limit(policy)
+		decision, err := rl.limiter.Store.Take(c.Request.Context(), policy+":"+rateLimitKey(c), limit)
+		if err != nil {
+			// store bermasalah jangan sampai menutup layanan (fail open)
+			rl.Logger.Error("Failed to check rate limit", "error", err, "policy", policy)
+			c.Next()
+			return
+		}
+
+		c.Header("RateLimit-Limit", strconv.Itoa(decision.Limit))
+		c.Header("RateLimit-Remaining", strconv.Itoa(decision.Remaining))
+		c.Header("RateLimit-Reset", strconv.Itoa(ceilSeconds(decision.Reset)))
+		c.Header("RateLimit-Policy", strconv.Itoa(limit.Requests)+";w="+strconv.Itoa(int(limit.Per.Seconds())))
+
+		if !decision.Allowed {
+			c.Header("Retry-After", strconv.Itoa(ceilSeconds(decision.RetryAfter)))
+			response.Error(c, http.StatusTooManyRequests, "Too many requests")
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+func rateLimitKey(c *gin.Context) string {
+	if userIDAny, ok := c.Get(ContextUserID); ok {
+		if userID, err := strings.GetUint64FromAny(userIDAny); err == nil && userID != 0 {
+			return "user:" + strconv.FormatUint(userID, 10)
+		}
+	}
+	return "ip:" + c.ClientIP()
+}
+
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
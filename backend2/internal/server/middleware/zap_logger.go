@@ -17,6 +17,8 @@ func ZapLogger(lg *logger.Logger) gin.HandlerFunc {
 			"status", c.Writer.Status(),
 			"ip", c.ClientIP(),
 			"latency_ms", lat.Milliseconds(),
+			"request_id", c.GetHeader(HeaderXRequestID),
+			"trace_id", TraceID(c),
 		)
 	}
 }
@@ -1,16 +1,25 @@
 package server
 
 import (
+	"net/http"
+
+	"github.com/dedegunawan/epnbp.unsil.ac.id-students-backend2/internal/domain/entity"
 	"github.com/dedegunawan/epnbp.unsil.ac.id-students-backend2/internal/transport/http/auth"
 	"github.com/dedegunawan/epnbp.unsil.ac.id-students-backend2/internal/transport/http/mahasiswa"
+	"github.com/dedegunawan/epnbp.unsil.ac.id-students-backend2/internal/transport/http/rbac"
 	"github.com/dedegunawan/epnbp.unsil.ac.id-students-backend2/internal/transport/http/user"
+	"github.com/dedegunawan/epnbp.unsil.ac.id-students-backend2/pkg/ratelimit"
 	"github.com/gin-gonic/gin"
 )
 
 type Handlers struct {
-	AuthSSO   *auth.AuthSsoHandler
-	User      *user.UserHandler
-	Mahasiswa *mahasiswa.MahasiswaHandler
+	AuthSSO    *auth.AuthSsoHandler
+	User       *user.UserHandler
+	Mahasiswa  *mahasiswa.MahasiswaHandler
+	Role       *rbac.RoleHandler
+	Permission *rbac.PermissionHandler
+	// Metrics endpoint scrape Prometheus
+	Metrics http.Handler
 	// nanti tambah lagi misalnya Product, Order, dsb.
 }
 type Middleware struct {
@@ -20,27 +29,40 @@ type Middleware struct {
 	Logger    gin.HandlerFunc
 	Recovery  gin.HandlerFunc
 	Rate      gin.HandlerFunc
+	Metrics   gin.HandlerFunc
+	Tracing   gin.HandlerFunc
+	// RateLimit membuat limiter dengan budget policy tertentu (per user setelah AuthJWT)
+	RateLimit func(policy string) gin.HandlerFunc
+	// RequirePermission membuat middleware cek permission (dipasang setelah AuthJWT)
+	RequirePermission func(permission string) gin.HandlerFunc
 	// nanti tambah lagi misalnya Product, Order, dsb.
 }
 
 func RegisterRoutes(r *gin.Engine, h *Handlers, m *Middleware) {
 
-	r.Use(m.CORS, m.RequestID, m.Logger, m.Recovery)
+	// didaftarkan sebelum r.Use supaya scrape tidak terkena log, rate limit dan tidak ikut terhitung
+	r.GET("/metrics", gin.WrapH(h.Metrics))
+
+	r.Use(m.Metrics, m.CORS, m.RequestID, m.Tracing, m.Logger, m.Recovery, m.Rate)
 
 	mainGroup := r.Group("/")
 	api := r.Group("/api/v1")
 
 	protected := api.Group("")
-	protected.Use(m.AuthJWT) // tinggal pakai
+	protected.Use(m.AuthJWT, m.RateLimit(ratelimit.PolicyUser)) // tinggal pakai
 
 	// public route
 	h.AuthSSO.RegisterRoute(mainGroup)
 
 	// protected route
-	h.User.RegisterRoute(protected)
-
 	h.Mahasiswa.RegisterRoute(protected)
 
+	// administrasi user / role / permission
+	h.User.RegisterRoute(protected.Group("", m.RequirePermission(entity.PermissionManageUsers)))
+	rbacGroup := protected.Group("", m.RequirePermission(entity.PermissionManageRoles))
+	h.Role.RegisterRoute(rbacGroup)
+	h.Permission.RegisterRoute(rbacGroup)
+
 	api.GET("/health", func(c *gin.Context) { c.JSON(200, gin.H{"success": true, "status": "ok"}) })
 
 }
@@ -2,21 +2,26 @@ package auth
 
 import (
 	"fmt"
+	"github.com/coreos/go-oidc/v3/oidc"
 	"github.com/dedegunawan/epnbp.unsil.ac.id-students-backend2/internal/domain/entity"
 	"github.com/dedegunawan/epnbp.unsil.ac.id-students-backend2/internal/domain/usecase"
+	"github.com/dedegunawan/epnbp.unsil.ac.id-students-backend2/internal/server/middleware"
 	"github.com/dedegunawan/epnbp.unsil.ac.id-students-backend2/pkg/authoidc"
 	"github.com/dedegunawan/epnbp.unsil.ac.id-students-backend2/pkg/encoder"
 	"github.com/dedegunawan/epnbp.unsil.ac.id-students-backend2/pkg/jwtmanager"
 	"github.com/dedegunawan/epnbp.unsil.ac.id-students-backend2/pkg/logger"
+	"github.com/dedegunawan/epnbp.unsil.ac.id-students-backend2/pkg/metrics"
 	"github.com/dedegunawan/epnbp.unsil.ac.id-students-backend2/pkg/request"
 	"github.com/dedegunawan/epnbp.unsil.ac.id-students-backend2/pkg/response"
 	"github.com/dedegunawan/epnbp.unsil.ac.id-students-backend2/pkg/strings"
+	"github.com/dedegunawan/epnbp.unsil.ac.id-students-backend2/pkg/tracing"
 	"github.com/gin-gonic/gin"
 	"golang.org/x/crypto/bcrypt"
 	"golang.org/x/oauth2"
 	"log"
 	"net/http"
 	"os"
+	"time"
 )
 
 type AuthSsoHandler struct {
@@ -48,6 +53,11 @@ func (h *AuthSsoHandler) SsoLogoutHandler(c *gin.Context) {
 	logoutURL := h.auth.GetLogoutURL()
 
 	// Hapus session lokal (jika pakai cookie/token)
+	if token := middleware.ExtractAccessToken(c); token != "" {
+		if err := h.usecases.UserTokenUsecase.RevokeToken(token); err != nil {
+			h.lg.Error("Failed to revoke token on logout", "error", err)
+		}
+	}
 
 	// Redirect ke SSO logout endpoint
 	c.Redirect(http.StatusTemporaryRedirect, logoutURL)
@@ -69,11 +79,15 @@ func (h *AuthSsoHandler) LoginHandler(c *gin.Context) {
 		return
 	}
 
-	h.lg.Info("Login using email:", user.Email)
+	h.lg.Infow("Login using email", "user_id", user.ID)
 
-	// Validasi password (dengan bcrypt)
+	// Validasi password (dengan bcrypt); password dan hash tidak pernah ditulis ke log
+	if user.PasswordHash == nil {
+		response.ErrorHandler(c, http.StatusUnauthorized, "Invalid email or password")
+		return
+	}
 	if err := bcrypt.CompareHashAndPassword([]byte(*user.PasswordHash), []byte(req.Password)); err != nil {
-		h.lg.Info(fmt.Sprintf("Login using email: %s, password: %s, hash: %s, err: %s", user.Email, req.Password, user.PasswordHash, err.Error()))
+		h.lg.Infow("Login failed: password mismatch", "user_id", user.ID)
 		response.ErrorHandler(c, http.StatusUnauthorized, "Invalid email or password")
 		return
 	}
@@ -83,7 +97,7 @@ func (h *AuthSsoHandler) LoginHandler(c *gin.Context) {
 		return
 	}
 
-	h.lg.Info("Try generate JWT by email :", user.Email)
+	h.lg.Infow("Try generate JWT", "user_id", user.ID)
 
 	// Buat token JWT
 	token, err := h.jwt.Generate(user.ID, user.Email, user.Name)
@@ -126,7 +140,11 @@ func (h *AuthSsoHandler) CallbackHandler(c *gin.Context) {
 		return
 	}
 
-	token, err := h.auth.OAuth2Config.Exchange(h.usecases.UserTokenUsecase.GetContext(), code)
+	// client ter-trace lewat ctx: token exchange ke provider jadi child span request dan membawa traceparent
+	exchangeCtx := oidc.ClientContext(c.Request.Context(), tracing.Client(tracing.UpstreamOIDC))
+	exchangeStart := time.Now()
+	token, err := h.auth.OAuth2Config.Exchange(exchangeCtx, code)
+	metrics.ObserveUpstream(metrics.UpstreamOIDC, "token_exchange", exchangeStart, err)
 	if err != nil {
 		log.Println("❌ Token exchange failed:", err)
 		response.ErrorHandler(c, http.StatusUnauthorized, "Token exchange failed")
@@ -139,7 +157,10 @@ func (h *AuthSsoHandler) CallbackHandler(c *gin.Context) {
 		return
 	}
 
+	// Verify bisa mengambil JWKS dari provider, jadi ikut dicatat sebagai panggilan upstream
+	verifyStart := time.Now()
 	idToken, err := h.auth.Verifier.Verify(h.usecases.UserTokenUsecase.GetContext(), rawIDToken)
+	metrics.ObserveUpstream(metrics.UpstreamOIDC, "verify_id_token", verifyStart, err)
 	//utils.Log.Info("Verify token email : ", idToken)
 	if err != nil {
 		response.ErrorHandler(c, http.StatusUnauthorized, "Invalid ID Token")
@@ -1,6 +1,7 @@
 package mahasiswa
 
 import (
+	"github.com/dedegunawan/epnbp.unsil.ac.id-students-backend2/internal/domain/entity"
 	"github.com/dedegunawan/epnbp.unsil.ac.id-students-backend2/internal/domain/usecase"
 	"github.com/dedegunawan/epnbp.unsil.ac.id-students-backend2/pkg/logger"
 	"github.com/dedegunawan/epnbp.unsil.ac.id-students-backend2/pkg/mahasiswa_manager"
@@ -47,10 +48,27 @@ func (mahasiswa *MahasiswaHandler) GetStudentBillStatus(c *gin.Context) {
 
 	if err != nil || mahasiswaManager == nil {
 		response.Error(c, http.StatusInternalServerError, "Failed to create mahasiswa manager: "+err.Error())
+		return
+	}
+
+	// status akademik yang diblokir tidak mendapat tagihan sama sekali (sama dengan backend lama)
+	var eligibility *entity.EligibilityResult
+	if mahasiswaManager.Mahasiswa != nil {
+		eligibility = mahasiswaManager.LoadEligibility()
+	}
+	if eligibility != nil && eligibility.IsBlocked() {
+		c.JSON(http.StatusForbidden, gin.H{
+			"error":       "Tagihan tidak dapat ditampilkan untuk status mahasiswa ini",
+			"eligibility": eligibility,
+		})
+		return
 	}
 
 	// tampilkan data tagihan mahasiswa
-	mahasiswaManager.LoadTagihan()
+	if err := mahasiswaManager.LoadTagihan(); err != nil {
+		response.Error(c, http.StatusInternalServerError, "Failed to load tagihan: "+err.Error())
+		return
+	}
 
 	allPaid := mahasiswaManager.IsAllPaid()
 	isGenerated := mahasiswaManager.IsTagihanGenerated()
@@ -61,8 +79,10 @@ func (mahasiswa *MahasiswaHandler) GetStudentBillStatus(c *gin.Context) {
 		Tahun:               mahasiswaManager.BudgetPeriod,
 		IsPaid:              allPaid,
 		IsGenerated:         isGenerated,
+		IsNotBilled:         mahasiswaManager.IsNotBilled(),
 		TagihanHarusDibayar: tagihanHarusDibayar,
 		HistoryTagihan:      historyTagihan,
+		Eligibility:         eligibility,
 	}
 
 	c.JSON(http.StatusOK, response)
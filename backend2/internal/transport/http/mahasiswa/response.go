@@ -25,11 +25,13 @@ type MahasiswaResponse struct {
 }
 
 type StudentBillResponse struct {
-	Tahun               *entity.BudgetPeriod `json:"tahun"`
-	IsPaid              bool                 `json:"isPaid"`
-	IsGenerated         bool                 `json:"isGenerated"`
-	TagihanHarusDibayar []entity.StudentBill `json:"tagihanHarusDibayar"`
-	HistoryTagihan      []entity.StudentBill `json:"historyTagihan"`
+	Tahun               *entity.BudgetPeriod      `json:"tahun"`
+	IsPaid              bool                      `json:"isPaid"`
+	IsGenerated         bool                      `json:"isGenerated"`
+	IsNotBilled         bool                      `json:"isNotBilled"` // status akademik no_bill: tidak ditagih, bukan lunas
+	TagihanHarusDibayar []entity.Tagihan          `json:"tagihanHarusDibayar"`
+	HistoryTagihan      []entity.Tagihan          `json:"historyTagihan"`
+	Eligibility         *entity.EligibilityResult `json:"eligibility,omitempty"`
 }
 
 func ConvertResponseFromMahasiswa(mahasiswa *entity.Mahasiswa) *MahasiswaResponse {
@@ -0,0 +1,105 @@
+package rbac
+
+import (
+	"net/http"
+
+	"github.com/dedegunawan/epnbp.unsil.ac.id-students-backend2/internal/domain/entity"
+	"github.com/dedegunawan/epnbp.unsil.ac.id-students-backend2/internal/domain/usecase"
+	"github.com/dedegunawan/epnbp.unsil.ac.id-students-backend2/pkg/request"
+	"github.com/dedegunawan/epnbp.unsil.ac.id-students-backend2/pkg/response"
+	"github.com/gin-gonic/gin"
+)
+
+type permissionReq struct {
+	Name        string `json:"name" binding:"required,slug,max=150"`
+	Description string `json:"description" binding:"max=1000"`
+}
+
+type PermissionHandler struct {
+	usecases *usecase.Usecase
+}
+
+func NewPermissionHandler(uc *usecase.Usecase) *PermissionHandler {
+	return &PermissionHandler{usecases: uc}
+}
+
+func (h *PermissionHandler) GetPermissions(c *gin.Context) {
+	page, size := request.Pagination(c)
+	items, total, err := h.usecases.PermissionUsecase.List(page, size)
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	response.Paginated(c, items, total, page, size)
+}
+
+func (h *PermissionHandler) FindByID(c *gin.Context) {
+	id, ok := request.ParamUint64OrAbort(c, "id")
+	if !ok {
+		return
+	}
+	permission, err := h.usecases.PermissionUsecase.FindByID(id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	response.OK(c, permission)
+}
+
+func (h *PermissionHandler) CreatePermission(c *gin.Context) {
+	var req permissionReq
+	if !request.BindJSONOrAbort(c, &req) {
+		return
+	}
+	permission := &entity.Permission{Name: req.Name, Description: req.Description}
+	if err := h.usecases.PermissionUsecase.Create(permission); err != nil {
+		respondError(c, err)
+		return
+	}
+	response.Created(c, permission)
+}
+
+func (h *PermissionHandler) UpdatePermission(c *gin.Context) {
+	id, ok := request.ParamUint64OrAbort(c, "id")
+	if !ok {
+		return
+	}
+	var req permissionReq
+	if !request.BindJSONOrAbort(c, &req) {
+		return
+	}
+	permission, err := h.usecases.PermissionUsecase.FindByID(id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	permission.Name = req.Name
+	permission.Description = req.Description
+	if err := h.usecases.PermissionUsecase.Update(permission); err != nil {
+		respondError(c, err)
+		return
+	}
+	response.OK(c, permission)
+}
+
+func (h *PermissionHandler) DeletePermission(c *gin.Context) {
+	id, ok := request.ParamUint64OrAbort(c, "id")
+	if !ok {
+		return
+	}
+	if err := h.usecases.PermissionUsecase.Delete(id); err != nil {
+		respondError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func (h *PermissionHandler) RegisterRoute(rg *gin.RouterGroup) {
+	g := rg.Group("/permissions")
+
+	g.GET("", h.GetPermissions)
+	g.GET("/:id", h.FindByID)
+	g.POST("", h.CreatePermission)
+	g.PUT("/:id", h.UpdatePermission)
+	g.DELETE("/:id", h.DeletePermission)
+}
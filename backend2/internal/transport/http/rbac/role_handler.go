@@ -0,0 +1,180 @@
+package rbac
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/dedegunawan/epnbp.unsil.ac.id-students-backend2/internal/domain/entity"
+	"github.com/dedegunawan/epnbp.unsil.ac.id-students-backend2/internal/domain/usecase"
+	"github.com/dedegunawan/epnbp.unsil.ac.id-students-backend2/pkg/request"
+	"github.com/dedegunawan/epnbp.unsil.ac.id-students-backend2/pkg/response"
+	"github.com/gin-gonic/gin"
+)
+
+type roleReq struct {
+	Name        string `json:"name" binding:"required,slug,max=100"`
+	Description string `json:"description" binding:"max=1000"`
+}
+
+type assignPermissionReq struct {
+	PermissionID uint64 `json:"permission_id" binding:"required"`
+}
+
+type RoleHandler struct {
+	usecases *usecase.Usecase
+}
+
+func NewRoleHandler(uc *usecase.Usecase) *RoleHandler {
+	return &RoleHandler{usecases: uc}
+}
+
+func (h *RoleHandler) GetRoles(c *gin.Context) {
+	page, size := request.Pagination(c)
+	items, total, err := h.usecases.RoleUsecase.List(page, size)
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	response.Paginated(c, items, total, page, size)
+}
+
+func (h *RoleHandler) FindByID(c *gin.Context) {
+	id, ok := request.ParamUint64OrAbort(c, "id")
+	if !ok {
+		return
+	}
+	role, err := h.usecases.RoleUsecase.FindByID(id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	permissions, err := h.usecases.PermissionUsecase.GetByRoleID(id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	role.Permissions = permissions
+	response.OK(c, role)
+}
+
+func (h *RoleHandler) CreateRole(c *gin.Context) {
+	var req roleReq
+	if !request.BindJSONOrAbort(c, &req) {
+		return
+	}
+	role := &entity.Role{Name: req.Name, Description: req.Description}
+	if err := h.usecases.RoleUsecase.Create(role); err != nil {
+		respondError(c, err)
+		return
+	}
+	response.Created(c, role)
+}
+
+func (h *RoleHandler) UpdateRole(c *gin.Context) {
+	id, ok := request.ParamUint64OrAbort(c, "id")
+	if !ok {
+		return
+	}
+	var req roleReq
+	if !request.BindJSONOrAbort(c, &req) {
+		return
+	}
+	role, err := h.usecases.RoleUsecase.FindByID(id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	role.Name = req.Name
+	role.Description = req.Description
+	if err := h.usecases.RoleUsecase.Update(role); err != nil {
+		respondError(c, err)
+		return
+	}
+	response.OK(c, role)
+}
+
+func (h *RoleHandler) DeleteRole(c *gin.Context) {
+	id, ok := request.ParamUint64OrAbort(c, "id")
+	if !ok {
+		return
+	}
+	if err := h.usecases.RoleUsecase.Delete(id); err != nil {
+		respondError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func (h *RoleHandler) GetPermissions(c *gin.Context) {
+	id, ok := request.ParamUint64OrAbort(c, "id")
+	if !ok {
+		return
+	}
+	if _, err := h.usecases.RoleUsecase.FindByID(id); err != nil {
+		respondError(c, err)
+		return
+	}
+	permissions, err := h.usecases.PermissionUsecase.GetByRoleID(id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	response.OK(c, permissions)
+}
+
+func (h *RoleHandler) AssignPermission(c *gin.Context) {
+	id, ok := request.ParamUint64OrAbort(c, "id")
+	if !ok {
+		return
+	}
+	var req assignPermissionReq
+	if !request.BindJSONOrAbort(c, &req) {
+		return
+	}
+	if err := h.usecases.RolePermissionUsecase.AssignPermission(id, req.PermissionID); err != nil {
+		respondError(c, err)
+		return
+	}
+	response.OK(c, gin.H{"role_id": id, "permission_id": req.PermissionID})
+}
+
+func (h *RoleHandler) RemovePermission(c *gin.Context) {
+	id, ok := request.ParamUint64OrAbort(c, "id")
+	if !ok {
+		return
+	}
+	permissionID, ok := request.ParamUint64OrAbort(c, "permissionId")
+	if !ok {
+		return
+	}
+	if err := h.usecases.RolePermissionUsecase.RemovePermission(id, permissionID); err != nil {
+		respondError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func (h *RoleHandler) RegisterRoute(rg *gin.RouterGroup) {
+	g := rg.Group("/roles")
+
+	g.GET("", h.GetRoles)
+	g.GET("/:id", h.FindByID)
+	g.POST("", h.CreateRole)
+	g.PUT("/:id", h.UpdateRole)
+	g.DELETE("/:id", h.DeleteRole)
+
+	g.GET("/:id/permissions", h.GetPermissions)
+	g.POST("/:id/permissions", h.AssignPermission)
+	g.DELETE("/:id/permissions/:permissionId", h.RemovePermission)
+}
+
+func respondError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, usecase.ErrNotFound):
+		response.Error(c, http.StatusNotFound, "not found")
+	case errors.Is(err, usecase.ErrAlreadyExists):
+		response.Error(c, http.StatusConflict, err.Error())
+	default:
+		response.Error(c, http.StatusInternalServerError, err.Error())
+	}
+}
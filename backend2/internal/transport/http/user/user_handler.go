@@ -1,14 +1,26 @@
 package user
 
 import (
+	"errors"
 	"github.com/dedegunawan/epnbp.unsil.ac.id-students-backend2/internal/domain/usecase"
 	"net/http"
-	"strconv"
 
+	"github.com/dedegunawan/epnbp.unsil.ac.id-students-backend2/pkg/request"
 	"github.com/dedegunawan/epnbp.unsil.ac.id-students-backend2/pkg/response"
 	"github.com/gin-gonic/gin"
 )
 
+type createUserReq struct {
+	Name     string `json:"name" binding:"required,max=120"`
+	Email    string `json:"email" binding:"required,email,max=180"`
+	Password string `json:"password" binding:"omitempty,min=8"`
+}
+
+type updateUserReq struct {
+	Name  string `json:"name" binding:"required,max=120"`
+	Email string `json:"email" binding:"required,email,max=180"`
+}
+
 type avatarReq struct {
 	Avatar string `json:"avatar" binding:"required,url"`
 }
@@ -17,6 +29,10 @@ type activeReq struct {
 	IsActive bool `json:"is_active"`
 }
 
+type assignRoleReq struct {
+	RoleID uint64 `json:"role_id" binding:"required"`
+}
+
 type UserHandler struct {
 	usecases usecase.Usecase
 }
@@ -26,57 +42,158 @@ func NewUserHandler(uc usecase.Usecase) *UserHandler {
 }
 
 func (h *UserHandler) FindById(c *gin.Context) {
-	id, _ := strconv.ParseUint(c.Param("id"), 10, 64)
+	id, ok := request.ParamUint64OrAbort(c, "id")
+	if !ok {
+		return
+	}
 	u, err := h.usecases.UserUsecase.GetByID(id)
 	if err != nil {
-		response.Error(c, 404, "not found")
+		respondError(c, err)
 		return
 	}
 	response.OK(c, u)
 }
 func (h *UserHandler) GetUsers(c *gin.Context) {
-	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
-	size, _ := strconv.Atoi(c.DefaultQuery("size", "10"))
+	page, size := request.Pagination(c)
 	items, total, err := h.usecases.UserUsecase.List(page, size)
 	if err != nil {
 		response.Error(c, 400, err.Error())
 		return
 	}
-	response.OK(c, gin.H{"items": items, "total": total, "page": page, "size": size})
+	response.Paginated(c, items, total, page, size)
+}
+func (h *UserHandler) CreateUser(c *gin.Context) {
+	var req createUserReq
+	if !request.BindJSONOrAbort(c, &req) {
+		return
+	}
+	u, err := h.usecases.UserUsecase.Create(req.Name, req.Email, req.Password)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	response.Created(c, u)
+}
+func (h *UserHandler) UpdateUser(c *gin.Context) {
+	id, ok := request.ParamUint64OrAbort(c, "id")
+	if !ok {
+		return
+	}
+	var req updateUserReq
+	if !request.BindJSONOrAbort(c, &req) {
+		return
+	}
+	u, err := h.usecases.UserUsecase.Update(id, req.Name, req.Email)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	response.OK(c, u)
 }
 func (h *UserHandler) UpdateAvatar(c *gin.Context) {
-	id, _ := strconv.ParseUint(c.Param("id"), 10, 64)
+	id, ok := request.ParamUint64OrAbort(c, "id")
+	if !ok {
+		return
+	}
 	var req avatarReq
-	if err := c.ShouldBindJSON(&req); err != nil {
-		response.Error(c, http.StatusBadRequest, err.Error())
+	if !request.BindJSONOrAbort(c, &req) {
 		return
 	}
 	if err := h.usecases.UserUsecase.UpdateAvatar(id, req.Avatar); err != nil {
-		response.Error(c, 400, err.Error())
+		respondError(c, err)
 		return
 	}
 	response.OK(c, gin.H{"id": id, "avatar": req.Avatar})
 }
 func (h *UserHandler) UpdateActiveStatus(c *gin.Context) {
-	id, _ := strconv.ParseUint(c.Param("id"), 10, 64)
+	id, ok := request.ParamUint64OrAbort(c, "id")
+	if !ok {
+		return
+	}
 	var req activeReq
-	if err := c.ShouldBindJSON(&req); err != nil {
-		response.Error(c, http.StatusBadRequest, err.Error())
+	if !request.BindJSONOrAbort(c, &req) {
 		return
 	}
 	if err := h.usecases.UserUsecase.SetActive(id, req.IsActive); err != nil {
-		response.Error(c, 400, err.Error())
+		respondError(c, err)
 		return
 	}
+	// user nonaktif langsung kehilangan semua sesi
+	if !req.IsActive {
+		if err := h.usecases.UserTokenUsecase.RevokeAllTokens(id); err != nil {
+			response.Error(c, http.StatusInternalServerError, "Failed to revoke user tokens")
+			return
+		}
+	}
 	response.OK(c, gin.H{"id": id, "is_active": req.IsActive})
 }
 
+func (h *UserHandler) GetRoles(c *gin.Context) {
+	id, ok := request.ParamUint64OrAbort(c, "id")
+	if !ok {
+		return
+	}
+	roles, err := h.usecases.UserRoleUsecase.GetRoles(id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	response.OK(c, roles)
+}
+func (h *UserHandler) AssignRole(c *gin.Context) {
+	id, ok := request.ParamUint64OrAbort(c, "id")
+	if !ok {
+		return
+	}
+	var req assignRoleReq
+	if !request.BindJSONOrAbort(c, &req) {
+		return
+	}
+	if err := h.usecases.UserRoleUsecase.AssignRole(id, req.RoleID); err != nil {
+		respondError(c, err)
+		return
+	}
+	response.OK(c, gin.H{"user_id": id, "role_id": req.RoleID})
+}
+func (h *UserHandler) RemoveRole(c *gin.Context) {
+	id, ok := request.ParamUint64OrAbort(c, "id")
+	if !ok {
+		return
+	}
+	roleID, ok := request.ParamUint64OrAbort(c, "roleId")
+	if !ok {
+		return
+	}
+	if err := h.usecases.UserRoleUsecase.RemoveRole(id, roleID); err != nil {
+		respondError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
 func (h *UserHandler) RegisterRoute(rg *gin.RouterGroup) {
 
 	g := rg.Group("/users")
 
 	g.GET("/:id", h.FindById)
 	g.GET("", h.GetUsers)
+	g.POST("", h.CreateUser)
+	g.PUT("/:id", h.UpdateUser)
 	g.PUT("/:id/avatar", h.UpdateAvatar)
 	g.PUT("/:id/active", h.UpdateActiveStatus)
+
+	g.GET("/:id/roles", h.GetRoles)
+	g.POST("/:id/roles", h.AssignRole)
+	g.DELETE("/:id/roles/:roleId", h.RemoveRole)
+}
+
+func respondError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, usecase.ErrNotFound):
+		response.Error(c, http.StatusNotFound, "not found")
+	case errors.Is(err, usecase.ErrAlreadyExists):
+		response.Error(c, http.StatusConflict, err.Error())
+	default:
+		response.Error(c, http.StatusBadRequest, err.Error())
+	}
 }
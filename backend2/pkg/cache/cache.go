@@ -0,0 +1,48 @@
+// Package cache menyediakan cache key-value dengan TTL (Redis atau in-memory).
+package cache
+
+import (
+	"bytes"
+	"context"
+	"encoding/gob"
+	"errors"
+	"time"
+)
+
+const (
+	DriverRedis  = "redis"
+	DriverMemory = "memory"
+	DriverNone   = "none"
+)
+
+// ErrMiss dikembalikan Get jika key tidak ada atau sudah kedaluwarsa
+var ErrMiss = errors.New("cache: miss")
+
+type Cache interface {
+	// Get mengisi dest (pointer) dari cache; ErrMiss jika tidak ada
+	Get(ctx context.Context, key string, dest any) error
+	// Set menyimpan value dengan TTL; ttl <= 0 berarti tidak disimpan
+	Set(ctx context.Context, key string, value any, ttl time.Duration) error
+	Delete(ctx context.Context, keys ...string) error
+}
+
+// Value di-encode dengan gob (bukan JSON) supaya field ber-tag json:"-"
+// seperti User.PasswordHash tetap utuh saat dibaca ulang dari cache.
+func encode(value any) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(value); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func decode(data []byte, dest any) error {
+	return gob.NewDecoder(bytes.NewReader(data)).Decode(dest)
+}
+
+// Noop cache yang selalu miss, untuk CACHE_DRIVER=none
+type Noop struct{}
+
+func (Noop) Get(context.Context, string, any) error                { return ErrMiss }
+func (Noop) Set(context.Context, string, any, time.Duration) error { return nil }
+func (Noop) Delete(context.Context, ...string) error               { return nil }
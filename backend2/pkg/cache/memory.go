@@ -0,0 +1,93 @@
+package cache
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+type memoryEntry struct {
+	data      []byte
+	expiresAt time.Time
+}
+
+// Memory cache in-process untuk test dan deployment satu node
+type Memory struct {
+	mu      sync.RWMutex
+	entries map[string]memoryEntry
+	now     func() time.Time
+}
+
+func NewMemory() *Memory {
+	return &Memory{entries: make(map[string]memoryEntry), now: time.Now}
+}
+
+func (m *Memory) Get(_ context.Context, key string, dest any) error {
+	m.mu.RLock()
+	entry, ok := m.entries[key]
+	m.mu.RUnlock()
+	if !ok {
+		return ErrMiss
+	}
+	if !m.now().Before(entry.expiresAt) {
+		m.mu.Lock()
+		if current, ok := m.entries[key]; ok && current.expiresAt.Equal(entry.expiresAt) {
+			delete(m.entries, key)
+		}
+		m.mu.Unlock()
+		return ErrMiss
+	}
+	return decode(entry.data, dest)
+}
+
+func (m *Memory) Set(_ context.Context, key string, value any, ttl time.Duration) error {
+	if ttl <= 0 {
+		return nil
+	}
+	data, err := encode(value)
+	if err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.entries[key] = memoryEntry{data: data, expiresAt: m.now().Add(ttl)}
+	return nil
+}
+
+func (m *Memory) Delete(_ context.Context, keys ...string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, key := range keys {
+		delete(m.entries, key)
+	}
+	return nil
+}
+
+// Purge membuang entry yang sudah kedaluwarsa; dipanggil berkala oleh pemilik cache
+func (m *Memory) Purge() {
+	now := m.now()
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for key, entry := range m.entries {
+		if !now.Before(entry.expiresAt) {
+			delete(m.entries, key)
+		}
+	}
+}
+
+// StartPurge menjalankan Purge tiap interval sampai ctx selesai
+func (m *Memory) StartPurge(ctx context.Context, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				m.Purge()
+			}
+		}
+	}()
+}
@@ -0,0 +1,62 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/dedegunawan/epnbp.unsil.ac.id-students-backend2/pkg/metrics"
+	goredis "github.com/redis/go-redis/v9"
+)
+
+// Redis cache bersama antar instance; semua key diberi prefix
+type Redis struct {
+	client goredis.UniversalClient
+	prefix string
+}
+
+func NewRedis(client goredis.UniversalClient, prefix string) *Redis {
+	return &Redis{client: client, prefix: prefix}
+}
+
+func (r *Redis) Get(ctx context.Context, key string, dest any) error {
+	start := time.Now()
+	data, err := r.client.Get(ctx, r.prefix+key).Bytes()
+	if errors.Is(err, goredis.Nil) {
+		metrics.ObserveUpstream(metrics.UpstreamRedis, "cache_get", start, nil)
+		return ErrMiss
+	}
+	metrics.ObserveUpstream(metrics.UpstreamRedis, "cache_get", start, err)
+	if err != nil {
+		return err
+	}
+	return decode(data, dest)
+}
+
+func (r *Redis) Set(ctx context.Context, key string, value any, ttl time.Duration) error {
+	if ttl <= 0 {
+		return nil
+	}
+	data, err := encode(value)
+	if err != nil {
+		return err
+	}
+	start := time.Now()
+	err = r.client.Set(ctx, r.prefix+key, data, ttl).Err()
+	metrics.ObserveUpstream(metrics.UpstreamRedis, "cache_set", start, err)
+	return err
+}
+
+func (r *Redis) Delete(ctx context.Context, keys ...string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	prefixed := make([]string, len(keys))
+	for i, key := range keys {
+		prefixed[i] = r.prefix + key
+	}
+	start := time.Now()
+	err := r.client.Del(ctx, prefixed...).Err()
+	metrics.ObserveUpstream(metrics.UpstreamRedis, "cache_delete", start, err)
+	return err
+}
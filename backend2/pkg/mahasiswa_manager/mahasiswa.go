@@ -19,6 +19,12 @@ type Mahasiswa struct {
 	usecases     *usecase.Usecase
 	BudgetPeriod *entity.BudgetPeriod
 	logger       *logger.Logger
+
+	Eligibility *entity.EligibilityResult
+
+	tagihan        []entity.Tagihan
+	historyTagihan []entity.Tagihan
+	tagihanLoaded  bool
 }
 
 func NewFromContext(c *gin.Context, uc *usecase.Usecase, logger *logger.Logger) (*Mahasiswa, error) {
@@ -1,24 +1,89 @@
 package mahasiswa_manager
 
-import "github.com/dedegunawan/epnbp.unsil.ac.id-students-backend2/internal/domain/entity"
+import (
+	"errors"
 
-func (mahasiswa *Mahasiswa) LoadTagihan() {
+	"github.com/dedegunawan/epnbp.unsil.ac.id-students-backend2/internal/domain/entity"
+)
 
+// LoadEligibility evaluasi kelayakan penagihan dari status akademik mahasiswa
+func (mahasiswa *Mahasiswa) LoadEligibility() *entity.EligibilityResult {
+	mahasiswa.Eligibility = mahasiswa.usecases.EligibilityUsecase.EvaluateMahasiswa(mahasiswa.Mahasiswa)
+	return mahasiswa.Eligibility
 }
 
+// LoadTagihan memuat tagihan yang harus dibayar dan riwayat pembayaran periode aktif
+func (mahasiswa *Mahasiswa) LoadTagihan() error {
+	if mahasiswa.Mahasiswa == nil {
+		return errors.New("Mahasiswa not found")
+	}
+	if mahasiswa.BudgetPeriod == nil {
+		return errors.New("Active budget period not found")
+	}
+
+	if mahasiswa.Eligibility == nil {
+		mahasiswa.LoadEligibility()
+	}
+
+	npm := mahasiswa.Mahasiswa.StudentID
+	tagihan, err := mahasiswa.usecases.BillingUsecase.GetTagihan(npm, mahasiswa.BudgetPeriod)
+	if err != nil {
+		mahasiswa.logger.Error("Failed to load tagihan", "error", err, "npm", npm)
+		return err
+	}
+	history, err := mahasiswa.usecases.BillingUsecase.GetHistory(npm, mahasiswa.BudgetPeriod)
+	if err != nil {
+		mahasiswa.logger.Error("Failed to load history tagihan", "error", err, "npm", npm)
+		return err
+	}
+
+	mahasiswa.tagihan = tagihan
+	mahasiswa.historyTagihan = history
+	mahasiswa.tagihanLoaded = true
+	return nil
+}
+
+// IsAllPaid true jika tagihan sudah dibuat dan tidak ada lagi sisa yang harus dibayar.
+// Mahasiswa no_bill tidak dianggap lunas (lihat IsNotBilled).
 func (mahasiswa *Mahasiswa) IsAllPaid() bool {
-	return false
+	if !mahasiswa.IsTagihanGenerated() || mahasiswa.IsNotBilled() {
+		return false
+	}
+	return len(mahasiswa.TagihanHarusDibayar()) == 0
 }
 
+// IsNotBilled true jika status akademik mahasiswa tidak ditagih (mis. cuti, lulus)
+func (mahasiswa *Mahasiswa) IsNotBilled() bool {
+	return mahasiswa.Eligibility != nil && mahasiswa.Eligibility.IsNotBilled()
+}
+
+// IsTagihanGenerated true jika ada tagihan atau riwayat pembayaran
 func (mahasiswa *Mahasiswa) IsTagihanGenerated() bool {
-	return true
+	return mahasiswa.tagihanLoaded && (len(mahasiswa.tagihan) > 0 || len(mahasiswa.historyTagihan) > 0)
 }
 
-func (mahasiswa *Mahasiswa) TagihanHarusDibayar() []entity.StudentBill {
-	var studentBills []entity.StudentBill
-	return studentBills
+// TagihanHarusDibayar tagihan setelah aturan kelayakan, sama dengan GetStudentBillStatusNew di
+// backend lama: no_bill tidak ditagih, bill_reduced dipotong persentase (selisihnya di potongan_status)
+func (mahasiswa *Mahasiswa) TagihanHarusDibayar() []entity.Tagihan {
+	if mahasiswa.Eligibility == nil {
+		return mahasiswa.tagihan
+	}
+	if mahasiswa.IsNotBilled() {
+		return nil
+	}
+
+	var result []entity.Tagihan
+	for _, tagihan := range mahasiswa.tagihan {
+		billed := mahasiswa.Eligibility.ApplyToAmount(tagihan.RemainingAmount)
+		tagihan.PotonganStatus = tagihan.RemainingAmount - billed
+		tagihan.RemainingAmount = billed
+		if tagihan.RemainingAmount > 0 {
+			result = append(result, tagihan)
+		}
+	}
+	return result
 }
-func (mahasiswa *Mahasiswa) HistoryTagihan() []entity.StudentBill {
-	var studentBills []entity.StudentBill
-	return studentBills
+
+func (mahasiswa *Mahasiswa) HistoryTagihan() []entity.Tagihan {
+	return mahasiswa.historyTagihan
 }
@@ -0,0 +1,107 @@
+// Package metrics metrik Prometheus untuk HTTP, query database dan panggilan ke sistem luar
+package metrics
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "epnbp2"
+
+// Nama upstream untuk label "upstream"
+const (
+	UpstreamOIDC  = "oidc"
+	UpstreamRedis = "redis"
+)
+
+// Nilai label "outcome"
+const (
+	OutcomeSuccess = "success"
+	OutcomeError   = "error"
+)
+
+// Registry terpisah dari prometheus.DefaultRegisterer supaya isi /metrics terkendali
+var Registry = prometheus.NewRegistry()
+
+var (
+	HTTPRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "http_requests_total",
+		Help:      "Number of HTTP requests by route, method and status.",
+	}, []string{"method", "route", "status"})
+
+	HTTPDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "HTTP request latency by route, method and status.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	DBQueryDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "db_query_duration_seconds",
+		Help:      "GORM query duration by database, table and operation.",
+		Buckets:   []float64{.001, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5},
+	}, []string{"database", "table", "operation", "outcome"})
+
+	UpstreamRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "upstream_requests_total",
+		Help:      "Number of calls to external systems (OIDC provider, Redis).",
+	}, []string{"upstream", "operation", "outcome"})
+
+	UpstreamDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "upstream_request_duration_seconds",
+		Help:      "Latency of calls to external systems (OIDC provider, Redis).",
+		Buckets:   []float64{.001, .005, .025, .1, .25, .5, 1, 2.5, 5, 10},
+	}, []string{"upstream", "operation", "outcome"})
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		HTTPRequests, HTTPDuration,
+		DBQueryDuration,
+		UpstreamRequests, UpstreamDuration,
+	)
+}
+
+// Outcome label "success"/"error" dari err
+func Outcome(err error) string {
+	if err != nil {
+		return OutcomeError
+	}
+	return OutcomeSuccess
+}
+
+// ObserveUpstream catat satu panggilan ke sistem luar yang dimulai pada start
+func ObserveUpstream(upstream, operation string, start time.Time, err error) {
+	outcome := Outcome(err)
+	UpstreamRequests.WithLabelValues(upstream, operation, outcome).Inc()
+	UpstreamDuration.WithLabelValues(upstream, operation, outcome).Observe(time.Since(start).Seconds())
+}
+
+// Handler endpoint /metrics. Jika token diisi, scraper wajib mengirim
+// "Authorization: Bearer <token>"; kosong berarti terbuka (batasi lewat jaringan).
+func Handler(token string) http.Handler {
+	next := promhttp.HandlerFor(Registry, promhttp.HandlerOpts{})
+	if token == "" {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		given := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if subtle.ConstantTimeCompare([]byte(given), []byte(token)) != 1 {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,77 @@
+// Package ratelimit token bucket per key (user atau IP) dengan penyimpanan Redis atau memori.
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Limit budget token bucket: kapasitas Requests, terisi penuh kembali dalam Per
+type Limit struct {
+	Requests int
+	Per      time.Duration
+}
+
+// ParseLimit format "<jumlah>/<s|m|h>", mis. "10/m" = 10 request per menit
+func ParseLimit(raw string) (Limit, error) {
+	parts := strings.SplitN(strings.TrimSpace(raw), "/", 2)
+	if len(parts) != 2 {
+		return Limit{}, fmt.Errorf("invalid rate limit %q (example: 10/m)", raw)
+	}
+	requests, err := strconv.Atoi(strings.TrimSpace(parts[0]))
+	if err != nil || requests <= 0 {
+		return Limit{}, fmt.Errorf("invalid request count in %q", raw)
+	}
+
+	var per time.Duration
+	switch strings.TrimSpace(parts[1]) {
+	case "s":
+		per = time.Second
+	case "m":
+		per = time.Minute
+	case "h":
+		per = time.Hour
+	default:
+		return Limit{}, fmt.Errorf("invalid time unit in %q (s, m or h)", raw)
+	}
+	return Limit{Requests: requests, Per: per}, nil
+}
+
+// ratePerMs token yang terisi per milidetik
+func (l Limit) ratePerMs() float64 {
+	return float64(l.Requests) / float64(l.Per.Milliseconds())
+}
+
+// Decision hasil pengambilan satu token
+type Decision struct {
+	Allowed    bool
+	Limit      int
+	Remaining  int
+	RetryAfter time.Duration // kapan token berikutnya tersedia (0 jika Allowed)
+	Reset      time.Duration // kapan bucket penuh kembali
+}
+
+// decide menghitung Decision dari sisa token setelah pengambilan
+func decide(limit Limit, allowed bool, tokens float64) Decision {
+	rate := limit.ratePerMs()
+	decision := Decision{
+		Allowed:   allowed,
+		Limit:     limit.Requests,
+		Remaining: int(math.Floor(tokens)),
+		Reset:     time.Duration(math.Ceil((float64(limit.Requests)-tokens)/rate)) * time.Millisecond,
+	}
+	if !allowed {
+		decision.RetryAfter = time.Duration(math.Ceil((1-tokens)/rate)) * time.Millisecond
+	}
+	return decision
+}
+
+// Store penyimpanan bucket; Take mengambil satu token dari bucket key
+type Store interface {
+	Name() string
+	Take(ctx context.Context, key string, limit Limit) (Decision, error)
+}
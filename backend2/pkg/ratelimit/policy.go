@@ -0,0 +1,88 @@
+package ratelimit
+
+import (
+	"fmt"
+	"net"
+	"strings"
+)
+
+// Nama budget per route; nilainya bisa diubah lewat env RATE_LIMIT_<NAMA>, mis. RATE_LIMIT_USER=60/m
+const (
+	PolicyDefault = "default" // semua request, per IP
+	PolicyUser    = "user"    // route protected, per user
+)
+
+// DefaultLimits budget bawaan jika env tidak diisi
+var DefaultLimits = map[string]string{
+	PolicyDefault: "1200/m",
+	PolicyUser:    "120/m",
+}
+
+// Limiter menggabungkan store, budget per policy dan allow-list
+type Limiter struct {
+	Store     Store
+	limits    map[string]Limit
+	allowNets []*net.IPNet
+}
+
+func NewLimiter(store Store, limits map[string]Limit, allowList []string) (*Limiter, error) {
+	if _, ok := limits[PolicyDefault]; !ok {
+		return nil, fmt.Errorf("rate limit policy %q is required", PolicyDefault)
+	}
+	l := &Limiter{Store: store, limits: limits}
+	for _, entry := range allowList {
+		if strings.TrimSpace(entry) == "" {
+			continue
+		}
+		ipNet, err := parseAllowEntry(entry)
+		if err != nil {
+			return nil, err
+		}
+		l.allowNets = append(l.allowNets, ipNet)
+	}
+	return l, nil
+}
+
+// Limit budget policy; fallback ke PolicyDefault jika policy tidak dikenal
+func (l *Limiter) Limit(policy string) Limit {
+	if limit, ok := l.limits[policy]; ok {
+		return limit
+	}
+	return l.limits[PolicyDefault]
+}
+
+// Allowed true jika IP termasuk allow-list (caller internal tidak dibatasi)
+func (l *Limiter) Allowed(ip string) bool {
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return false
+	}
+	for _, ipNet := range l.allowNets {
+		if ipNet.Contains(parsed) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseAllowEntry menerima IP tunggal atau CIDR
+func parseAllowEntry(entry string) (*net.IPNet, error) {
+	entry = strings.TrimSpace(entry)
+	if !strings.Contains(entry, "/") {
+		ip := net.ParseIP(entry)
+		if ip == nil {
+			return nil, fmt.Errorf("invalid allow-list entry %q", entry)
+		}
+		bits := 128
+		if ip.To4() != nil {
+			ip = ip.To4()
+			bits = 32
+		}
+		return &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)}, nil
+	}
+	_, ipNet, err := net.ParseCIDR(entry)
+	if err != nil {
+		return nil, fmt.Errorf("invalid allow-list entry %q: %w", entry, err)
+	}
+	return ipNet, nil
+}
@@ -0,0 +1,68 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	goredis "github.com/redis/go-redis/v9"
+)
+
+// takeScript token bucket atomik di Redis; waktu diambil dari server Redis supaya sama untuk semua instance
+var takeScript = goredis.NewScript(`
+local capacity = tonumber(ARGV[1])
+local rate = tonumber(ARGV[2])
+local t = redis.call('TIME')
+local now = tonumber(t[1]) * 1000 + math.floor(tonumber(t[2]) / 1000)
+
+local data = redis.call('HMGET', KEYS[1], 'tokens', 'ts')
+local tokens = tonumber(data[1])
+local ts = tonumber(data[2])
+if tokens == nil then
+  tokens = capacity
+  ts = now
+end
+
+tokens = math.min(capacity, tokens + math.max(0, now - ts) * rate)
+local allowed = 0
+if tokens >= 1 then
+  tokens = tokens - 1
+  allowed = 1
+end
+
+redis.call('HSET', KEYS[1], 'tokens', tostring(tokens), 'ts', now)
+redis.call('PEXPIRE', KEYS[1], math.ceil(capacity / rate))
+return {allowed, tostring(tokens)}
+`)
+
+// RedisStore bucket bersama untuk semua instance backend2
+type RedisStore struct {
+	client goredis.UniversalClient
+	prefix string
+}
+
+func NewRedisStore(client goredis.UniversalClient, prefix string) *RedisStore {
+	return &RedisStore{client: client, prefix: prefix}
+}
+
+func (s *RedisStore) Name() string {
+	return "redis"
+}
+
+func (s *RedisStore) Take(ctx context.Context, key string, limit Limit) (Decision, error) {
+	raw, err := takeScript.Run(ctx, s.client, []string{s.prefix + key}, limit.Requests, limit.ratePerMs()).Slice()
+	if err != nil {
+		return Decision{}, err
+	}
+	if len(raw) != 2 {
+		return Decision{}, fmt.Errorf("unexpected rate limit script reply: %v", raw)
+	}
+
+	allowed, _ := raw[0].(int64)
+	tokensStr, _ := raw[1].(string)
+	tokens, err := strconv.ParseFloat(tokensStr, 64)
+	if err != nil {
+		return Decision{}, fmt.Errorf("invalid remaining tokens %q: %w", tokensStr, err)
+	}
+	return decide(limit, allowed == 1, tokens), nil
+}
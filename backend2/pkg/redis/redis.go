@@ -1,6 +1,8 @@
 package redis
 
 import (
+	"context"
+
 	"github.com/dedegunawan/epnbp.unsil.ac.id-students-backend2/config"
 	"github.com/redis/go-redis/v9"
 )
@@ -33,3 +35,16 @@ func NewRedisClient(config config.RedisConfig) *RedisClient {
 	}
 
 }
+
+// Client akses go-redis client (mis. untuk pkg/cache)
+func (r *RedisClient) Client() *redis.Client {
+	return r.client
+}
+
+func (r *RedisClient) Ping(ctx context.Context) error {
+	return r.client.Ping(ctx).Err()
+}
+
+func (r *RedisClient) Close() error {
+	return r.client.Close()
+}
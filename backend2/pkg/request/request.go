@@ -2,11 +2,16 @@ package request
 
 import (
 	"errors"
+	"github.com/dedegunawan/epnbp.unsil.ac.id-students-backend2/pkg/validator"
 	"github.com/gin-gonic/gin"
-	"github.com/go-playground/validator/v10"
 	"io"
 	"net/http"
-	"strings"
+	"strconv"
+)
+
+const (
+	DefaultPageSize = 10
+	MaxPageSize     = 100
 )
 
 func BindJSONOrAbort(c *gin.Context, obj interface{}) bool {
@@ -22,28 +27,37 @@ func BindJSONOrAbort(c *gin.Context, obj interface{}) bool {
 }
 
 func RespondValidationError(c *gin.Context, err error) {
-	var ve validator.ValidationErrors
-	if ok := strings.Contains(err.Error(), "validation for"); err != nil && ok {
-		if errors.As(err, &ve) {
-			out := make(map[string]string)
-			for _, fe := range ve {
-				field := strings.ToLower(fe.Field())
-				switch fe.Tag() {
-				case "required":
-					out[field] = field + " is required"
-				case "email":
-					out[field] = "invalid email format"
-				case "min":
-					out[field] = field + " must be at least " + fe.Param() + " characters"
-				default:
-					out[field] = "invalid value"
-				}
-			}
-			c.JSON(http.StatusBadRequest, gin.H{"errors": out})
-			return
-		}
+	if out, ok := validator.Messages(err); ok {
+		c.JSON(http.StatusBadRequest, gin.H{"errors": out})
+		return
 	}
 
 	// fallback if not validation error
 	c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 }
+
+// ParamUint64OrAbort ambil path param numerik (mis. :id), 400 jika tidak valid
+func ParamUint64OrAbort(c *gin.Context, name string) (uint64, bool) {
+	id, err := strconv.ParseUint(c.Param(name), 10, 64)
+	if err != nil || id == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid " + name})
+		return 0, false
+	}
+	return id, true
+}
+
+// Pagination query ?page=&size= dengan batas aman (page >= 1, 1 <= size <= MaxPageSize)
+func Pagination(c *gin.Context) (page, size int) {
+	page, _ = strconv.Atoi(c.DefaultQuery("page", "1"))
+	size, _ = strconv.Atoi(c.DefaultQuery("size", strconv.Itoa(DefaultPageSize)))
+	if page < 1 {
+		page = 1
+	}
+	if size < 1 {
+		size = DefaultPageSize
+	}
+	if size > MaxPageSize {
+		size = MaxPageSize
+	}
+	return page, size
+}
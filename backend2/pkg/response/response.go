@@ -47,3 +47,20 @@ func ErrorHandler(c *gin.Context, code int, message string) {
 		return
 	}
 }
+
+// Page data list berhalaman; bentuknya dipakai semua endpoint list
+type Page struct {
+	Items      any   `json:"items"`
+	Total      int64 `json:"total"`
+	Page       int   `json:"page"`
+	Size       int   `json:"size"`
+	TotalPages int   `json:"total_pages"`
+}
+
+func Paginated(c *gin.Context, items any, total int64, page, size int) {
+	totalPages := 0
+	if size > 0 {
+		totalPages = int((total + int64(size) - 1) / int64(size))
+	}
+	OK(c, Page{Items: items, Total: total, Page: page, Size: size, TotalPages: totalPages})
+}
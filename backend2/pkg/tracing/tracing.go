@@ -0,0 +1,86 @@
+// Package tracing OpenTelemetry: TracerProvider global, propagasi W3C trace context dan transport HTTP ter-trace
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentationName = "github.com/dedegunawan/epnbp.unsil.ac.id-students-backend2"
+
+// Nama upstream untuk span HTTP keluar
+const (
+	UpstreamOIDC = "oidc"
+)
+
+// ShutdownFunc flush span yang masih di buffer
+type ShutdownFunc func(context.Context) error
+
+// Init memasang TracerProvider global. exporter: otlp (OTEL_EXPORTER_OTLP_ENDPOINT), stdout/console
+// untuk lokal, atau none/kosong (tracing mati, propagasi traceparent tetap jalan).
+// Sampling mengikuti OTEL_TRACES_SAMPLER/OTEL_TRACES_SAMPLER_ARG.
+func Init(ctx context.Context, exporter, serviceName string) (ShutdownFunc, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	spanExporter, err := newExporter(ctx, strings.ToLower(exporter))
+	if err != nil || spanExporter == nil {
+		return func(context.Context) error { return nil }, err
+	}
+
+	res, err := resource.New(ctx,
+		resource.WithAttributes(semconv.ServiceName(serviceName)),
+		resource.WithFromEnv(),
+		resource.WithTelemetrySDK(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("create tracing resource: %w", err)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(spanExporter),
+		sdktrace.WithResource(res),
+	)
+	otel.SetTracerProvider(provider)
+	return provider.Shutdown, nil
+}
+
+func newExporter(ctx context.Context, name string) (sdktrace.SpanExporter, error) {
+	switch name {
+	case "", "none":
+		return nil, nil
+	case "otlp":
+		return otlptracehttp.New(ctx)
+	case "stdout", "console":
+		return stdouttrace.New(stdouttrace.WithPrettyPrint())
+	default:
+		return nil, fmt.Errorf("unknown OTEL_TRACES_EXPORTER %q", name)
+	}
+}
+
+// Tracer tracer aplikasi dari provider global
+func Tracer() trace.Tracer {
+	return otel.Tracer(instrumentationName)
+}
+
+// Client http.Client yang setiap request-nya menjadi span client dan membawa header traceparent
+func Client(upstream string) *http.Client {
+	return &http.Client{
+		Transport: otelhttp.NewTransport(http.DefaultTransport,
+			otelhttp.WithSpanNameFormatter(func(_ string, r *http.Request) string {
+				return upstream + " " + r.Method
+			}),
+		),
+	}
+}
@@ -1,2 +1,78 @@
 // validator utils
 package validator
+
+import (
+	"errors"
+	"reflect"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+var (
+	registerOnce sync.Once
+	// slug: huruf kecil, angka dan . _ - (mis. nama permission "user.manage")
+	slugPattern = regexp.MustCompile(`^[a-z0-9]+([._-][a-z0-9]+)*$`)
+)
+
+// Register mendaftarkan aturan tambahan ke validator gin (binding:"...") dan
+// memakai nama field JSON di pesan error. Aman dipanggil berkali-kali.
+func Register() {
+	registerOnce.Do(func() {
+		v, ok := binding.Validator.Engine().(*validator.Validate)
+		if !ok {
+			return
+		}
+		v.RegisterTagNameFunc(jsonFieldName)
+		_ = v.RegisterValidation("slug", func(fl validator.FieldLevel) bool {
+			return slugPattern.MatchString(fl.Field().String())
+		})
+	})
+}
+
+// Struct validasi struct di luar binding request (mis. di usecase)
+func Struct(obj any) error {
+	Register()
+	return binding.Validator.ValidateStruct(obj)
+}
+
+// Messages mengubah error validasi menjadi pesan per field; false jika bukan error validasi
+func Messages(err error) (map[string]string, bool) {
+	var ve validator.ValidationErrors
+	if !errors.As(err, &ve) {
+		return nil, false
+	}
+
+	out := make(map[string]string, len(ve))
+	for _, fe := range ve {
+		field := strings.ToLower(fe.Field())
+		switch fe.Tag() {
+		case "required":
+			out[field] = field + " is required"
+		case "email":
+			out[field] = "invalid email format"
+		case "url":
+			out[field] = "invalid url format"
+		case "min":
+			out[field] = field + " must be at least " + fe.Param() + " characters"
+		case "max":
+			out[field] = field + " must be at most " + fe.Param() + " characters"
+		case "slug":
+			out[field] = field + " may only contain lowercase letters, digits, '.', '_' and '-'"
+		default:
+			out[field] = "invalid value"
+		}
+	}
+	return out, true
+}
+
+func jsonFieldName(field reflect.StructField) string {
+	name := strings.SplitN(field.Tag.Get("json"), ",", 2)[0]
+	if name == "-" || name == "" {
+		return field.Name
+	}
+	return name
+}
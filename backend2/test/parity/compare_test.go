@@ -0,0 +1,245 @@
+package parity
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Difference satu perbedaan nilai pada path JSON yang sama
+type Difference struct {
+	Path     string `json:"path"`
+	Backend  string `json:"backend"`
+	Backend2 string `json:"backend2"`
+}
+
+// Options aturan pembanding untuk satu endpoint
+type Options struct {
+	// Fields memetakan path respon backend -> path respon backend2.
+	// Jika kosong seluruh body dibandingkan apa adanya.
+	Fields map[string]string `json:"fields"`
+	// Ignore path yang tidak dibandingkan, "[]" cocok dengan index array manapun (mis. "historyTagihan[].created_at")
+	Ignore []string `json:"ignore"`
+	// Loose membandingkan nilai skalar dari bentuk teksnya (2023 == "2023")
+	Loose bool `json:"loose"`
+}
+
+var arrayIndex = regexp.MustCompile(`\[\d+\]`)
+
+// Decode JSON dengan angka tetap presisi (json.Number)
+func Decode(body []byte) (any, error) {
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	var doc any
+	if err := decoder.Decode(&doc); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+// Project mengambil field yang dipetakan dari kedua respon dengan nama path backend
+func Project(doc any, fields map[string]string, backend2Side bool) any {
+	if len(fields) == 0 {
+		return doc
+	}
+	projected := make(map[string]any, len(fields))
+	for backendPath, backend2Path := range fields {
+		path := backendPath
+		if backend2Side {
+			path = backend2Path
+		}
+		projected[backendPath] = lookup(doc, path)
+	}
+	return projected
+}
+
+func lookup(doc any, path string) any {
+	current := doc
+	for _, key := range strings.Split(path, ".") {
+		object, ok := current.(map[string]any)
+		if !ok {
+			return nil
+		}
+		current = object[key]
+	}
+	return current
+}
+
+// Compare membandingkan dua dokumen hasil Decode/Project
+func Compare(backend, backend2 any, opt Options) []Difference {
+	var diffs []Difference
+	compareValue("", backend, backend2, opt, &diffs)
+	return diffs
+}
+
+func compareValue(path string, a, b any, opt Options, diffs *[]Difference) {
+	if ignored(path, opt.Ignore) {
+		return
+	}
+	// null, [] dan {} dianggap sama: backend lama sering mengirim null untuk slice kosong
+	if isEmpty(a) && isEmpty(b) {
+		return
+	}
+
+	switch av := a.(type) {
+	case map[string]any:
+		bv, ok := b.(map[string]any)
+		if !ok {
+			break
+		}
+		for _, key := range unionKeys(av, bv) {
+			compareValue(join(path, key), av[key], bv[key], opt, diffs)
+		}
+		return
+	case []any:
+		bv, ok := b.([]any)
+		if !ok {
+			break
+		}
+		n := len(av)
+		if len(bv) > n {
+			n = len(bv)
+		}
+		for i := 0; i < n; i++ {
+			var ai, bi any
+			if i < len(av) {
+				ai = av[i]
+			}
+			if i < len(bv) {
+				bi = bv[i]
+			}
+			compareValue(fmt.Sprintf("%s[%d]", path, i), ai, bi, opt, diffs)
+		}
+		return
+	}
+
+	if scalarEqual(a, b, opt.Loose) {
+		return
+	}
+	*diffs = append(*diffs, Difference{Path: displayPath(path), Backend: render(a), Backend2: render(b)})
+}
+
+func scalarEqual(a, b any, loose bool) bool {
+	an, aNum := a.(json.Number)
+	bn, bNum := b.(json.Number)
+	if aNum && bNum {
+		af, errA := an.Float64()
+		bf, errB := bn.Float64()
+		return errA == nil && errB == nil && af == bf
+	}
+
+	as, aStr := a.(string)
+	bs, bStr := b.(string)
+	if aStr && bStr {
+		return as == bs || sameInstant(as, bs)
+	}
+
+	if loose {
+		return scalarText(a) == scalarText(b)
+	}
+
+	_, aMap := a.(map[string]any)
+	_, bMap := b.(map[string]any)
+	_, aArr := a.([]any)
+	_, bArr := b.([]any)
+	if aMap || bMap || aArr || bArr {
+		return false
+	}
+	return a == b
+}
+
+// sameInstant waktu yang sama dengan zona atau presisi berbeda dianggap sama
+func sameInstant(a, b string) bool {
+	ta, errA := time.Parse(time.RFC3339Nano, a)
+	tb, errB := time.Parse(time.RFC3339Nano, b)
+	return errA == nil && errB == nil && ta.Equal(tb)
+}
+
+func scalarText(v any) string {
+	switch value := v.(type) {
+	case nil:
+		return ""
+	case json.Number:
+		if f, err := value.Float64(); err == nil {
+			return fmt.Sprintf("%v", f)
+		}
+		return value.String()
+	case string:
+		return value
+	default:
+		return fmt.Sprintf("%v", value)
+	}
+}
+
+func isEmpty(v any) bool {
+	switch value := v.(type) {
+	case nil:
+		return true
+	case []any:
+		return len(value) == 0
+	case map[string]any:
+		return len(value) == 0
+	}
+	return false
+}
+
+func ignored(path string, patterns []string) bool {
+	if path == "" {
+		return false
+	}
+	generic := arrayIndex.ReplaceAllString(path, "[]")
+	for _, pattern := range patterns {
+		if pattern == path || pattern == generic {
+			return true
+		}
+	}
+	return false
+}
+
+func unionKeys(a, b map[string]any) []string {
+	seen := make(map[string]struct{}, len(a)+len(b))
+	for key := range a {
+		seen[key] = struct{}{}
+	}
+	for key := range b {
+		seen[key] = struct{}{}
+	}
+	keys := make([]string, 0, len(seen))
+	for key := range seen {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func join(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+func displayPath(path string) string {
+	if path == "" {
+		return "(body)"
+	}
+	return path
+}
+
+func render(v any) string {
+	if v == nil {
+		return "<tidak ada>"
+	}
+	encoded, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+	if len(encoded) > 120 {
+		return string(encoded[:117]) + "..."
+	}
+	return string(encoded)
+}
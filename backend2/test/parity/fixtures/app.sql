@@ -0,0 +1,6 @@
+-- User backend2 (db1) untuk mahasiswa fixture; backend lama cukup memakai klaim email di JWT.
+DELETE FROM user_tokens WHERE user_id IN (SELECT id FROM (SELECT id FROM users WHERE email = '9990000001@student.unsil.ac.id') AS fixture_users);
+DELETE FROM users WHERE email = '9990000001@student.unsil.ac.id';
+
+INSERT INTO users (name, email, is_active, created_at, updated_at) VALUES
+  ('Mahasiswa Parity', '9990000001@student.unsil.ac.id', 1, NOW(), NOW());
@@ -0,0 +1,66 @@
+-- Data uji parity di database PNBP. HANYA untuk database uji yang skemanya sudah dimuat
+-- (dump skema PNBP), jangan dijalankan ke database produksi. parity_test.go hanya menjalankannya
+-- dengan PARITY_DB_CONFIRM=yes ke database berakhiran _test dan mengaktifkan kembali periode
+-- yang semula aktif setelah uji selesai.
+-- Semua baris memakai id 990001+ dan NPM 9990000001 agar mudah dibersihkan ulang.
+--
+-- Skenario:
+--   20241: registrasi lunas (tidak muncul di tagihan)
+--   20242: cicilan 3 angsuran: lunas, dibayar sebagian lewat invoice, belum dibayar
+--          (registrasi 20242 juga ada tapi harus diabaikan karena tahun ini punya cicilan)
+--   20251 (aktif): registrasi UKT 3.000.000, beasiswa aktif 500.000, sudah bayar 1.000.000
+--   status akademik "A" (bill_full): tanpa status, kedua backend memblokir tagihan (403) dan
+--   respon tidak ikut dibandingkan
+
+DELETE FROM payments WHERE id BETWEEN 990001 AND 990099;
+DELETE FROM invoice_relations WHERE id BETWEEN 990001 AND 990099;
+DELETE FROM invoices WHERE id BETWEEN 990001 AND 990099;
+DELETE FROM detail_beasiswa WHERE npm = '9990000001';
+DELETE FROM beasiswa WHERE id BETWEEN 990001 AND 990099;
+DELETE FROM detail_cicilans WHERE id BETWEEN 990001 AND 990099;
+DELETE FROM cicilans WHERE npm = '9990000001';
+DELETE FROM registrasi_mahasiswa WHERE npm = '9990000001';
+DELETE FROM mahasiswa_masters WHERE student_id = '9990000001';
+DELETE FROM budget_periods WHERE id BETWEEN 990001 AND 990099;
+DELETE FROM status_akademiks WHERE id = 990001;
+
+UPDATE budget_periods SET is_active = 0 WHERE is_active = 1;
+
+INSERT INTO budget_periods (id, kode, year, semester, name, start_date, end_date, fiscal_year, payment_start_date, payment_end_date, is_active, description, created_at, updated_at) VALUES
+  (990001, '20241', 2024, 1, 'Ganjil 2024/2025', '2024-08-01 00:00:00', '2025-01-31 23:59:59', '2024', '2024-08-01 00:00:00', '2024-08-31 23:59:59', 0, 'parity', NOW(), NOW()),
+  (990002, '20242', 2024, 2, 'Genap 2024/2025', '2025-02-01 00:00:00', '2025-07-31 23:59:59', '2025', '2025-02-01 00:00:00', '2025-02-28 23:59:59', 0, 'parity', NOW(), NOW()),
+  (990003, '20251', 2025, 1, 'Ganjil 2025/2026', '2025-08-01 00:00:00', '2026-01-31 23:59:59', '2025', '2025-08-01 00:00:00', '2025-08-31 23:59:59', 1, 'parity', NOW(), NOW());
+
+INSERT INTO status_akademiks (id, kode, nama, created_at, updated_at) VALUES
+  (990001, 'A', 'Aktif', NOW(), NOW());
+
+INSERT INTO mahasiswa_masters (id, student_id, nama_lengkap, email, prodi_id, tahun_masuk, semester_masuk_id, status_akademik_id, ukt, master_tagihan_id, created_at, updated_at) VALUES
+  (990001, '9990000001', 'Mahasiswa Parity', '9990000001@student.unsil.ac.id', 0, 2024, 990001, 990001, 3.00, 0, NOW(), NOW());
+
+INSERT INTO registrasi_mahasiswa (id, npm, tahun_id, kel_ukt, nominal_ukt, sudah_bayar, nominal_bayar, status_student_epnbp, created_at, updated_at) VALUES
+  (990001, '9990000001', '20241', '3', 3000000.00, 1, 3000000.00, 'paid', '2024-08-02 08:00:00', '2024-08-02 08:00:00'),
+  (990002, '9990000001', '20242', '3', 3000000.00, 0, 0.00, NULL, '2025-02-02 08:00:00', '2025-02-02 08:00:00'),
+  (990003, '9990000001', '20251', '3', 3000000.00, 0, 1000000.00, NULL, '2025-08-02 08:00:00', '2025-08-02 08:00:00');
+
+INSERT INTO cicilans (id, npm, tahun_id, kel_ukt, nominal_ukt, jumlah_cicilan, catatan, created_at, updated_at) VALUES
+  (990001, '9990000001', '20242', '3', 3000000, 3, 'parity', '2025-02-03 08:00:00', '2025-02-03 08:00:00');
+
+INSERT INTO detail_cicilans (id, cicilan_id, sequence_no, due_date, amount, status, catatan) VALUES
+  (990001, 990001, 1, '2025-02-10 00:00:00', 1000000, 'paid', ''),
+  (990002, 990001, 2, '2025-03-10 00:00:00', 1000000, 'unpaid', ''),
+  (990003, 990001, 3, '2025-04-10 00:00:00', 1000000, '', '');
+
+INSERT INTO invoices (id, status, created_at, updated_at) VALUES
+  (990001, 'Paid', '2025-03-05 08:00:00', '2025-03-05 08:00:00');
+
+INSERT INTO invoice_relations (id, invoice_id, detail_cicilan_id, created_at, updated_at) VALUES
+  (990001, 990001, 990002, '2025-03-05 08:00:00', '2025-03-05 08:00:00');
+
+INSERT INTO payments (id, invoice_id, amount, created_at, updated_at) VALUES
+  (990001, 990001, 400000, '2025-03-05 08:00:00', '2025-03-05 08:00:00');
+
+INSERT INTO beasiswa (id, no_sk, deskripsi, tanggal_sk, status, created_at, updated_at) VALUES
+  (990001, 'PARITY/SK/001', 'Beasiswa uji parity', '2025-07-01', 'active', NOW(), NOW());
+
+INSERT INTO detail_beasiswa (beasiswa_id, npm, tahun_id, kel_ukt_saat_ini, nominal_ukt_saat_ini, jenis_beasiswa, nominal_beasiswa, nominal_yang_dibayar, created_at, updated_at) VALUES
+  (990001, '9990000001', '20251', '3', 3000000.00, 'parity', 500000.00, 0.00, NOW(), NOW());
@@ -0,0 +1,354 @@
+// Package parity membandingkan respon backend lama dan backend2 untuk user fixture yang sama.
+//
+// Kedua router dibangun di proses uji ini (routes.SetupRouter dan app.New) di atas database uji
+// yang sama, diisi dari fixtures/*.sql, lalu setiap endpoint di cases.json dipanggil dengan user
+// yang sama dan body JSON-nya dibandingkan. Uji dilewati jika database uji tidak dikonfigurasi.
+//
+// Env (konfigurasi backend2 biasa; database PNBP yang sama dipakai backend lama):
+//
+//	PARITY_DB_CONFIRM=yes                                               wajib, fixture menghapus & mengubah data
+//	DB_PNBP_HOST DB_PNBP_PORT DB_PNBP_USER DB_PNBP_PASS DB_PNBP_NAME   database PNBP UJI (skema PNBP sudah dimuat)
+//	DB_HOST DB_PORT DB_USER DB_PASS DB_NAME                            database db1 backend2
+//	JWT_SECRET                                                          dipakai kedua backend
+//
+// Env database sama dengan konfigurasi produksi, jadi nama kedua database wajib berakhiran _test.
+//
+//	cd backend2/test/parity && PARITY_DB_CONFIRM=yes DB_PNBP_NAME=pnbp_test DB_NAME=app_test JWT_SECRET=... go test ./...
+package parity
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dedegunawan/backend-ujian-telp-v5/database"
+	"github.com/dedegunawan/backend-ujian-telp-v5/database/migrations"
+	"github.com/dedegunawan/backend-ujian-telp-v5/models"
+	"github.com/dedegunawan/backend-ujian-telp-v5/routes"
+	"github.com/dedegunawan/backend-ujian-telp-v5/services"
+	"github.com/dedegunawan/backend-ujian-telp-v5/utils"
+	"github.com/dedegunawan/epnbp.unsil.ac.id-students-backend2/config"
+	"github.com/dedegunawan/epnbp.unsil.ac.id-students-backend2/internal/app"
+	"github.com/dedegunawan/epnbp.unsil.ac.id-students-backend2/internal/domain/entity"
+	repositoryImpelementation "github.com/dedegunawan/epnbp.unsil.ac.id-students-backend2/internal/repository_implementation/mysql"
+	"github.com/dedegunawan/epnbp.unsil.ac.id-students-backend2/pkg/jwtmanager"
+	"github.com/dedegunawan/epnbp.unsil.ac.id-students-backend2/pkg/logger"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+	gormLogger "gorm.io/gorm/logger"
+)
+
+const (
+	fixtureEmail = "9990000001@student.unsil.ac.id"
+	fixtureName  = "Mahasiswa Parity"
+)
+
+// Request request ke salah satu backend
+type Request struct {
+	Method string `json:"method"`
+	Path   string `json:"path"`
+}
+
+// Case satu endpoint yang dibandingkan
+type Case struct {
+	Name string `json:"name"`
+	// Status status HTTP yang diharapkan dari kedua backend; supaya fixture yang rusak (mis. kedua
+	// backend 403) tidak lolos hanya karena statusnya sama
+	Status   int     `json:"status"`
+	Backend  Request `json:"backend"`
+	Backend2 Request `json:"backend2"`
+	Options
+}
+
+// target router salah satu backend beserta access token user fixture
+type target struct {
+	handler http.Handler
+	token   string
+}
+
+func TestParity(t *testing.T) {
+	if os.Getenv("PARITY_DB_CONFIRM") != "yes" {
+		t.Skip("PARITY_DB_CONFIRM=yes tidak di-set, uji parity butuh database uji (lihat doc package)")
+	}
+	for _, key := range []string{"DB_PNBP_NAME", "DB_NAME", "JWT_SECRET"} {
+		if os.Getenv(key) == "" {
+			t.Fatalf("%s tidak di-set, uji parity butuh database uji (lihat doc package)", key)
+		}
+	}
+	// Fixture menghapus baris dan menonaktifkan periode aktif; jangan sampai mengenai database produksi
+	for _, key := range []string{"DB_PNBP_NAME", "DB_NAME"} {
+		if name := os.Getenv(key); !strings.HasSuffix(name, "_test") {
+			t.Fatalf("%s=%q bukan database uji, nama database harus berakhiran _test", key, name)
+		}
+	}
+
+	cases := loadCases(t, "cases.json")
+	backend, backend2 := setupTargets(t)
+
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			backendStatus, backendBody := call(backend, c.Backend)
+			backend2Status, backend2Body := call(backend2, c.Backend2)
+
+			if backendStatus != backend2Status {
+				t.Errorf("status berbeda: backend=%d backend2=%d\nbackend : %s\nbackend2: %s",
+					backendStatus, backend2Status, backendBody, backend2Body)
+				return
+			}
+			if c.Status != 0 && backendStatus != c.Status {
+				t.Errorf("status %d, diharapkan %d\nbackend : %s\nbackend2: %s", backendStatus, c.Status, backendBody, backend2Body)
+				return
+			}
+			// Body error tiap backend berbeda bentuk; cukup status yang dibandingkan
+			if backendStatus >= 400 {
+				return
+			}
+
+			backendDoc, err := Decode(backendBody)
+			if err != nil {
+				t.Fatalf("backend: respon bukan JSON: %v", err)
+			}
+			backend2Doc, err := Decode(backend2Body)
+			if err != nil {
+				t.Fatalf("backend2: respon bukan JSON: %v", err)
+			}
+
+			diffs := Compare(
+				Project(backendDoc, c.Fields, false),
+				Project(backend2Doc, c.Fields, true),
+				c.Options,
+			)
+			if len(diffs) > 0 {
+				t.Errorf("%s %s vs %s %s\n%s", methodOf(c.Backend), c.Backend.Path, methodOf(c.Backend2), c.Backend2.Path, report(diffs))
+			}
+		})
+	}
+}
+
+// setupTargets membangun kedua router di atas database uji yang sama dan menerbitkan token user fixture
+func setupTargets(t *testing.T) (target, target) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	// Kedua backend melakukan discovery OIDC saat start; token Keycloak tidak dipakai di uji ini
+	issuer := fakeOIDCIssuer(t)
+	t.Setenv("OIDC_ISSUER", issuer)
+	t.Setenv("NOTIFICATION_ENABLED", "false")
+	t.Setenv("WEBHOOK_ENABLED", "false")
+
+	cfg := config.FromEnv()
+	quiet := &gorm.Config{Logger: gormLogger.Default.LogMode(gormLogger.Silent)}
+
+	dbPnbp, err := gorm.Open(mysql.Open(cfg.DBPNBP.DSN()), quiet)
+	if err != nil {
+		t.Fatalf("connect db pnbp: %v", err)
+	}
+
+	// backend lama: tabel miliknya (users, user_tokens, ...) dibuat lewat migrasi seperti saat start
+	utils.InitLogger()
+	database.DBPNBP = dbPnbp
+	migrator, err := migrations.New(dbPnbp)
+	if err != nil {
+		t.Fatalf("migrasi backend: %v", err)
+	}
+	if _, err := migrator.Up(0); err != nil {
+		t.Fatalf("migrasi backend: %v", err)
+	}
+	if err := services.LoadEligibilityRules(); err != nil {
+		t.Fatalf("aturan kelayakan backend: %v", err)
+	}
+
+	restoreActivePeriods(t, dbPnbp)
+	execSQLFile(t, dbPnbp, filepath.Join("fixtures", "pnbp.sql"))
+
+	application, err := app.New(cfg, logger.New("warn", filepath.Join(t.TempDir(), "backend2.log")))
+	if err != nil {
+		t.Fatalf("bootstrap backend2: %v", err)
+	}
+	db1 := application.DB["db1"]
+	execSQLFile(t, db1, filepath.Join("fixtures", "app.sql"))
+
+	backend := target{handler: routes.SetupRouter(), token: backendToken(t, dbPnbp)}
+	backend2 := target{handler: application.Router.Engine, token: backend2Token(t, cfg, db1)}
+	return backend, backend2
+}
+
+// restoreActivePeriods mencatat budget_periods yang aktif sebelum fixture menggantinya dengan periode
+// parity, lalu mengaktifkannya kembali setelah uji selesai
+func restoreActivePeriods(t *testing.T, db *gorm.DB) {
+	t.Helper()
+	var activeIDs []uint
+	err := db.Table("budget_periods").
+		Where("is_active = 1 AND id NOT BETWEEN 990001 AND 990099").
+		Pluck("id", &activeIDs).Error
+	if err != nil {
+		t.Fatalf("baca periode aktif: %v", err)
+	}
+
+	t.Cleanup(func() {
+		if err := db.Exec("DELETE FROM budget_periods WHERE id BETWEEN 990001 AND 990099").Error; err != nil {
+			t.Errorf("hapus periode parity: %v", err)
+		}
+		if len(activeIDs) == 0 {
+			return
+		}
+		if err := db.Exec("UPDATE budget_periods SET is_active = 1 WHERE id IN ?", activeIDs).Error; err != nil {
+			t.Errorf("kembalikan periode aktif %v: %v", activeIDs, err)
+		}
+	})
+}
+
+// backendToken JWT internal backend lama beserta baris sesinya di user_tokens; mahasiswa tidak
+// ada di tabel users sehingga UserID sesi kosong, sama seperti sesi dari login SSO
+func backendToken(t *testing.T, db *gorm.DB) string {
+	t.Helper()
+	expiresAt := time.Now().Add(15 * time.Minute)
+	token, err := utils.GenerateJWT(uuid.Nil, fixtureEmail, fixtureName, expiresAt)
+	if err != nil {
+		t.Fatalf("token backend: %v", err)
+	}
+
+	session := &models.UserToken{
+		Email:           fixtureEmail,
+		AccessTokenHash: utils.HashToken(token),
+		TokenType:       "Bearer",
+		JwtType:         models.JWTTypeInternal,
+		ExpiresAt:       expiresAt,
+		UserAgent:       "parity",
+		CreatedAt:       time.Now(),
+	}
+	if err := services.NewUserTokenService(db, nil).Repo.Create(session); err != nil {
+		t.Fatalf("simpan sesi backend: %v", err)
+	}
+	t.Cleanup(func() { db.Where("id = ?", session.ID).Delete(&models.UserToken{}) })
+	return token
+}
+
+// backend2Token JWT internal backend2 yang dicatat di user_tokens seperti login biasa
+func backend2Token(t *testing.T, cfg config.Config, db *gorm.DB) string {
+	t.Helper()
+	user, err := repositoryImpelementation.NewUserRepository(db).FindByEmail(fixtureEmail)
+	if err != nil {
+		t.Fatalf("user fixture %s tidak ada di db1: %v", fixtureEmail, err)
+	}
+
+	mgr := jwtmanager.New(cfg.JWTSecret, cfg.JWTIssuer, 15*time.Minute)
+	token, err := mgr.Generate(user.ID, fixtureEmail, fixtureName)
+	if err != nil {
+		t.Fatalf("token backend2: %v", err)
+	}
+
+	userToken := &entity.UserToken{
+		UserID:      user.ID,
+		AccessToken: token,
+		ExpiresAt:   time.Now().Add(15 * time.Minute),
+		TokenType:   "Bearer",
+		JwtType:     entity.JWTTypeInternal,
+		UserAgent:   "parity",
+	}
+	if err := repositoryImpelementation.NewUserTokenRepository(db).Create(userToken); err != nil {
+		t.Fatalf("simpan user token backend2: %v", err)
+	}
+	t.Cleanup(func() { db.Where("id = ?", userToken.ID).Delete(&entity.UserToken{}) })
+	return token
+}
+
+// fakeOIDCIssuer dokumen discovery minimal supaya inisialisasi OIDC kedua backend berhasil
+func fakeOIDCIssuer(t *testing.T) string {
+	t.Helper()
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/.well-known/openid-configuration":
+			json.NewEncoder(w).Encode(map[string]any{
+				"issuer":                 server.URL,
+				"authorization_endpoint": server.URL + "/auth",
+				"token_endpoint":         server.URL + "/token",
+				"jwks_uri":               server.URL + "/certs",
+				"end_session_endpoint":   server.URL + "/logout",
+			})
+		case "/certs":
+			w.Write([]byte(`{"keys":[]}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server.URL
+}
+
+func loadCases(t *testing.T, path string) []Case {
+	t.Helper()
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var cases []Case
+	if err := json.Unmarshal(raw, &cases); err != nil {
+		t.Fatalf("invalid cases file %s: %v", path, err)
+	}
+	return cases
+}
+
+// call mengirim request ke router di proses yang sama
+func call(target target, req Request) (int, []byte) {
+	httpReq := httptest.NewRequest(methodOf(req), req.Path, nil)
+	httpReq.Header.Set("Accept", "application/json")
+	httpReq.Header.Set("Authorization", "Bearer "+target.token)
+
+	recorder := httptest.NewRecorder()
+	target.handler.ServeHTTP(recorder, httpReq)
+	return recorder.Code, recorder.Body.Bytes()
+}
+
+// execSQLFile menjalankan file SQL per statement (DSN tidak memakai multiStatements)
+func execSQLFile(t *testing.T, db *gorm.DB, path string) {
+	t.Helper()
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var lines []string
+	for _, line := range strings.Split(string(raw), "\n") {
+		if trimmed := strings.TrimSpace(line); trimmed == "" || strings.HasPrefix(trimmed, "--") {
+			continue
+		}
+		lines = append(lines, line)
+	}
+
+	for _, statement := range strings.Split(strings.Join(lines, "\n"), ";\n") {
+		statement = strings.TrimSuffix(strings.TrimSpace(statement), ";")
+		if statement == "" {
+			continue
+		}
+		if err := db.Exec(statement).Error; err != nil {
+			t.Fatalf("%s: %v\n%s", filepath.Base(path), err, statement)
+		}
+	}
+}
+
+// report daftar perbedaan yang mudah dibaca
+func report(diffs []Difference) string {
+	var b strings.Builder
+	for _, diff := range diffs {
+		fmt.Fprintf(&b, "- %s\n    backend : %s\n    backend2: %s\n", diff.Path, diff.Backend, diff.Backend2)
+	}
+	return b.String()
+}
+
+func methodOf(req Request) string {
+	if req.Method == "" {
+		return http.MethodGet
+	}
+	return req.Method
+}